@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package customizations
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	redisenterprise "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230701/storage"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/extensions"
+)
+
+var _ extensions.PreReconciliationChecker = &RedisEnterpriseExtension{}
+
+// PreReconcileCheck blocks reconciliation if capacity checks are enabled and Redis Enterprise isn't available in the
+// requested location. The location of an existing cache can't change, so only new caches are checked.
+func (ext *RedisEnterpriseExtension) PreReconcileCheck(
+	ctx context.Context,
+	obj genruntime.MetaObject,
+	owner genruntime.MetaObject,
+	resourceResolver *resolver.Resolver,
+	armClient *genericarmclient.GenericClient,
+	log logr.Logger,
+	next extensions.PreReconcileCheckFunc,
+) (extensions.PreReconcileCheckResult, error) {
+	// This has to be the current hub storage version. It will need to be updated
+	// if the hub storage version changes.
+	typedObj, ok := obj.(*redisenterprise.RedisEnterprise)
+	if !ok {
+		return extensions.PreReconcileCheckResult{},
+			errors.Errorf("cannot run on unknown resource type %T, expected *redisenterprise.RedisEnterprise", obj)
+	}
+
+	// Type assert that we are the hub type. This will fail to compile if
+	// the hub type has been changed but this extension has not
+	var _ conversion.Hub = typedObj
+
+	if typedObj.Status.Id == nil {
+		problem, err := checkCacheLocation(ctx, armClient, "redisEnterprise", to.Value(typedObj.Spec.Location))
+		if err != nil {
+			return extensions.PreReconcileCheckResult{}, err
+		}
+
+		if problem != "" {
+			return extensions.BlockReconcile(problem), nil
+		}
+	}
+
+	return next(ctx, obj, owner, resourceResolver, armClient, log)
+}
@@ -18,10 +18,13 @@ import (
 
 	redis "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230401/storage"
 
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	"github.com/Azure/azure-service-operator/v2/internal/util/to"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/extensions"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/secrets"
 )
 
@@ -128,3 +131,64 @@ func intPtrToString(i *int) string {
 
 	return strconv.Itoa(*i)
 }
+
+var _ extensions.PreReconciliationChecker = &RedisExtension{}
+
+// PreReconcileCheck blocks reconciliation if capacity checks are enabled and Redis isn't available in the requested
+// location. The location of an existing cache can't change, so only new caches are checked.
+func (ext *RedisExtension) PreReconcileCheck(
+	ctx context.Context,
+	obj genruntime.MetaObject,
+	owner genruntime.MetaObject,
+	resourceResolver *resolver.Resolver,
+	armClient *genericarmclient.GenericClient,
+	log logr.Logger,
+	next extensions.PreReconcileCheckFunc,
+) (extensions.PreReconcileCheckResult, error) {
+	// This has to be the current hub storage version. It will need to be updated
+	// if the hub storage version changes.
+	typedObj, ok := obj.(*redis.Redis)
+	if !ok {
+		return extensions.PreReconcileCheckResult{},
+			errors.Errorf("cannot run on unknown resource type %T, expected *redis.Redis", obj)
+	}
+
+	// Type assert that we are the hub type. This will fail to compile if
+	// the hub type has been changed but this extension has not
+	var _ conversion.Hub = typedObj
+
+	if typedObj.Status.Id == nil {
+		problem, err := checkCacheLocation(ctx, armClient, "redis", to.Value(typedObj.Spec.Location))
+		if err != nil {
+			return extensions.PreReconcileCheckResult{}, err
+		}
+
+		if problem != "" {
+			return extensions.BlockReconcile(problem), nil
+		}
+	}
+
+	return next(ctx, obj, owner, resourceResolver, armClient, log)
+}
+
+// checkCacheLocation checks that resourceType of Microsoft.Cache is available in location, if capacity checks are
+// enabled. Returns a message explaining the problem, or an empty string if it's available (or nothing needs checking).
+func checkCacheLocation(
+	ctx context.Context,
+	armClient *genericarmclient.GenericClient,
+	resourceType string,
+	location string,
+) (string, error) {
+	checker, subscription, ok := capacity.CheckerFrom(ctx)
+	if !ok || location == "" {
+		// Not enabled, or not enough information to check
+		return "", nil
+	}
+
+	problem, err := checker.CheckResourceTypeLocation(ctx, armClient, subscription, "Microsoft.Cache", resourceType, location)
+	if err != nil {
+		return "", errors.Wrap(err, "checking capacity")
+	}
+
+	return problem, nil
+}
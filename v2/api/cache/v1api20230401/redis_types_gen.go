@@ -229,7 +229,17 @@ func (redis *Redis) updateValidations() []func(old runtime.Object) (admission.Wa
 		func(old runtime.Object) (admission.Warnings, error) {
 			return redis.validateSecretDestinations()
 		},
+		redis.validateImmutableProperties}
+}
+
+// validateImmutableProperties validates that create-only properties are not changed once the resource has been created
+func (redis *Redis) validateImmutableProperties(old runtime.Object) (admission.Warnings, error) {
+	oldObj, ok := old.(*Redis)
+	if !ok {
+		return nil, nil
 	}
+
+	return genruntime.ValidateImmutableProperties(oldObj, redis, "location")
 }
 
 // validateOwnerReference validates the owner field
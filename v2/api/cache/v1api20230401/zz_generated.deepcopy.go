@@ -1297,6 +1297,110 @@ func (in *RedisProperties_STATUS_ARM) DeepCopy() *RedisProperties_STATUS_ARM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisRegenerateKey) DeepCopyInto(out *RedisRegenerateKey) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisRegenerateKey.
+func (in *RedisRegenerateKey) DeepCopy() *RedisRegenerateKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisRegenerateKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisRegenerateKey) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisRegenerateKeyList) DeepCopyInto(out *RedisRegenerateKeyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RedisRegenerateKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisRegenerateKeyList.
+func (in *RedisRegenerateKeyList) DeepCopy() *RedisRegenerateKeyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisRegenerateKeyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisRegenerateKeyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisRegenerateKeyOperatorSecrets) DeepCopyInto(out *RedisRegenerateKeyOperatorSecrets) {
+	*out = *in
+	if in.PrimaryKey != nil {
+		in, out := &in.PrimaryKey, &out.PrimaryKey
+		*out = new(genruntime.SecretDestination)
+		**out = **in
+	}
+	if in.SecondaryKey != nil {
+		in, out := &in.SecondaryKey, &out.SecondaryKey
+		*out = new(genruntime.SecretDestination)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisRegenerateKeyOperatorSecrets.
+func (in *RedisRegenerateKeyOperatorSecrets) DeepCopy() *RedisRegenerateKeyOperatorSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisRegenerateKeyOperatorSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisRegenerateKeyOperatorSpec) DeepCopyInto(out *RedisRegenerateKeyOperatorSpec) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = new(RedisRegenerateKeyOperatorSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisRegenerateKeyOperatorSpec.
+func (in *RedisRegenerateKeyOperatorSpec) DeepCopy() *RedisRegenerateKeyOperatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisRegenerateKeyOperatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Redis_FirewallRule_STATUS) DeepCopyInto(out *Redis_FirewallRule_STATUS) {
 	*out = *in
@@ -1722,6 +1826,113 @@ func (in *Redis_PatchSchedule_Spec_ARM) DeepCopy() *Redis_PatchSchedule_Spec_ARM
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redis_RegenerateKey_STATUS) DeepCopyInto(out *Redis_RegenerateKey_STATUS) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]conditions.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrimaryKey != nil {
+		in, out := &in.PrimaryKey, &out.PrimaryKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecondaryKey != nil {
+		in, out := &in.SecondaryKey, &out.SecondaryKey
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis_RegenerateKey_STATUS.
+func (in *Redis_RegenerateKey_STATUS) DeepCopy() *Redis_RegenerateKey_STATUS {
+	if in == nil {
+		return nil
+	}
+	out := new(Redis_RegenerateKey_STATUS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redis_RegenerateKey_STATUS_ARM) DeepCopyInto(out *Redis_RegenerateKey_STATUS_ARM) {
+	*out = *in
+	if in.PrimaryKey != nil {
+		in, out := &in.PrimaryKey, &out.PrimaryKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecondaryKey != nil {
+		in, out := &in.SecondaryKey, &out.SecondaryKey
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis_RegenerateKey_STATUS_ARM.
+func (in *Redis_RegenerateKey_STATUS_ARM) DeepCopy() *Redis_RegenerateKey_STATUS_ARM {
+	if in == nil {
+		return nil
+	}
+	out := new(Redis_RegenerateKey_STATUS_ARM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redis_RegenerateKey_Spec) DeepCopyInto(out *Redis_RegenerateKey_Spec) {
+	*out = *in
+	if in.KeyType != nil {
+		in, out := &in.KeyType, &out.KeyType
+		*out = new(Redis_RegenerateKey_KeyType_Spec)
+		**out = **in
+	}
+	if in.OperatorSpec != nil {
+		in, out := &in.OperatorSpec, &out.OperatorSpec
+		*out = new(RedisRegenerateKeyOperatorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(genruntime.KnownResourceReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis_RegenerateKey_Spec.
+func (in *Redis_RegenerateKey_Spec) DeepCopy() *Redis_RegenerateKey_Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(Redis_RegenerateKey_Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redis_RegenerateKey_Spec_ARM) DeepCopyInto(out *Redis_RegenerateKey_Spec_ARM) {
+	*out = *in
+	if in.KeyType != nil {
+		in, out := &in.KeyType, &out.KeyType
+		*out = new(Redis_RegenerateKey_KeyType_Spec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis_RegenerateKey_Spec_ARM.
+func (in *Redis_RegenerateKey_Spec_ARM) DeepCopy() *Redis_RegenerateKey_Spec_ARM {
+	if in == nil {
+		return nil
+	}
+	out := new(Redis_RegenerateKey_Spec_ARM)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Redis_STATUS) DeepCopyInto(out *Redis_STATUS) {
 	*out = *in
@@ -0,0 +1,102 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package customizations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	compute "github.com/Azure/azure-service-operator/v2/api/compute/v1api20220301/storage"
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/extensions"
+)
+
+// newCapacityTestContext returns a context carrying a capacity checker with no cache
+func newCapacityTestContext() context.Context {
+	return capacity.WithChecker(context.Background(), capacity.NewChecker(nil), "00000000-0000-0000-0000-000000000000")
+}
+
+// proceed is the next pre-reconciliation check, allowing reconciliation to proceed
+func proceed(
+	_ context.Context,
+	_ genruntime.MetaObject,
+	_ genruntime.MetaObject,
+	_ *resolver.Resolver,
+	_ *genericarmclient.GenericClient,
+	_ logr.Logger,
+) (extensions.PreReconcileCheckResult, error) {
+	return extensions.ProceedWithReconcile(), nil
+}
+
+// The checker has no cache, so any attempt to query Azure panics; these tests show that no query is made for
+// resources that already exist and aren't growing.
+
+func Test_VirtualMachinePreReconcileCheck_WhenExistingVMNotResized_DoesNotCheck(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	vm := &compute.VirtualMachine{
+		Spec: compute.VirtualMachine_Spec{
+			Location:        to.Ptr("westus2"),
+			HardwareProfile: &compute.HardwareProfile{VmSize: to.Ptr("Standard_D2s_v3")},
+		},
+		Status: compute.VirtualMachine_STATUS{
+			Id:              to.Ptr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg/providers/Microsoft.Compute/virtualMachines/myvm"),
+			HardwareProfile: &compute.HardwareProfile_STATUS{VmSize: to.Ptr("standard_d2s_v3")},
+		},
+	}
+
+	extension := &VirtualMachineExtension{}
+	check, err := extension.PreReconcileCheck(newCapacityTestContext(), vm, nil, nil, nil, logr.Discard(), proceed)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(check.BlockReconciliation()).To(BeFalse())
+}
+
+func Test_VirtualMachineScaleSetPreReconcileCheck_WhenExistingScaleSetNotGrowing_DoesNotCheck(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	vmss := &compute.VirtualMachineScaleSet{
+		Spec: compute.VirtualMachineScaleSet_Spec{
+			Location: to.Ptr("westus2"),
+			Sku:      &compute.Sku{Name: to.Ptr("Standard_D2s_v3"), Capacity: to.Ptr(2)},
+		},
+		Status: compute.VirtualMachineScaleSet_STATUS{
+			Id:  to.Ptr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/myrg/providers/Microsoft.Compute/virtualMachineScaleSets/myvmss"),
+			Sku: &compute.Sku_STATUS{Name: to.Ptr("Standard_D2s_v3"), Capacity: to.Ptr(3)},
+		},
+	}
+
+	extension := &VirtualMachineScaleSetExtension{}
+	check, err := extension.PreReconcileCheck(newCapacityTestContext(), vmss, nil, nil, nil, logr.Discard(), proceed)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(check.BlockReconciliation()).To(BeFalse())
+}
+
+func Test_VirtualMachinePreReconcileCheck_WhenCapacityChecksDisabled_DoesNotCheck(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// A new VM would need checking, if capacity checks were enabled
+	vm := &compute.VirtualMachine{
+		Spec: compute.VirtualMachine_Spec{
+			Location:        to.Ptr("westus2"),
+			HardwareProfile: &compute.HardwareProfile{VmSize: to.Ptr("Standard_D2s_v3")},
+		},
+	}
+
+	extension := &VirtualMachineExtension{}
+	check, err := extension.PreReconcileCheck(context.Background(), vm, nil, nil, nil, logr.Discard(), proceed)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(check.BlockReconciliation()).To(BeFalse())
+}
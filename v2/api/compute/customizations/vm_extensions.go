@@ -6,9 +6,19 @@ Licensed under the MIT license.
 package customizations
 
 import (
+	"context"
+	"strings"
+
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
+	compute "github.com/Azure/azure-service-operator/v2/api/compute/v1api20220301/storage"
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/extensions"
 )
@@ -35,3 +45,74 @@ func (e *VirtualMachineExtension) ClassifyError(
 
 	return details, nil
 }
+
+var _ extensions.PreReconciliationChecker = &VirtualMachineExtension{}
+
+// PreReconcileCheck blocks reconciliation if capacity checks are enabled and Azure doesn't have the capacity for the VM.
+func (e *VirtualMachineExtension) PreReconcileCheck(
+	ctx context.Context,
+	obj genruntime.MetaObject,
+	owner genruntime.MetaObject,
+	resourceResolver *resolver.Resolver,
+	armClient *genericarmclient.GenericClient,
+	log logr.Logger,
+	next extensions.PreReconcileCheckFunc,
+) (extensions.PreReconcileCheckResult, error) {
+	// This has to be the current hub storage version. It will need to be updated
+	// if the hub storage version changes.
+	vm, ok := obj.(*compute.VirtualMachine)
+	if !ok {
+		return extensions.PreReconcileCheckResult{},
+			errors.Errorf("cannot run on unknown resource type %T, expected *compute.VirtualMachine", obj)
+	}
+
+	// Type assert that we are the hub type. This will fail to compile if
+	// the hub type has been changed but this extension has not
+	var _ conversion.Hub = vm
+
+	if checker, subscription, ok := capacity.CheckerFrom(ctx); ok {
+		problem, err := checkVirtualMachineCapacity(ctx, checker, subscription, vm, armClient)
+		if err != nil {
+			return extensions.PreReconcileCheckResult{}, errors.Wrap(err, "checking capacity")
+		}
+
+		if problem != "" {
+			return extensions.BlockReconcile(problem), nil
+		}
+	}
+
+	return next(ctx, obj, owner, resourceResolver, armClient, log)
+}
+
+// checkVirtualMachineCapacity checks that the VM size is available, and that there's enough vCPU quota to create the
+// VM. Existing VMs are only checked if they're being resized, and then only for the availability of the new size; we
+// don't try to work out the effect of resizing on quota.
+// Returns a message explaining the problem, or an empty string if there's capacity (or nothing needs checking).
+func checkVirtualMachineCapacity(
+	ctx context.Context,
+	checker *capacity.Checker,
+	subscription string,
+	vm *compute.VirtualMachine,
+	armClient *genericarmclient.GenericClient,
+) (string, error) {
+	location := to.Value(vm.Spec.Location)
+	if location == "" || vm.Spec.HardwareProfile == nil || vm.Spec.HardwareProfile.VmSize == nil {
+		// Not enough information to check
+		return "", nil
+	}
+
+	request := capacity.VMRequest{
+		Size:  *vm.Spec.HardwareProfile.VmSize,
+		Zones: vm.Spec.Zones,
+	}
+
+	if vm.Status.Id == nil {
+		// A new VM needs quota
+		request.Instances = 1
+	} else if vm.Status.HardwareProfile != nil && strings.EqualFold(to.Value(vm.Status.HardwareProfile.VmSize), request.Size) {
+		// Nothing has changed since the VM was created
+		return "", nil
+	}
+
+	return checker.CheckVirtualMachines(ctx, armClient, subscription, location, request)
+}
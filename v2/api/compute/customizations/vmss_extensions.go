@@ -6,9 +6,19 @@ Licensed under the MIT license.
 package customizations
 
 import (
+	"context"
+	"strings"
+
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
+	compute "github.com/Azure/azure-service-operator/v2/api/compute/v1api20220301/storage"
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/extensions"
 )
@@ -35,3 +45,78 @@ func (e *VirtualMachineScaleSetExtension) ClassifyError(
 
 	return details, nil
 }
+
+var _ extensions.PreReconciliationChecker = &VirtualMachineScaleSetExtension{}
+
+// PreReconcileCheck blocks reconciliation if capacity checks are enabled and Azure doesn't have the capacity for the
+// scale set.
+func (e *VirtualMachineScaleSetExtension) PreReconcileCheck(
+	ctx context.Context,
+	obj genruntime.MetaObject,
+	owner genruntime.MetaObject,
+	resourceResolver *resolver.Resolver,
+	armClient *genericarmclient.GenericClient,
+	log logr.Logger,
+	next extensions.PreReconcileCheckFunc,
+) (extensions.PreReconcileCheckResult, error) {
+	// This has to be the current hub storage version. It will need to be updated
+	// if the hub storage version changes.
+	vmss, ok := obj.(*compute.VirtualMachineScaleSet)
+	if !ok {
+		return extensions.PreReconcileCheckResult{},
+			errors.Errorf("cannot run on unknown resource type %T, expected *compute.VirtualMachineScaleSet", obj)
+	}
+
+	// Type assert that we are the hub type. This will fail to compile if
+	// the hub type has been changed but this extension has not
+	var _ conversion.Hub = vmss
+
+	if checker, subscription, ok := capacity.CheckerFrom(ctx); ok {
+		problem, err := checkVirtualMachineScaleSetCapacity(ctx, checker, subscription, vmss, armClient)
+		if err != nil {
+			return extensions.PreReconcileCheckResult{}, errors.Wrap(err, "checking capacity")
+		}
+
+		if problem != "" {
+			return extensions.BlockReconcile(problem), nil
+		}
+	}
+
+	return next(ctx, obj, owner, resourceResolver, armClient, log)
+}
+
+// checkVirtualMachineScaleSetCapacity checks that the VM size is available, and that there's enough vCPU quota for the
+// instances of the scale set. Existing scale sets are only checked if their size has changed or their capacity has
+// increased.
+// Returns a message explaining the problem, or an empty string if there's capacity (or nothing needs checking).
+func checkVirtualMachineScaleSetCapacity(
+	ctx context.Context,
+	checker *capacity.Checker,
+	subscription string,
+	vmss *compute.VirtualMachineScaleSet,
+	armClient *genericarmclient.GenericClient,
+) (string, error) {
+	location := to.Value(vmss.Spec.Location)
+	if location == "" || vmss.Spec.Sku == nil || vmss.Spec.Sku.Name == nil {
+		// Not enough information to check
+		return "", nil
+	}
+
+	size := *vmss.Spec.Sku.Name
+	request := capacity.VMRequest{
+		Size:      size,
+		Zones:     vmss.Spec.Zones,
+		Instances: to.Value(vmss.Spec.Sku.Capacity),
+	}
+
+	// Instances already running count towards current usage, so we only need quota for the additional instances
+	if vmss.Status.Id != nil && vmss.Status.Sku != nil && strings.EqualFold(to.Value(vmss.Status.Sku.Name), size) {
+		request.Instances -= to.Value(vmss.Status.Sku.Capacity)
+		if request.Instances <= 0 {
+			// Not growing, so nothing to check
+			return "", nil
+		}
+	}
+
+	return checker.CheckVirtualMachines(ctx, armClient, subscription, location, request)
+}
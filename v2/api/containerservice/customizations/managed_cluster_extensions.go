@@ -20,9 +20,11 @@ import (
 	containerservice "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20230201/storage"
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
 
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/extensions"
@@ -141,11 +143,11 @@ var nonBlockingManagedClusterProvisioningStates = set.Make(
 )
 
 func (ext *ManagedClusterExtension) PreReconcileCheck(
-	_ context.Context,
+	ctx context.Context,
 	obj genruntime.MetaObject,
 	_ genruntime.MetaObject,
 	_ *resolver.Resolver,
-	_ *genericarmclient.GenericClient,
+	armClient *genericarmclient.GenericClient,
 	_ logr.Logger,
 	_ extensions.PreReconcileCheckFunc,
 ) (extensions.PreReconcileCheckResult, error) {
@@ -172,9 +174,88 @@ func (ext *ManagedClusterExtension) PreReconcileCheck(
 			nil
 	}
 
+	// If capacity checks are enabled, check Azure has the capacity for the agent pools
+	if checker, subscription, ok := capacity.CheckerFrom(ctx); ok {
+		problem, err := checkManagedClusterCapacity(ctx, checker, subscription, managedCluster, armClient)
+		if err != nil {
+			return extensions.PreReconcileCheckResult{}, errors.Wrap(err, "checking capacity")
+		}
+
+		if problem != "" {
+			return extensions.BlockReconcile(problem), nil
+		}
+	}
+
 	return extensions.ProceedWithReconcile(), nil
 }
 
+// checkManagedClusterCapacity checks that the VM size of each agent pool is available, and that there's enough vCPU
+// quota for the nodes. For an existing cluster, only agent pools that are new, have changed size, or have more nodes
+// are checked.
+// Returns a message explaining the problem, or an empty string if there's capacity (or nothing needs checking).
+func checkManagedClusterCapacity(
+	ctx context.Context,
+	checker *capacity.Checker,
+	subscription string,
+	managedCluster *containerservice.ManagedCluster,
+	armClient *genericarmclient.GenericClient,
+) (string, error) {
+	location := to.Value(managedCluster.Spec.Location)
+	if location == "" {
+		// Not enough information to check
+		return "", nil
+	}
+
+	// Nodes already running count towards current usage, so we only need quota for additional nodes
+	currentNodes := make(map[string]int, len(managedCluster.Status.AgentPoolProfiles))
+	if managedCluster.Status.Id != nil {
+		for _, profile := range managedCluster.Status.AgentPoolProfiles {
+			currentNodes[agentPoolKey(profile.Name, profile.VmSize)] = to.Value(profile.Count)
+		}
+	}
+
+	requests := make([]capacity.VMRequest, 0, len(managedCluster.Spec.AgentPoolProfiles))
+	for _, profile := range managedCluster.Spec.AgentPoolProfiles {
+		if profile.VmSize == nil {
+			continue
+		}
+
+		current, exists := currentNodes[agentPoolKey(profile.Name, profile.VmSize)]
+		additional := agentPoolNodeCount(profile.Count, profile.MinCount) - current
+		if exists && additional <= 0 {
+			// The agent pool isn't growing, so nothing to check
+			continue
+		}
+
+		requests = append(requests, capacity.VMRequest{
+			Size:      *profile.VmSize,
+			Zones:     profile.AvailabilityZones,
+			Instances: additional,
+		})
+	}
+
+	if len(requests) == 0 {
+		return "", nil
+	}
+
+	return checker.CheckVirtualMachines(ctx, armClient, subscription, location, requests...)
+}
+
+// agentPoolKey identifies nodes of the same size in the same agent pool
+func agentPoolKey(name *string, vmSize *string) string {
+	return strings.ToLower(to.Value(name) + "/" + to.Value(vmSize))
+}
+
+// agentPoolNodeCount returns the number of nodes an agent pool needs; if autoscaling, count may be omitted, in
+// which case the pool starts with minCount nodes
+func agentPoolNodeCount(count *int, minCount *int) int {
+	if count != nil {
+		return *count
+	}
+
+	return to.Value(minCount)
+}
+
 func clusterProvisioningStateBlocksReconciliation(provisioningState *string) bool {
 	if provisioningState == nil {
 		return false
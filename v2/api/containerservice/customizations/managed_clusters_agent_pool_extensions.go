@@ -16,9 +16,11 @@ import (
 
 	containerservice "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20230201/storage"
 
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/extensions"
 )
@@ -36,11 +38,11 @@ var nonBlockingManagedClustersAgentPoolProvisioningStates = set.Make(
 )
 
 func (ext *ManagedClustersAgentPoolExtension) PreReconcileCheck(
-	_ context.Context,
+	ctx context.Context,
 	obj genruntime.MetaObject,
 	owner genruntime.MetaObject,
 	_ *resolver.Resolver,
-	_ *genericarmclient.GenericClient,
+	armClient *genericarmclient.GenericClient,
 	_ logr.Logger,
 	_ extensions.PreReconcileCheckFunc,
 ) (extensions.PreReconcileCheckResult, error) {
@@ -80,9 +82,63 @@ func (ext *ManagedClustersAgentPoolExtension) PreReconcileCheck(
 			nil
 	}
 
+	// If capacity checks are enabled, check Azure has the capacity for the nodes; agent pools are always in the same
+	// location as their cluster
+	if managedCluster, ok := owner.(*containerservice.ManagedCluster); ok {
+		if checker, subscription, ok := capacity.CheckerFrom(ctx); ok {
+			problem, err := checkAgentPoolCapacity(ctx, checker, subscription, agentPool, managedCluster, armClient)
+			if err != nil {
+				return extensions.PreReconcileCheckResult{}, errors.Wrap(err, "checking capacity")
+			}
+
+			if problem != "" {
+				return extensions.BlockReconcile(problem), nil
+			}
+		}
+	}
+
 	return extensions.ProceedWithReconcile(), nil
 }
 
+// checkAgentPoolCapacity checks that the VM size is available, and that there's enough vCPU quota for the nodes of the
+// pool. Existing agent pools are only checked if their size has changed or they have more nodes.
+// Returns a message explaining the problem, or an empty string if there's capacity (or nothing needs checking).
+func checkAgentPoolCapacity(
+	ctx context.Context,
+	checker *capacity.Checker,
+	subscription string,
+	agentPool *containerservice.ManagedClustersAgentPool,
+	managedCluster *containerservice.ManagedCluster,
+	armClient *genericarmclient.GenericClient,
+) (string, error) {
+	location := to.Value(managedCluster.Status.Location)
+	if location == "" {
+		location = to.Value(managedCluster.Spec.Location)
+	}
+
+	if location == "" || agentPool.Spec.VmSize == nil {
+		// Not enough information to check
+		return "", nil
+	}
+
+	request := capacity.VMRequest{
+		Size:      *agentPool.Spec.VmSize,
+		Zones:     agentPool.Spec.AvailabilityZones,
+		Instances: agentPoolNodeCount(agentPool.Spec.Count, agentPool.Spec.MinCount),
+	}
+
+	// Nodes already running count towards current usage, so we only need quota for additional nodes
+	if agentPool.Status.Id != nil && strings.EqualFold(to.Value(agentPool.Status.VmSize), request.Size) {
+		request.Instances -= to.Value(agentPool.Status.Count)
+		if request.Instances <= 0 {
+			// Not growing, so nothing to check
+			return "", nil
+		}
+	}
+
+	return checker.CheckVirtualMachines(ctx, armClient, subscription, location, request)
+}
+
 func agentPoolProvisioningStateBlocksReconciliation(provisioningState *string) bool {
 	if provisioningState == nil {
 		return false
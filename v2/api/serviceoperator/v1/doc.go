@@ -0,0 +1,8 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package v1 contains hand-crafted API Schema definitions for configuring the operator itself
+// +groupName=serviceoperator.azure.com
+package v1
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:rbac:groups=serviceoperator.azure.com,resources=asopolicies,verbs=get;list;watch
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+// ASOPolicy restricts the Azure resources that may be created in the namespaces it applies to.
+// Where several policies apply to a namespace, a resource must satisfy all of them.
+type ASOPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ASOPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type ASOPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ASOPolicy `json:"items"`
+}
+
+type ASOPolicySpec struct {
+	// NamespaceSelector: Selects the namespaces the policy applies to. If omitted, the policy applies to all namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// AllowedLocations: The Azure locations in which resources may be created, such as "westus2".
+	// If empty, resources may be created in any location.
+	AllowedLocations []string `json:"allowedLocations,omitempty"`
+
+	// RequiredTags: The names of tags that must be set on every resource that supports tags.
+	RequiredTags []string `json:"requiredTags,omitempty"`
+
+	// Resources: Restrictions on specific kinds of resource.
+	Resources []ResourcePolicy `json:"resources,omitempty"`
+}
+
+// ResourcePolicy restricts a single kind of resource
+type ResourcePolicy struct {
+	// +kubebuilder:validation:Required
+	// Group: The group of the resource, such as "cache.azure.com".
+	Group string `json:"group,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Kind: The kind of the resource, such as "Redis".
+	Kind string `json:"kind,omitempty"`
+
+	// AllowedSKUs: The allowed values of spec.sku.name, compared case-insensitively. If empty, any SKU is allowed.
+	AllowedSKUs []string `json:"allowedSKUs,omitempty"`
+
+	// AllowedTiers: The allowed values of spec.sku.tier, compared case-insensitively. If empty, any tier is allowed.
+	AllowedTiers []string `json:"allowedTiers,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// MaxCount: The maximum number of resources of this kind in each namespace. If omitted, there is no limit.
+	MaxCount *int `json:"maxCount,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ASOPolicy{}, &ASOPolicyList{})
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/version"
+
+	"github.com/Azure/azure-service-operator/v2/cmd/asoctl/internal/azure"
+)
+
+var (
+	cloudOptions      = azure.NewCloudOptionsFromEnvironment()      // Cloud selection, shared by all ARM-using commands
+	credentialOptions = azure.NewCredentialOptionsFromEnvironment() // Credential selection, shared by all ARM-using commands
+)
+
+// addAzureFlags adds the flags used to select an Azure cloud and credential.
+// Defaults are taken from the environment so the flags only need to be used to override them.
+func addAzureFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&cloudOptions.Name,
+		"cloud",
+		cloudOptions.Name,
+		fmt.Sprintf("Azure cloud to use: AzurePublic, AzureChina or AzureUSGovernment (env %s)", azure.CloudEnvironmentVariable))
+	flags.StringVar(
+		&cloudOptions.ResourceManagerEndpoint,
+		"resource-manager-endpoint",
+		cloudOptions.ResourceManagerEndpoint,
+		"Azure Resource Manager endpoint for a custom cloud (env AZURE_RESOURCE_MANAGER_ENDPOINT)")
+	flags.StringVar(
+		&cloudOptions.ResourceManagerAudience,
+		"resource-manager-audience",
+		cloudOptions.ResourceManagerAudience,
+		"Azure Resource Manager AAD audience for a custom cloud (env AZURE_RESOURCE_MANAGER_AUDIENCE)")
+	flags.StringVar(
+		&cloudOptions.AzureAuthorityHost,
+		"authority-host",
+		cloudOptions.AzureAuthorityHost,
+		"AAD authority host for a custom cloud (env AZURE_AUTHORITY_HOST)")
+
+	sources := make([]string, 0, len(azure.CredentialSources))
+	for _, source := range azure.CredentialSources {
+		sources = append(sources, string(source))
+	}
+
+	flags.StringVar(
+		&credentialOptions.Source,
+		"credential",
+		credentialOptions.Source,
+		fmt.Sprintf(
+			"Credential used to authenticate with Azure: %s (env %s)",
+			strings.Join(sources, ", "),
+			azure.CredentialSourceEnvironmentVariable))
+	flags.StringVar(
+		&credentialOptions.TenantID,
+		"tenant-id",
+		credentialOptions.TenantID,
+		"AAD tenant of the identity (env AZURE_TENANT_ID)")
+	flags.StringVar(
+		&credentialOptions.ClientID,
+		"client-id",
+		credentialOptions.ClientID,
+		"Client ID of the service principal or managed identity (env AZURE_CLIENT_ID); "+
+			"the client secret is only read from AZURE_CLIENT_SECRET")
+	flags.StringVar(
+		&credentialOptions.ClientCertificatePath,
+		"client-certificate",
+		credentialOptions.ClientCertificatePath,
+		fmt.Sprintf(
+			"Path to a PEM or PKCS12 client certificate (env %s); any password is read from AZURE_CLIENT_CERTIFICATE_PASSWORD",
+			azure.ClientCertificatePathEnvironmentVariable))
+	flags.StringVar(
+		&credentialOptions.FederatedTokenFile,
+		"federated-token-file",
+		credentialOptions.FederatedTokenFile,
+		fmt.Sprintf("Path to a federated token for workload identity (env %s)", azure.FederatedTokenFileEnvironmentVariable))
+	flags.StringVar(
+		&credentialOptions.Secret,
+		"credential-secret",
+		credentialOptions.Secret,
+		fmt.Sprintf(
+			"ASO credential secret to read from the cluster, as <namespace>/<name> (env %s)",
+			azure.CredentialSecretEnvironmentVariable))
+}
+
+// newARMClient creates a client for Azure Resource Manager using the cloud and credential selected by the user
+func newARMClient(ctx context.Context) (*genericarmclient.GenericClient, error) {
+	activeCloud, err := cloudOptions.Cloud()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to select Azure cloud")
+	}
+
+	creds, err := credentialOptions.Credential(ctx, activeCloud, newKubeClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get Azure credential")
+	}
+
+	clientOptions := &genericarmclient.GenericClientOptions{
+		UserAgent: "asoctl/" + version.BuildVersion,
+	}
+
+	client, err := genericarmclient.NewGenericClient(activeCloud, creds, clientOptions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create ARM client")
+	}
+
+	return client, nil
+}
+
+// newKubeClient creates a client for the current Kubernetes cluster, aware of all ASO resources
+func newKubeClient() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get Kubernetes config")
+	}
+
+	cl, err := client.New(cfg, client.Options{Scheme: api.CreateScheme()})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create Kubernetes client")
+	}
+
+	return cl, nil
+}
@@ -19,13 +19,16 @@ import (
 
 // newCleanCRDsCommand creates a new cobra command for cleaning deprecated CRDs from a cluster
 func newCleanCRDsCommand() *cobra.Command {
-	dryRunFlagName := "dry-run"
-	dryRun := false
+	var options crd.CleanerOptions
 
 	cmd := &cobra.Command{
 		Use:   "crds",
 		Short: "Clean deprecated Custom Resource Definition (CRD) versions from cluster",
-		Args:  cobra.NoArgs,
+		Long: "Clean deprecated Custom Resource Definition (CRD) versions from cluster. " +
+			"Every object of each selected CRD is rewritten to the current storage version, " +
+			"then the removed versions are dropped from status.storedVersions. " +
+			"By default, deprecated beta versions are removed from all ASO CRDs.",
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.GetConfig()
 			if err != nil {
@@ -44,15 +47,33 @@ func newCleanCRDsCommand() *cobra.Command {
 				return errors.Wrap(err, "unable to create Kubernetes client")
 			}
 
-			return crd.NewCleaner(
+			return crd.NewCleanerWithOptions(
 				apiExtClient.CustomResourceDefinitions(),
 				cl,
-				dryRun,
+				options,
 				CreateLogger()).Run(ctx)
 		},
 	}
 
-	cmd.Flags().BoolVar(&dryRun, dryRunFlagName, false, "Dry run (don't actually clean anything)")
+	cmd.Flags().BoolVar(&options.DryRun, "dry-run", false, "Dry run (don't actually clean anything)")
+
+	cmd.Flags().StringSliceVar(
+		&options.Selectors,
+		"crd",
+		nil,
+		"Only clean CRDs whose group or name matches one of these glob patterns, e.g. storage.azure.com or *.network.azure.com")
+
+	cmd.Flags().StringSliceVar(
+		&options.Versions,
+		"versions",
+		nil,
+		"Stored versions to remove, e.g. v1api20200601; storage variants are removed too (default deprecated beta versions)")
+
+	cmd.Flags().StringVar(
+		&options.CheckpointPath,
+		"checkpoint",
+		"",
+		"File used to record progress; rerun with the same file to resume an interrupted run")
 
 	return cmd
 }
@@ -8,14 +8,10 @@ package cmd
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/Azure/azure-service-operator/v2/api"
-	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
-	"github.com/Azure/azure-service-operator/v2/internal/version"
 
 	"github.com/Azure/azure-service-operator/v2/cmd/asoctl/internal/importing"
 )
@@ -55,20 +51,9 @@ func importAzureResource(ctx context.Context, armIDs []string, options importAzu
 
 	log, progress := CreateLoggerAndProgressBar()
 
-	//TODO: Support other Azure clouds
-	activeCloud := cloud.AzurePublic
-	creds, err := azidentity.NewDefaultAzureCredential(nil)
+	client, err := newARMClient(ctx)
 	if err != nil {
-		return errors.Wrap(err, "unable to get default Azure credential")
-	}
-
-	clientOptions := &genericarmclient.GenericClientOptions{
-		UserAgent: "asoctl/" + version.BuildVersion,
-	}
-
-	client, err := genericarmclient.NewGenericClient(activeCloud, creds, clientOptions)
-	if err != nil {
-		return errors.Wrapf(err, "failed to create ARM client")
+		return err
 	}
 
 	importer := importing.NewResourceImporter(api.CreateScheme(), client, log, progress)
@@ -0,0 +1,22 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+// newMigrateCommand creates a new cobra Command when invoked from the command line
+func newMigrateCommand() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrates resources from earlier versions of Azure Service Operator",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	migrateV1Command := newMigrateV1Command()
+	cmd.AddCommand(migrateV1Command)
+
+	return cmd, nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+
+	"github.com/Azure/azure-service-operator/v2/cmd/asoctl/internal/migration"
+)
+
+func newMigrateV1Command() *cobra.Command {
+	var options migrateV1Options
+
+	cmd := &cobra.Command{
+		Use:   "v1",
+		Short: "Convert ASO v1 resources into equivalent ASO v2 resources",
+		Long: "Convert ASO v1 (azure.microsoft.com) resources into equivalent ASO v2 resources. " +
+			"Each v2 resource is annotated with reconcile-policy: skip so that it adopts the existing Azure resource " +
+			"without modifying it. Resources are read from the cluster, or from a file exported with kubectl.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrateV1(cmd.Context(), options)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&options.file,
+		"file",
+		"",
+		"Read ASO v1 resources from a YAML file instead of the cluster; secrets can't be copied")
+
+	cmd.Flags().StringVarP(
+		&options.namespace,
+		"namespace",
+		"n",
+		"",
+		"Only migrate resources in this namespace (default all namespaces)")
+
+	cmd.Flags().IntVar(
+		&options.secretNamingVersion,
+		"secret-naming-version",
+		int(migration.SecretNamingV2),
+		"Secret naming convention used by ASO v1 (AZURE_SECRET_NAMING_VERSION); 1 or 2")
+
+	cmd.Flags().StringVarP(
+		&options.outputPath,
+		"output",
+		"o",
+		"",
+		"Write ASO v2 resources to a file instead of stdout")
+
+	return cmd
+}
+
+type migrateV1Options struct {
+	file                string
+	namespace           string
+	secretNamingVersion int
+	outputPath          string
+}
+
+// migrateV1 converts ASO v1 resources and writes the YAML of the equivalent v2 resources to stdout or a file
+func migrateV1(ctx context.Context, options migrateV1Options) error {
+	log := CreateLogger()
+
+	naming := migration.SecretNamingVersion(options.secretNamingVersion)
+	if naming != migration.SecretNamingV1 && naming != migration.SecretNamingV2 {
+		return errors.Errorf("--secret-naming-version must be 1 or 2, not %d", options.secretNamingVersion)
+	}
+
+	var objs []*unstructured.Unstructured
+	var reader migration.SecretReader
+	if options.file != "" {
+		var err error
+		objs, err = migration.LoadV1ResourcesFromFile(options.file)
+		if err != nil {
+			return err
+		}
+	} else {
+		kubeClient, err := newKubeClient()
+		if err != nil {
+			return err
+		}
+
+		objs, err = migration.ListV1Resources(ctx, kubeClient, options.namespace)
+		if err != nil {
+			return err
+		}
+
+		reader = kubeClient
+	}
+
+	if len(objs) == 0 {
+		log.Info("No ASO v1 resources found")
+		return nil
+	}
+
+	migrator := migration.NewMigrator(api.CreateScheme(), naming, reader, log)
+	result, err := migrator.Migrate(ctx, objs)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range result.Issues() {
+		log.Info(
+			"Needs attention",
+			"kind", issue.Kind,
+			"namespace", issue.Namespace,
+			"name", issue.Name,
+			"issue", issue.Message)
+	}
+
+	log.Info(
+		"Migrated resources",
+		"v1", len(objs),
+		"v2", result.Count(),
+		"issues", len(result.Issues()))
+
+	if options.outputPath == "" {
+		return result.SaveToWriter(os.Stdout)
+	}
+
+	return result.SaveToSingleFile(options.outputPath)
+}
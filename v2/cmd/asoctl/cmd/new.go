@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+	"github.com/Azure/azure-service-operator/v2/internal/crdmanagement"
+
+	"github.com/Azure/azure-service-operator/v2/cmd/asoctl/internal/templates"
+)
+
+// newNewCommand creates a new cobra command for generating a starter manifest for a resource
+func newNewCommand() (*cobra.Command, error) {
+	var options newOptions
+
+	cmd := &cobra.Command{
+		Use:   "new <group>/<kind>",
+		Short: "Generate a starter manifest for an ASO resource",
+		Long: "Generate a commented YAML skeleton for an ASO resource, e.g. storage/StorageAccount. " +
+			"Required fields are included and optional fields are commented out. Required fields, allowed values and " +
+			"descriptions are taken from the CRD, read from --crd-file if given, or otherwise from the current cluster " +
+			"if available. If no version is specified, the latest stable version is used.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return newSkeleton(cmd.Context(), args[0], options)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&options.version,
+		"version",
+		"",
+		"API version of the resource, e.g. v1api20220901")
+
+	cmd.Flags().StringVar(
+		&options.crdFile,
+		"crd-file",
+		"",
+		"YAML file of ASO CRDs to use instead of the CRDs installed in the cluster")
+
+	cmd.Flags().StringVarP(
+		&options.outputPath,
+		"output",
+		"o",
+		"",
+		"Write the manifest to a file instead of stdout")
+
+	return cmd, nil
+}
+
+type newOptions struct {
+	version    string
+	crdFile    string
+	outputPath string
+}
+
+// newSkeleton writes a skeleton manifest for the specified resource to stdout or a file
+func newSkeleton(ctx context.Context, resource string, options newOptions) error {
+	log := CreateLogger()
+	scheme := api.CreateScheme()
+
+	gvk, err := templates.FindGroupVersionKind(scheme, resource, options.version)
+	if err != nil {
+		return err
+	}
+
+	crd, err := findCRD(ctx, gvk.GroupKind(), options.crdFile, log)
+	if err != nil {
+		return err
+	}
+
+	if crd == nil {
+		log.Info(
+			"No CRD available; required fields and allowed values won't be shown",
+			"group", gvk.Group,
+			"kind", gvk.Kind)
+	}
+
+	var out io.Writer = os.Stdout
+	if options.outputPath != "" {
+		file, err := os.Create(options.outputPath)
+		if err != nil {
+			return errors.Wrapf(err, "unable to create file %s", options.outputPath)
+		}
+
+		defer file.Close()
+		out = file
+	}
+
+	return templates.WriteSkeleton(out, scheme, gvk, crd)
+}
+
+// findCRD returns the CRD for the specified kind, from a file if given, otherwise from the cluster.
+// Returns nil if no CRD is available from the cluster, as the skeleton can still be generated without it.
+func findCRD(
+	ctx context.Context,
+	gk schema.GroupKind,
+	crdFile string,
+	log logr.Logger,
+) (*apiextensions.CustomResourceDefinition, error) {
+	if crdFile != "" {
+		crds, err := templates.LoadCRDsFromFile(crdFile)
+		if err != nil {
+			return nil, err
+		}
+
+		crd := templates.FindCRD(crds, gk)
+		if crd == nil {
+			return nil, errors.Errorf("no CRD for %s found in %s", gk, crdFile)
+		}
+
+		return crd, nil
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.V(1).Info("Unable to get Kubernetes config", "error", err.Error())
+		return nil, nil
+	}
+
+	apiExtClient, err := v1.NewForConfig(cfg)
+	if err != nil {
+		log.V(1).Info("Unable to create Kubernetes client", "error", err.Error())
+		return nil, nil
+	}
+
+	crd, err := templates.FindCRDInCluster(
+		ctx,
+		apiExtClient,
+		gk,
+		crdmanagement.ServiceOperatorAppLabel+"="+crdmanagement.ServiceOperatorAppValue)
+	if err != nil {
+		log.V(1).Info("Unable to read CRD from cluster", "error", err.Error())
+		return nil, nil
+	}
+
+	return crd, nil
+}
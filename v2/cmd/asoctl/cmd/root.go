@@ -7,6 +7,7 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	"github.com/Azure/azure-service-operator/v2/internal/version"
 	"github.com/Azure/azure-service-operator/v2/pkg/xcontext"
@@ -27,7 +28,7 @@ func Execute() {
 	if err := cmd.ExecuteContext(ctx); err != nil {
 		log := CreateLogger()
 		log.Error(err, "failed to execute command")
-		return
+		os.Exit(1)
 	}
 }
 
@@ -42,12 +43,17 @@ func newRootCommand() (*cobra.Command, error) {
 
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Silence most logging")
+	addAzureFlags(rootCmd.PersistentFlags())
 
 	rootCmd.Flags().SortFlags = false
 
 	cmds := []func() (*cobra.Command, error){
 		newCleanCommand,
 		newImportCommand,
+		newMigrateCommand,
+		newNewCommand,
+		newTreeCommand,
+		newValidateCommand,
 		version.NewCommand,
 	}
 
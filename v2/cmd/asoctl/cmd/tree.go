@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+
+	"github.com/Azure/azure-service-operator/v2/cmd/asoctl/internal/tree"
+)
+
+// newTreeCommand creates a new cobra command for showing the ownership tree of a resource
+func newTreeCommand() (*cobra.Command, error) {
+	var options treeOptions
+
+	cmd := &cobra.Command{
+		Use:     "tree <kind>/<name>",
+		Aliases: []string{"status"},
+		Short:   "Show the ownership tree and readiness of an ASO resource",
+		Long: "Show the owners of an ASO resource, the resource itself, and all the resources it owns, " +
+			"along with the Ready condition of each. The kind may be qualified with a group, e.g. ResourceGroup.resources.azure.com.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showTree(cmd.Context(), args[0], options)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&options.namespace,
+		"namespace",
+		"n",
+		"default",
+		"Namespace of the resource")
+
+	cmd.Flags().BoolVar(
+		&options.azure,
+		"azure",
+		false,
+		"Also check whether each resource exists in Azure")
+
+	return cmd, nil
+}
+
+type treeOptions struct {
+	namespace string
+	azure     bool
+}
+
+// showTree builds and prints the ownership tree of the specified resource
+func showTree(ctx context.Context, resource string, options treeOptions) error {
+	log := CreateLogger()
+
+	kind, name, ok := strings.Cut(resource, "/")
+	if !ok || kind == "" || name == "" {
+		return errors.Errorf("resource %q must be specified as <kind>/<name>", resource)
+	}
+
+	kubeClient, err := newKubeClient()
+	if err != nil {
+		return err
+	}
+
+	var armClient *genericarmclient.GenericClient
+	if options.azure {
+		armClient, err = newARMClient(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	builder, err := tree.NewBuilder(kubeClient, armClient, log)
+	if err != nil {
+		return err
+	}
+
+	gvk, err := builder.FindGroupVersionKind(kind)
+	if err != nil {
+		return err
+	}
+
+	root, err := builder.Build(ctx, gvk, types.NamespacedName{Namespace: options.namespace, Name: name})
+	if err != nil {
+		return err
+	}
+
+	return tree.Print(os.Stdout, root, time.Now())
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+
+	"github.com/Azure/azure-service-operator/v2/cmd/asoctl/internal/validate"
+)
+
+// newValidateCommand creates a new cobra command for validating ASO manifests offline
+func newValidateCommand() (*cobra.Command, error) {
+	var options validateOptions
+
+	cmd := &cobra.Command{
+		Use:   "validate <file or folder>...",
+		Short: "Validate ASO manifests without a cluster",
+		Long: "Validate ASO manifests without a cluster, running the same defaulting and validation as the ASO webhooks. " +
+			"Also checks that owners and references are defined in the manifests, that no two resources write to the same " +
+			"secret or config map key, and that no deprecated API versions are used. Folders are searched recursively for " +
+			"*.yaml and *.yml files. Exits with an error if any errors are found.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return validateManifests(args, options)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&options.format,
+		"format",
+		string(validate.FormatText),
+		"Output format: text, json or sarif")
+
+	cmd.Flags().StringVarP(
+		&options.outputPath,
+		"output",
+		"o",
+		"",
+		"Write findings to a file instead of stdout")
+
+	return cmd, nil
+}
+
+type validateOptions struct {
+	format     string
+	outputPath string
+}
+
+// validateManifests validates the manifests at the specified paths, writing findings to stdout or a file
+func validateManifests(paths []string, options validateOptions) error {
+	log := CreateLogger()
+
+	files, err := validate.FindManifests(paths)
+	if err != nil {
+		return err
+	}
+
+	validator := validate.NewValidator(api.CreateScheme())
+	for _, file := range files {
+		err = validator.AddFile(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	findings := validator.Validate()
+
+	var out io.Writer = os.Stdout
+	if options.outputPath != "" {
+		file, err := os.Create(options.outputPath)
+		if err != nil {
+			return errors.Wrapf(err, "unable to create file %s", options.outputPath)
+		}
+
+		defer file.Close()
+		out = file
+	}
+
+	err = validate.WriteFindings(out, findings, validate.Format(options.format))
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info(
+		"Validation finished",
+		"files", len(files),
+		"findings", len(findings))
+
+	if validate.HasErrors(findings) {
+		return errors.New("validation found errors")
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package azure
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	commonconfig "github.com/Azure/azure-service-operator/v2/pkg/common/config"
+)
+
+// CloudEnvironmentVariable is the environment variable used to select a named Azure cloud
+const CloudEnvironmentVariable = "ASOCTL_CLOUD"
+
+// CloudOptions captures the options used to select which Azure cloud asoctl talks to.
+// Either a named cloud can be selected, or a custom cloud configured by supplying the ARM endpoint,
+// ARM audience and/or AAD authority host (using the same semantics as the operator configuration).
+type CloudOptions struct {
+	// Name is the name of a well known Azure cloud (AzurePublic, AzureChina or AzureUSGovernment).
+	Name string
+
+	// ResourceManagerEndpoint is the Azure Resource Manager endpoint for a custom cloud.
+	ResourceManagerEndpoint string
+
+	// ResourceManagerAudience is the Azure Resource Manager AAD audience for a custom cloud.
+	ResourceManagerAudience string
+
+	// AzureAuthorityHost is the URL of the AAD authority for a custom cloud.
+	AzureAuthorityHost string
+}
+
+// namedClouds contains the well known Azure clouds, keyed by lowercase name.
+var namedClouds = map[string]cloud.Configuration{
+	"azurepublic":       cloud.AzurePublic,
+	"public":            cloud.AzurePublic,
+	"azurechina":        cloud.AzureChina,
+	"china":             cloud.AzureChina,
+	"azureusgovernment": cloud.AzureGovernment,
+	"usgovernment":      cloud.AzureGovernment,
+}
+
+// NewCloudOptionsFromEnvironment creates CloudOptions populated from the environment.
+// The variables used for a custom cloud are the same as those used to configure the operator itself.
+func NewCloudOptionsFromEnvironment() CloudOptions {
+	return CloudOptions{
+		Name:                    os.Getenv(CloudEnvironmentVariable),
+		ResourceManagerEndpoint: os.Getenv(commonconfig.ResourceManagerEndpoint),
+		ResourceManagerAudience: os.Getenv(commonconfig.ResourceManagerAudience),
+		AzureAuthorityHost:      os.Getenv(commonconfig.AzureAuthorityHost),
+	}
+}
+
+// Cloud returns the cloud configuration selected by these options.
+// If nothing has been specified, the Azure public cloud is returned.
+func (o CloudOptions) Cloud() (cloud.Configuration, error) {
+	hasCustomCloud := o.ResourceManagerEndpoint != "" ||
+		o.ResourceManagerAudience != "" ||
+		o.AzureAuthorityHost != ""
+
+	if o.Name != "" {
+		if hasCustomCloud {
+			return cloud.Configuration{}, errors.Errorf(
+				"cloud %q cannot be combined with a custom resource manager endpoint, audience or authority host",
+				o.Name)
+		}
+
+		result, ok := namedClouds[strings.ToLower(o.Name)]
+		if !ok {
+			return cloud.Configuration{}, errors.Errorf(
+				"unknown cloud %q, expected one of AzurePublic, AzureChina or AzureUSGovernment",
+				o.Name)
+		}
+
+		return result, nil
+	}
+
+	values := config.Values{
+		ResourceManagerEndpoint: o.ResourceManagerEndpoint,
+		ResourceManagerAudience: o.ResourceManagerAudience,
+		AzureAuthorityHost:      o.AzureAuthorityHost,
+	}
+
+	return values.Cloud(), nil
+}
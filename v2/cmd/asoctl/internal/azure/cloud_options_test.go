@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	. "github.com/onsi/gomega"
+)
+
+func TestCloudOptions_Cloud_ReturnsExpectedConfiguration(t *testing.T) {
+	t.Parallel()
+
+	customEndpoint := "https://management.example.com"
+
+	cases := []struct {
+		name     string
+		options  CloudOptions
+		expected cloud.Configuration
+	}{
+		{
+			name:     "Default is public cloud",
+			options:  CloudOptions{},
+			expected: cloud.AzurePublic,
+		},
+		{
+			name:     "Named China cloud",
+			options:  CloudOptions{Name: "AzureChina"},
+			expected: cloud.AzureChina,
+		},
+		{
+			name:     "Named government cloud is case insensitive",
+			options:  CloudOptions{Name: "azureusgovernment"},
+			expected: cloud.AzureGovernment,
+		},
+		{
+			name:    "Custom endpoint uses defaults for audience and authority",
+			options: CloudOptions{ResourceManagerEndpoint: customEndpoint},
+			expected: cloud.Configuration{
+				ActiveDirectoryAuthorityHost: "https://login.microsoftonline.com/",
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {
+						Endpoint: customEndpoint,
+						Audience: "https://management.core.windows.net/",
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			actual, err := c.options.Cloud()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestCloudOptions_Cloud_ReturnsErrorForInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options CloudOptions
+	}{
+		{
+			name:    "Unknown named cloud",
+			options: CloudOptions{Name: "AzureMoon"},
+		},
+		{
+			name: "Named cloud combined with custom endpoint",
+			options: CloudOptions{
+				Name:                    "AzureChina",
+				ResourceManagerEndpoint: "https://management.example.com",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := c.options.Cloud()
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func TestCredentialOptions_CredentialSource(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		source   string
+		expected CredentialSource
+		isError  bool
+	}{
+		{"", CredentialSourceDefault, false},
+		{"azure-cli", CredentialSourceAzureCLI, false},
+		{"Client-Secret", CredentialSourceClientSecret, false},
+		{"kubernetes-secret", CredentialSourceKubernetesSecret, false},
+		{"magic", "", true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.source, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			options := CredentialOptions{Source: c.source}
+			actual, err := options.CredentialSource()
+			if c.isError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(actual).To(Equal(c.expected))
+			}
+		})
+	}
+}
+
+func TestCredentialOptions_SecretName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		secret  string
+		isError bool
+	}{
+		{"ns/aso-credential", false},
+		{"aso-credential", true},
+		{"/aso-credential", true},
+		{"ns/", true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.secret, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			options := CredentialOptions{Secret: c.secret}
+			name, err := options.SecretName()
+			if c.isError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(name.String()).To(Equal(c.secret))
+			}
+		})
+	}
+}
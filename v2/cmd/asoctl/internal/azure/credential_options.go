@@ -0,0 +1,293 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package azure
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonconfig "github.com/Azure/azure-service-operator/v2/pkg/common/config"
+)
+
+// CredentialSource identifies how asoctl obtains the credential used to talk to Azure
+type CredentialSource string
+
+const (
+	// CredentialSourceDefault uses the azidentity default credential chain
+	CredentialSourceDefault CredentialSource = "default"
+	// CredentialSourceClientSecret uses a service principal with a client secret
+	CredentialSourceClientSecret CredentialSource = "client-secret"
+	// CredentialSourceClientCertificate uses a service principal with a client certificate
+	CredentialSourceClientCertificate CredentialSource = "client-certificate"
+	// CredentialSourceWorkloadIdentity uses a federated token read from a file
+	CredentialSourceWorkloadIdentity CredentialSource = "workload-identity"
+	// CredentialSourceAzureCLI uses the identity currently logged into the Azure CLI
+	CredentialSourceAzureCLI CredentialSource = "azure-cli"
+	// CredentialSourceKubernetesSecret uses an ASO credential secret read from a cluster
+	CredentialSourceKubernetesSecret CredentialSource = "kubernetes-secret"
+)
+
+// CredentialSources lists all the supported credential sources
+var CredentialSources = []CredentialSource{
+	CredentialSourceDefault,
+	CredentialSourceClientSecret,
+	CredentialSourceClientCertificate,
+	CredentialSourceWorkloadIdentity,
+	CredentialSourceAzureCLI,
+	CredentialSourceKubernetesSecret,
+}
+
+const (
+	// CredentialSourceEnvironmentVariable is the environment variable used to select the credential source
+	CredentialSourceEnvironmentVariable = "ASOCTL_CREDENTIAL"
+	// ClientCertificatePathEnvironmentVariable is the environment variable giving the path to a client certificate.
+	// This matches the variable used by the Azure SDK EnvironmentCredential.
+	ClientCertificatePathEnvironmentVariable = "AZURE_CLIENT_CERTIFICATE_PATH"
+	// FederatedTokenFileEnvironmentVariable is the environment variable giving the path to a federated token file.
+	// This matches the variable injected by Azure Workload Identity.
+	FederatedTokenFileEnvironmentVariable = "AZURE_FEDERATED_TOKEN_FILE"
+	// CredentialSecretEnvironmentVariable is the environment variable giving the namespace/name of a credential secret
+	CredentialSecretEnvironmentVariable = "ASOCTL_CREDENTIAL_SECRET"
+)
+
+// CredentialOptions captures the options used to select the credential asoctl uses to talk to Azure.
+// Secrets (client secret and certificate password) are only read from the environment, never from flags,
+// so that they don't end up in shell history.
+type CredentialOptions struct {
+	// Source selects how the credential is obtained
+	Source string
+
+	// TenantID is the AAD tenant of the identity
+	TenantID string
+
+	// ClientID is the client ID of the service principal or managed identity
+	ClientID string
+
+	// ClientSecret is the secret of the service principal, used with CredentialSourceClientSecret
+	ClientSecret string
+
+	// ClientCertificatePath is the path to a PEM or PKCS12 certificate, used with CredentialSourceClientCertificate
+	ClientCertificatePath string
+
+	// ClientCertificatePassword is the password protecting the certificate, if any
+	ClientCertificatePassword string
+
+	// FederatedTokenFile is the path to a federated token, used with CredentialSourceWorkloadIdentity
+	FederatedTokenFile string
+
+	// Secret is the namespace/name of an ASO credential secret, used with CredentialSourceKubernetesSecret
+	Secret string
+}
+
+// NewCredentialOptionsFromEnvironment creates CredentialOptions populated from the environment.
+func NewCredentialOptionsFromEnvironment() CredentialOptions {
+	return CredentialOptions{
+		Source:                    os.Getenv(CredentialSourceEnvironmentVariable),
+		TenantID:                  os.Getenv(commonconfig.AzureTenantID),
+		ClientID:                  os.Getenv(commonconfig.AzureClientID),
+		ClientSecret:              os.Getenv(commonconfig.AzureClientSecret),
+		ClientCertificatePath:     os.Getenv(ClientCertificatePathEnvironmentVariable),
+		ClientCertificatePassword: os.Getenv(commonconfig.AzureClientCertificatePassword),
+		FederatedTokenFile:        os.Getenv(FederatedTokenFileEnvironmentVariable),
+		Secret:                    os.Getenv(CredentialSecretEnvironmentVariable),
+	}
+}
+
+// CredentialSource returns the selected credential source, defaulting to CredentialSourceDefault
+func (o CredentialOptions) CredentialSource() (CredentialSource, error) {
+	if o.Source == "" {
+		return CredentialSourceDefault, nil
+	}
+
+	for _, source := range CredentialSources {
+		if strings.EqualFold(o.Source, string(source)) {
+			return source, nil
+		}
+	}
+
+	return "", errors.Errorf("unknown credential source %q", o.Source)
+}
+
+// SecretName returns the namespaced name of the credential secret to read from the cluster.
+func (o CredentialOptions) SecretName() (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(o.Secret, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, errors.Errorf("credential secret %q must be specified as <namespace>/<name>", o.Secret)
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// Credential creates the token credential selected by these options for the specified cloud.
+// kubeClient is only used (and required) when the credential is read from a Kubernetes secret.
+func (o CredentialOptions) Credential(
+	ctx context.Context,
+	cfg cloud.Configuration,
+	kubeClient func() (client.Client, error),
+) (azcore.TokenCredential, error) {
+	source, err := o.CredentialSource()
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := azcore.ClientOptions{
+		Cloud: cfg,
+	}
+
+	switch source {
+	case CredentialSourceDefault:
+		return azidentity.NewDefaultAzureCredential(
+			&azidentity.DefaultAzureCredentialOptions{
+				ClientOptions: clientOptions,
+				TenantID:      o.TenantID,
+			})
+
+	case CredentialSourceClientSecret:
+		return newClientSecretCredential(o.TenantID, o.ClientID, o.ClientSecret, clientOptions)
+
+	case CredentialSourceClientCertificate:
+		if o.ClientCertificatePath == "" {
+			return nil, errors.Errorf("client certificate path must be specified to use a %s credential", source)
+		}
+
+		certificate, err := os.ReadFile(o.ClientCertificatePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading client certificate from %s", o.ClientCertificatePath)
+		}
+
+		return newClientCertificateCredential(
+			o.TenantID,
+			o.ClientID,
+			certificate,
+			[]byte(o.ClientCertificatePassword),
+			clientOptions)
+
+	case CredentialSourceWorkloadIdentity:
+		if o.FederatedTokenFile == "" {
+			return nil, errors.Errorf("federated token file must be specified to use a %s credential", source)
+		}
+
+		return azidentity.NewWorkloadIdentityCredential(
+			&azidentity.WorkloadIdentityCredentialOptions{
+				ClientOptions: clientOptions,
+				ClientID:      o.ClientID,
+				TenantID:      o.TenantID,
+				TokenFilePath: o.FederatedTokenFile,
+			})
+
+	case CredentialSourceAzureCLI:
+		return azidentity.NewAzureCLICredential(
+			&azidentity.AzureCLICredentialOptions{
+				TenantID: o.TenantID,
+			})
+
+	case CredentialSourceKubernetesSecret:
+		return o.credentialFromKubernetesSecret(ctx, clientOptions, kubeClient)
+	}
+
+	return nil, errors.Errorf("unsupported credential source %q", source)
+}
+
+// credentialFromKubernetesSecret creates a credential from an ASO credential secret (such as aso-credential)
+// read from the cluster. Only service principal secrets can be used from outside the cluster; secrets using
+// workload identity or pod identity depend on the operator's pod environment.
+func (o CredentialOptions) credentialFromKubernetesSecret(
+	ctx context.Context,
+	clientOptions azcore.ClientOptions,
+	kubeClient func() (client.Client, error),
+) (azcore.TokenCredential, error) {
+	secretName, err := o.SecretName()
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := kubeClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create Kubernetes client")
+	}
+
+	var secret v1.Secret
+	err = cl.Get(ctx, secretName, &secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading credential secret %s", secretName)
+	}
+
+	tenantID := string(secret.Data[commonconfig.AzureTenantID])
+	clientID := string(secret.Data[commonconfig.AzureClientID])
+
+	if clientSecret, ok := secret.Data[commonconfig.AzureClientSecret]; ok {
+		return newClientSecretCredential(tenantID, clientID, string(clientSecret), clientOptions)
+	}
+
+	if clientCert, ok := secret.Data[commonconfig.AzureClientCertificate]; ok {
+		password := secret.Data[commonconfig.AzureClientCertificatePassword]
+		return newClientCertificateCredential(tenantID, clientID, clientCert, password, clientOptions)
+	}
+
+	return nil, errors.Errorf(
+		"credential secret %s contains neither %s nor %s; workload identity and pod identity secrets can only be used by the operator",
+		secretName,
+		commonconfig.AzureClientSecret,
+		commonconfig.AzureClientCertificate)
+}
+
+func newClientSecretCredential(
+	tenantID string,
+	clientID string,
+	clientSecret string,
+	clientOptions azcore.ClientOptions,
+) (azcore.TokenCredential, error) {
+	if tenantID == "" || clientID == "" || clientSecret == "" {
+		return nil, errors.Errorf(
+			"tenant ID, client ID and client secret must all be specified to use a %s credential",
+			CredentialSourceClientSecret)
+	}
+
+	return azidentity.NewClientSecretCredential(
+		tenantID,
+		clientID,
+		clientSecret,
+		&azidentity.ClientSecretCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+}
+
+func newClientCertificateCredential(
+	tenantID string,
+	clientID string,
+	certificate []byte,
+	password []byte,
+	clientOptions azcore.ClientOptions,
+) (azcore.TokenCredential, error) {
+	if tenantID == "" || clientID == "" {
+		return nil, errors.Errorf(
+			"tenant ID and client ID must both be specified to use a %s credential",
+			CredentialSourceClientCertificate)
+	}
+
+	certs, key, err := azidentity.ParseCertificates(certificate, password)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse certificate for %q", clientID)
+	}
+
+	return azidentity.NewClientCertificateCredential(
+		tenantID,
+		clientID,
+		certs,
+		key,
+		&azidentity.ClientCertificateCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+}
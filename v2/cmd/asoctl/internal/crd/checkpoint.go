@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package crd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// checkpoint records the progress of a storage version migration so that an interrupted run can be resumed.
+// A nil checkpoint records nothing.
+type checkpoint struct {
+	path string
+	// CRDs contains progress for each CRD, keyed by CRD name
+	CRDs map[string]*crdCheckpoint `json:"crds"`
+}
+
+// crdCheckpoint records the progress of migrating a single CRD
+type crdCheckpoint struct {
+	// Versions is the set of stored versions being dropped; progress is discarded if this changes
+	Versions []string `json:"versions"`
+	// Migrated contains the namespaced names of objects already rewritten
+	Migrated []string `json:"migrated,omitempty"`
+	// Completed is true once status.storedVersions has been patched
+	Completed bool `json:"completed,omitempty"`
+
+	migrated map[string]bool
+}
+
+// loadCheckpoint reads the checkpoint at path, returning an empty checkpoint if the file doesn't exist yet.
+// If path is empty, nil is returned and progress isn't recorded.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	result := &checkpoint{
+		path: path,
+		CRDs: make(map[string]*crdCheckpoint),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return result, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading checkpoint %s", path)
+	}
+
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing checkpoint %s", path)
+	}
+
+	for _, crd := range result.CRDs {
+		crd.migrated = make(map[string]bool, len(crd.Migrated))
+		for _, name := range crd.Migrated {
+			crd.migrated[name] = true
+		}
+	}
+
+	return result, nil
+}
+
+// forCRD returns the progress for the named CRD, starting afresh if the versions being dropped have changed.
+// Progress is always tracked in memory, even if it isn't being saved.
+func (c *checkpoint) forCRD(name string, versions []string) *crdCheckpoint {
+	result := &crdCheckpoint{
+		Versions: versions,
+		migrated: make(map[string]bool),
+	}
+
+	if c == nil {
+		return result
+	}
+
+	if existing, ok := c.CRDs[name]; ok && equalVersions(existing.Versions, versions) {
+		return existing
+	}
+
+	c.CRDs[name] = result
+	return result
+}
+
+// save writes the checkpoint to disk, replacing the file atomically so an interruption can't corrupt it
+func (c *checkpoint) save() error {
+	if c == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "serializing checkpoint")
+	}
+
+	temp := filepath.Join(filepath.Dir(c.path), "."+filepath.Base(c.path)+".tmp")
+	err = os.WriteFile(temp, data, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "writing checkpoint %s", temp)
+	}
+
+	err = os.Rename(temp, c.path)
+	if err != nil {
+		return errors.Wrapf(err, "replacing checkpoint %s", c.path)
+	}
+
+	return nil
+}
+
+// isMigrated returns true if the object has already been rewritten
+func (c *crdCheckpoint) isMigrated(name types.NamespacedName) bool {
+	return c.migrated[name.String()]
+}
+
+// markMigrated records that the object has been rewritten
+func (c *crdCheckpoint) markMigrated(name types.NamespacedName) {
+	if c.migrated[name.String()] {
+		return
+	}
+
+	c.migrated[name.String()] = true
+	c.Migrated = append(c.Migrated, name.String())
+}
+
+// isCompleted returns true if the CRD has been fully cleaned
+func (c *crdCheckpoint) isCompleted() bool {
+	return c.Completed
+}
+
+// markCompleted records that the CRD has been fully cleaned; the list of migrated objects is no longer needed
+func (c *crdCheckpoint) markCompleted() {
+	c.Completed = true
+	c.Migrated = nil
+	c.migrated = make(map[string]bool)
+}
+
+func equalVersions(left []string, right []string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+
+	return true
+}
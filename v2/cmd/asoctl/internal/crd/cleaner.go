@@ -8,6 +8,7 @@ package crd
 import (
 	"context"
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
 	"time"
@@ -22,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,19 +32,55 @@ import (
 	"github.com/Azure/azure-service-operator/v2/internal/crdmanagement"
 )
 
+// checkpointInterval is the number of objects migrated between saves of the checkpoint
+const checkpointInterval = 50
+
+// deprecatedVersionRegexp matches the stored versions removed when no versions are specified
+var deprecatedVersionRegexp = regexp.MustCompile(`((v1alpha1api|v1beta)\d{8}(preview)?(storage)?|v1beta1)`) // handcrafted (non-ARM) resources have v1beta1 version
+
 type Cleaner struct {
 	apiExtensionsClient apiextensionsclient.CustomResourceDefinitionInterface
 	client              client.Client
 	migrationBackoff    wait.Backoff
-	dryRun              bool
+	options             CleanerOptions
 	log                 logr.Logger
 }
 
+// CleanerOptions configures which stored versions a Cleaner removes
+type CleanerOptions struct {
+	// DryRun reports what would be done without modifying anything
+	DryRun bool
+
+	// Selectors restricts cleaning to CRDs whose group or name matches one of these glob patterns,
+	// e.g. "storage.azure.com" or "*.network.azure.com". If empty, all ASO CRDs are cleaned.
+	Selectors []string
+
+	// Versions lists the stored versions to remove. A version also matches its storage variant, so "v1api20200601"
+	// removes "v1api20200601storage". If empty, deprecated beta versions are removed.
+	Versions []string
+
+	// CheckpointPath, if set, is a file used to record progress so that an interrupted run can be resumed
+	CheckpointPath string
+}
+
 func NewCleaner(
 	apiExtensionsClient apiextensionsclient.CustomResourceDefinitionInterface,
 	client client.Client,
 	dryRun bool,
 	log logr.Logger) *Cleaner {
+	return NewCleanerWithOptions(
+		apiExtensionsClient,
+		client,
+		CleanerOptions{DryRun: dryRun},
+		log)
+}
+
+// NewCleanerWithOptions creates a Cleaner that removes the stored versions selected by options
+func NewCleanerWithOptions(
+	apiExtensionsClient apiextensionsclient.CustomResourceDefinitionInterface,
+	client client.Client,
+	options CleanerOptions,
+	log logr.Logger) *Cleaner {
 	migrationBackoff := wait.Backoff{
 		Duration: 2 * time.Second, // wait 2s between attempts, this will help us in a state of conflict.
 		Steps:    3,               // 3 retry on error attempts per object
@@ -52,63 +91,70 @@ func NewCleaner(
 		apiExtensionsClient: apiExtensionsClient,
 		client:              client,
 		migrationBackoff:    migrationBackoff,
-		dryRun:              dryRun,
+		options:             options,
 		log:                 log,
 	}
 }
 
 func (c *Cleaner) Run(ctx context.Context) error {
-	if c.dryRun {
+	if c.options.DryRun {
 		c.log.Info("Starting update (dry run)")
 	} else {
 		c.log.Info("Starting update")
 	}
 
-	appLabelRequirement, err := labels.NewRequirement(crdmanagement.ServiceOperatorAppLabel, selection.Equals, []string{crdmanagement.ServiceOperatorAppValue})
+	crds, err := c.listCRDs(ctx)
 	if err != nil {
 		return err
 	}
-	selector := labels.NewSelector()
-	selector = selector.Add(*appLabelRequirement)
-	crdsWithNewLabel, err := c.apiExtensionsClient.List(ctx, v1.ListOptions{LabelSelector: selector.String()})
-	if err != nil {
-		return errors.Wrap(err, "failed to list CRDs")
-	}
 
-	versionLabelRequirement, err := labels.NewRequirement(crdmanagement.ServiceOperatorVersionLabelOld, selection.Exists, []string{})
+	progress, err := loadCheckpoint(c.options.CheckpointPath)
 	if err != nil {
 		return err
 	}
-	selector = labels.NewSelector()
-	selector = selector.Add(*versionLabelRequirement)
-	crdsWithOldLabel, err := c.apiExtensionsClient.List(ctx, v1.ListOptions{LabelSelector: selector.String()})
-	if err != nil {
-		return errors.Wrap(err, "failed to list CRDs")
-	}
 
-	var crds []apiextensions.CustomResourceDefinition
-	crds = append(crds, crdsWithNewLabel.Items...)
-	crds = append(crds, crdsWithOldLabel.Items...)
+	if c.options.DryRun {
+		// Never record progress for a dry run
+		progress = nil
+	}
 
 	var updated int
 	var asoCRDsSeen int
-	deprecatedVersionRegexp := regexp.MustCompile(`((v1alpha1api|v1beta)\d{8}(preview)?(storage)?|v1beta1)`) // handcrafted (non-ARM) resources have v1beta1 version
 
 	for _, crd := range crds {
 		crd := crd
 
+		if !c.isSelected(crd) {
+			continue
+		}
+
 		asoCRDsSeen++
-		newStoredVersions, deprecatedVersion := removeMatchingStoredVersions(crd.Status.StoredVersions, deprecatedVersionRegexp)
+		newStoredVersions, removedVersions := c.removeStoredVersions(crd.Status.StoredVersions)
+
+		// If the slice was not updated, there is no version to deprecate.
+		if len(removedVersions) == 0 {
+			c.log.Info(
+				"Nothing to update",
+				"crd-name", crd.Name)
+			continue
+		}
 
 		// If there is no new version found other than the matched version, we short circuit here, as there is no updated version found in the CRDs
 		if len(newStoredVersions) <= 0 {
-			return errors.New(fmt.Sprintf("it doesn't look like your version of ASO is one that supports deprecating version %q. Have you upgraded ASO yet?", deprecatedVersion))
+			return errors.New(fmt.Sprintf("it doesn't look like your version of ASO is one that supports deprecating version %q. Have you upgraded ASO yet?", removedVersions[len(removedVersions)-1]))
 		}
 
-		// If the slice was not updated, there is no version to deprecate.
-		if len(newStoredVersions) == len(crd.Status.StoredVersions) {
+		if len(c.options.Versions) > 0 {
+			// Rewriting objects is only useful if they're rewritten to a version we're keeping
+			if storage := storageVersion(crd); storage != "" && containsString(removedVersions, storage) {
+				return errors.Errorf("cannot remove version %q from %s as it is still the storage version", storage, crd.Name)
+			}
+		}
+
+		crdProgress := progress.forCRD(crd.Name, removedVersions)
+		if crdProgress.isCompleted() {
 			c.log.Info(
-				"Nothing to update",
+				"Already cleaned, skipping",
 				"crd-name", crd.Name)
 			continue
 		}
@@ -118,19 +164,33 @@ func (c *Cleaner) Run(ctx context.Context) error {
 		activeVersion := newStoredVersions[len(newStoredVersions)-1]
 		c.log.Info(
 			"Starting cleanup",
-			"crd-name", crd.Name)
+			"crd-name", crd.Name,
+			"removing", removedVersions)
 
 		objectsToMigrate, err := c.getObjectsForMigration(ctx, crd, activeVersion)
 		if err != nil {
 			return err
 		}
 
-		err = c.migrateObjects(ctx, objectsToMigrate)
+		err = c.migrateObjects(ctx, objectsToMigrate, removedVersions, activeVersion, progress, crdProgress)
 		if err != nil {
 			return err
 		}
 
-		err = c.updateStorageVersions(ctx, crd, newStoredVersions)
+		current, err := c.verifyMigrated(ctx, crd, activeVersion, objectsToMigrate, crdProgress)
+		if err != nil {
+			return err
+		}
+
+		// Stored versions may have been added while we were migrating, so work from the latest
+		newStoredVersions, _ = c.removeStoredVersions(current.Status.StoredVersions)
+		err = c.updateStorageVersions(ctx, *current, newStoredVersions)
+		if err != nil {
+			return err
+		}
+
+		crdProgress.markCompleted()
+		err = progress.save()
 		if err != nil {
 			return err
 		}
@@ -139,10 +199,14 @@ func (c *Cleaner) Run(ctx context.Context) error {
 	}
 
 	if asoCRDsSeen <= 0 {
+		if len(c.options.Selectors) > 0 {
+			return errors.Errorf("found no Azure Service Operator CRDs matching %s", strings.Join(c.options.Selectors, ", "))
+		}
+
 		return errors.New("found no Azure Service Operator CRDs, make sure you have ASO installed.")
 	}
 
-	if c.dryRun {
+	if c.options.DryRun {
 		c.log.Info("Update finished (dry run)")
 	} else {
 		c.log.Info(
@@ -153,12 +217,92 @@ func (c *Cleaner) Run(ctx context.Context) error {
 	return nil
 }
 
+// listCRDs returns all the ASO CRDs in the cluster, whether labelled by current or older versions of ASO
+func (c *Cleaner) listCRDs(ctx context.Context) ([]apiextensions.CustomResourceDefinition, error) {
+	appLabelRequirement, err := labels.NewRequirement(crdmanagement.ServiceOperatorAppLabel, selection.Equals, []string{crdmanagement.ServiceOperatorAppValue})
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.NewSelector()
+	selector = selector.Add(*appLabelRequirement)
+	crdsWithNewLabel, err := c.apiExtensionsClient.List(ctx, v1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list CRDs")
+	}
+
+	versionLabelRequirement, err := labels.NewRequirement(crdmanagement.ServiceOperatorVersionLabelOld, selection.Exists, []string{})
+	if err != nil {
+		return nil, err
+	}
+	selector = labels.NewSelector()
+	selector = selector.Add(*versionLabelRequirement)
+	crdsWithOldLabel, err := c.apiExtensionsClient.List(ctx, v1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list CRDs")
+	}
+
+	var crds []apiextensions.CustomResourceDefinition
+	crds = append(crds, crdsWithNewLabel.Items...)
+	crds = append(crds, crdsWithOldLabel.Items...)
+
+	return crds, nil
+}
+
+// isSelected returns true if the CRD matches the selectors, or if there are no selectors
+func (c *Cleaner) isSelected(crd apiextensions.CustomResourceDefinition) bool {
+	if len(c.options.Selectors) == 0 {
+		return true
+	}
+
+	for _, selector := range c.options.Selectors {
+		selector = strings.ToLower(selector)
+		for _, candidate := range []string{crd.Spec.Group, crd.Name} {
+			if matched, err := path.Match(selector, strings.ToLower(candidate)); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// removeStoredVersions returns the stored versions to keep and those to remove
+func (c *Cleaner) removeStoredVersions(storedVersions []string) ([]string, []string) {
+	if len(c.options.Versions) == 0 {
+		return removeMatchingStoredVersions(storedVersions, deprecatedVersionRegexp)
+	}
+
+	keep := make([]string, 0, len(storedVersions))
+	var remove []string
+	for _, version := range storedVersions {
+		if c.isVersionToRemove(version) {
+			remove = append(remove, version)
+			continue
+		}
+
+		keep = append(keep, version)
+	}
+
+	return keep, remove
+}
+
+// isVersionToRemove returns true if the version, or the API version it's the storage variant of, was requested
+func (c *Cleaner) isVersionToRemove(version string) bool {
+	for _, v := range c.options.Versions {
+		if version == v || version == v+"storage" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c *Cleaner) updateStorageVersions(
 	ctx context.Context,
 	crd apiextensions.CustomResourceDefinition,
 	newStoredVersions []string) error {
 
-	if c.dryRun {
+	if c.options.DryRun {
 		c.log.Info(
 			"Would update storedVersions",
 			"crd-name", crd.Name,
@@ -179,10 +323,24 @@ func (c *Cleaner) updateStorageVersions(
 	return nil
 }
 
-func (c *Cleaner) migrateObjects(ctx context.Context, objectsToMigrate *unstructured.UnstructuredList) error {
-	for _, obj := range objectsToMigrate.Items {
+func (c *Cleaner) migrateObjects(
+	ctx context.Context,
+	objectsToMigrate *unstructured.UnstructuredList,
+	removedVersions []string,
+	activeVersion string,
+	progress *checkpoint,
+	crdProgress *crdCheckpoint,
+) error {
+	var skipped int
+	for i, obj := range objectsToMigrate.Items {
 		obj := obj
-		if c.dryRun {
+		name := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		if crdProgress.isMigrated(name) {
+			skipped++
+			continue
+		}
+
+		if c.options.DryRun {
 			c.log.Info(
 				"Would migrate resource",
 				"name", obj.GetName(),
@@ -190,33 +348,39 @@ func (c *Cleaner) migrateObjects(ctx context.Context, objectsToMigrate *unstruct
 			continue
 		}
 
-		originalVersionFieldPath := []string{"spec", "originalVersion"}
-
-		originalVersion, found, err := unstructured.NestedString(obj.Object, originalVersionFieldPath...)
+		err := c.updateOriginalVersion(&obj, removedVersions, activeVersion)
 		if err != nil {
 			return errors.Wrap(err,
 				fmt.Sprintf("migrating %q of kind %s", obj.GetName(), obj.GroupVersionKind().Kind))
 		}
 
-		if found {
-			originalVersion = strings.Replace(originalVersion, "v1alpha1api", "v1beta", 1)
-			err = unstructured.SetNestedField(obj.Object, originalVersion, originalVersionFieldPath...)
-			if err != nil {
-				return errors.Wrap(err,
-					fmt.Sprintf("migrating %q of kind %s", obj.GetName(), obj.GroupVersionKind().Kind))
+		err = retry.OnError(c.migrationBackoff, isErrorFatal, func() error { return c.client.Update(ctx, &obj) })
+		if isErrorFatal(err) {
+			// Keep the progress we've made so far
+			if saveErr := progress.save(); saveErr != nil {
+				return kerrors.NewAggregate([]error{err, saveErr})
 			}
-		} else {
-			// If we don't find the originalVersion, it may not have been set.
-			// This can happen for some resources such as ResourceGroup which were handcrafted in versions prior to v2.0.0 and thus didn't have a StorageVersion.
+
+			return err
+		}
+
+		if err != nil {
+			// The object has been deleted, or changed by someone else since we listed it. Either way we didn't
+			// rewrite it, so we leave it for verifyMigrated() to check.
 			c.log.Info(
-				"originalVersion not found. Continuing with the latest.",
+				"Resource changed during migration",
 				"name", obj.GetName(),
-				"kind", obj.GroupVersionKind().Kind)
+				"kind", obj.GroupVersionKind().Kind,
+				"reason", apierrors.ReasonForError(err))
+			continue
 		}
 
-		err = retry.OnError(c.migrationBackoff, isErrorFatal, func() error { return c.client.Update(ctx, &obj) })
-		if isErrorFatal(err) {
-			return err
+		crdProgress.markMigrated(name)
+		if (i+1)%checkpointInterval == 0 {
+			err = progress.save()
+			if err != nil {
+				return err
+			}
 		}
 
 		c.log.Info(
@@ -227,9 +391,103 @@ func (c *Cleaner) migrateObjects(ctx context.Context, objectsToMigrate *unstruct
 
 	c.log.Info(
 		"Migration finished",
-		"resource-count", len(objectsToMigrate.Items))
+		"resource-count", len(objectsToMigrate.Items),
+		"already-migrated", skipped)
 
-	return nil
+	return progress.save()
+}
+
+// updateOriginalVersion ensures spec.originalVersion doesn't refer to a version that's being removed
+func (c *Cleaner) updateOriginalVersion(obj *unstructured.Unstructured, removedVersions []string, activeVersion string) error {
+	originalVersionFieldPath := []string{"spec", "originalVersion"}
+
+	originalVersion, found, err := unstructured.NestedString(obj.Object, originalVersionFieldPath...)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		// If we don't find the originalVersion, it may not have been set.
+		// This can happen for some resources such as ResourceGroup which were handcrafted in versions prior to v2.0.0 and thus didn't have a StorageVersion.
+		c.log.Info(
+			"originalVersion not found. Continuing with the latest.",
+			"name", obj.GetName(),
+			"kind", obj.GroupVersionKind().Kind)
+		return nil
+	}
+
+	if len(c.options.Versions) == 0 {
+		originalVersion = strings.Replace(originalVersion, "v1alpha1api", "v1beta", 1)
+	} else if c.isVersionToRemove(originalVersion) {
+		// The original API version is going away, so use the API version of the storage version we're migrating to
+		originalVersion = strings.TrimSuffix(activeVersion, "storage")
+	}
+
+	return unstructured.SetNestedField(obj.Object, originalVersion, originalVersionFieldPath...)
+}
+
+// verifyMigrated checks that every object that existed before migration has been rewritten, returning the CRD as it
+// is now.
+// We re-read the CRD to check the storage version hasn't changed while we were migrating objects (e.g. because ASO
+// was upgraded), as objects we rewrote would then be stored in a version that's no longer current.
+// Objects can't be queried for the version they're stored in, but any object written since we listed them will have
+// been stored in the current storage version, so an object is only still in an old version if it's unchanged and
+// we didn't rewrite it.
+func (c *Cleaner) verifyMigrated(
+	ctx context.Context,
+	crd apiextensions.CustomResourceDefinition,
+	activeVersion string,
+	before *unstructured.UnstructuredList,
+	crdProgress *crdCheckpoint,
+) (*apiextensions.CustomResourceDefinition, error) {
+	if c.options.DryRun {
+		return &crd, nil
+	}
+
+	current, err := c.apiExtensionsClient.Get(ctx, crd.Name, v1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "re-reading CRD %s", crd.Name)
+	}
+
+	if original, latest := storageVersion(crd), storageVersion(*current); original != latest {
+		return nil, errors.Errorf(
+			"storage version of %s changed from %q to %q while migrating objects; run again to migrate them to the new storage version",
+			crd.Name,
+			original,
+			latest)
+	}
+
+	after, err := c.getObjectsForMigration(ctx, *current, activeVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	originalResourceVersions := make(map[types.UID]string, len(before.Items))
+	for _, obj := range before.Items {
+		originalResourceVersions[obj.GetUID()] = obj.GetResourceVersion()
+	}
+
+	var remaining []string
+	for _, obj := range after.Items {
+		name := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		if crdProgress.isMigrated(name) {
+			continue
+		}
+
+		if rv, ok := originalResourceVersions[obj.GetUID()]; ok && rv == obj.GetResourceVersion() {
+			remaining = append(remaining, name.String())
+		}
+	}
+
+	if len(remaining) > 0 {
+		return nil, errors.Errorf(
+			"%d objects of %s may still be stored in a removed version: %s",
+			len(remaining),
+			crd.Name,
+			strings.Join(remaining, ", "))
+	}
+
+	return current, nil
 }
 
 func isErrorFatal(err error) bool {
@@ -265,18 +523,40 @@ func (c *Cleaner) getObjectsForMigration(ctx context.Context, crd apiextensions.
 	return list, nil
 }
 
-// removeMatchingStoredVersions returns a new list of storedVersions by removing the non-storage matched version
-func removeMatchingStoredVersions(oldVersions []string, versionRegexp *regexp.Regexp) ([]string, string) {
+// removeMatchingStoredVersions returns a new list of storedVersions by removing the matched versions, along with the
+// versions removed
+func removeMatchingStoredVersions(oldVersions []string, versionRegexp *regexp.Regexp) ([]string, []string) {
 	newStoredVersions := make([]string, 0, len(oldVersions))
-	var matchedStoredVersion string
+	var matchedStoredVersions []string
 	for _, version := range oldVersions {
 		if versionRegexp.MatchString(version) {
-			matchedStoredVersion = version
+			matchedStoredVersions = append(matchedStoredVersions, version)
 			continue
 		}
 
 		newStoredVersions = append(newStoredVersions, version)
 	}
 
-	return newStoredVersions, matchedStoredVersion
+	return newStoredVersions, matchedStoredVersions
+}
+
+// storageVersion returns the version the CRD currently stores objects in, if known
+func storageVersion(crd apiextensions.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+
+	return ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
 }
@@ -7,6 +7,8 @@ package crd
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -15,11 +17,13 @@ import (
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fake2 "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/Azure/azure-service-operator/v2/api"
 	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
@@ -355,3 +359,227 @@ func newCRDWithStoredVersions(versions ...string) *v1.CustomResourceDefinition {
 
 	return definition
 }
+
+func Test_CleanCRDs_WithVersions_RemovesOnlySpecifiedVersions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	fakeApiExtClient := fake.NewSimpleClientset().ApiextensionsV1()
+	fakeClient := fake2.NewClientBuilder().WithScheme(api.CreateScheme()).Build()
+	cleaner := NewCleanerWithOptions(
+		fakeApiExtClient.CustomResourceDefinitions(),
+		fakeClient,
+		CleanerOptions{Versions: []string{"v1api20200601"}},
+		logr.Discard())
+
+	definition := newCRDWithStoredVersions("v1beta20200601", "v1api20200601storage", "v1api20210401storage")
+	definition.Spec.Versions = []v1.CustomResourceDefinitionVersion{
+		{Name: "v1api20200601storage"},
+		{Name: "v1api20210401storage", Storage: true},
+	}
+
+	_, err := fakeApiExtClient.CustomResourceDefinitions().Create(context.TODO(), definition, metav1.CreateOptions{})
+	g.Expect(err).To(BeNil())
+
+	err = cleaner.Run(context.TODO())
+	g.Expect(err).To(BeNil())
+
+	crd, err := fakeApiExtClient.CustomResourceDefinitions().Get(context.TODO(), definition.Name, metav1.GetOptions{})
+	g.Expect(err).To(BeNil())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta20200601", "v1api20210401storage"}))
+}
+
+func Test_CleanCRDs_WithVersions_ReturnsError_IfStorageVersion(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	fakeApiExtClient := fake.NewSimpleClientset().ApiextensionsV1()
+	fakeClient := fake2.NewClientBuilder().WithScheme(api.CreateScheme()).Build()
+	cleaner := NewCleanerWithOptions(
+		fakeApiExtClient.CustomResourceDefinitions(),
+		fakeClient,
+		CleanerOptions{Versions: []string{"v1api20200601"}},
+		logr.Discard())
+
+	definition := newCRDWithStoredVersions("v1api20200601storage", "v1api20210401storage")
+	definition.Spec.Versions = []v1.CustomResourceDefinitionVersion{
+		{Name: "v1api20200601storage", Storage: true},
+		{Name: "v1api20210401storage"},
+	}
+
+	_, err := fakeApiExtClient.CustomResourceDefinitions().Create(context.TODO(), definition, metav1.CreateOptions{})
+	g.Expect(err).To(BeNil())
+
+	err = cleaner.Run(context.TODO())
+	g.Expect(err).To(MatchError(ContainSubstring("still the storage version")))
+}
+
+func Test_CleanCRDs_WithSelectors_SkipsOtherGroups(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	fakeApiExtClient := fake.NewSimpleClientset().ApiextensionsV1()
+	fakeClient := fake2.NewClientBuilder().WithScheme(api.CreateScheme()).Build()
+	cleaner := NewCleanerWithOptions(
+		fakeApiExtClient.CustomResourceDefinitions(),
+		fakeClient,
+		CleanerOptions{Selectors: []string{"*.network.azure.com"}},
+		logr.Discard())
+
+	definition := newCRDWithStoredVersions("v1beta20200601", "v1api20200601")
+	_, err := fakeApiExtClient.CustomResourceDefinitions().Create(context.TODO(), definition, metav1.CreateOptions{})
+	g.Expect(err).To(BeNil())
+
+	err = cleaner.Run(context.TODO())
+	g.Expect(err).To(MatchError(ContainSubstring("found no Azure Service Operator CRDs matching")))
+
+	crd, err := fakeApiExtClient.CustomResourceDefinitions().Get(context.TODO(), definition.Name, metav1.GetOptions{})
+	g.Expect(err).To(BeNil())
+	g.Expect(crd.Status.StoredVersions).To(ContainElement("v1beta20200601"))
+}
+
+func Test_CleanCRDs_WithCheckpoint_SkipsMigratedObjects(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	err := os.WriteFile(
+		checkpointPath,
+		[]byte(`{"crds":{"resourcegroups.resources.azure.com":{"versions":["v1beta20200601"],"migrated":["test-ns/test-rg"]}}}`),
+		0o600)
+	g.Expect(err).To(BeNil())
+
+	fakeApiExtClient := fake.NewSimpleClientset().ApiextensionsV1()
+	fakeClient := fake2.NewClientBuilder().WithScheme(api.CreateScheme()).Build()
+	cleaner := NewCleanerWithOptions(
+		fakeApiExtClient.CustomResourceDefinitions(),
+		fakeClient,
+		CleanerOptions{CheckpointPath: checkpointPath},
+		logr.Discard())
+
+	definition := newCRDWithStoredVersions("v1beta20200601", "v1api20200601")
+	_, err = fakeApiExtClient.CustomResourceDefinitions().Create(context.TODO(), definition, metav1.CreateOptions{})
+	g.Expect(err).To(BeNil())
+
+	ns := newNamespace("test-ns")
+	g.Expect(fakeClient.Create(context.TODO(), ns)).To(Succeed())
+
+	migrated := newResourceGroup("test-rg", ns.Name)
+	g.Expect(fakeClient.Create(context.TODO(), migrated)).To(Succeed())
+
+	pending := newResourceGroup("test-rg-2", ns.Name)
+	g.Expect(fakeClient.Create(context.TODO(), pending)).To(Succeed())
+
+	err = cleaner.Run(context.TODO())
+	g.Expect(err).To(BeNil())
+
+	var updatedRG resources.ResourceGroup
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(migrated), &updatedRG)).To(Succeed())
+	g.Expect(updatedRG.ResourceVersion).To(Equal(migrated.ResourceVersion))
+
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pending), &updatedRG)).To(Succeed())
+	g.Expect(updatedRG.ResourceVersion).ToNot(Equal(pending.ResourceVersion))
+
+	// Progress is recorded as complete, so a second run does nothing
+	progress, err := loadCheckpoint(checkpointPath)
+	g.Expect(err).To(BeNil())
+	g.Expect(progress.CRDs).To(HaveKey(definition.Name))
+	g.Expect(progress.CRDs[definition.Name].Completed).To(BeTrue())
+}
+
+func Test_CleanCRDs_WhenUpdateConflicts_DoesNotRecordObjectAsMigrated(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	fakeApiExtClient := fake.NewSimpleClientset().ApiextensionsV1()
+	fakeClient := fake2.NewClientBuilder().
+		WithScheme(api.CreateScheme()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.UpdateOption) error {
+				return apierrors.NewConflict(resources.GroupVersion.WithResource("resourcegroups").GroupResource(), obj.GetName(), nil)
+			},
+		}).
+		Build()
+	cleaner := NewCleanerWithOptions(
+		fakeApiExtClient.CustomResourceDefinitions(),
+		fakeClient,
+		CleanerOptions{CheckpointPath: checkpointPath},
+		logr.Discard())
+
+	definition := newCRDWithStoredVersions("v1beta20200601", "v1api20200601")
+	_, err := fakeApiExtClient.CustomResourceDefinitions().Create(context.TODO(), definition, metav1.CreateOptions{})
+	g.Expect(err).To(BeNil())
+
+	ns := newNamespace("test-ns")
+	g.Expect(fakeClient.Create(context.TODO(), ns)).To(Succeed())
+
+	rg := newResourceGroup("test-rg", ns.Name)
+	g.Expect(fakeClient.Create(context.TODO(), rg)).To(Succeed())
+
+	// The object wasn't rewritten by us or anyone else, so it may still be stored in the old version
+	err = cleaner.Run(context.TODO())
+	g.Expect(err).To(MatchError(ContainSubstring("may still be stored in a removed version")))
+
+	progress, err := loadCheckpoint(checkpointPath)
+	g.Expect(err).To(BeNil())
+	g.Expect(progress.CRDs).To(HaveKey(definition.Name))
+	g.Expect(progress.CRDs[definition.Name].Migrated).To(BeEmpty())
+	g.Expect(progress.CRDs[definition.Name].Completed).To(BeFalse())
+
+	crd, err := fakeApiExtClient.CustomResourceDefinitions().Get(context.TODO(), definition.Name, metav1.GetOptions{})
+	g.Expect(err).To(BeNil())
+	g.Expect(crd.Status.StoredVersions).To(ContainElement("v1beta20200601"))
+}
+
+func Test_CleanCRDs_WhenStorageVersionChangesDuringMigration_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	fakeApiExtClient := fake.NewSimpleClientset().ApiextensionsV1()
+	definition := newCRDWithStoredVersions("v1beta20200601", "v1api20200601")
+	definition.Spec.Versions = []v1.CustomResourceDefinitionVersion{
+		{Name: "v1api20200601", Storage: true},
+		{Name: "v1api20200601storage"},
+	}
+
+	_, err := fakeApiExtClient.CustomResourceDefinitions().Create(context.TODO(), definition, metav1.CreateOptions{})
+	g.Expect(err).To(BeNil())
+
+	// Simulate an upgrade of ASO changing the storage version while objects are being migrated
+	fakeClient := fake2.NewClientBuilder().
+		WithScheme(api.CreateScheme()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				upgraded := definition.DeepCopy()
+				upgraded.Spec.Versions[0].Storage = false
+				upgraded.Spec.Versions[1].Storage = true
+				_, err := fakeApiExtClient.CustomResourceDefinitions().Update(ctx, upgraded, metav1.UpdateOptions{})
+				if err != nil {
+					return err
+				}
+
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	cleaner := NewCleaner(
+		fakeApiExtClient.CustomResourceDefinitions(),
+		fakeClient,
+		false, // dry-run
+		logr.Discard())
+
+	ns := newNamespace("test-ns")
+	g.Expect(fakeClient.Create(context.TODO(), ns)).To(Succeed())
+
+	rg := newResourceGroup("test-rg", ns.Name)
+	g.Expect(fakeClient.Create(context.TODO(), rg)).To(Succeed())
+
+	err = cleaner.Run(context.TODO())
+	g.Expect(err).To(MatchError(ContainSubstring("changed from \"v1api20200601\" to \"v1api20200601storage\"")))
+
+	crd, err := fakeApiExtClient.CustomResourceDefinitions().Get(context.TODO(), definition.Name, metav1.GetOptions{})
+	g.Expect(err).To(BeNil())
+	g.Expect(crd.Status.StoredVersions).To(ContainElement("v1beta20200601"))
+}
@@ -0,0 +1,581 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cache "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230401"
+	eventhub "github.com/Azure/azure-service-operator/v2/api/eventhub/v1api20211101"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	sql "github.com/Azure/azure-service-operator/v2/api/sql/v1api20211101"
+	storage "github.com/Azure/azure-service-operator/v2/api/storage/v1api20220901"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// mapper converts a single ASO v1 resource into one or more ASO v2 resources, adding them to the migration context
+type mapper func(mc *migrationContext, r *v1Resource) error
+
+// mappers contains the mapper for each supported v1 kind
+var mappers = map[string]mapper{
+	"ResourceGroup":        migrateResourceGroup,
+	"AzureSqlServer":       migrateAzureSqlServer,
+	"AzureSqlDatabase":     migrateAzureSqlDatabase,
+	"AzureSqlFirewallRule": migrateAzureSqlFirewallRule,
+	"MySQLServer":          migrateMySQL,
+	"MySQLDatabase":        migrateMySQL,
+	"MySQLFirewallRule":    migrateMySQL,
+	"StorageAccount":       migrateStorageAccount,
+	"BlobContainer":        migrateBlobContainer,
+	"RedisCache":           migrateRedisCache,
+	"EventhubNamespace":    migrateEventhubNamespace,
+	"Eventhub":             migrateEventhub,
+}
+
+func migrateResourceGroup(mc *migrationContext, r *v1Resource) error {
+	rg := &resources.ResourceGroup{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: resources.ResourceGroup_Spec{
+			AzureName: r.Name(),
+		},
+	}
+
+	if location, ok := r.String("location"); ok {
+		rg.Spec.Location = &location
+	}
+
+	// v1 resource names are Azure names, which may not be valid Kubernetes names
+	rg.Name = strings.ToLower(r.Name())
+	mc.resourceGroups[types.NamespacedName{Namespace: rg.Namespace, Name: rg.Name}] = rg
+	return mc.add(rg)
+}
+
+func migrateAzureSqlServer(mc *migrationContext, r *v1Resource) error {
+	server := &sql.Server{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: sql.Server_Spec{
+			Owner: mc.resourceGroupOwner(r, "resourceGroup"),
+		},
+	}
+
+	if location, ok := r.String("location"); ok {
+		server.Spec.Location = &location
+	}
+
+	// v1 keeps the administrator credentials in a secret named after the server; v2 reads the password from a secret
+	// reference and needs the login in the spec
+	r.Use("subscriptionId")
+	secretName := mc.v1SecretName(r.Kind(), r.Name())
+	copied, err := mc.copySecret(r, secretName)
+	if err != nil {
+		return err
+	}
+
+	server.Spec.AdministratorLoginPassword = &genruntime.SecretReference{
+		Name: copied,
+		Key:  "password",
+	}
+
+	if login, ok := mc.readSecretValue(r.Namespace(), secretName, "username"); ok {
+		server.Spec.AdministratorLogin = &login
+	} else {
+		mc.result.addIssue(r.Kind(), r.Namespace(), r.Name(), "spec.administratorLogin must be set manually")
+	}
+
+	return mc.add(server)
+}
+
+func migrateAzureSqlDatabase(mc *migrationContext, r *v1Resource) error {
+	serverName, ok := r.String("server")
+	if !ok {
+		return errors.Errorf("spec.server is required")
+	}
+
+	r.Use("resourceGroup")
+	r.Use("subscriptionId")
+
+	db := &sql.ServersDatabase{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: sql.Servers_Database_Spec{
+			AzureName: r.Name(),
+			Owner:     &genruntime.KnownResourceReference{Name: serverName},
+		},
+	}
+
+	if location, ok := r.String("location"); ok {
+		db.Spec.Location = &location
+	}
+
+	if dbName, ok := r.String("dbName"); ok {
+		db.Spec.AzureName = dbName
+	}
+
+	if name, ok := r.String("sku", "name"); ok {
+		db.Spec.Sku = &sql.Sku{Name: &name}
+		if tier, ok := r.String("sku", "tier"); ok {
+			db.Spec.Sku.Tier = &tier
+		}
+
+		if size, ok := r.String("sku", "size"); ok {
+			db.Spec.Sku.Size = &size
+		}
+
+		if family, ok := r.String("sku", "family"); ok {
+			db.Spec.Sku.Family = &family
+		}
+
+		if capacity, ok := r.Int("sku", "capacity"); ok {
+			db.Spec.Sku.Capacity = &capacity
+		}
+	} else if edition, ok := r.Int("edition"); ok {
+		// Edition was deprecated by v1 in favour of sku, and used numeric codes
+		mc.result.addIssue(r.Kind(), r.Namespace(), r.Name(), fmt.Sprintf("spec.edition %d is deprecated, spec.sku must be set manually", edition))
+	}
+
+	if maxSize, ok := r.String("maxSize"); ok {
+		quantity, err := resource.ParseQuantity(maxSize)
+		if err != nil {
+			return errors.Wrapf(err, "parsing spec.maxSize %q", maxSize)
+		}
+
+		db.Spec.MaxSizeBytes = ptr(int(quantity.Value()))
+	}
+
+	if elasticPoolID, ok := r.String("elasticPoolId"); ok {
+		db.Spec.ElasticPoolReference = &genruntime.ResourceReference{ARMID: elasticPoolID}
+	}
+
+	err := mc.add(db)
+	if err != nil {
+		return err
+	}
+
+	// v1 folded the backup retention policies into the database; v2 has a resource for each
+	weekly, hasWeekly := r.String("weeklyRetention")
+	monthly, hasMonthly := r.String("monthlyRetention")
+	yearly, hasYearly := r.String("yearlyRetention")
+	weekOfYear, hasWeekOfYear := r.Int("weekOfYear")
+	if hasWeekly || hasMonthly || hasYearly {
+		policy := &sql.ServersDatabasesBackupLongTermRetentionPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      r.Name() + "-long-term-retention",
+				Namespace: r.Namespace(),
+			},
+			Spec: sql.Servers_Databases_BackupLongTermRetentionPolicy_Spec{
+				Owner: &genruntime.KnownResourceReference{Name: r.Name()},
+			},
+		}
+
+		if hasWeekly {
+			policy.Spec.WeeklyRetention = &weekly
+		}
+
+		if hasMonthly {
+			policy.Spec.MonthlyRetention = &monthly
+		}
+
+		if hasYearly {
+			policy.Spec.YearlyRetention = &yearly
+		}
+
+		if hasWeekOfYear && weekOfYear != 0 {
+			policy.Spec.WeekOfYear = &weekOfYear
+		}
+
+		err = mc.add(policy)
+		if err != nil {
+			return err
+		}
+	}
+
+	if retentionDays, ok := r.Int("shortTermRetentionPolicy", "retentionDays"); ok {
+		policy := &sql.ServersDatabasesBackupShortTermRetentionPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      r.Name() + "-short-term-retention",
+				Namespace: r.Namespace(),
+			},
+			Spec: sql.Servers_Databases_BackupShortTermRetentionPolicy_Spec{
+				Owner:         &genruntime.KnownResourceReference{Name: r.Name()},
+				RetentionDays: &retentionDays,
+			},
+		}
+
+		err = mc.add(policy)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migrateAzureSqlFirewallRule(mc *migrationContext, r *v1Resource) error {
+	serverName, ok := r.String("server")
+	if !ok {
+		return errors.Errorf("spec.server is required")
+	}
+
+	r.Use("resourceGroup")
+	r.Use("subscriptionID")
+
+	rule := &sql.ServersFirewallRule{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: sql.Servers_FirewallRule_Spec{
+			AzureName: r.Name(),
+			Owner:     &genruntime.KnownResourceReference{Name: serverName},
+		},
+	}
+
+	if start, ok := r.String("startIpAddress"); ok {
+		rule.Spec.StartIpAddress = &start
+	}
+
+	if end, ok := r.String("endIpAddress"); ok {
+		rule.Spec.EndIpAddress = &end
+	}
+
+	return mc.add(rule)
+}
+
+func migrateMySQL(mc *migrationContext, r *v1Resource) error {
+	// ASO v1 manages Azure Database for MySQL single servers, which ASO v2 doesn't support
+	mc.result.addIssue(
+		r.Kind(),
+		r.Namespace(),
+		r.Name(),
+		"MySQL single server is not supported by ASO v2; migrate to a flexible server (dbformysql.azure.com) instead")
+
+	// Mark everything as used so we don't also report every field
+	spec, _ := r.obj.Object["spec"].(map[string]interface{})
+	for k := range spec {
+		r.Use(k)
+	}
+
+	return nil
+}
+
+func migrateStorageAccount(mc *migrationContext, r *v1Resource) error {
+	account := &storage.StorageAccount{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: storage.StorageAccount_Spec{
+			Owner: mc.resourceGroupOwner(r, "resourceGroup"),
+		},
+	}
+
+	if location, ok := r.String("location"); ok {
+		account.Spec.Location = &location
+	}
+
+	if name, ok := r.String("sku", "name"); ok {
+		account.Spec.Sku = &storage.Sku{Name: ptr(storage.SkuName(name))}
+	}
+
+	if kind, ok := r.String("kind"); ok {
+		account.Spec.Kind = ptr(storage.StorageAccount_Kind_Spec(kind))
+	}
+
+	if tier, ok := r.String("accessTier"); ok {
+		account.Spec.AccessTier = ptr(storage.StorageAccountPropertiesCreateParameters_AccessTier(tier))
+	}
+
+	if httpsOnly, ok := r.Bool("supportsHttpsTrafficOnly"); ok {
+		account.Spec.SupportsHttpsTrafficOnly = &httpsOnly
+	}
+
+	if dataLake, ok := r.Bool("dataLakeEnabled"); ok {
+		account.Spec.IsHnsEnabled = &dataLake
+	}
+
+	// v1 wrote the account keys as key0 and key1; v2 can export them to the same keys
+	resourceGroup, _ := r.String("resourceGroup")
+	secretName := r.Name()
+	if mc.migrator.secretNaming == SecretNamingV1 {
+		secretName = fmt.Sprintf("storageaccount-%s-%s", resourceGroup, r.Name())
+	}
+
+	secretName = mc.v1SecretName(r.Kind(), secretName)
+	account.Spec.OperatorSpec = &storage.StorageAccountOperatorSpec{
+		Secrets: &storage.StorageAccountOperatorSecrets{
+			Key1: secretDestination(secretName, "key0"),
+			Key2: secretDestination(secretName, "key1"),
+		},
+	}
+
+	mc.result.addIssue(
+		r.Kind(),
+		r.Namespace(),
+		r.Name(),
+		fmt.Sprintf("keys will be written to secret %q; connection strings are no longer exported", secretName+migratedSecretSuffix))
+
+	return mc.add(account)
+}
+
+func migrateBlobContainer(mc *migrationContext, r *v1Resource) error {
+	accountName, ok := r.String("accountname")
+	if !ok {
+		return errors.Errorf("spec.accountname is required")
+	}
+
+	r.Use("location")
+	r.Use("resourcegroup")
+
+	// v2 models the blob service explicitly; create one per storage account
+	blobServiceName := accountName + "-blobservice"
+	key := types.NamespacedName{Namespace: r.Namespace(), Name: accountName}
+	if !mc.blobServices[key] {
+		mc.blobServices[key] = true
+		blobService := &storage.StorageAccountsBlobService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      blobServiceName,
+				Namespace: r.Namespace(),
+			},
+			Spec: storage.StorageAccounts_BlobService_Spec{
+				Owner: &genruntime.KnownResourceReference{Name: accountName},
+			},
+		}
+
+		err := mc.add(blobService)
+		if err != nil {
+			return err
+		}
+	}
+
+	container := &storage.StorageAccountsBlobServicesContainer{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: storage.StorageAccounts_BlobServices_Container_Spec{
+			AzureName: r.Name(),
+			Owner:     &genruntime.KnownResourceReference{Name: blobServiceName},
+		},
+	}
+
+	if access, ok := r.String("accesslevel"); ok {
+		container.Spec.PublicAccess = ptr(storage.ContainerProperties_PublicAccess(access))
+	}
+
+	return mc.add(container)
+}
+
+func migrateRedisCache(mc *migrationContext, r *v1Resource) error {
+	redis := &cache.Redis{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: cache.Redis_Spec{
+			Owner: mc.resourceGroupOwner(r, "resourceGroup"),
+		},
+	}
+
+	if location, ok := r.String("location"); ok {
+		redis.Spec.Location = &location
+	}
+
+	if name, ok := r.String("properties", "sku", "name"); ok {
+		redis.Spec.Sku = &cache.Sku{Name: ptr(cache.Sku_Name(name))}
+		if family, ok := r.String("properties", "sku", "family"); ok {
+			redis.Spec.Sku.Family = ptr(cache.Sku_Family(family))
+		}
+
+		if capacity, ok := r.Int("properties", "sku", "capacity"); ok {
+			redis.Spec.Sku.Capacity = &capacity
+		}
+	}
+
+	if nonSSL, ok := r.Bool("properties", "enableNonSslPort"); ok {
+		redis.Spec.EnableNonSslPort = &nonSSL
+	}
+
+	if subnetID, ok := r.String("properties", "subnetId"); ok {
+		redis.Spec.SubnetReference = &genruntime.ResourceReference{ARMID: subnetID}
+	}
+
+	if staticIP, ok := r.String("properties", "staticIp"); ok {
+		redis.Spec.StaticIP = &staticIP
+	}
+
+	if shardCount, ok := r.Int("properties", "shardCount"); ok {
+		redis.Spec.ShardCount = &shardCount
+	}
+
+	if configuration := r.StringMap("properties", "configuration"); len(configuration) > 0 {
+		config, unknown, err := redisConfiguration(configuration)
+		if err != nil {
+			return err
+		}
+
+		redis.Spec.RedisConfiguration = config
+		for _, key := range unknown {
+			mc.result.addIssue(r.Kind(), r.Namespace(), r.Name(), fmt.Sprintf("spec.properties.configuration.%s could not be mapped", key))
+		}
+	}
+
+	// Secrets in Key Vault aren't found by the v2 secret export
+	if vault, ok := r.String("keyVaultToStoreSecrets"); ok {
+		mc.result.addIssue(r.Kind(), r.Namespace(), r.Name(), fmt.Sprintf("secrets in Key Vault %q are not migrated; keys will be written to a Kubernetes secret", vault))
+	}
+
+	secretName := r.Name()
+	if name, ok := r.String("secretName"); ok {
+		secretName = name
+	}
+
+	secretName = mc.v1SecretName(r.Kind(), secretName)
+	redis.Spec.OperatorSpec = &cache.RedisOperatorSpec{
+		Secrets: &cache.RedisOperatorSecrets{
+			PrimaryKey:   secretDestination(secretName, "primaryKey"),
+			SecondaryKey: secretDestination(secretName, "secondaryKey"),
+		},
+	}
+
+	return mc.add(redis)
+}
+
+// redisConfiguration converts the free-form v1 Redis configuration into the v2 type, whose JSON names match the Azure
+// configuration names. Any keys not supported by v2 are returned.
+func redisConfiguration(configuration map[string]string) (*cache.RedisCreateProperties_RedisConfiguration, []string, error) {
+	data, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "serializing redis configuration")
+	}
+
+	var result cache.RedisCreateProperties_RedisConfiguration
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "deserializing redis configuration")
+	}
+
+	// Round trip to find out which keys survived
+	data, err = json.Marshal(result)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "serializing redis configuration")
+	}
+
+	var known map[string]string
+	err = json.Unmarshal(data, &known)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "deserializing redis configuration")
+	}
+
+	var unknown []string
+	for k := range configuration {
+		if _, ok := known[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+
+	sort.Strings(unknown)
+	return &result, unknown, nil
+}
+
+func migrateEventhubNamespace(mc *migrationContext, r *v1Resource) error {
+	namespace := &eventhub.Namespace{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: eventhub.Namespace_Spec{
+			Owner: mc.resourceGroupOwner(r, "resourceGroup"),
+		},
+	}
+
+	if location, ok := r.String("location"); ok {
+		namespace.Spec.Location = &location
+	}
+
+	if name, ok := r.String("sku", "name"); ok {
+		namespace.Spec.Sku = &eventhub.Sku{Name: ptr(eventhub.Sku_Name(name))}
+		if tier, ok := r.String("sku", "tier"); ok {
+			namespace.Spec.Sku.Tier = ptr(eventhub.Sku_Tier(tier))
+		}
+
+		if capacity, ok := r.Int("sku", "capacity"); ok {
+			namespace.Spec.Sku.Capacity = &capacity
+		}
+	}
+
+	if autoInflate, ok := r.Bool("properties", "isAutoInflateEnabled"); ok {
+		namespace.Spec.IsAutoInflateEnabled = &autoInflate
+	}
+
+	if maxUnits, ok := r.Int("properties", "maximumThroughputUnits"); ok {
+		namespace.Spec.MaximumThroughputUnits = &maxUnits
+	}
+
+	if kafka, ok := r.Bool("properties", "kafkaEnabled"); ok {
+		namespace.Spec.KafkaEnabled = &kafka
+	}
+
+	return mc.add(namespace)
+}
+
+func migrateEventhub(mc *migrationContext, r *v1Resource) error {
+	namespaceName, ok := r.String("namespace")
+	if !ok {
+		return errors.Errorf("spec.namespace is required")
+	}
+
+	r.Use("location")
+	r.Use("resourceGroup")
+
+	hub := &eventhub.NamespacesEventhub{
+		ObjectMeta: mc.objectMeta(r),
+		Spec: eventhub.Namespaces_Eventhub_Spec{
+			AzureName: r.Name(),
+			Owner:     &genruntime.KnownResourceReference{Name: namespaceName},
+		},
+	}
+
+	if retention, ok := r.Int("properties", "messageRetentionInDays"); ok {
+		hub.Spec.MessageRetentionInDays = &retention
+	}
+
+	if partitions, ok := r.Int("properties", "partitionCount"); ok {
+		hub.Spec.PartitionCount = &partitions
+	}
+
+	err := mc.add(hub)
+	if err != nil {
+		return err
+	}
+
+	if ruleName, ok := r.String("authorizationRule", "name"); ok {
+		rule := &eventhub.NamespacesEventhubsAuthorizationRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      strings.ToLower(r.Name() + "-" + ruleName),
+				Namespace: r.Namespace(),
+			},
+			Spec: eventhub.Namespaces_Eventhubs_AuthorizationRule_Spec{
+				AzureName: ruleName,
+				Owner:     &genruntime.KnownResourceReference{Name: r.Name()},
+			},
+		}
+
+		for _, right := range r.StringSlice("authorizationRule", "rights") {
+			rule.Spec.Rights = append(rule.Spec.Rights, eventhub.Namespaces_Eventhubs_AuthorizationRule_Properties_Rights_Spec(right))
+		}
+
+		err = mc.add(rule)
+		if err != nil {
+			return err
+		}
+	}
+
+	// v2 doesn't export the connection strings of an event hub, so keep a copy of the v1 secret
+	if vault, ok := r.String("keyVaultToStoreSecrets"); ok {
+		mc.result.addIssue(r.Kind(), r.Namespace(), r.Name(), fmt.Sprintf("secrets in Key Vault %q are not migrated", vault))
+		return nil
+	}
+
+	secretName := r.Name()
+	if name, ok := r.String("secretName"); ok {
+		secretName = name
+	}
+
+	_, err = mc.copySecret(r, mc.v1SecretName(r.Kind(), secretName))
+	return err
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package migration
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// MigrationResult represents the result of migrating ASO v1 resources
+type MigrationResult struct {
+	resources []client.Object
+	issues    []Issue
+}
+
+// Issue is something about a v1 resource that couldn't be migrated automatically and needs attention
+type Issue struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Message   string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s %s/%s: %s", i.Kind, i.Namespace, i.Name, i.Message)
+}
+
+// Count returns the number of ASO v2 resources (including secrets) created
+func (r *MigrationResult) Count() int {
+	return len(r.resources)
+}
+
+// Resources returns the ASO v2 resources (including secrets) created
+func (r *MigrationResult) Resources() []client.Object {
+	return r.resources
+}
+
+// Issues returns the issues found during migration
+func (r *MigrationResult) Issues() []Issue {
+	return r.issues
+}
+
+// SaveToWriter writes all the resources to the destination as a multi-document YAML stream
+func (r *MigrationResult) SaveToWriter(destination io.Writer) error {
+	buf := bufio.NewWriter(destination)
+	defer func(buf *bufio.Writer) {
+		_ = buf.Flush()
+	}(buf)
+
+	_, err := buf.WriteString("---\n")
+	if err != nil {
+		return errors.Wrap(err, "unable to save to writer")
+	}
+
+	// Sort objects into a deterministic order
+	resources := slices.Clone(r.resources)
+	slices.SortFunc(
+		resources,
+		func(left client.Object, right client.Object) int {
+			leftGVK := left.GetObjectKind().GroupVersionKind()
+			rightGVK := right.GetObjectKind().GroupVersionKind()
+			if c := strings.Compare(leftGVK.Group, rightGVK.Group); c != 0 {
+				return c
+			}
+
+			if c := strings.Compare(leftGVK.Kind, rightGVK.Kind); c != 0 {
+				return c
+			}
+
+			if c := strings.Compare(left.GetNamespace(), right.GetNamespace()); c != 0 {
+				return c
+			}
+
+			return strings.Compare(left.GetName(), right.GetName())
+		})
+
+	for _, resource := range resources {
+		data, err := yaml.Marshal(resource)
+		if err != nil {
+			return errors.Wrap(err, "unable to save to writer")
+		}
+
+		data = redactStatus(data)
+
+		_, err = buf.Write(data)
+		if err != nil {
+			return errors.Wrap(err, "unable to save to writer")
+		}
+
+		_, err = buf.WriteString("---\n")
+		if err != nil {
+			return errors.Wrap(err, "unable to save to writer")
+		}
+	}
+
+	return nil
+}
+
+// SaveToSingleFile writes all the resources to the file at path
+func (r *MigrationResult) SaveToSingleFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create file %s", path)
+	}
+
+	defer file.Close()
+
+	err = r.SaveToWriter(file)
+	if err != nil {
+		// cleanup in case of errors
+		file.Close()
+		os.Remove(path)
+	}
+
+	return errors.Wrapf(err, "unable to save to file %s", path)
+}
+
+func (r *MigrationResult) addIssue(kind string, namespace string, name string, message string) {
+	r.issues = append(
+		r.issues,
+		Issue{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+			Message:   message,
+		})
+}
+
+// redactStatus removes any empty `status { }` blocks from the yaml.
+func redactStatus(data []byte) []byte {
+	content := string(data)
+	content = strings.Replace(content, "status: {}", "", -1)
+	return []byte(content)
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// SecretNamingVersion identifies the convention ASO v1 used to name the secrets it created.
+// This mirrors the AZURE_SECRET_NAMING_VERSION setting of the v1 operator.
+type SecretNamingVersion int
+
+const (
+	SecretNamingV1 SecretNamingVersion = 1
+	SecretNamingV2 SecretNamingVersion = 2
+)
+
+// migratedSecretSuffix is appended to the name of each v1 secret to give the name of the secret used by ASO v2.
+// We can't reuse the v1 secret directly as it is usually owned (and will be deleted along with) the v1 resource,
+// and ASO v2 refuses to write to secrets it doesn't own.
+const migratedSecretSuffix = "-migrated"
+
+// SecretReader reads the secrets created by ASO v1
+type SecretReader interface {
+	Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error
+}
+
+// Migrator converts ASO v1 resources into the equivalent ASO v2 resources
+type Migrator struct {
+	scheme       *runtime.Scheme
+	secretNaming SecretNamingVersion
+	secretReader SecretReader // Optional; if nil, secrets can't be copied
+	log          logr.Logger
+}
+
+// NewMigrator creates a new Migrator.
+// scheme must contain the ASO v2 types.
+// secretReader is used to copy the secrets created by ASO v1; pass nil when working from files.
+func NewMigrator(
+	scheme *runtime.Scheme,
+	secretNaming SecretNamingVersion,
+	secretReader SecretReader,
+	log logr.Logger,
+) *Migrator {
+	return &Migrator{
+		scheme:       scheme,
+		secretNaming: secretNaming,
+		secretReader: secretReader,
+		log:          log,
+	}
+}
+
+// Migrate converts the specified v1 resources to v2
+func (m *Migrator) Migrate(ctx context.Context, objs []*unstructured.Unstructured) (*MigrationResult, error) {
+	mc := &migrationContext{
+		ctx:            ctx,
+		migrator:       m,
+		result:         &MigrationResult{},
+		resourceGroups: make(map[types.NamespacedName]*resources.ResourceGroup),
+		inferredGroups: make(map[types.NamespacedName]*resources.ResourceGroup),
+		blobServices:   make(map[types.NamespacedName]bool),
+	}
+
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != V1Group {
+			mc.result.addIssue(obj.GetKind(), obj.GetNamespace(), obj.GetName(), "not an ASO v1 resource, skipped")
+			continue
+		}
+
+		mapper, ok := mappers[obj.GetKind()]
+		if !ok {
+			mc.result.addIssue(obj.GetKind(), obj.GetNamespace(), obj.GetName(), "no ASO v2 equivalent is known, skipped")
+			continue
+		}
+
+		r := newV1Resource(obj)
+		err := mapper(mc, r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "migrating %s %s/%s", r.Kind(), r.Namespace(), r.Name())
+		}
+
+		for _, field := range r.UnmappedFields() {
+			mc.result.addIssue(r.Kind(), r.Namespace(), r.Name(), fmt.Sprintf("%s could not be mapped", field))
+		}
+
+		m.log.V(1).Info(
+			"Migrated",
+			"kind", r.Kind(),
+			"namespace", r.Namespace(),
+			"name", r.Name())
+	}
+
+	// Any resource groups referenced but not migrated are created so the v2 resources have an owner
+	for name, rg := range mc.inferredGroups {
+		if _, ok := mc.resourceGroups[name]; ok {
+			continue
+		}
+
+		mc.result.addIssue(
+			"ResourceGroup",
+			name.Namespace,
+			name.Name,
+			fmt.Sprintf("no v1 ResourceGroup found, created one assuming location %q", to(rg.Spec.Location)))
+		err := mc.add(rg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mc.result, nil
+}
+
+// migrationContext holds the state of a single migration
+type migrationContext struct {
+	ctx      context.Context
+	migrator *Migrator
+	result   *MigrationResult
+	// resourceGroups contains the resource groups migrated from v1
+	resourceGroups map[types.NamespacedName]*resources.ResourceGroup
+	// inferredGroups contains the resource groups referenced by other v1 resources
+	inferredGroups map[types.NamespacedName]*resources.ResourceGroup
+	// blobServices contains the storage accounts for which we've created a blob service
+	blobServices map[types.NamespacedName]bool
+}
+
+// add adds a v2 resource to the result, configured for safe adoption
+func (mc *migrationContext) add(obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, mc.migrator.scheme)
+	if err != nil {
+		return errors.Wrapf(err, "looking up GVK for %T", obj)
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	if _, ok := obj.(genruntime.ARMMetaObject); ok {
+		// Adopt the existing Azure resource without modifying it
+		anns := obj.GetAnnotations()
+		if anns == nil {
+			anns = make(map[string]string)
+		}
+
+		anns[annotations.ReconcilePolicy] = string(annotations.ReconcilePolicySkip)
+		obj.SetAnnotations(anns)
+	}
+
+	mc.result.resources = append(mc.result.resources, obj)
+	return nil
+}
+
+// objectMeta returns metadata for a v2 resource with the same name and namespace as the v1 resource
+func (mc *migrationContext) objectMeta(r *v1Resource) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      r.Name(),
+		Namespace: r.Namespace(),
+	}
+}
+
+// resourceGroupOwner returns the owner for a resource in the resource group named by the v1 resource.
+// field is the name of the spec field containing the resource group name; this varies between v1 kinds.
+func (mc *migrationContext) resourceGroupOwner(r *v1Resource, field string) *genruntime.KnownResourceReference {
+	name, ok := r.String(field)
+	if !ok {
+		mc.result.addIssue(r.Kind(), r.Namespace(), r.Name(), fmt.Sprintf("spec.%s is missing, owner must be set manually", field))
+		return nil
+	}
+
+	// Ensure the resource group exists in the v2 resources, inferring its location from the first resource in it.
+	// v2 resource group names must be lowercase for Kubernetes, Azure names are preserved as AzureName.
+	key := types.NamespacedName{Namespace: r.Namespace(), Name: strings.ToLower(name)}
+	if _, ok := mc.inferredGroups[key]; !ok {
+		rg := &resources.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: resources.ResourceGroup_Spec{
+				AzureName: name,
+			},
+		}
+
+		if location, ok, _ := unstructured.NestedString(r.obj.Object, "spec", "location"); ok && location != "" {
+			rg.Spec.Location = &location
+		}
+
+		mc.inferredGroups[key] = rg
+	}
+
+	return &genruntime.KnownResourceReference{Name: key.Name}
+}
+
+// v1SecretName returns the name of the secret created by ASO v1 for the resource, following the conventions of
+// pkg/secrets/kube. kind is the kind used for the secret key, and name the (possibly customized) secret name.
+func (mc *migrationContext) v1SecretName(kind string, name string) string {
+	if mc.migrator.secretNaming == SecretNamingV1 {
+		return name
+	}
+
+	return strings.ToLower(kind) + "-" + name
+}
+
+// copySecret copies a secret created by ASO v1 so that it outlives the v1 resource, returning the name of the copy.
+// If secrets can't be read (because we're working from files), an issue is reported and the name of the copy is still
+// returned so that the v2 resources can refer to it once it has been created manually.
+func (mc *migrationContext) copySecret(r *v1Resource, secretName string) (string, error) {
+	copyName := secretName + migratedSecretSuffix
+
+	if mc.migrator.secretReader == nil {
+		mc.result.addIssue(
+			r.Kind(),
+			r.Namespace(),
+			r.Name(),
+			fmt.Sprintf("secret %q must be copied to %q manually (no cluster access)", secretName, copyName))
+		return copyName, nil
+	}
+
+	var secret corev1.Secret
+	err := mc.migrator.secretReader.Get(
+		mc.ctx,
+		types.NamespacedName{Namespace: r.Namespace(), Name: secretName},
+		&secret)
+	if apierrors.IsNotFound(err) {
+		mc.result.addIssue(
+			r.Kind(),
+			r.Namespace(),
+			r.Name(),
+			fmt.Sprintf("secret %q not found; check the secret naming version, or whether secrets are stored in Key Vault", secretName))
+		return copyName, nil
+	} else if err != nil {
+		return "", errors.Wrapf(err, "reading secret %s", secretName)
+	}
+
+	migrated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      copyName,
+			Namespace: r.Namespace(),
+		},
+		Data: secret.Data,
+		Type: secret.Type,
+	}
+
+	err = mc.add(migrated)
+	if err != nil {
+		return "", err
+	}
+
+	return copyName, nil
+}
+
+// readSecretValue reads a single value from a secret created by ASO v1, returning false if it can't be read
+func (mc *migrationContext) readSecretValue(namespace string, secretName string, key string) (string, bool) {
+	if mc.migrator.secretReader == nil {
+		return "", false
+	}
+
+	var secret corev1.Secret
+	err := mc.migrator.secretReader.Get(
+		mc.ctx,
+		types.NamespacedName{Namespace: namespace, Name: secretName},
+		&secret)
+	if err != nil {
+		return "", false
+	}
+
+	value, ok := secret.Data[key]
+	return string(value), ok
+}
+
+// secretDestination returns where ASO v2 should write a secret value previously written by v1
+func secretDestination(secretName string, key string) *genruntime.SecretDestination {
+	return &genruntime.SecretDestination{
+		Name: secretName + migratedSecretSuffix,
+		Key:  key,
+	}
+}
+
+// to returns the value of a pointer, or the zero value if the pointer is nil
+func to[T any](ptr *T) T {
+	var result T
+	if ptr != nil {
+		result = *ptr
+	}
+
+	return result
+}
+
+// ptr returns a pointer to the value
+func ptr[T any](value T) *T {
+	return &value
+}
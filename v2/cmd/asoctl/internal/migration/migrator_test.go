@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package migration
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+	cache "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230401"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	sql "github.com/Azure/azure-service-operator/v2/api/sql/v1api20211101"
+	storage "github.com/Azure/azure-service-operator/v2/api/storage/v1api20220901"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+const v1Resources = `
+apiVersion: azure.microsoft.com/v1alpha1
+kind: ResourceGroup
+metadata:
+  name: MyGroup
+  namespace: test
+spec:
+  location: westus2
+---
+apiVersion: azure.microsoft.com/v1alpha1
+kind: StorageAccount
+metadata:
+  name: mystorage
+  namespace: test
+spec:
+  location: westus2
+  resourceGroup: MyGroup
+  sku:
+    name: Standard_LRS
+  kind: StorageV2
+  accessTier: Hot
+  supportsHttpsTrafficOnly: true
+  networkRule:
+    bypass: AzureServices
+---
+apiVersion: azure.microsoft.com/v1beta1
+kind: AzureSqlServer
+metadata:
+  name: mysqlserver
+  namespace: test
+spec:
+  location: eastus
+  resourceGroup: other-group
+`
+
+func migrate(t *testing.T, yaml string, objs ...client.Object) *MigrationResult {
+	g := NewGomegaWithT(t)
+
+	v1, err := LoadV1Resources(strings.NewReader(yaml))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	scheme := api.CreateScheme()
+	var reader SecretReader
+	if len(objs) > 0 {
+		reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	migrator := NewMigrator(scheme, SecretNamingV2, reader, logr.Discard())
+	result, err := migrator.Migrate(context.TODO(), v1)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return result
+}
+
+func findResource[T client.Object](result *MigrationResult, name string) T {
+	var zero T
+	for _, obj := range result.Resources() {
+		if typed, ok := obj.(T); ok && obj.GetName() == name {
+			return typed
+		}
+	}
+
+	return zero
+}
+
+func issueMessages(result *MigrationResult, name string) []string {
+	var messages []string
+	for _, issue := range result.Issues() {
+		if issue.Name == name {
+			messages = append(messages, issue.Message)
+		}
+	}
+
+	return messages
+}
+
+func TestMigrator_StorageAccount_MapsSpecAndSecrets(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	result := migrate(t, v1Resources)
+
+	account := findResource[*storage.StorageAccount](result, "mystorage")
+	g.Expect(account).ToNot(BeNil())
+	g.Expect(account.Annotations).To(HaveKeyWithValue(annotations.ReconcilePolicy, string(annotations.ReconcilePolicySkip)))
+	g.Expect(account.Spec.Owner.Name).To(Equal("mygroup"))
+	g.Expect(*account.Spec.Sku.Name).To(Equal(storage.SkuName("Standard_LRS")))
+	g.Expect(*account.Spec.Kind).To(Equal(storage.StorageAccount_Kind_Spec("StorageV2")))
+	g.Expect(*account.Spec.SupportsHttpsTrafficOnly).To(BeTrue())
+	g.Expect(account.Spec.OperatorSpec.Secrets.Key1.Name).To(Equal("storageaccount-mystorage-migrated"))
+	g.Expect(account.Spec.OperatorSpec.Secrets.Key1.Key).To(Equal("key0"))
+
+	g.Expect(issueMessages(result, "mystorage")).To(ContainElement("spec.networkRule.bypass could not be mapped"))
+}
+
+func TestMigrator_ResourceGroup_UsesAzureName(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	result := migrate(t, v1Resources)
+
+	rg := findResource[*resources.ResourceGroup](result, "mygroup")
+	g.Expect(rg).ToNot(BeNil())
+	g.Expect(rg.Spec.AzureName).To(Equal("MyGroup"))
+	g.Expect(*rg.Spec.Location).To(Equal("westus2"))
+	g.Expect(issueMessages(result, "mygroup")).To(BeEmpty())
+}
+
+func TestMigrator_MissingResourceGroup_IsInferred(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	result := migrate(t, v1Resources)
+
+	rg := findResource[*resources.ResourceGroup](result, "other-group")
+	g.Expect(rg).ToNot(BeNil())
+	g.Expect(*rg.Spec.Location).To(Equal("eastus"))
+	g.Expect(issueMessages(result, "other-group")).To(HaveLen(1))
+}
+
+func TestMigrator_AzureSqlServer_CopiesSecret(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "azuresqlserver-mysqlserver",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("hunter2"),
+		},
+	}
+
+	result := migrate(t, v1Resources, secret)
+
+	server := findResource[*sql.Server](result, "mysqlserver")
+	g.Expect(server).ToNot(BeNil())
+	g.Expect(*server.Spec.AdministratorLogin).To(Equal("admin"))
+	g.Expect(server.Spec.AdministratorLoginPassword.Name).To(Equal("azuresqlserver-mysqlserver-migrated"))
+	g.Expect(server.Spec.AdministratorLoginPassword.Key).To(Equal("password"))
+
+	copied := findResource[*corev1.Secret](result, "azuresqlserver-mysqlserver-migrated")
+	g.Expect(copied).ToNot(BeNil())
+	g.Expect(copied.OwnerReferences).To(BeEmpty())
+	g.Expect(copied.Data).To(HaveKeyWithValue("password", []byte("hunter2")))
+	g.Expect(copied.Annotations).ToNot(HaveKey(annotations.ReconcilePolicy))
+}
+
+func TestMigrator_AzureSqlServer_WithoutCluster_ReportsIssues(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	result := migrate(t, v1Resources)
+
+	g.Expect(findResource[*corev1.Secret](result, "azuresqlserver-mysqlserver-migrated")).To(BeNil())
+	g.Expect(issueMessages(result, "mysqlserver")).To(ContainElement("spec.administratorLogin must be set manually"))
+}
+
+func TestMigrator_MySQL_IsReportedAsUnsupported(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	result := migrate(t, `
+apiVersion: azure.microsoft.com/v1alpha1
+kind: MySQLServer
+metadata:
+  name: mysql
+  namespace: test
+spec:
+  location: westus2
+  resourceGroup: rg
+`)
+
+	g.Expect(result.Count()).To(Equal(0))
+	g.Expect(issueMessages(result, "mysql")).To(HaveLen(1))
+}
+
+func TestRedisConfiguration_ReportsUnknownKeys(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	config, unknown, err := redisConfiguration(map[string]string{
+		"maxmemory-policy": "allkeys-lru",
+		"not-a-setting":    "true",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(config).To(Equal(&cache.RedisCreateProperties_RedisConfiguration{MaxmemoryPolicy: ptr("allkeys-lru")}))
+	g.Expect(unknown).To(Equal([]string{"not-a-setting"}))
+}
+
+func TestMigrationResult_SaveToWriter_IsDeterministic(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var first bytes.Buffer
+	g.Expect(migrate(t, v1Resources).SaveToWriter(&first)).To(Succeed())
+
+	var second bytes.Buffer
+	g.Expect(migrate(t, v1Resources).SaveToWriter(&second)).To(Succeed())
+
+	g.Expect(first.String()).To(Equal(second.String()))
+	g.Expect(first.String()).To(ContainSubstring("kind: StorageAccount"))
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package migration
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LoadV1ResourcesFromFile reads ASO v1 resources from a multi-document YAML file, as produced by kubectl get -o yaml.
+// Lists are expanded into their items.
+func LoadV1ResourcesFromFile(path string) ([]*unstructured.Unstructured, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", path)
+	}
+
+	defer file.Close()
+
+	result, err := LoadV1Resources(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	return result, nil
+}
+
+// LoadV1Resources reads ASO v1 resources from a multi-document YAML stream
+func LoadV1Resources(source io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bufio.NewReader(source), 4096)
+
+	var result []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "decoding YAML")
+		}
+
+		if len(obj.Object) == 0 {
+			// Empty document
+			continue
+		}
+
+		if obj.IsList() {
+			err = obj.EachListItem(func(item runtime.Object) error {
+				result = append(result, item.(*unstructured.Unstructured))
+				return nil
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "expanding list")
+			}
+
+			continue
+		}
+
+		result = append(result, obj)
+	}
+
+	return result, nil
+}
+
+// ListV1Resources lists all the ASO v1 resources in the cluster.
+// If namespace is empty, all namespaces are searched.
+// Kinds whose CRDs aren't installed are skipped.
+func ListV1Resources(ctx context.Context, kubeClient client.Client, namespace string) ([]*unstructured.Unstructured, error) {
+	var result []*unstructured.Unstructured
+	for _, gvk := range V1Kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		var opts []client.ListOption
+		if namespace != "" {
+			opts = append(opts, client.InNamespace(namespace))
+		}
+
+		err := kubeClient.List(ctx, list, opts...)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "listing %s", gvk.Kind)
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			item.SetGroupVersionKind(gvk)
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// V1Group is the API group used by ASO v1 resources
+const V1Group = "azure.microsoft.com"
+
+// V1Kinds lists the ASO v1 kinds that can be migrated, along with the API version to read them with.
+// Kinds served by v1beta1 are read using that version, everything else using v1alpha1.
+var V1Kinds = []schema.GroupVersionKind{
+	{Group: V1Group, Version: "v1alpha1", Kind: "ResourceGroup"},
+	{Group: V1Group, Version: "v1beta1", Kind: "AzureSqlServer"},
+	{Group: V1Group, Version: "v1beta1", Kind: "AzureSqlDatabase"},
+	{Group: V1Group, Version: "v1beta1", Kind: "AzureSqlFirewallRule"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "MySQLServer"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "MySQLDatabase"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "MySQLFirewallRule"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "RedisCache"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "StorageAccount"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "BlobContainer"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "EventhubNamespace"},
+	{Group: V1Group, Version: "v1alpha1", Kind: "Eventhub"},
+}
+
+// v1Resource wraps an ASO v1 custom resource, keeping track of which spec fields have been mapped so that we can
+// report the ones that haven't.
+type v1Resource struct {
+	obj  *unstructured.Unstructured
+	used map[string]bool
+}
+
+func newV1Resource(obj *unstructured.Unstructured) *v1Resource {
+	return &v1Resource{
+		obj:  obj,
+		used: make(map[string]bool),
+	}
+}
+
+// Kind returns the kind of the resource
+func (r *v1Resource) Kind() string {
+	return r.obj.GetKind()
+}
+
+// Name returns the name of the resource; for ASO v1 this is also the name of the resource in Azure
+func (r *v1Resource) Name() string {
+	return r.obj.GetName()
+}
+
+// Namespace returns the namespace of the resource
+func (r *v1Resource) Namespace() string {
+	return r.obj.GetNamespace()
+}
+
+// String returns the string at the specified path within the spec, marking it as used
+func (r *v1Resource) String(path ...string) (string, bool) {
+	value, ok := r.field(path...)
+	if !ok {
+		return "", false
+	}
+
+	s, ok := value.(string)
+	return s, ok && s != ""
+}
+
+// Int returns the integer at the specified path within the spec, marking it as used
+func (r *v1Resource) Int(path ...string) (int, bool) {
+	value, ok := r.field(path...)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case int64:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+
+	return 0, false
+}
+
+// Bool returns the boolean at the specified path within the spec, marking it as used
+func (r *v1Resource) Bool(path ...string) (bool, bool) {
+	value, ok := r.field(path...)
+	if !ok {
+		return false, false
+	}
+
+	b, ok := value.(bool)
+	return b, ok
+}
+
+// StringSlice returns the strings at the specified path within the spec, marking it as used
+func (r *v1Resource) StringSlice(path ...string) []string {
+	value, ok := r.field(path...)
+	if !ok {
+		return nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// StringMap returns the map at the specified path within the spec, marking it as used
+func (r *v1Resource) StringMap(path ...string) map[string]string {
+	value, ok := r.field(path...)
+	if !ok {
+		return nil
+	}
+
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = fmt.Sprint(v)
+	}
+
+	return result
+}
+
+// Use marks the specified path within the spec as used without reading it; use this for fields that are handled
+// implicitly, such as the resource group (which becomes the owner)
+func (r *v1Resource) Use(path ...string) {
+	r.used[strings.Join(path, ".")] = true
+}
+
+// UnmappedFields returns the paths of all populated spec fields that have not been used
+func (r *v1Resource) UnmappedFields() []string {
+	spec, ok := r.obj.Object["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	r.collectUnmapped(spec, nil, &result)
+	sort.Strings(result)
+	return result
+}
+
+func (r *v1Resource) collectUnmapped(value interface{}, path []string, result *[]string) {
+	key := strings.Join(path, ".")
+	if r.used[key] {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			r.collectUnmapped(child, append(path, k), result)
+		}
+	case nil:
+		// Nothing to report
+	case string:
+		if v != "" {
+			*result = append(*result, "spec."+key)
+		}
+	default:
+		*result = append(*result, "spec."+key)
+	}
+}
+
+// field returns the value at the specified path within the spec, marking it as used
+func (r *v1Resource) field(path ...string) (interface{}, bool) {
+	r.Use(path...)
+	value, ok, err := unstructured.NestedFieldNoCopy(r.obj.Object, append([]string{"spec"}, path...)...)
+	return value, ok && err == nil
+}
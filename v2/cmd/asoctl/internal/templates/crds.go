@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package templates
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// LoadCRDsFromFile loads the CRDs from a multi-document YAML file, such as the one published with each ASO release.
+// Documents that aren't CRDs are ignored.
+func LoadCRDsFromFile(path string) ([]apiextensions.CustomResourceDefinition, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", path)
+	}
+
+	defer file.Close()
+
+	crds, err := LoadCRDs(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading CRDs from %s", path)
+	}
+
+	return crds, nil
+}
+
+// LoadCRDs loads the CRDs from a multi-document YAML stream. Documents that aren't CRDs are ignored.
+func LoadCRDs(r io.Reader) ([]apiextensions.CustomResourceDefinition, error) {
+	var result []apiextensions.CustomResourceDefinition
+	reader := yaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "reading YAML")
+		}
+
+		var crd apiextensions.CustomResourceDefinition
+		if err := yaml.Unmarshal(doc, &crd); err != nil {
+			return nil, errors.Wrap(err, "parsing YAML")
+		}
+
+		if crd.Kind != "CustomResourceDefinition" {
+			continue
+		}
+
+		result = append(result, crd)
+	}
+}
+
+// FindCRD returns the CRD defining the specified group and kind, or nil if there isn't one
+func FindCRD(crds []apiextensions.CustomResourceDefinition, gk schema.GroupKind) *apiextensions.CustomResourceDefinition {
+	for i := range crds {
+		crd := &crds[i]
+		if strings.EqualFold(crd.Spec.Group, gk.Group) && strings.EqualFold(crd.Spec.Names.Kind, gk.Kind) {
+			return crd
+		}
+	}
+
+	return nil
+}
+
+// FindCRDInCluster returns the CRD defining the specified group and kind from the cluster, or nil if there isn't one.
+// The CRD is fetched by name (<plural>.<group>); listing the CRDs matching labelSelector is only needed when the plural
+// of the kind isn't the one we'd guess.
+func FindCRDInCluster(
+	ctx context.Context,
+	client apiextensionsclient.CustomResourceDefinitionsGetter,
+	gk schema.GroupKind,
+	labelSelector string,
+) (*apiextensions.CustomResourceDefinition, error) {
+	plural, _ := meta.UnsafeGuessKindToResource(gk.WithVersion(""))
+	name := plural.Resource + "." + gk.Group
+
+	crd, err := client.CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if err == nil && strings.EqualFold(crd.Spec.Names.Kind, gk.Kind) {
+		return crd, nil
+	}
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "getting CRD %s", name)
+	}
+
+	crds, err := client.CustomResourceDefinitions().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing CRDs")
+	}
+
+	return FindCRD(crds.Items, gk), nil
+}
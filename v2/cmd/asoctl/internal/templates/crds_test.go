@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package templates
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newCRD(group string, kind string, plural string) *apiextensions.CustomResourceDefinition {
+	return &apiextensions.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   plural + "." + group,
+			Labels: map[string]string{"app.kubernetes.io/name": "azure-service-operator"},
+		},
+		Spec: apiextensions.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensions.CustomResourceDefinitionNames{
+				Kind:   kind,
+				Plural: plural,
+			},
+		},
+	}
+}
+
+func TestFindCRDInCluster_WhenPluralMatchesKind_GetsCRDWithoutListing(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client := fake.NewSimpleClientset(newCRD("storage.azure.com", "StorageAccount", "storageaccounts"))
+	client.PrependReactor("list", "customresourcedefinitions", func(k8stesting.Action) (bool, runtime.Object, error) {
+		t.Error("expected CRD to be fetched by name, without listing")
+		return false, nil, nil
+	})
+
+	crd, err := FindCRDInCluster(
+		context.Background(),
+		client.ApiextensionsV1(),
+		schema.GroupKind{Group: "storage.azure.com", Kind: "StorageAccount"},
+		"app.kubernetes.io/name=azure-service-operator")
+	g.Expect(err).To(Succeed())
+	g.Expect(crd).NotTo(BeNil())
+	g.Expect(crd.Name).To(Equal("storageaccounts.storage.azure.com"))
+}
+
+func TestFindCRDInCluster_WhenPluralIrregular_FallsBackToListing(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client := fake.NewSimpleClientset(newCRD("cache.azure.com", "Redis", "redis"))
+
+	crd, err := FindCRDInCluster(
+		context.Background(),
+		client.ApiextensionsV1(),
+		schema.GroupKind{Group: "cache.azure.com", Kind: "Redis"},
+		"app.kubernetes.io/name=azure-service-operator")
+	g.Expect(err).To(Succeed())
+	g.Expect(crd).NotTo(BeNil())
+	g.Expect(crd.Name).To(Equal("redis.cache.azure.com"))
+}
+
+func TestFindCRDInCluster_WhenNotInstalled_ReturnsNil(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client := fake.NewSimpleClientset()
+
+	crd, err := FindCRDInCluster(
+		context.Background(),
+		client.ApiextensionsV1(),
+		schema.GroupKind{Group: "storage.azure.com", Kind: "StorageAccount"},
+		"app.kubernetes.io/name=azure-service-operator")
+	g.Expect(err).To(Succeed())
+	g.Expect(crd).To(BeNil())
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package templates
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// azureGroupSuffix is appended to groups given without a domain, allowing "storage" as shorthand for "storage.azure.com"
+const azureGroupSuffix = ".azure.com"
+
+// FindGroupVersionKind finds the GVK of a resource in the scheme.
+// resource is of the form <group>/<kind>; the group may omit the .azure.com suffix and the kind is case-insensitive.
+// version is optional; if empty, the latest stable version is used, falling back to the latest preview version.
+func FindGroupVersionKind(scheme *runtime.Scheme, resource string, version string) (schema.GroupVersionKind, error) {
+	group, kind, ok := strings.Cut(resource, "/")
+	if !ok || group == "" || kind == "" {
+		return schema.GroupVersionKind{}, errors.Errorf("expected resource of the form <group>/<kind>, but got %q", resource)
+	}
+
+	if !strings.Contains(group, ".") {
+		group += azureGroupSuffix
+	}
+
+	var candidates []schema.GroupVersionKind
+	for gvk := range scheme.AllKnownTypes() {
+		if !strings.EqualFold(gvk.Group, group) || !strings.EqualFold(gvk.Kind, kind) {
+			continue
+		}
+
+		if strings.HasSuffix(gvk.Version, "storage") {
+			// Storage versions are an implementation detail, never written by users
+			continue
+		}
+
+		candidates = append(candidates, gvk)
+	}
+
+	if len(candidates) == 0 {
+		return schema.GroupVersionKind{}, errors.Errorf("no resource %s found in group %s", kind, group)
+	}
+
+	if version != "" {
+		for _, gvk := range candidates {
+			if gvk.Version == version {
+				return gvk, nil
+			}
+		}
+
+		return schema.GroupVersionKind{}, errors.Errorf(
+			"version %s of %s/%s not found; available versions are %s",
+			version,
+			candidates[0].Group,
+			candidates[0].Kind,
+			strings.Join(versions(candidates), ", "))
+	}
+
+	return latest(candidates), nil
+}
+
+// latest returns the latest stable version, or the latest preview version if there are no stable versions
+func latest(candidates []schema.GroupVersionKind) schema.GroupVersionKind {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Version < candidates[j].Version
+	})
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if !isPreview(candidates[i].Version) {
+			return candidates[i]
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
+
+func isPreview(version string) bool {
+	return strings.Contains(version, "preview")
+}
+
+func versions(candidates []schema.GroupVersionKind) []string {
+	result := make([]string, 0, len(candidates))
+	for _, gvk := range candidates {
+		result = append(result, gvk.Version)
+	}
+
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,418 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// maxDepth limits how deeply nested properties are expanded, keeping the skeleton readable
+const maxDepth = 8
+
+// maxDescriptionLength limits the length of descriptions included as comments
+const maxDescriptionLength = 120
+
+var (
+	knownResourceReferenceType  = reflect.TypeOf(genruntime.KnownResourceReference{})
+	resourceReferenceType       = reflect.TypeOf(genruntime.ResourceReference{})
+	arbitraryOwnerReferenceType = reflect.TypeOf(genruntime.ArbitraryOwnerReference{})
+	secretReferenceType         = reflect.TypeOf(genruntime.SecretReference{})
+	secretDestinationType       = reflect.TypeOf(genruntime.SecretDestination{})
+	configMapReferenceType      = reflect.TypeOf(genruntime.ConfigMapReference{})
+	configMapDestinationType    = reflect.TypeOf(genruntime.ConfigMapDestination{})
+)
+
+// WriteSkeleton writes a commented YAML skeleton for a resource of the specified kind.
+// Required fields are included; optional fields are commented out.
+// crd is optional; if provided, its schema is used to identify required fields, allowed values and descriptions
+// (these come from kubebuilder markers which are only available in the CRD). Without it, only the owner is treated
+// as required.
+func WriteSkeleton(
+	w io.Writer,
+	scheme *runtime.Scheme,
+	gvk schema.GroupVersionKind,
+	crd *apiextensions.CustomResourceDefinition,
+) error {
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		return errors.Wrapf(err, "creating %s", gvk)
+	}
+
+	specField, ok := reflect.TypeOf(obj).Elem().FieldByName("Spec")
+	if !ok {
+		return errors.Errorf("%s has no spec", gvk)
+	}
+
+	s := &skeleton{}
+	s.add(0, false, false, "apiVersion: "+gvk.GroupVersion().String())
+	s.add(0, false, false, "kind: "+gvk.Kind)
+	s.add(0, false, false, "metadata:")
+	s.add(1, false, false, "name: sample-"+strings.ToLower(gvk.Kind))
+	s.add(1, false, false, "namespace: default")
+	s.add(0, false, false, "spec:")
+	s.writeStruct(indirect(specField.Type), specSchema(crd, gvk.Version), 1, false)
+
+	return s.writeTo(w)
+}
+
+// skeleton accumulates the lines of a YAML skeleton
+type skeleton struct {
+	lines []line
+	stack []reflect.Type // Types being expanded, used to detect recursion
+}
+
+// line is a single line of YAML
+type line struct {
+	indent     int
+	commented  bool // Content commented out because it's optional
+	annotation bool // A comment describing the content that follows
+	text       string
+}
+
+func (s *skeleton) add(indent int, commented bool, annotation bool, text string) {
+	s.lines = append(s.lines, line{indent: indent, commented: commented, annotation: annotation, text: text})
+}
+
+func (s *skeleton) comment(indent int, text string) {
+	s.add(indent, false, true, text)
+}
+
+func (s *skeleton) writeTo(w io.Writer) error {
+	for _, l := range s.lines {
+		var b strings.Builder
+		b.WriteString(strings.Repeat("  ", l.indent))
+		if l.commented || l.annotation {
+			b.WriteString("# ")
+		}
+
+		b.WriteString(l.text)
+		b.WriteString("\n")
+		_, err := io.WriteString(w, b.String())
+		if err != nil {
+			return errors.Wrap(err, "writing skeleton")
+		}
+	}
+
+	return nil
+}
+
+// writeStruct writes the fields of a struct
+func (s *skeleton) writeStruct(t reflect.Type, props *apiextensions.JSONSchemaProps, indent int, commented bool) {
+	s.stack = append(s.stack, t)
+	defer func() { s.stack = s.stack[:len(s.stack)-1] }()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if !field.IsExported() || name == "" {
+			continue
+		}
+
+		var fieldProps *apiextensions.JSONSchemaProps
+		required := false
+		if props != nil {
+			if p, ok := props.Properties[name]; ok {
+				fieldProps = &p
+			}
+
+			required = containsString(props.Required, name)
+		} else if field.Type == reflect.PtrTo(knownResourceReferenceType) && name == "owner" {
+			// Without a schema, we can't know what's required; assume every resource needs an owner
+			required = true
+		}
+
+		s.writeField(field, name, fieldProps, required, indent, commented || !required)
+	}
+}
+
+// writeField writes a single field, preceded by comments describing it
+func (s *skeleton) writeField(
+	field reflect.StructField,
+	name string,
+	props *apiextensions.JSONSchemaProps,
+	required bool,
+	indent int,
+	commented bool,
+) {
+	t := indirect(field.Type)
+
+	if description := summarize(props); description != "" {
+		s.comment(indent, description)
+	}
+
+	if required {
+		s.comment(indent, "Required")
+	}
+
+	if values := enumValues(props); len(values) > 0 {
+		s.comment(indent, "Allowed values: "+strings.Join(values, ", "))
+	}
+
+	switch t {
+	case knownResourceReferenceType:
+		s.writeKnownReference(field, name, indent, commented)
+		return
+	case resourceReferenceType, arbitraryOwnerReferenceType:
+		s.writeReference(name, indent, commented)
+		return
+	case secretReferenceType:
+		s.writeNameKey(name, "Secret containing the value", indent, commented)
+		return
+	case configMapReferenceType:
+		s.writeNameKey(name, "Config map containing the value", indent, commented)
+		return
+	case secretDestinationType:
+		s.writeNameKey(name, fmt.Sprintf("Secret to write %s to", name), indent, commented)
+		return
+	case configMapDestinationType:
+		s.writeNameKey(name, fmt.Sprintf("Config map to write %s to", name), indent, commented)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if s.isExpanding(t) || len(s.stack) >= maxDepth || t.NumField() == 0 {
+			s.add(indent, commented, false, name+": {}")
+			return
+		}
+
+		s.add(indent, commented, false, name+":")
+		s.writeStruct(t, props, indent+1, commented)
+	case reflect.Slice:
+		s.writeSlice(t, name, props, indent, commented)
+	case reflect.Map:
+		s.add(indent, commented, false, name+": {}")
+	default:
+		s.add(indent, commented, false, name+": "+placeholder(t))
+	}
+}
+
+// writeKnownReference writes a reference to an owner of a known kind, showing both forms of reference
+func (s *skeleton) writeKnownReference(field reflect.StructField, name string, indent int, commented bool) {
+	if kind := field.Tag.Get("kind"); kind != "" {
+		s.comment(indent, fmt.Sprintf("Reference to a %s (%s) in the same namespace", kind, field.Tag.Get("group")))
+	}
+
+	s.add(indent, commented, false, name+":")
+	s.add(indent+1, commented, false, `name: ""`)
+	s.comment(indent+1, "Or, to refer to an existing Azure resource not managed by ASO, replace name with:")
+	s.add(indent+1, true, false, `armId: ""`)
+}
+
+// writeReference writes a reference to a resource of any kind, showing both forms of reference
+func (s *skeleton) writeReference(name string, indent int, commented bool) {
+	s.add(indent, commented, false, name+":")
+	s.comment(indent+1, "Reference to a resource in the same namespace")
+	s.add(indent+1, commented, false, `group: ""`)
+	s.add(indent+1, commented, false, `kind: ""`)
+	s.add(indent+1, commented, false, `name: ""`)
+	s.comment(indent+1, "Or, to refer to an existing Azure resource not managed by ASO, replace group, kind and name with:")
+	s.add(indent+1, true, false, `armId: ""`)
+}
+
+// writeNameKey writes a reference to (or destination in) a secret or config map
+func (s *skeleton) writeNameKey(name string, description string, indent int, commented bool) {
+	s.comment(indent, description)
+	s.add(indent, commented, false, name+":")
+	s.add(indent+1, commented, false, `name: ""`)
+	s.add(indent+1, commented, false, `key: ""`)
+}
+
+// writeSlice writes a slice, with a single element showing its structure
+func (s *skeleton) writeSlice(t reflect.Type, name string, props *apiextensions.JSONSchemaProps, indent int, commented bool) {
+	elem := indirect(t.Elem())
+	if elem.Kind() != reflect.Struct || s.isExpanding(elem) || len(s.stack) >= maxDepth {
+		if elem.Kind() == reflect.Struct || elem.Kind() == reflect.Map {
+			s.add(indent, commented, false, name+": []")
+			return
+		}
+
+		s.add(indent, commented, false, name+":")
+		s.add(indent+1, commented, false, "- "+placeholder(elem))
+		return
+	}
+
+	var itemProps *apiextensions.JSONSchemaProps
+	if props != nil && props.Items != nil {
+		itemProps = props.Items.Schema
+	}
+
+	// Write the element, then turn its first line of content into the start of a list item
+	item := &skeleton{stack: s.stack}
+	item.writeStruct(elem, itemProps, indent+2, commented)
+
+	first := -1
+	for i, l := range item.lines {
+		if !l.annotation && (!l.commented || commented) {
+			first = i
+			break
+		}
+	}
+
+	if first == -1 {
+		// Every field of the element is optional, so the whole list is too
+		commented = true
+		for i := range item.lines {
+			item.lines[i].commented = item.lines[i].commented || !item.lines[i].annotation
+		}
+
+		for i, l := range item.lines {
+			if !l.annotation {
+				first = i
+				break
+			}
+		}
+	}
+
+	s.add(indent, commented, false, name+":")
+	if first == -1 {
+		return
+	}
+
+	item.lines[first].indent--
+	item.lines[first].text = "- " + item.lines[first].text
+	s.lines = append(s.lines, item.lines...)
+}
+
+// isExpanding returns true if the type is already being expanded, meaning it is recursive
+func (s *skeleton) isExpanding(t reflect.Type) bool {
+	for _, st := range s.stack {
+		if st == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// specSchema returns the schema of the spec for the specified version, if available
+func specSchema(crd *apiextensions.CustomResourceDefinition, version string) *apiextensions.JSONSchemaProps {
+	if crd == nil {
+		return nil
+	}
+
+	for _, v := range crd.Spec.Versions {
+		if v.Name != version || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		if spec, ok := v.Schema.OpenAPIV3Schema.Properties["spec"]; ok {
+			return &spec
+		}
+	}
+
+	return nil
+}
+
+// summarize returns the first sentence of the description, if any
+func summarize(props *apiextensions.JSONSchemaProps) string {
+	if props == nil {
+		return ""
+	}
+
+	description := strings.TrimSpace(props.Description)
+	if i := strings.Index(description, "\n"); i >= 0 {
+		description = description[:i]
+	}
+
+	// Generated descriptions are prefixed with the property name, and often with whether it's required; we show that
+	// separately
+	if name, rest, ok := strings.Cut(description, ": "); ok && !strings.Contains(name, " ") {
+		description = rest
+	}
+
+	description = strings.TrimPrefix(description, "Required. ")
+
+	if i := strings.Index(description, ". "); i >= 0 {
+		description = description[:i+1]
+	}
+
+	if len(description) > maxDescriptionLength {
+		description = description[:maxDescriptionLength-3] + "..."
+	}
+
+	return description
+}
+
+// enumValues returns the allowed values of the property, if restricted
+func enumValues(props *apiextensions.JSONSchemaProps) []string {
+	if props == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(props.Enum))
+	for _, raw := range props.Enum {
+		var value any
+		if err := json.Unmarshal(raw.Raw, &value); err != nil {
+			continue
+		}
+
+		result = append(result, fmt.Sprint(value))
+	}
+
+	return result
+}
+
+// placeholder returns an empty value of the right type for a primitive
+func placeholder(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "0"
+	case reflect.Float32, reflect.Float64:
+		return "0.0"
+	case reflect.String:
+		return `""`
+	default:
+		return "{}"
+	}
+}
+
+// jsonName returns the name of the field in JSON, or "" if it isn't serialized
+func jsonName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package templates
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+	storage "github.com/Azure/azure-service-operator/v2/api/storage/v1api20220901"
+)
+
+// storageAccountCRD is a cut down CRD with just enough schema to test use of required fields, enums and descriptions
+const storageAccountCRD = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ignored
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: storageaccounts.storage.azure.com
+spec:
+  group: storage.azure.com
+  names:
+    kind: StorageAccount
+    plural: storageaccounts
+  scope: Namespaced
+  versions:
+  - name: v1api20220901
+    served: true
+    storage: false
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required:
+            - location
+            - owner
+            properties:
+              kind:
+                description: "Kind: Required. Indicates the type of storage account."
+                type: string
+                enum:
+                - BlobStorage
+                - StorageV2
+              location:
+                description: "Location: Required. Gets or sets the location of the resource."
+                type: string
+              owner:
+                type: object
+              sku:
+                type: object
+                required:
+                - name
+                properties:
+                  name:
+                    type: string
+`
+
+func loadStorageAccountCRD(g *WithT) []byte {
+	crds, err := LoadCRDs(strings.NewReader(storageAccountCRD))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crds).To(HaveLen(1))
+
+	crd := FindCRD(crds, schema.GroupKind{Group: "storage.azure.com", Kind: "storageaccount"})
+	g.Expect(crd).ToNot(BeNil())
+
+	var buffer bytes.Buffer
+	gvk := storage.GroupVersion.WithKind("StorageAccount")
+	g.Expect(WriteSkeleton(&buffer, api.CreateScheme(), gvk, crd)).To(Succeed())
+
+	return buffer.Bytes()
+}
+
+func TestWriteSkeleton_WithCRD_IncludesRequiredFields(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	skeleton := string(loadStorageAccountCRD(g))
+
+	g.Expect(skeleton).To(ContainSubstring("\n  location: \"\"\n"))
+	g.Expect(skeleton).To(ContainSubstring("\n  owner:\n    name: \"\"\n"))
+	g.Expect(skeleton).To(ContainSubstring("\n  # Gets or sets the location of the resource.\n  # Required\n"))
+
+	// Optional fields are commented out, as are the required fields within them
+	g.Expect(skeleton).To(ContainSubstring("\n  # sku:\n    # Required\n    # name: \"\"\n"))
+}
+
+func TestWriteSkeleton_WithCRD_ShowsAllowedValues(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	skeleton := string(loadStorageAccountCRD(g))
+
+	g.Expect(skeleton).To(ContainSubstring("  # Indicates the type of storage account.\n  # Allowed values: BlobStorage, StorageV2\n  # kind: \"\"\n"))
+}
+
+func TestWriteSkeleton_ShowsReferencesAndSecretDestinations(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	skeleton := string(loadStorageAccountCRD(g))
+
+	g.Expect(skeleton).To(ContainSubstring("# Reference to a ResourceGroup (resources.azure.com) in the same namespace"))
+	g.Expect(skeleton).To(ContainSubstring("    # armId: \"\"\n"))
+	g.Expect(skeleton).To(ContainSubstring("# Secret to write key1 to\n      # key1:\n        # name: \"\"\n        # key: \"\"\n"))
+	g.Expect(skeleton).To(ContainSubstring("# group: \"\"\n"))
+}
+
+func TestWriteSkeleton_IsValidManifest(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	scheme := api.CreateScheme()
+	skeleton := loadStorageAccountCRD(g)
+
+	// Use a strict decoder to ensure every uncommented field is known
+	decoder := serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDeserializer()
+	obj, _, err := decoder.Decode(skeleton, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	account, ok := obj.(*storage.StorageAccount)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(account.Name).To(Equal("sample-storageaccount"))
+	g.Expect(account.Spec.Owner).ToNot(BeNil())
+}
+
+func TestWriteSkeleton_WithoutCRD_TreatsOwnerAsRequired(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var buffer bytes.Buffer
+	gvk := storage.GroupVersion.WithKind("StorageAccount")
+	g.Expect(WriteSkeleton(&buffer, api.CreateScheme(), gvk, nil)).To(Succeed())
+
+	g.Expect(buffer.String()).To(ContainSubstring("\n  owner:\n    name: \"\"\n"))
+	g.Expect(buffer.String()).To(ContainSubstring("\n  # location: \"\"\n"))
+}
+
+func TestFindGroupVersionKind(t *testing.T) {
+	t.Parallel()
+
+	scheme := api.CreateScheme()
+
+	cases := map[string]struct {
+		resource        string
+		version         string
+		expectedVersion string
+		expectedError   string
+	}{
+		"Short group selects latest stable version": {
+			resource:        "resources/ResourceGroup",
+			expectedVersion: "v1api20200601",
+		},
+		"Full group and lowercase kind": {
+			resource:        "storage.azure.com/storageaccount",
+			version:         "v1api20210401",
+			expectedVersion: "v1api20210401",
+		},
+		"Storage versions are excluded": {
+			resource:      "storage/StorageAccount",
+			version:       "v1api20220901storage",
+			expectedError: "available versions are",
+		},
+		"Unknown kind": {
+			resource:      "storage/Bucket",
+			expectedError: "no resource Bucket found in group storage.azure.com",
+		},
+		"Missing kind": {
+			resource:      "storage",
+			expectedError: "expected resource of the form <group>/<kind>",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			gvk, err := FindGroupVersionKind(scheme, c.resource, c.version)
+			if c.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(c.expectedError)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(gvk.Version).To(Equal(c.expectedVersion))
+		})
+	}
+}
@@ -0,0 +1,338 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tree
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+// Builder constructs the ownership tree around an ASO resource
+type Builder struct {
+	kubeClient   kubeclient.Client
+	resolver     *resolver.Resolver
+	armClient    *genericarmclient.GenericClient
+	storageTypes map[schema.GroupKind]schema.GroupVersionKind
+	log          logr.Logger
+	// objects caches every ASO resource found in each namespace, used to find children
+	objects map[string][]genruntime.ARMMetaObject
+}
+
+// NewBuilder creates a new Builder.
+// kubeClient is used to find resources in the cluster.
+// armClient is optional; if provided, the existence of each resource in Azure is checked.
+func NewBuilder(
+	kubeClient client.Client,
+	armClient *genericarmclient.GenericClient,
+	log logr.Logger,
+) (*Builder, error) {
+	cl := kubeclient.NewClient(kubeClient)
+	storageTypes := findStorageTypes(cl.Scheme())
+
+	res := resolver.NewResolver(cl)
+	registrations := make([]*registration.StorageType, 0, len(storageTypes))
+	for _, gvk := range storageTypes {
+		obj, err := cl.Scheme().New(gvk)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating %s", gvk)
+		}
+
+		registrations = append(registrations, &registration.StorageType{Obj: obj.(client.Object)})
+	}
+
+	err := res.IndexStorageTypes(cl.Scheme(), registrations)
+	if err != nil {
+		return nil, errors.Wrap(err, "indexing storage types")
+	}
+
+	return &Builder{
+		kubeClient:   cl,
+		resolver:     res,
+		armClient:    armClient,
+		storageTypes: storageTypes,
+		log:          log,
+		objects:      make(map[string][]genruntime.ARMMetaObject),
+	}, nil
+}
+
+// FindGroupVersionKind finds the storage GVK for the kind specified by the user.
+// kind may be a bare kind (e.g. ResourceGroup) or qualified by group (e.g. ResourceGroup.resources.azure.com);
+// matching is case-insensitive.
+func (b *Builder) FindGroupVersionKind(kind string) (schema.GroupVersionKind, error) {
+	k, group, qualified := strings.Cut(kind, ".")
+
+	var matches []schema.GroupVersionKind
+	for gk, gvk := range b.storageTypes {
+		if !strings.EqualFold(gk.Kind, k) {
+			continue
+		}
+
+		if qualified && !strings.EqualFold(gk.Group, group) {
+			continue
+		}
+
+		matches = append(matches, gvk)
+	}
+
+	if len(matches) == 0 {
+		return schema.GroupVersionKind{}, errors.Errorf("no ASO resource kind matches %q", kind)
+	}
+
+	if len(matches) > 1 {
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, m.GroupKind().String())
+		}
+
+		sort.Strings(names)
+		return schema.GroupVersionKind{}, errors.Errorf(
+			"kind %q is ambiguous, qualify it with a group: %s",
+			kind,
+			strings.Join(names, ", "))
+	}
+
+	return matches[0], nil
+}
+
+// Build returns the root of a tree containing all the owners of the specified resource, the resource itself,
+// and all the resources it (transitively) owns.
+func (b *Builder) Build(ctx context.Context, gvk schema.GroupVersionKind, name types.NamespacedName) (*Node, error) {
+	obj, err := b.kubeClient.GetObject(ctx, name, gvk)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %s %s", gvk.Kind, name)
+	}
+
+	armObj, ok := obj.(genruntime.ARMMetaObject)
+	if !ok {
+		return nil, errors.Errorf("%s %s is not an ARM resource", gvk.Kind, name)
+	}
+
+	target := newObjectNode(armObj)
+	target.Target = true
+
+	err = b.addChildren(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := b.addOwners(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.armClient != nil {
+		root.Walk(func(node *Node) {
+			b.checkAzure(ctx, node)
+		})
+	}
+
+	return root, nil
+}
+
+// addOwners walks up the ownership chain of node, returning the topmost node.
+// This uses the same owner resolution as resolver.ResolveResourceHierarchy, but keeps going when an owner is missing
+// so that the user can see where the chain is broken.
+func (b *Builder) addOwners(ctx context.Context, node *Node) (*Node, error) {
+	current := node
+	for current.Object != nil {
+		owner := current.Object.Owner()
+		details, err := b.resolver.ResolveOwner(ctx, current.Object)
+		if err != nil {
+			var notFound *core.ReferenceNotFound
+			if errors.As(err, &notFound) {
+				missing := newMissingNode(owner)
+				missing.Children = []*Node{current}
+				return missing, nil
+			}
+
+			return nil, errors.Wrapf(err, "resolving owner of %s %s", current.Kind, current.Name)
+		}
+
+		var parent *Node
+		switch details.Result {
+		case resolver.OwnerNotExpected:
+			return current, nil
+		case resolver.OwnerFoundARM:
+			parent = newARMNode(details.ARMID)
+		case resolver.OwnerFoundKubernetes:
+			parent = newObjectNode(details.Owner)
+		default:
+			return nil, errors.Errorf("unexpected owner result %q", details.Result)
+		}
+
+		parent.Children = []*Node{current}
+		current = parent
+	}
+
+	return current, nil
+}
+
+// addChildren recursively finds all the resources owned by node
+func (b *Builder) addChildren(ctx context.Context, node *Node) error {
+	objects, err := b.objectsInNamespace(ctx, node.Object.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	gvk := node.Object.GetObjectKind().GroupVersionKind()
+	for _, obj := range objects {
+		owner := obj.Owner()
+		if owner == nil {
+			continue
+		}
+
+		isChild := false
+		if owner.IsDirectARMReference() {
+			isChild = node.ARMID != "" && strings.EqualFold(owner.ARMID, node.ARMID)
+		} else {
+			isChild = owner.Name == node.Object.GetName() &&
+				owner.Kind == gvk.Kind &&
+				owner.Group == gvk.Group
+		}
+
+		if !isChild {
+			continue
+		}
+
+		child := newObjectNode(obj)
+		err = b.addChildren(ctx, child)
+		if err != nil {
+			return err
+		}
+
+		node.Children = append(node.Children, child)
+	}
+
+	sort.Slice(node.Children, func(i, j int) bool {
+		left := node.Children[i]
+		right := node.Children[j]
+		if left.Kind != right.Kind {
+			return left.Kind < right.Kind
+		}
+
+		return left.Name < right.Name
+	})
+
+	return nil
+}
+
+// objectsInNamespace returns all the ASO resources in the specified namespace.
+// Kinds whose CRDs aren't installed in the cluster are skipped.
+func (b *Builder) objectsInNamespace(ctx context.Context, namespace string) ([]genruntime.ARMMetaObject, error) {
+	if objs, ok := b.objects[namespace]; ok {
+		return objs, nil
+	}
+
+	var result []genruntime.ARMMetaObject
+	for _, gvk := range b.storageTypes {
+		listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+		obj, err := b.kubeClient.Scheme().New(listGVK)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating %s", listGVK)
+		}
+
+		list, ok := obj.(client.ObjectList)
+		if !ok {
+			return nil, errors.Errorf("%s is not a list", listGVK)
+		}
+
+		err = b.kubeClient.List(ctx, list, client.InNamespace(namespace))
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			// CRD not installed, nothing to find
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "listing %s in namespace %s", gvk.Kind, namespace)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, errors.Wrapf(err, "extracting %s", listGVK)
+		}
+
+		for _, item := range items {
+			if armObj, ok := item.(genruntime.ARMMetaObject); ok {
+				// Lists don't populate the TypeMeta of their items
+				armObj.GetObjectKind().SetGroupVersionKind(gvk)
+				result = append(result, armObj)
+			}
+		}
+	}
+
+	b.log.V(1).Info(
+		"Found resources",
+		"namespace", namespace,
+		"count", len(result))
+
+	b.objects[namespace] = result
+	return result, nil
+}
+
+// checkAzure checks whether the resource represented by node exists in Azure
+func (b *Builder) checkAzure(ctx context.Context, node *Node) {
+	if node.Object == nil || node.ARMID == "" {
+		// ARM ID owners have no API version we can use, and resources without an ARM ID haven't been created yet
+		node.Azure = AzureStateUnknown
+		return
+	}
+
+	exists, _, err := b.armClient.CheckExistenceWithGetByID(ctx, node.ARMID, node.Object.GetAPIVersion())
+	if err != nil {
+		b.log.Error(err, "Unable to check existence in Azure", "id", node.ARMID)
+		node.Azure = AzureStateError
+		return
+	}
+
+	if exists {
+		node.Azure = AzureStateExists
+	} else {
+		node.Azure = AzureStateMissing
+	}
+}
+
+// findStorageTypes returns the storage (hub) GVK for each ASO resource kind in the scheme
+func findStorageTypes(scheme *runtime.Scheme) map[schema.GroupKind]schema.GroupVersionKind {
+	result := make(map[schema.GroupKind]schema.GroupVersionKind)
+	for gvk := range scheme.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Group, ".azure.com") {
+			continue
+		}
+
+		obj, err := scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := obj.(conversion.Hub); !ok {
+			continue
+		}
+
+		if _, ok := obj.(genruntime.ARMMetaObject); !ok {
+			continue
+		}
+
+		result[gvk.GroupKind()] = gvk
+	}
+
+	return result
+}
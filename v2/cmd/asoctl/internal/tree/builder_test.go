@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tree
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601/storage"
+	storage "github.com/Azure/azure-service-operator/v2/api/storage/v1api20220901storage"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+const namespace = "test"
+
+func newResourceGroup(name string) *resources.ResourceGroup {
+	return &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status: resources.ResourceGroup_STATUS{
+			Conditions: []conditions.Condition{
+				{
+					Type:   conditions.ConditionTypeReady,
+					Status: metav1.ConditionTrue,
+					Reason: conditions.ReasonSucceeded,
+				},
+			},
+		},
+	}
+}
+
+func newStorageAccount(name string, owner *genruntime.KnownResourceReference) *storage.StorageAccount {
+	return &storage.StorageAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: storage.StorageAccount_Spec{
+			Owner: owner,
+		},
+		Status: storage.StorageAccount_STATUS{
+			Conditions: []conditions.Condition{
+				{
+					Type:     conditions.ConditionTypeReady,
+					Status:   metav1.ConditionFalse,
+					Severity: conditions.ConditionSeverityWarning,
+					Reason:   conditions.ReasonWaitingForOwner.Name,
+				},
+			},
+		},
+	}
+}
+
+func newBlobService(owner string) *storage.StorageAccountsBlobService {
+	return &storage.StorageAccountsBlobService{
+		ObjectMeta: metav1.ObjectMeta{Name: owner + "-blob", Namespace: namespace},
+		Spec: storage.StorageAccounts_BlobService_Spec{
+			Owner: &genruntime.KnownResourceReference{Name: owner},
+		},
+	}
+}
+
+func newTestBuilder(t *testing.T, objs ...client.Object) *Builder {
+	g := NewGomegaWithT(t)
+
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(api.CreateScheme()).
+		WithObjects(objs...).
+		Build()
+
+	builder, err := NewBuilder(kubeClient, nil, logr.Discard())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return builder
+}
+
+func TestBuilder_FindGroupVersionKind(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	builder := newTestBuilder(t)
+
+	gvk, err := builder.FindGroupVersionKind("resourcegroup")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gvk.Group).To(Equal("resources.azure.com"))
+
+	gvk, err = builder.FindGroupVersionKind("StorageAccount.storage.azure.com")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gvk.Kind).To(Equal("StorageAccount"))
+
+	_, err = builder.FindGroupVersionKind("NotAKind")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestBuilder_Build_IncludesOwnersAndChildren(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	rg := newResourceGroup("rg")
+	account := newStorageAccount("account", &genruntime.KnownResourceReference{Name: "rg"})
+	blob := newBlobService("account")
+	other := newStorageAccount("other", &genruntime.KnownResourceReference{Name: "another-rg"})
+
+	builder := newTestBuilder(t, rg, account, blob, other)
+
+	gvk, err := builder.FindGroupVersionKind("StorageAccount")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	root, err := builder.Build(context.TODO(), gvk, types.NamespacedName{Namespace: namespace, Name: "account"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(root.Kind).To(Equal("ResourceGroup"))
+	g.Expect(root.Name).To(Equal("rg"))
+	g.Expect(root.Children).To(HaveLen(1))
+
+	target := root.Children[0]
+	g.Expect(target.Target).To(BeTrue())
+	g.Expect(target.Name).To(Equal("account"))
+	g.Expect(target.Children).To(HaveLen(1))
+	g.Expect(target.Children[0].Kind).To(Equal("StorageAccountsBlobService"))
+}
+
+func TestBuilder_Build_ShowsMissingOwner(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	account := newStorageAccount("account", &genruntime.KnownResourceReference{Name: "missing-rg"})
+	builder := newTestBuilder(t, account)
+
+	gvk, err := builder.FindGroupVersionKind("StorageAccount")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	root, err := builder.Build(context.TODO(), gvk, types.NamespacedName{Namespace: namespace, Name: "account"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(root.Missing).To(BeTrue())
+	g.Expect(root.Name).To(Equal("missing-rg"))
+	g.Expect(root.Children).To(HaveLen(1))
+}
+
+func TestBuilder_Build_ShowsARMIDOwner(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	armID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg"
+	account := newStorageAccount("account", &genruntime.KnownResourceReference{ARMID: armID})
+	builder := newTestBuilder(t, account)
+
+	gvk, err := builder.FindGroupVersionKind("StorageAccount")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	root, err := builder.Build(context.TODO(), gvk, types.NamespacedName{Namespace: namespace, Name: "account"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(root.ARMID).To(Equal(armID))
+	g.Expect(root.Object).To(BeNil())
+	g.Expect(root.Children).To(HaveLen(1))
+}
+
+func TestPrint_WritesTree(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	now := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	rg := newResourceGroup("rg")
+	rg.CreationTimestamp = metav1.NewTime(now.Add(-3 * time.Hour))
+	account := newStorageAccount("account", &genruntime.KnownResourceReference{Name: "rg"})
+	account.CreationTimestamp = metav1.NewTime(now.Add(-5 * time.Minute))
+
+	root := newObjectNode(rg)
+	root.Kind = "ResourceGroup"
+	target := newObjectNode(account)
+	target.Kind = "StorageAccount"
+	target.Target = true
+	root.Children = []*Node{target}
+
+	var buffer bytes.Buffer
+	err := Print(&buffer, root, now)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(buffer.String()).To(Equal(
+		"NAME                          READY  SEVERITY  REASON           AGE\n" +
+			"ResourceGroup/rg              True   -         Succeeded        3h\n" +
+			"└── StorageAccount/account *  False  Warning   WaitingForOwner  5m\n"))
+}
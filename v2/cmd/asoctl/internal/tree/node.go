@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tree
+
+import (
+	"time"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// AzureState captures whether the resource represented by a node exists in Azure
+type AzureState string
+
+const (
+	AzureStateNotChecked AzureState = ""        // --azure was not requested
+	AzureStateExists     AzureState = "Exists"  // Resource was found in Azure
+	AzureStateMissing    AzureState = "Missing" // Resource was not found in Azure
+	AzureStateUnknown    AzureState = "Unknown" // Resource has no ARM ID yet, or existence couldn't be checked
+	AzureStateError      AzureState = "Error"   // Checking existence failed
+)
+
+// Node is a single resource in an ownership tree
+type Node struct {
+	// Kind and Name identify the node; for ARM ID owners, Kind is "ARM" and Name is the ARM ID
+	Kind string
+	Name string
+
+	// Object is the resource represented by this node; nil if the node is an ARM ID owner or a missing owner
+	Object genruntime.ARMMetaObject
+
+	// ARMID is the ARM ID of the node, if known
+	ARMID string
+
+	// Missing is true if the node is an owner that couldn't be found in the cluster
+	Missing bool
+
+	// Target is true for the node the user asked about
+	Target bool
+
+	// Azure captures whether the resource exists in Azure, if checked
+	Azure AzureState
+
+	// Children are the resources owned by this node
+	Children []*Node
+}
+
+// newObjectNode creates a node representing a resource in the cluster
+func newObjectNode(obj genruntime.ARMMetaObject) *Node {
+	return &Node{
+		Kind:   obj.GetObjectKind().GroupVersionKind().Kind,
+		Name:   obj.GetName(),
+		Object: obj,
+		ARMID:  genruntime.GetResourceIDOrDefault(obj),
+	}
+}
+
+// newARMNode creates a node representing an owner referenced directly by ARM ID
+func newARMNode(armID string) *Node {
+	return &Node{
+		Kind:  "ARM",
+		Name:  armID,
+		ARMID: armID,
+	}
+}
+
+// newMissingNode creates a node representing an owner that doesn't exist in the cluster
+func newMissingNode(ref *genruntime.ResourceReference) *Node {
+	return &Node{
+		Kind:    ref.Kind,
+		Name:    ref.Name,
+		Missing: true,
+	}
+}
+
+// Ready returns the Ready condition of the node, if any
+func (n *Node) Ready() *conditions.Condition {
+	if n.Object == nil {
+		return nil
+	}
+
+	return genruntime.GetReadyCondition(n.Object)
+}
+
+// Age returns the age of the node, relative to now, if known
+func (n *Node) Age(now time.Time) (time.Duration, bool) {
+	if n.Object == nil {
+		return 0, false
+	}
+
+	created := n.Object.GetCreationTimestamp()
+	if created.IsZero() {
+		return 0, false
+	}
+
+	return now.Sub(created.Time), true
+}
+
+// Walk visits this node and all its descendants, depth first
+func (n *Node) Walk(visit func(node *Node)) {
+	visit(n)
+	for _, child := range n.Children {
+		child.Walk(visit)
+	}
+}
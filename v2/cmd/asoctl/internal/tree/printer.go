@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Print writes the tree rooted at root to w as a table, one row per node.
+// now is used to calculate the age of each resource.
+func Print(w io.Writer, root *Node, now time.Time) error {
+	showAzure := false
+	root.Walk(func(node *Node) {
+		showAzure = showAzure || node.Azure != AzureStateNotChecked
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	headings := []string{"NAME", "READY", "SEVERITY", "REASON", "AGE"}
+	if showAzure {
+		headings = append(headings, "AZURE")
+	}
+
+	_, err := fmt.Fprintln(tw, strings.Join(headings, "\t"))
+	if err != nil {
+		return err
+	}
+
+	err = printNode(tw, root, "", "", now, showAzure)
+	if err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+func printNode(
+	w io.Writer,
+	node *Node,
+	prefix string,
+	childPrefix string,
+	now time.Time,
+	showAzure bool,
+) error {
+	columns := []string{
+		prefix + describe(node),
+		"-",
+		"-",
+		"-",
+		"-",
+	}
+
+	if ready := node.Ready(); ready != nil {
+		columns[1] = string(ready.Status)
+		if ready.Severity != "" {
+			columns[2] = string(ready.Severity)
+		}
+
+		if ready.Reason != "" {
+			columns[3] = ready.Reason
+		}
+	}
+
+	if age, ok := node.Age(now); ok {
+		columns[4] = duration.HumanDuration(age)
+	}
+
+	if showAzure {
+		azure := string(node.Azure)
+		if azure == "" {
+			azure = "-"
+		}
+
+		columns = append(columns, azure)
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(columns, "\t"))
+	if err != nil {
+		return err
+	}
+
+	for i, child := range node.Children {
+		last := i == len(node.Children)-1
+		branch := "├── "
+		continuation := "│   "
+		if last {
+			branch = "└── "
+			continuation = "    "
+		}
+
+		err = printNode(w, child, childPrefix+branch, childPrefix+continuation, now, showAzure)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describe returns the name to display for a node
+func describe(node *Node) string {
+	var result string
+	if node.Object == nil && !node.Missing {
+		// Owner referenced by ARM ID
+		result = "armId: " + node.ARMID
+	} else {
+		result = node.Kind + "/" + node.Name
+	}
+
+	if node.Missing {
+		result += " (not found)"
+	}
+
+	if node.Target {
+		result += " *"
+	}
+
+	return result
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity indicates how serious a finding is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // The manifest will be rejected by the cluster, or won't work
+	SeverityWarning Severity = "warning" // The manifest may not work as intended
+)
+
+// Rule identifies the check that produced a finding
+type Rule struct {
+	ID          string
+	Description string
+}
+
+var (
+	RuleParse              = Rule{ID: "parse", Description: "Manifest must be valid YAML describing a Kubernetes object"}
+	RuleUnknownKind        = Rule{ID: "unknown-kind", Description: "Resource kind and version must be supported by ASO"}
+	RuleUnknownField       = Rule{ID: "unknown-field", Description: "Resource must only contain fields defined by its schema"}
+	RuleDeprecatedVersion  = Rule{ID: "deprecated-version", Description: "Resource must not use a deprecated API version"}
+	RuleStorageVersion     = Rule{ID: "storage-version", Description: "Resource should use an API version rather than a storage version"}
+	RuleWebhook            = Rule{ID: "webhook", Description: "Resource must pass the validation performed by the ASO webhook"}
+	RuleOwnerNotFound      = Rule{ID: "owner-not-found", Description: "Owner should be defined in the manifests being validated"}
+	RuleReferenceNotFound  = Rule{ID: "reference-not-found", Description: "Referenced resources should be defined in the manifests being validated"}
+	RuleSecretCollision    = Rule{ID: "secret-collision", Description: "No two resources may write to the same secret key"}
+	RuleConfigMapCollision = Rule{ID: "configmap-collision", Description: "No two resources may write to the same config map key"}
+	RuleDuplicateResource  = Rule{ID: "duplicate-resource", Description: "Each resource must only be defined once"}
+)
+
+// Rules lists all the rules, in the order they're documented
+var Rules = []Rule{
+	RuleParse,
+	RuleUnknownKind,
+	RuleUnknownField,
+	RuleDeprecatedVersion,
+	RuleStorageVersion,
+	RuleWebhook,
+	RuleOwnerNotFound,
+	RuleReferenceNotFound,
+	RuleSecretCollision,
+	RuleConfigMapCollision,
+	RuleDuplicateResource,
+}
+
+// Finding is a single problem found with a manifest
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	var result strings.Builder
+	result.WriteString(f.File)
+	if f.Line > 0 {
+		result.WriteString(fmt.Sprintf(":%d", f.Line))
+	}
+
+	result.WriteString(fmt.Sprintf(": %s: ", f.Severity))
+	if f.Kind != "" {
+		result.WriteString(fmt.Sprintf("%s/%s: ", f.Kind, f.Name))
+	}
+
+	result.WriteString(f.Message)
+	result.WriteString(fmt.Sprintf(" [%s]", f.Rule))
+	return result.String()
+}
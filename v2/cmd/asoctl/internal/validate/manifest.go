@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package validate
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// document is a single YAML document from a manifest file
+type document struct {
+	file string
+	line int // Line number of the first line of the document, starting from 1
+	data []byte
+}
+
+// FindManifests returns the YAML files at the specified paths, searching directories recursively
+func FindManifests(paths []string) ([]string, error) {
+	var result []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", path)
+		}
+
+		if !info.IsDir() {
+			result = append(result, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			ext := strings.ToLower(filepath.Ext(p))
+			if !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				result = append(result, p)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "searching %s", path)
+		}
+	}
+
+	return result, nil
+}
+
+// splitDocuments splits a multi-document YAML file into its documents, keeping track of where each starts
+func splitDocuments(file string, data []byte) []document {
+	var result []document
+	var current bytes.Buffer
+	start := 1
+	line := 0
+
+	flush := func() {
+		if len(bytes.TrimSpace(current.Bytes())) > 0 {
+			result = append(result, document{
+				file: file,
+				line: start,
+				data: append([]byte(nil), current.Bytes()...),
+			})
+		}
+
+		current.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if strings.TrimRight(text, " \t") == "---" {
+			flush()
+			start = line + 1
+			continue
+		}
+
+		if current.Len() == 0 && isBlankOrComment(text) {
+			// Report the line of the content, not of any preamble
+			start = line + 1
+			continue
+		}
+
+		current.WriteString(text)
+		current.WriteByte('\n')
+	}
+
+	flush()
+	return result
+}
+
+func isBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
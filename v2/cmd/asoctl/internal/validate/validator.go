@@ -0,0 +1,407 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// defaultNamespace is assumed for resources that don't specify a namespace
+const defaultNamespace = "default"
+
+// deprecatedVersionRegexp matches the API versions removed from ASO in v2.0.0 (v1alpha1api) and v2.4.0 (v1beta)
+var deprecatedVersionRegexp = regexp.MustCompile(`^(v1alpha1api|v1beta)\d{8}(preview)?$|^v1beta1$`)
+
+// defaulter is implemented by resources with a defaulting webhook
+type defaulter interface {
+	Default()
+}
+
+// createValidator is implemented by resources with a validating webhook
+type createValidator interface {
+	ValidateCreate() (admission.Warnings, error)
+}
+
+// Validator checks ASO manifests offline, without a cluster
+type Validator struct {
+	scheme   *runtime.Scheme
+	decoder  runtime.Decoder
+	findings []Finding
+	objects  []*manifestObject
+}
+
+// manifestObject is a resource loaded from a manifest
+type manifestObject struct {
+	doc document
+	obj client.Object
+}
+
+// NewValidator creates a new Validator; scheme must contain the ASO types
+func NewValidator(scheme *runtime.Scheme) *Validator {
+	return &Validator{
+		scheme:  scheme,
+		decoder: serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDeserializer(),
+	}
+}
+
+// AddFile loads all the resources in the manifest file at path
+func (v *Validator) AddFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", path)
+	}
+
+	v.AddManifest(path, data)
+	return nil
+}
+
+// AddManifest loads all the resources in a manifest; file is used when reporting findings
+func (v *Validator) AddManifest(file string, data []byte) {
+	for _, doc := range splitDocuments(file, data) {
+		v.addDocument(doc)
+	}
+}
+
+// Validate checks all the loaded resources, returning the findings in file order
+func (v *Validator) Validate() []Finding {
+	for _, mo := range v.objects {
+		v.validateWebhook(mo)
+	}
+
+	v.validateUnique()
+	v.validateReferences()
+	v.validateSecretDestinations()
+	v.validateConfigMapDestinations()
+
+	result := v.findings
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+
+		return result[i].Line < result[j].Line
+	})
+
+	return result
+}
+
+func (v *Validator) addDocument(doc document) {
+	// Read just enough to identify the resource, so we can report on it even if it can't be decoded
+	var header struct {
+		runtime.TypeMeta `json:",inline"`
+		Metadata         struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+
+	err := yaml.Unmarshal(doc.data, &header)
+	if err != nil {
+		v.report(doc, nil, RuleParse, SeverityError, err.Error())
+		return
+	}
+
+	if header.APIVersion == "" || header.Kind == "" {
+		v.report(doc, nil, RuleParse, SeverityError, "apiVersion and kind are required")
+		return
+	}
+
+	gvk := schema.FromAPIVersionAndKind(header.APIVersion, header.Kind)
+	name := header.Metadata.Name
+	if !strings.HasSuffix(gvk.Group, ".azure.com") {
+		// Not an ASO resource; we still decode known types (such as secrets) so they can satisfy references
+		if !v.scheme.Recognizes(gvk) {
+			return
+		}
+	}
+
+	if !v.scheme.Recognizes(gvk) {
+		v.reportUnknownKind(doc, gvk, name)
+		return
+	}
+
+	obj, _, err := v.decoder.Decode(doc.data, nil, nil)
+	if runtime.IsStrictDecodingError(err) {
+		for _, e := range strictErrors(err) {
+			v.reportWithKind(doc, gvk.Kind, name, RuleUnknownField, SeverityError, e)
+		}
+	} else if err != nil {
+		v.reportWithKind(doc, gvk.Kind, name, RuleParse, SeverityError, err.Error())
+		return
+	}
+
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+
+	if clientObj.GetNamespace() == "" {
+		clientObj.SetNamespace(defaultNamespace)
+	}
+
+	mo := &manifestObject{doc: doc, obj: clientObj}
+	v.objects = append(v.objects, mo)
+
+	if strings.HasSuffix(gvk.Version, "storage") {
+		v.reportObject(mo, RuleStorageVersion, SeverityWarning,
+			fmt.Sprintf("%s is a storage version used internally by ASO; use %s instead", gvk.Version, strings.TrimSuffix(gvk.Version, "storage")))
+	}
+}
+
+// reportUnknownKind reports a kind that isn't in the scheme, suggesting a replacement if it's a deprecated version
+func (v *Validator) reportUnknownKind(doc document, gvk schema.GroupVersionKind, name string) {
+	if deprecatedVersionRegexp.MatchString(gvk.Version) {
+		replacement := gvk
+		replacement.Version = stableVersion(gvk.Version)
+		message := fmt.Sprintf("API version %s has been removed", gvk.GroupVersion())
+		if v.scheme.Recognizes(replacement) {
+			message += fmt.Sprintf("; use %s instead", replacement.GroupVersion())
+		}
+
+		v.reportWithKind(doc, gvk.Kind, name, RuleDeprecatedVersion, SeverityError, message)
+		return
+	}
+
+	var versions []string
+	for known := range v.scheme.AllKnownTypes() {
+		if known.Group == gvk.Group && known.Kind == gvk.Kind && !strings.HasSuffix(known.Version, "storage") {
+			versions = append(versions, known.Version)
+		}
+	}
+
+	if len(versions) > 0 {
+		sort.Strings(versions)
+		v.reportWithKind(doc, gvk.Kind, name, RuleUnknownKind, SeverityError,
+			fmt.Sprintf("version %s of %s is not supported; supported versions are %s", gvk.Version, gvk.GroupKind(), strings.Join(versions, ", ")))
+		return
+	}
+
+	v.reportWithKind(doc, gvk.Kind, name, RuleUnknownKind, SeverityError, fmt.Sprintf("%s is not supported by ASO", gvk.GroupKind()))
+}
+
+// validateWebhook runs the defaulting and validating webhooks, as the cluster would when the resource is created
+func (v *Validator) validateWebhook(mo *manifestObject) {
+	if d, ok := mo.obj.(defaulter); ok {
+		d.Default()
+	}
+
+	validator, ok := mo.obj.(createValidator)
+	if !ok {
+		return
+	}
+
+	warnings, err := validator.ValidateCreate()
+	for _, warning := range warnings {
+		v.reportObject(mo, RuleWebhook, SeverityWarning, warning)
+	}
+
+	if err != nil {
+		v.reportObject(mo, RuleWebhook, SeverityError, err.Error())
+	}
+}
+
+// validateUnique checks that no resource is defined more than once
+func (v *Validator) validateUnique() {
+	seen := make(map[string]*manifestObject, len(v.objects))
+	for _, mo := range v.objects {
+		key := objectKey(mo.obj.GetObjectKind().GroupVersionKind().GroupKind(), mo.obj.GetNamespace(), mo.obj.GetName())
+		if first, ok := seen[key]; ok {
+			v.reportObject(mo, RuleDuplicateResource, SeverityError,
+				fmt.Sprintf("already defined at %s:%d", first.doc.file, first.doc.line))
+			continue
+		}
+
+		seen[key] = mo
+	}
+}
+
+// validateReferences checks that owners and other resources referenced by name are defined in the manifests
+func (v *Validator) validateReferences() {
+	known := make(map[string]bool, len(v.objects))
+	for _, mo := range v.objects {
+		known[objectKey(mo.obj.GetObjectKind().GroupVersionKind().GroupKind(), mo.obj.GetNamespace(), mo.obj.GetName())] = true
+	}
+
+	for _, mo := range v.objects {
+		resource, ok := mo.obj.(genruntime.ARMMetaObject)
+		if !ok {
+			continue
+		}
+
+		if owner := resource.Owner(); owner != nil && owner.IsKubernetesReference() {
+			if !known[objectKey(owner.GroupKind(), mo.obj.GetNamespace(), owner.Name)] {
+				v.reportObject(mo, RuleOwnerNotFound, SeverityWarning,
+					fmt.Sprintf("owner %s %q is not defined in these manifests", owner.Kind, owner.Name))
+			}
+		}
+
+		refs, err := reflecthelpers.FindResourceReferences(resource.GetSpec())
+		if err != nil {
+			v.reportObject(mo, RuleReferenceNotFound, SeverityError, err.Error())
+			continue
+		}
+
+		var missing []string
+		for ref := range refs {
+			if !ref.IsKubernetesReference() {
+				continue
+			}
+
+			if !known[objectKey(ref.GroupKind(), mo.obj.GetNamespace(), ref.Name)] {
+				missing = append(missing, fmt.Sprintf("%s %q", ref.GroupKind(), ref.Name))
+			}
+		}
+
+		sort.Strings(missing)
+		for _, m := range missing {
+			v.reportObject(mo, RuleReferenceNotFound, SeverityWarning,
+				fmt.Sprintf("referenced resource %s is not defined in these manifests", m))
+		}
+	}
+}
+
+// validateSecretDestinations checks that no two resources write to the same key of the same secret.
+// Collisions within a single resource are found by the webhook.
+func (v *Validator) validateSecretDestinations() {
+	writers := make(map[string]*manifestObject)
+	for _, mo := range v.objects {
+		destinations, err := reflecthelpers.Find[genruntime.SecretDestination](mo.obj)
+		if err != nil {
+			v.reportObject(mo, RuleSecretCollision, SeverityError, err.Error())
+			continue
+		}
+
+		for _, dest := range sortedDestinations(destinations, func(d genruntime.SecretDestination) string { return d.String() }) {
+			key := mo.obj.GetNamespace() + "/" + dest.Name + "/" + dest.Key
+			if first, ok := writers[key]; ok && first != mo {
+				v.reportObject(mo, RuleSecretCollision, SeverityError,
+					fmt.Sprintf("secret destination %s is also written by %s %q", dest.String(), first.obj.GetObjectKind().GroupVersionKind().Kind, first.obj.GetName()))
+				continue
+			}
+
+			writers[key] = mo
+		}
+	}
+}
+
+// validateConfigMapDestinations checks that no two resources write to the same key of the same config map.
+// Collisions within a single resource are found by the webhook.
+func (v *Validator) validateConfigMapDestinations() {
+	writers := make(map[string]*manifestObject)
+	for _, mo := range v.objects {
+		destinations, err := reflecthelpers.Find[genruntime.ConfigMapDestination](mo.obj)
+		if err != nil {
+			v.reportObject(mo, RuleConfigMapCollision, SeverityError, err.Error())
+			continue
+		}
+
+		for _, dest := range sortedDestinations(destinations, func(d genruntime.ConfigMapDestination) string { return d.String() }) {
+			key := mo.obj.GetNamespace() + "/" + dest.Name + "/" + dest.Key
+			if first, ok := writers[key]; ok && first != mo {
+				v.reportObject(mo, RuleConfigMapCollision, SeverityError,
+					fmt.Sprintf("config map destination %s is also written by %s %q", dest.String(), first.obj.GetObjectKind().GroupVersionKind().Kind, first.obj.GetName()))
+				continue
+			}
+
+			writers[key] = mo
+		}
+	}
+}
+
+func (v *Validator) report(doc document, obj client.Object, rule Rule, severity Severity, message string) {
+	var kind string
+	var name string
+	if obj != nil {
+		kind = obj.GetObjectKind().GroupVersionKind().Kind
+		name = obj.GetName()
+	}
+
+	v.reportWithKind(doc, kind, name, rule, severity, message)
+}
+
+func (v *Validator) reportObject(mo *manifestObject, rule Rule, severity Severity, message string) {
+	v.report(mo.doc, mo.obj, rule, severity, message)
+}
+
+func (v *Validator) reportWithKind(doc document, kind string, name string, rule Rule, severity Severity, message string) {
+	v.findings = append(v.findings, Finding{
+		Rule:     rule.ID,
+		Severity: severity,
+		File:     doc.file,
+		Line:     doc.line,
+		Kind:     kind,
+		Name:     name,
+		Message:  message,
+	})
+}
+
+// HasErrors returns true if any of the findings is an error
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func objectKey(gk schema.GroupKind, namespace string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", gk.String(), namespace, name)
+}
+
+// stableVersion returns the version that replaced a deprecated version
+func stableVersion(version string) string {
+	if version == "v1beta1" { // For handcrafted resources
+		return "v1"
+	}
+
+	result := strings.Replace(version, "v1alpha1api", "v1api", 1)
+	return strings.Replace(result, "v1beta", "v1api", 1)
+}
+
+// strictErrors returns the individual messages of a strict decoding error
+func strictErrors(err error) []string {
+	strict, ok := runtime.AsStrictDecodingError(err)
+	if !ok {
+		return []string{err.Error()}
+	}
+
+	result := make([]string, 0, len(strict.Errors()))
+	for _, e := range strict.Errors() {
+		result = append(result, e.Error())
+	}
+
+	return result
+}
+
+// sortedDestinations returns destinations in a deterministic order
+func sortedDestinations[T comparable](destinations map[T]struct{}, key func(T) string) []T {
+	result := make([]T, 0, len(destinations))
+	for d := range destinations {
+		result = append(result, d)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return key(result[i]) < key(result[j])
+	})
+
+	return result
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/api"
+)
+
+const validManifest = `# A resource group and storage account
+apiVersion: resources.azure.com/v1api20200601
+kind: ResourceGroup
+metadata:
+  name: rg
+spec:
+  location: westus2
+---
+apiVersion: storage.azure.com/v1api20220901
+kind: StorageAccount
+metadata:
+  name: account
+spec:
+  location: westus2
+  kind: StorageV2
+  sku:
+    name: Standard_LRS
+  owner:
+    name: rg
+  operatorSpec:
+    secrets:
+      key1:
+        name: account-secret
+        key: key1
+`
+
+func validate(manifests map[string]string) []Finding {
+	validator := NewValidator(api.CreateScheme())
+	for file, content := range manifests {
+		validator.AddManifest(file, []byte(content))
+	}
+
+	return validator.Validate()
+}
+
+func rules(findings []Finding) []string {
+	result := make([]string, 0, len(findings))
+	for _, f := range findings {
+		result = append(result, f.Rule)
+	}
+
+	return result
+}
+
+func TestValidator_ValidManifest_HasNoFindings(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := validate(map[string]string{"valid.yaml": validManifest})
+	g.Expect(findings).To(BeEmpty())
+}
+
+func TestValidator_DeprecatedVersion_SuggestsReplacement(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := validate(map[string]string{"rg.yaml": `
+apiVersion: resources.azure.com/v1beta20200601
+kind: ResourceGroup
+metadata:
+  name: rg
+spec:
+  location: westus2
+`})
+
+	g.Expect(findings).To(HaveLen(1))
+	g.Expect(findings[0].Rule).To(Equal(RuleDeprecatedVersion.ID))
+	g.Expect(findings[0].Severity).To(Equal(SeverityError))
+	g.Expect(findings[0].Line).To(Equal(2))
+	g.Expect(findings[0].Message).To(ContainSubstring("use resources.azure.com/v1api20200601 instead"))
+}
+
+func TestValidator_UnknownField_IsReported(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := validate(map[string]string{"rg.yaml": `
+apiVersion: resources.azure.com/v1api20200601
+kind: ResourceGroup
+metadata:
+  name: rg
+spec:
+  location: westus2
+  locaton: eastus
+`})
+
+	g.Expect(rules(findings)).To(Equal([]string{RuleUnknownField.ID}))
+	g.Expect(findings[0].Message).To(ContainSubstring("locaton"))
+}
+
+func TestValidator_WebhookValidation_IsRun(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// The owner must be specified by name or ARM ID, not both
+	findings := validate(map[string]string{"account.yaml": `
+apiVersion: storage.azure.com/v1api20220901
+kind: StorageAccount
+metadata:
+  name: account
+spec:
+  location: westus2
+  owner:
+    name: rg
+    armId: /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg
+`})
+
+	g.Expect(rules(findings)).To(ContainElement(RuleWebhook.ID))
+}
+
+func TestValidator_MissingOwner_IsReported(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := validate(map[string]string{"account.yaml": `
+apiVersion: storage.azure.com/v1api20220901
+kind: StorageAccount
+metadata:
+  name: account
+spec:
+  location: westus2
+  owner:
+    name: missing-rg
+`})
+
+	g.Expect(rules(findings)).To(Equal([]string{RuleOwnerNotFound.ID}))
+	g.Expect(findings[0].Severity).To(Equal(SeverityWarning))
+}
+
+func TestValidator_OwnerInAnotherFile_IsFound(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := validate(map[string]string{
+		"rg.yaml": `
+apiVersion: resources.azure.com/v1api20200601
+kind: ResourceGroup
+metadata:
+  name: rg
+spec:
+  location: westus2
+`,
+		"account.yaml": `
+apiVersion: storage.azure.com/v1api20220901
+kind: StorageAccount
+metadata:
+  name: account
+spec:
+  location: westus2
+  owner:
+    name: rg
+`,
+	})
+
+	g.Expect(findings).To(BeEmpty())
+}
+
+func TestValidator_SecretCollision_AcrossResources_IsReported(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := validate(map[string]string{"valid.yaml": validManifest + `---
+apiVersion: storage.azure.com/v1api20220901
+kind: StorageAccount
+metadata:
+  name: other-account
+spec:
+  location: westus2
+  owner:
+    name: rg
+  operatorSpec:
+    secrets:
+      key2:
+        name: account-secret
+        key: key1
+`})
+
+	g.Expect(rules(findings)).To(Equal([]string{RuleSecretCollision.ID}))
+	g.Expect(findings[0].Name).To(Equal("other-account"))
+	g.Expect(findings[0].Message).To(ContainSubstring(`StorageAccount "account"`))
+}
+
+func TestValidator_DuplicateResource_IsReported(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := validate(map[string]string{"valid.yaml": validManifest + `---
+apiVersion: resources.azure.com/v1api20200601
+kind: ResourceGroup
+metadata:
+  name: rg
+spec:
+  location: westus2
+`})
+
+	g.Expect(rules(findings)).To(Equal([]string{RuleDuplicateResource.ID}))
+	g.Expect(findings[0].Message).To(Equal("already defined at valid.yaml:2"))
+}
+
+func TestWriteFindings_SARIF(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	findings := []Finding{
+		{
+			Rule:     RuleOwnerNotFound.ID,
+			Severity: SeverityWarning,
+			File:     "manifests/account.yaml",
+			Line:     3,
+			Kind:     "StorageAccount",
+			Name:     "account",
+			Message:  "owner is missing",
+		},
+	}
+
+	var buffer bytes.Buffer
+	g.Expect(WriteFindings(&buffer, findings, FormatSARIF)).To(Succeed())
+
+	var log map[string]any
+	g.Expect(json.Unmarshal(buffer.Bytes(), &log)).To(Succeed())
+	g.Expect(log).To(HaveKeyWithValue("version", "2.1.0"))
+
+	results := log["runs"].([]any)[0].(map[string]any)["results"].([]any)
+	g.Expect(results).To(HaveLen(1))
+
+	result := results[0].(map[string]any)
+	g.Expect(result).To(HaveKeyWithValue("ruleId", "owner-not-found"))
+	g.Expect(result).To(HaveKeyWithValue("level", "warning"))
+}
+
+func TestWriteFindings_JSON_WritesEmptyArray(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var buffer bytes.Buffer
+	g.Expect(WriteFindings(&buffer, nil, FormatJSON)).To(Succeed())
+	g.Expect(buffer.String()).To(Equal("[]\n"))
+}
@@ -23,11 +23,16 @@ type Flags struct {
 	CRDManagementMode    string
 	CRDPatterns          string // This is a ; delimited string containing a collection of patterns
 	PreUpgradeCheck      bool
+	OTLPEndpoint         string
+	ShardCount           int
+	ShardBy              string
+	CapacityChecks       bool
+	EnforcePolicies      bool
 }
 
 func (f Flags) String() string {
 	return fmt.Sprintf(
-		"MetricsAddr: %s, HealthAddr: %s, WebhookPort: %d, WebhookCertDir: %s, EnableLeaderElection: %t, CRDManagementMode: %s, CRDPatterns: %s, PreUpgradeCheck: %t",
+		"MetricsAddr: %s, HealthAddr: %s, WebhookPort: %d, WebhookCertDir: %s, EnableLeaderElection: %t, CRDManagementMode: %s, CRDPatterns: %s, PreUpgradeCheck: %t, OTLPEndpoint: %s, ShardCount: %d, ShardBy: %s, CapacityChecks: %t, EnforcePolicies: %t",
 		f.MetricsAddr,
 		f.HealthAddr,
 		f.WebhookPort,
@@ -35,7 +40,12 @@ func (f Flags) String() string {
 		f.EnableLeaderElection,
 		f.CRDManagementMode,
 		f.CRDPatterns,
-		f.PreUpgradeCheck)
+		f.PreUpgradeCheck,
+		f.OTLPEndpoint,
+		f.ShardCount,
+		f.ShardBy,
+		f.CapacityChecks,
+		f.EnforcePolicies)
 }
 
 func ParseFlags(args []string) (Flags, error) {
@@ -51,6 +61,11 @@ func ParseFlags(args []string) (Flags, error) {
 	var crdManagementMode string
 	var crdPatterns string
 	var preUpgradeCheck bool
+	var otlpEndpoint string
+	var shardCount int
+	var shardBy string
+	var capacityChecks bool
+	var enforcePolicies bool
 
 	// default here for 'MetricsAddr' is set to "0", which sets metrics to be disabled if 'metrics-addr' flag is omitted.
 	flagSet.StringVar(&metricsAddr, "metrics-addr", "0", "The address the metric endpoint binds to.")
@@ -60,10 +75,20 @@ func ParseFlags(args []string) (Flags, error) {
 	flagSet.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controllers manager. Enabling this will ensure there is only one active controllers manager.")
 	flagSet.StringVar(&crdManagementMode, "crd-management", "auto",
-		"Instructs the operator on how it should manage the Custom Resource Definitions. One of 'auto', 'none'")
+		"Instructs the operator on how it should manage the Custom Resource Definitions. One of 'auto', 'none', 'lazy'")
 	flagSet.StringVar(&crdPatterns, "crd-pattern", "", "Install these CRDs. CRDs already in the cluster will also always be upgraded.")
 	flagSet.BoolVar(&preUpgradeCheck, "pre-upgrade-check", false,
 		"Enable pre upgrade check to check if existing crds contain helm 'keep' policy.")
+	flagSet.StringVar(&otlpEndpoint, "otlp-endpoint", "",
+		"The URL of an OpenTelemetry collector (such as http://otel-collector:4318) to export traces to via OTLP. Tracing is disabled if omitted.")
+	flagSet.IntVar(&shardCount, "shard-count", 0,
+		"Divide resources into this many shards, shared between all replicas of the operator using Leases. Sharding is disabled if omitted.")
+	flagSet.StringVar(&shardBy, "shard-by", "namespace",
+		"How resources are assigned to shards when sharding is enabled. One of 'namespace', 'groupkind'")
+	flagSet.BoolVar(&capacityChecks, "capacity-checks", false,
+		"Before creating VMs, scale sets, AKS clusters and caches, check that the SKU is available in the region and there is enough quota.")
+	flagSet.BoolVar(&enforcePolicies, "enforce-policies", false,
+		"Enforce the ASOPolicy resources in the cluster, restricting the locations, SKUs, tags and number of resources in each namespace.")
 
 	flagSet.Parse(args[1:]) //nolint:errcheck
 
@@ -76,5 +101,10 @@ func ParseFlags(args []string) (Flags, error) {
 		CRDManagementMode:    crdManagementMode,
 		CRDPatterns:          crdPatterns,
 		PreUpgradeCheck:      preUpgradeCheck,
+		OTLPEndpoint:         otlpEndpoint,
+		ShardCount:           shardCount,
+		ShardBy:              shardBy,
+		CapacityChecks:       capacityChecks,
+		EnforcePolicies:      enforcePolicies,
 	}, nil
 }
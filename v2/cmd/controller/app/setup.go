@@ -18,15 +18,18 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	clientconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -36,17 +39,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/Azure/azure-service-operator/v2/api"
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/config"
 	"github.com/Azure/azure-service-operator/v2/internal/controllers"
 	"github.com/Azure/azure-service-operator/v2/internal/crdmanagement"
 	"github.com/Azure/azure-service-operator/v2/internal/identity"
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
 	asometrics "github.com/Azure/azure-service-operator/v2/internal/metrics"
+	"github.com/Azure/azure-service-operator/v2/internal/policy"
 	armreconciler "github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/generic"
+	"github.com/Azure/azure-service-operator/v2/internal/sharding"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/internal/util/interval"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
 	"github.com/Azure/azure-service-operator/v2/internal/util/lockedrand"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
 	common "github.com/Azure/azure-service-operator/v2/pkg/common/config"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
@@ -143,12 +151,39 @@ func SetupControllerManager(ctx context.Context, setupLog logr.Logger, flgs Flag
 		os.Exit(1)
 	}
 
+	if flgs.OTLPEndpoint != "" {
+		err = setupTracing(ctx, mgr, flgs.OTLPEndpoint)
+		if err != nil {
+			setupLog.Error(err, "failed to set up tracing")
+			os.Exit(1)
+		}
+	}
+
 	clients, err := initializeClients(cfg, mgr)
 	if err != nil {
 		setupLog.Error(err, "failed to initialize clients")
 		os.Exit(1)
 	}
 
+	if flgs.CapacityChecks && cfg.OperatorMode.IncludesWatchers() {
+		cache := capacity.NewCache(clock.New(), capacity.DefaultSKUTTL, capacity.DefaultUsageTTL)
+		clients.options.Capacity = capacity.NewChecker(cache)
+	}
+
+	if flgs.EnforcePolicies {
+		// Policies are checked by the webhooks, and re-checked when reconciling so that bypassing the webhooks isn't
+		// enough to bypass them
+		clients.options.Policy = policy.NewEnforcer(mgr.GetClient(), mgr.GetScheme())
+	}
+
+	if flgs.ShardCount > 0 && cfg.OperatorMode.IncludesWatchers() {
+		err = setupSharding(mgr, flgs, cfg, clients)
+		if err != nil {
+			setupLog.Error(err, "failed to set up sharding")
+			os.Exit(1)
+		}
+	}
+
 	// TODO: Put all of the CRD stuff into a method?
 	crdManager, err := newCRDManager(clients.log, mgr.GetConfig())
 	if err != nil {
@@ -165,7 +200,9 @@ func SetupControllerManager(ctx context.Context, setupLog logr.Logger, flgs Flag
 	// load the goal CRDs from disk and apply them.
 	goalCRDs := existingCRDs
 	switch flgs.CRDManagementMode {
-	case "auto":
+	case "auto", "lazy":
+		// In lazy mode, only CRDs which already exist (or which match the crd-pattern) are installed or upgraded at
+		// startup; others are installed on demand, when requested by a namespace.
 		goalCRDs, err = crdManager.LoadOperatorCRDs(crdmanagement.CRDLocation, cfg.PodNamespace)
 		if err != nil {
 			setupLog.Error(err, "failed to load CRDs from disk")
@@ -182,7 +219,7 @@ func SetupControllerManager(ctx context.Context, setupLog logr.Logger, flgs Flag
 			}
 
 			included := crdmanagement.IncludedCRDs(installationInstructions)
-			if len(included) == 0 {
+			if len(included) == 0 && flgs.CRDManagementMode == "auto" {
 				err = errors.New("No existing CRDs in cluster and no --crd-pattern specified")
 				setupLog.Error(err, "failed to apply CRDs")
 				os.Exit(1)
@@ -205,9 +242,10 @@ func SetupControllerManager(ctx context.Context, setupLog logr.Logger, flgs Flag
 	// to skip watching of these not-ready resources.
 	nonReadyResources := crdmanagement.GetNonReadyCRDs(cfg, crdManager, goalCRDs, existingCRDs)
 
+	var registry *generic.Registry
 	if cfg.OperatorMode.IncludesWatchers() {
 		//nolint:contextcheck
-		err = initializeWatchers(nonReadyResources, cfg, mgr, clients)
+		registry, err = initializeWatchers(nonReadyResources, cfg, mgr, clients)
 		if err != nil {
 			setupLog.Error(err, "failed to initialize watchers")
 			os.Exit(1)
@@ -215,16 +253,26 @@ func SetupControllerManager(ctx context.Context, setupLog logr.Logger, flgs Flag
 	}
 
 	if cfg.OperatorMode.IncludesWebhooks() {
+		// CRDs may be installed or upgraded after startup, so we serve webhooks for all known types
 		objs := controllers.GetKnownTypes()
+		if errs := generic.RegisterWebhooks(mgr, objs, clients.options.Policy); errs != nil {
+			setupLog.Error(err, "failed to register webhook for gvks")
+			os.Exit(1)
+		}
+	}
 
-		objs, err = crdmanagement.FilterKnownTypesByReadyCRDs(clients.log, scheme, nonReadyResources, objs)
+	if cfg.OperatorMode.IncludesWatchers() {
+		err = setupCRDWatcher(mgr, flgs, clients, registry)
 		if err != nil {
-			setupLog.Error(err, "failed to filter known types by ready CRDs")
+			setupLog.Error(err, "failed to set up CRD watcher")
 			os.Exit(1)
 		}
+	}
 
-		if errs := generic.RegisterWebhooks(mgr, objs); errs != nil {
-			setupLog.Error(err, "failed to register webhook for gvks")
+	if flgs.CRDManagementMode == "lazy" {
+		err = setupLazyCRDInstallation(mgr, cfg, crdManager, goalCRDs, clients, registry)
+		if err != nil {
+			setupLog.Error(err, "failed to set up lazy CRD installation")
 			os.Exit(1)
 		}
 	}
@@ -252,6 +300,65 @@ func SetupControllerManager(ctx context.Context, setupLog logr.Logger, flgs Flag
 	return mgr
 }
 
+// setupTracing configures OpenTelemetry to export spans to the OTLP collector at endpoint. Spans still buffered
+// are flushed when the manager stops.
+func setupTracing(ctx context.Context, mgr manager.Manager, endpoint string) error {
+	provider, err := tracing.NewOTLPTracerProvider(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	otel.SetTracerProvider(provider)
+
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		// ctx is already cancelled, so we need a fresh one to flush any remaining spans
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return provider.Shutdown(shutdownCtx)
+	}))
+}
+
+// setupSharding configures the controllers so that each replica of the operator only reconciles the resources in the
+// shards it holds. As every replica is active, controllers no longer wait to be elected leader.
+func setupSharding(mgr manager.Manager, flgs Flags, cfg config.Values, clients *clients) error {
+	strategy, err := sharding.ParseStrategy(flgs.ShardBy)
+	if err != nil {
+		return err
+	}
+
+	replicaID, err := os.Hostname()
+	if err != nil {
+		return errors.Wrap(err, "unable to determine identity of this replica")
+	}
+
+	// Leases must be read directly, as the cache may not include the operator namespace
+	leaseClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return errors.Wrap(err, "unable to create client for leases")
+	}
+
+	coordinator, err := sharding.NewCoordinator(
+		leaseClient,
+		sharding.Options{
+			Namespace:  cfg.PodNamespace,
+			Identity:   replicaID,
+			ShardCount: flgs.ShardCount,
+			Strategy:   strategy,
+			Log:        clients.log.WithName("sharding"),
+		})
+	if err != nil {
+		return err
+	}
+
+	clients.options.Sharding = coordinator
+	clients.options.NeedLeaderElection = to.Ptr(false)
+
+	return mgr.Add(coordinator)
+}
+
 func getDefaultAzureCredential(cfg config.Values, setupLog logr.Logger) (*identity.Credential, error) {
 	tokenCred, err := getDefaultAzureTokenCredential(cfg, setupLog)
 	if err != nil {
@@ -316,7 +423,9 @@ type clients struct {
 
 func initializeClients(cfg config.Values, mgr ctrl.Manager) (*clients, error) {
 	armMetrics := asometrics.NewARMClientMetrics()
-	asometrics.RegisterMetrics(armMetrics)
+	resourceMetrics := asometrics.NewResourceMetrics()
+	controllerMetrics := asometrics.NewControllerMetrics()
+	asometrics.RegisterMetrics(armMetrics, resourceMetrics, controllerMetrics)
 
 	log := ctrl.Log.WithName("controllers")
 
@@ -342,6 +451,8 @@ func initializeClients(cfg config.Values, mgr ctrl.Manager) (*clients, error) {
 	positiveConditions := conditions.NewPositiveConditionBuilder(clock.New())
 
 	options := makeControllerOptions(log, cfg)
+	options.ResourceMetrics = resourceMetrics
+	options.ControllerMetrics = controllerMetrics
 
 	return &clients{
 		positiveConditions:   positiveConditions,
@@ -353,7 +464,14 @@ func initializeClients(cfg config.Values, mgr ctrl.Manager) (*clients, error) {
 	}, nil
 }
 
-func initializeWatchers(nonReadyResources map[string]apiextensions.CustomResourceDefinition, cfg config.Values, mgr ctrl.Manager, clients *clients) error {
+// initializeWatchers starts controllers for all storage types with ready CRDs, returning a Registry that can be used
+// to start controllers for the remaining storage types later.
+func initializeWatchers(
+	nonReadyResources map[string]apiextensions.CustomResourceDefinition,
+	cfg config.Values,
+	mgr ctrl.Manager,
+	clients *clients,
+) (*generic.Registry, error) {
 	clients.log.V(Status).Info("Configuration details", "config", cfg.String())
 
 	objs, err := controllers.GetKnownStorageTypes(
@@ -364,16 +482,10 @@ func initializeWatchers(nonReadyResources map[string]apiextensions.CustomResourc
 		clients.positiveConditions,
 		clients.options)
 	if err != nil {
-		return errors.Wrap(err, "failed getting storage types and reconcilers")
+		return nil, errors.Wrap(err, "failed getting storage types and reconcilers")
 	}
 
-	// Filter the types to register
-	objs, err = crdmanagement.FilterStorageTypesByReadyCRDs(clients.log, mgr.GetScheme(), nonReadyResources, objs)
-	if err != nil {
-		return errors.Wrap(err, "failed to filter storage types by ready CRDs")
-	}
-
-	err = generic.RegisterAll(
+	registry, err := generic.NewRegistry(
 		mgr,
 		mgr.GetFieldIndexer(),
 		clients.kubeClient,
@@ -381,10 +493,77 @@ func initializeWatchers(nonReadyResources map[string]apiextensions.CustomResourc
 		objs,
 		clients.options)
 	if err != nil {
-		return errors.Wrap(err, "failed to register gvks")
+		return nil, errors.Wrap(err, "failed to create controller registry")
+	}
+
+	// Filter the types to register
+	objs, err = crdmanagement.FilterStorageTypesByReadyCRDs(clients.log, mgr.GetScheme(), nonReadyResources, objs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to filter storage types by ready CRDs")
+	}
+
+	kinds := make([]schema.GroupKind, 0, len(objs))
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj.Obj, mgr.GetScheme())
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating GVK for obj %T", obj.Obj)
+		}
+
+		kinds = append(kinds, gvk.GroupKind())
+	}
+
+	_, err = registry.Register(kinds...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register gvks")
+	}
+
+	return registry, nil
+}
+
+// setupCRDWatcher starts controllers for CRDs which become ready after the operator has started
+func setupCRDWatcher(mgr ctrl.Manager, flgs Flags, clients *clients, registry *generic.Registry) error {
+	patterns := flgs.CRDPatterns
+	if flgs.CRDManagementMode == "lazy" {
+		// Namespaces may request any CRD, not just those matching the crd-pattern
+		patterns = ""
 	}
 
-	return nil
+	watcher := crdmanagement.NewCRDWatcher(
+		clients.kubeClient,
+		registry,
+		patterns,
+		mgr.GetEventRecorderFor("crd-watcher"),
+		clients.log.WithName("crd-watcher"))
+
+	return watcher.SetupWithManager(mgr)
+}
+
+// setupLazyCRDInstallation installs CRDs when they are requested by a namespace, starting their controllers
+// without requiring a restart.
+func setupLazyCRDInstallation(
+	mgr ctrl.Manager,
+	cfg config.Values,
+	crdManager *crdmanagement.Manager,
+	goalCRDs []apiextensions.CustomResourceDefinition,
+	clients *clients,
+	registry *generic.Registry,
+) error {
+	// We need to be able to both install CRDs (webhooks mode) and start controllers (watchers mode)
+	if cfg.OperatorMode != config.OperatorModeBoth {
+		return errors.Errorf("lazy CRD management requires operator mode %s, but was %s", config.OperatorModeBoth, cfg.OperatorMode)
+	}
+
+	installer := crdmanagement.NewLazyInstaller(
+		crdManager,
+		clients.kubeClient,
+		goalCRDs,
+		mgr.GetEventRecorderFor("crd-lazy-installer"),
+		func(_ context.Context, kinds []schema.GroupKind) ([]schema.GroupKind, error) {
+			return registry.RegisterAfterStart(kinds...)
+		},
+		clients.log.WithName("crd-lazy-installer"))
+
+	return installer.SetupWithManager(mgr)
 }
 
 func makeControllerOptions(log logr.Logger, cfg config.Values) generic.Options {
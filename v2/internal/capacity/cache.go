@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+)
+
+const (
+	computeAPIVersion   = "2021-07-01"
+	providersAPIVersion = "2021-04-01"
+
+	// DefaultSKUTTL is how long we cache resource SKUs and provider locations; these rarely change
+	DefaultSKUTTL = 6 * time.Hour
+
+	// DefaultUsageTTL is how long we cache quota usage; this changes as resources are created and deleted
+	DefaultUsageTTL = 5 * time.Minute
+)
+
+// Cache caches the results of the Compute Resource SKUs and Usage APIs (and the Resource Providers API) per
+// subscription and location, so that we don't query ARM every time a resource is reconciled.
+type Cache struct {
+	clock    clock.Clock
+	skuTTL   time.Duration
+	usageTTL time.Duration
+
+	lock      sync.Mutex
+	skus      map[cacheKey]cacheEntry[[]ResourceSKU]
+	usages    map[cacheKey]cacheEntry[[]Usage]
+	providers map[cacheKey]cacheEntry[[]ProviderResourceType]
+}
+
+// cacheKey identifies a cached ARM response; scope is either a location or a provider namespace
+type cacheKey struct {
+	subscription string
+	scope        string
+}
+
+type cacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// NewCache creates a new Cache.
+// skuTTL is how long resource SKUs and provider locations are cached for.
+// usageTTL is how long quota usage is cached for.
+func NewCache(clk clock.Clock, skuTTL time.Duration, usageTTL time.Duration) *Cache {
+	return &Cache{
+		clock:     clk,
+		skuTTL:    skuTTL,
+		usageTTL:  usageTTL,
+		skus:      make(map[cacheKey]cacheEntry[[]ResourceSKU]),
+		usages:    make(map[cacheKey]cacheEntry[[]Usage]),
+		providers: make(map[cacheKey]cacheEntry[[]ProviderResourceType]),
+	}
+}
+
+// ResourceSKUs returns the Microsoft.Compute resource SKUs available to subscription in location
+func (c *Cache) ResourceSKUs(
+	ctx context.Context,
+	armClient *genericarmclient.GenericClient,
+	subscription string,
+	location string,
+) ([]ResourceSKU, error) {
+	key := cacheKey{subscription: strings.ToLower(subscription), scope: normalizeLocation(location)}
+	return getOrFetch(c, c.skus, key, c.skuTTL, func() ([]ResourceSKU, error) {
+		query := url.Values{}
+		query.Set("$filter", fmt.Sprintf("location eq '%s'", normalizeLocation(location)))
+
+		containerID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Compute/skus", subscription)
+		result, err := genericarmclient.ListByContainerIDWithQuery[ResourceSKU](ctx, armClient, containerID, computeAPIVersion, query)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing resource SKUs in %s", location)
+		}
+
+		return result, nil
+	})
+}
+
+// Usages returns the current usage of Microsoft.Compute quotas for subscription in location
+func (c *Cache) Usages(
+	ctx context.Context,
+	armClient *genericarmclient.GenericClient,
+	subscription string,
+	location string,
+) ([]Usage, error) {
+	key := cacheKey{subscription: strings.ToLower(subscription), scope: normalizeLocation(location)}
+	return getOrFetch(c, c.usages, key, c.usageTTL, func() ([]Usage, error) {
+		containerID := fmt.Sprintf(
+			"/subscriptions/%s/providers/Microsoft.Compute/locations/%s/usages",
+			subscription,
+			normalizeLocation(location))
+		result, err := genericarmclient.ListByContainerID[Usage](ctx, armClient, containerID, computeAPIVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing compute usage in %s", location)
+		}
+
+		return result, nil
+	})
+}
+
+// ProviderResourceTypes returns the resource types of the resource provider namespace, along with the locations
+// in which each is available to subscription
+func (c *Cache) ProviderResourceTypes(
+	ctx context.Context,
+	armClient *genericarmclient.GenericClient,
+	subscription string,
+	namespace string,
+) ([]ProviderResourceType, error) {
+	key := cacheKey{subscription: strings.ToLower(subscription), scope: strings.ToLower(namespace)}
+	return getOrFetch(c, c.providers, key, c.skuTTL, func() ([]ProviderResourceType, error) {
+		id := fmt.Sprintf("/subscriptions/%s/providers/%s", subscription, namespace)
+
+		var result provider
+		_, err := armClient.GetByID(ctx, id, providersAPIVersion, &result)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting resource provider %s", namespace)
+		}
+
+		return result.ResourceTypes, nil
+	})
+}
+
+// getOrFetch returns the cached value for key, if present and not expired, or fetches and caches a new value.
+// The lock isn't held while fetching, so concurrent callers may occasionally fetch the same value.
+func getOrFetch[T any](
+	c *Cache,
+	entries map[cacheKey]cacheEntry[T],
+	key cacheKey,
+	ttl time.Duration,
+	fetch func() (T, error),
+) (T, error) {
+	c.lock.Lock()
+	entry, ok := entries[key]
+	c.lock.Unlock()
+
+	now := c.clock.Now()
+	if ok && now.Before(entry.expires) {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entries[key] = cacheEntry[T]{
+		value:   value,
+		expires: now.Add(ttl),
+	}
+
+	return value, nil
+}
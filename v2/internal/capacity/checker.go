@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+)
+
+// Checker checks that Azure has the capacity for resources before we try to create them, so that users get a precise
+// message explaining what's wrong instead of a failed PUT.
+type Checker struct {
+	cache *Cache
+}
+
+// VMRequest describes virtual machines that need capacity in a location
+type VMRequest struct {
+	Size      string   // VM size, such as "Standard_D2s_v3"
+	Zones     []string // Availability zones the VMs will be placed in, if any
+	Instances int      // Number of additional VMs needed; may be zero if only checking the size is available
+}
+
+// NewChecker creates a new Checker using the given cache
+func NewChecker(cache *Cache) *Checker {
+	return &Checker{
+		cache: cache,
+	}
+}
+
+// CheckVirtualMachines checks that the requested VM sizes are available to subscription in location (and the
+// requested zones), and that there is enough vCPU quota for the additional VMs.
+// Returns a message explaining the problem, or an empty string if there's capacity for the VMs.
+func (c *Checker) CheckVirtualMachines(
+	ctx context.Context,
+	armClient *genericarmclient.GenericClient,
+	subscription string,
+	location string,
+	requests ...VMRequest,
+) (string, error) {
+	skus, err := c.cache.ResourceSKUs(ctx, armClient, subscription, location)
+	if err != nil {
+		return "", err
+	}
+
+	vmSKUs := make(map[string]*ResourceSKU, len(skus))
+	for i := range skus {
+		sku := &skus[i]
+		if strings.EqualFold(sku.ResourceType, virtualMachinesResourceType) {
+			vmSKUs[strings.ToLower(sku.Name)] = sku
+		}
+	}
+
+	// vCPUs needed, by quota name
+	required := make(map[string]int64)
+	for _, request := range requests {
+		sku, ok := vmSKUs[strings.ToLower(request.Size)]
+		if !ok {
+			return fmt.Sprintf(
+				"VM size %q is not available in location %q. Choose a size listed by 'az vm list-skus --location %s'",
+				request.Size,
+				location,
+				normalizeLocation(location)), nil
+		}
+
+		if reason, restricted := sku.LocationRestriction(location); restricted {
+			return fmt.Sprintf(
+				"VM size %q is not available to subscription %q in location %q (%s). Choose a different size or location, or request access to the size",
+				request.Size,
+				subscription,
+				location,
+				reason), nil
+		}
+
+		if unavailable := sku.UnavailableZones(location, request.Zones); len(unavailable) > 0 {
+			return fmt.Sprintf(
+				"VM size %q is not available in zone(s) %s of location %q. Choose different zones, or a different size",
+				request.Size,
+				strings.Join(unavailable, ", "),
+				location), nil
+		}
+
+		if request.Instances <= 0 {
+			continue
+		}
+
+		vCPUs, ok := sku.VCPUs()
+		if !ok {
+			// Can't check quota without knowing the size of the VM
+			continue
+		}
+
+		cores := int64(vCPUs * request.Instances)
+		required[sku.Family] += cores
+		required[totalRegionalCores] += cores
+	}
+
+	if len(required) == 0 {
+		return "", nil
+	}
+
+	usages, err := c.cache.Usages(ctx, armClient, subscription, location)
+	if err != nil {
+		return "", err
+	}
+
+	var problems []string
+	for _, usage := range usages {
+		needed, ok := required[usage.Name.Value]
+		if !ok || needed <= usage.Available() {
+			continue
+		}
+
+		problems = append(problems, fmt.Sprintf(
+			"%q needs %d more, but only %d of %d are available",
+			usage.Name.LocalizedValue,
+			needed,
+			maxInt64(usage.Available(), 0),
+			usage.Limit))
+	}
+
+	if len(problems) == 0 {
+		return "", nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Sprintf(
+		"Insufficient vCPU quota for subscription %q in location %q: %s. Request a quota increase, or choose a different size or location",
+		subscription,
+		location,
+		strings.Join(problems, "; ")), nil
+}
+
+// CheckResourceTypeLocation checks that resourceType of the resource provider namespace is available to
+// subscription in location.
+// Returns a message explaining the problem, or an empty string if the resource type is available.
+func (c *Checker) CheckResourceTypeLocation(
+	ctx context.Context,
+	armClient *genericarmclient.GenericClient,
+	subscription string,
+	namespace string,
+	resourceType string,
+	location string,
+) (string, error) {
+	resourceTypes, err := c.cache.ProviderResourceTypes(ctx, armClient, subscription, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	for _, rt := range resourceTypes {
+		if !strings.EqualFold(rt.ResourceType, resourceType) {
+			continue
+		}
+
+		if containsLocation(rt.Locations, location) {
+			return "", nil
+		}
+
+		return fmt.Sprintf(
+			"%s/%s is not available in location %q. Choose one of: %s",
+			namespace,
+			resourceType,
+			location,
+			strings.Join(rt.Locations, ", ")), nil
+	}
+
+	// If the provider doesn't list the resource type, we don't know enough to block
+	return "", nil
+}
+
+func maxInt64(a int64, b int64) int64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
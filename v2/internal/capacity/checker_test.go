@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/testcommon"
+)
+
+// These tests replay recordings of the Compute Resource SKUs, Compute Usage and Resource Providers APIs.
+// The recordings were trimmed to the handful of SKUs and quotas used by the tests.
+
+const testLocation = "westus2"
+
+func newTestContext(t *testing.T, g *WithT) testcommon.PerTestContext {
+	cfg, err := config.ReadFromEnvironment()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tc, err := testContext.ForTest(t, cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return tc
+}
+
+func Test_Cache_ResourceSKUs_RefreshesAfterTTL(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	tc := newTestContext(t, g)
+	clk := clock.NewMock()
+	cache := capacity.NewCache(clk, time.Hour, time.Minute)
+
+	skus, err := cache.ResourceSKUs(ctx, tc.AzureClient, tc.AzureSubscription, testLocation)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(skus).To(HaveLen(4))
+
+	// Served from the cache, so there's no request to ARM
+	clk.Add(30 * time.Minute)
+	skus, err = cache.ResourceSKUs(ctx, tc.AzureClient, tc.AzureSubscription, "West US 2")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(skus).To(HaveLen(4))
+
+	// Once the entry expires, we query ARM again
+	clk.Add(time.Hour)
+	skus, err = cache.ResourceSKUs(ctx, tc.AzureClient, tc.AzureSubscription, testLocation)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(skus).To(HaveLen(5))
+}
+
+func Test_Checker_CheckVirtualMachines(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	tc := newTestContext(t, g)
+	checker := capacity.NewChecker(capacity.NewCache(clock.NewMock(), capacity.DefaultSKUTTL, capacity.DefaultUsageTTL))
+
+	// All the checks below share cached responses, so only one request is made to each API
+	cases := []struct {
+		name     string
+		requests []capacity.VMRequest
+		expected string
+	}{
+		{
+			name:     "Available size within quota",
+			requests: []capacity.VMRequest{{Size: "Standard_D2s_v3", Zones: []string{"1", "3"}, Instances: 2}},
+		},
+		{
+			name:     "Size matched case-insensitively",
+			requests: []capacity.VMRequest{{Size: "standard_d2s_v3", Instances: 1}},
+		},
+		{
+			name:     "Unknown size",
+			requests: []capacity.VMRequest{{Size: "Standard_X1", Instances: 1}},
+			expected: `VM size "Standard_X1" is not available in location "westus2"`,
+		},
+		{
+			name:     "Size restricted for subscription",
+			requests: []capacity.VMRequest{{Size: "Standard_E2s_v3"}},
+			expected: `(NotAvailableForSubscription). Choose a different size or location`,
+		},
+		{
+			name:     "Size restricted in zone",
+			requests: []capacity.VMRequest{{Size: "Standard_D4s_v3", Zones: []string{"1", "3"}}},
+			expected: `VM size "Standard_D4s_v3" is not available in zone(s) 3 of location "westus2"`,
+		},
+		{
+			name:     "Family quota exceeded",
+			requests: []capacity.VMRequest{{Size: "Standard_D4s_v3", Instances: 2}},
+			expected: `"Standard DSv3 Family vCPUs" needs 8 more, but only 6 of 10 are available. Request a quota increase`,
+		},
+		{
+			name: "Family quota exceeded across requests",
+			requests: []capacity.VMRequest{
+				{Size: "Standard_D2s_v3", Instances: 2},
+				{Size: "Standard_D4s_v3", Instances: 1},
+			},
+			expected: `"Standard DSv3 Family vCPUs" needs 8 more`,
+		},
+	}
+
+	for _, c := range cases {
+		problem, err := checker.CheckVirtualMachines(ctx, tc.AzureClient, tc.AzureSubscription, testLocation, c.requests...)
+		g.Expect(err).ToNot(HaveOccurred(), c.name)
+		if c.expected == "" {
+			g.Expect(problem).To(BeEmpty(), c.name)
+		} else {
+			g.Expect(problem).To(ContainSubstring(c.expected), c.name)
+		}
+	}
+}
+
+func Test_Checker_CheckResourceTypeLocation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	tc := newTestContext(t, g)
+	checker := capacity.NewChecker(capacity.NewCache(clock.NewMock(), capacity.DefaultSKUTTL, capacity.DefaultUsageTTL))
+
+	problem, err := checker.CheckResourceTypeLocation(ctx, tc.AzureClient, tc.AzureSubscription, "Microsoft.Cache", "redis", testLocation)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(problem).To(BeEmpty())
+
+	problem, err = checker.CheckResourceTypeLocation(ctx, tc.AzureClient, tc.AzureSubscription, "Microsoft.Cache", "redisEnterprise", testLocation)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(problem).To(Equal(`Microsoft.Cache/redisEnterprise is not available in location "westus2". Choose one of: East US, North Europe`))
+
+	// Unknown resource types aren't blocked
+	problem, err = checker.CheckResourceTypeLocation(ctx, tc.AzureClient, tc.AzureSubscription, "Microsoft.Cache", "redisUnknown", testLocation)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(problem).To(BeEmpty())
+}
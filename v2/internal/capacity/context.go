@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity
+
+import (
+	"context"
+)
+
+// checkerKey is the key used to store a subscriptionChecker in a context.Context
+type checkerKey struct{}
+
+// subscriptionChecker pairs a Checker with the subscription of the resource being reconciled
+type subscriptionChecker struct {
+	checker      *Checker
+	subscription string
+}
+
+// WithChecker returns a copy of ctx carrying checker, for use by the PreReconciliationChecker of a resource in the
+// specified subscription.
+// When capacity checks are enabled, the reconciler uses this to make the checker available to resource extensions.
+func WithChecker(ctx context.Context, checker *Checker, subscription string) context.Context {
+	return context.WithValue(ctx, checkerKey{}, subscriptionChecker{checker: checker, subscription: subscription})
+}
+
+// CheckerFrom returns the Checker carried by ctx, along with the subscription to check.
+// Returns false if capacity checks aren't enabled.
+func CheckerFrom(ctx context.Context) (*Checker, string, bool) {
+	sc, ok := ctx.Value(checkerKey{}).(subscriptionChecker)
+	if !ok || sc.checker == nil || sc.subscription == "" {
+		return nil, "", false
+	}
+
+	return sc.checker, sc.subscription, true
+}
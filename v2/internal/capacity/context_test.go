@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_CheckerFrom_WhenContextHasChecker_ReturnsCheckerAndSubscription(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	checker := NewChecker(nil)
+	ctx := WithChecker(context.Background(), checker, "00000000-0000-0000-0000-000000000000")
+
+	actual, subscription, ok := CheckerFrom(ctx)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(actual).To(BeIdenticalTo(checker))
+	g.Expect(subscription).To(Equal("00000000-0000-0000-0000-000000000000"))
+}
+
+func Test_CheckerFrom_WhenContextHasNoChecker_ReturnsFalse(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	_, _, ok := CheckerFrom(context.Background())
+	g.Expect(ok).To(BeFalse())
+}
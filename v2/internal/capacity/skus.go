@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+)
+
+// ResourceSKU is a SKU of a Microsoft.Compute resource type, as returned by the Compute Resource SKUs API.
+// Only the properties we use are included.
+type ResourceSKU struct {
+	ResourceType string            `json:"resourceType,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Family       string            `json:"family,omitempty"`
+	Locations    []string          `json:"locations,omitempty"`
+	LocationInfo []SKULocationInfo `json:"locationInfo,omitempty"`
+	Capabilities []SKUCapability   `json:"capabilities,omitempty"`
+	Restrictions []SKURestriction  `json:"restrictions,omitempty"`
+}
+
+// SKULocationInfo describes where a SKU is available
+type SKULocationInfo struct {
+	Location string   `json:"location,omitempty"`
+	Zones    []string `json:"zones,omitempty"`
+}
+
+// SKUCapability is a named capability of a SKU, such as the number of vCPUs
+type SKUCapability struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// SKURestriction describes where a SKU can't be used by the subscription
+type SKURestriction struct {
+	Type            string             `json:"type,omitempty"` // Either "Location" or "Zone"
+	Values          []string           `json:"values,omitempty"`
+	RestrictionInfo SKURestrictionInfo `json:"restrictionInfo,omitempty"`
+	ReasonCode      string             `json:"reasonCode,omitempty"`
+}
+
+// SKURestrictionInfo lists the locations or zones a restriction applies to
+type SKURestrictionInfo struct {
+	Locations []string `json:"locations,omitempty"`
+	Zones     []string `json:"zones,omitempty"`
+}
+
+const (
+	restrictionTypeLocation = "Location"
+	restrictionTypeZone     = "Zone"
+
+	virtualMachinesResourceType = "virtualMachines"
+	vCPUsCapability             = "vCPUs"
+)
+
+// VCPUs returns the number of vCPUs of a virtual machine SKU, if known
+func (sku *ResourceSKU) VCPUs() (int, bool) {
+	for _, capability := range sku.Capabilities {
+		if strings.EqualFold(capability.Name, vCPUsCapability) {
+			result, err := strconv.Atoi(capability.Value)
+			return result, err == nil
+		}
+	}
+
+	return 0, false
+}
+
+// LocationRestriction returns the reason the SKU can't be used in location, if it is restricted there
+func (sku *ResourceSKU) LocationRestriction(location string) (string, bool) {
+	for _, restriction := range sku.Restrictions {
+		if restriction.Type == restrictionTypeLocation && containsLocation(restriction.Values, location) {
+			return restriction.ReasonCode, true
+		}
+	}
+
+	return "", false
+}
+
+// UnavailableZones returns those of zones in which the SKU can't be used in location
+func (sku *ResourceSKU) UnavailableZones(location string, zones []string) []string {
+	if len(zones) == 0 {
+		return nil
+	}
+
+	available := set.Make[string]()
+	for _, info := range sku.LocationInfo {
+		if normalizeLocation(info.Location) == normalizeLocation(location) {
+			available.AddAll(set.Make(info.Zones...))
+		}
+	}
+
+	for _, restriction := range sku.Restrictions {
+		if restriction.Type == restrictionTypeZone && containsLocation(restriction.RestrictionInfo.Locations, location) {
+			for _, zone := range restriction.RestrictionInfo.Zones {
+				available.Remove(zone)
+			}
+		}
+	}
+
+	var result []string
+	for _, zone := range zones {
+		if !available.Contains(zone) {
+			result = append(result, zone)
+		}
+	}
+
+	return result
+}
+
+// normalizeLocation converts a location name ("West US 2") into the form used in resource specs ("westus2")
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+func containsLocation(locations []string, location string) bool {
+	for _, l := range locations {
+		if normalizeLocation(l) == normalizeLocation(location) {
+			return true
+		}
+	}
+
+	return false
+}
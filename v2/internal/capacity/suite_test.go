@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity_test
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/Azure/azure-service-operator/v2/internal/testcommon"
+)
+
+var testContext testcommon.TestContext
+
+func setup() error {
+	recordReplay := os.Getenv("RECORD_REPLAY") != "0"
+
+	log.Println("Running test setup")
+
+	nameConfig := testcommon.NewResourceNameConfig(
+		testcommon.ResourcePrefix,
+		"-",
+		6,
+		testcommon.ResourceNamerModeRandomBasedOnTestName)
+
+	// set global test context
+	testContext = testcommon.NewTestContext(testcommon.DefaultTestRegion, recordReplay, nameConfig)
+
+	log.Println("Done with test setup")
+
+	return nil
+}
+
+func teardown() error {
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(testcommon.SetupTeardownTestMain(m, setup, teardown))
+}
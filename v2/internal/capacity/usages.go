@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package capacity
+
+// Usage is the current usage of a quota-limited resource in a location, as returned by the Compute Usage API
+type Usage struct {
+	Name         UsageName `json:"name,omitempty"`
+	CurrentValue int64     `json:"currentValue,omitempty"`
+	Limit        int64     `json:"limit,omitempty"`
+	Unit         string    `json:"unit,omitempty"`
+}
+
+// UsageName identifies a quota-limited resource
+type UsageName struct {
+	Value          string `json:"value,omitempty"`          // For example, "standardDSv3Family"
+	LocalizedValue string `json:"localizedValue,omitempty"` // For example, "Standard DSv3 Family vCPUs"
+}
+
+// totalRegionalCores is the name of the quota limiting the total number of vCPUs in a location
+const totalRegionalCores = "cores"
+
+// Available returns the amount of the resource that can still be used before the quota is reached
+func (u Usage) Available() int64 {
+	return u.Limit - u.CurrentValue
+}
+
+// ProviderResourceType describes where a resource type of a resource provider is available, as returned by the
+// Resource Providers API. Only the properties we use are included.
+type ProviderResourceType struct {
+	ResourceType string   `json:"resourceType,omitempty"`
+	Locations    []string `json:"locations,omitempty"`
+}
+
+// provider is a resource provider, as returned by the Resource Providers API
+type provider struct {
+	Namespace     string                 `json:"namespace,omitempty"`
+	ResourceTypes []ProviderResourceType `json:"resourceTypes,omitempty"`
+}
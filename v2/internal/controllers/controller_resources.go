@@ -27,10 +27,12 @@ import (
 
 	mysqlv1 "github.com/Azure/azure-service-operator/v2/api/dbformysql/v1"
 	postgresqlv1 "github.com/Azure/azure-service-operator/v2/api/dbforpostgresql/v1"
+	serviceoperatorv1 "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
 	"github.com/Azure/azure-service-operator/v2/internal/identity"
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/dataplane"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/generic"
 	mysqlreconciler "github.com/Azure/azure-service-operator/v2/internal/reconcilers/mysql"
 	postgresqlreconciler "github.com/Azure/azure-service-operator/v2/internal/reconcilers/postgresql"
@@ -51,7 +53,7 @@ func GetKnownStorageTypes(
 	options generic.Options) ([]*registration.StorageType, error) {
 
 	resourceResolver := resolver.NewResolver(kubeClient)
-	knownStorageTypes, err := getGeneratedStorageTypes(mgr, armConnectionFactory, kubeClient, resourceResolver, positiveConditions, options)
+	knownStorageTypes, err := getGeneratedStorageTypes(mgr, armConnectionFactory, credentialProvider, kubeClient, resourceResolver, positiveConditions, options)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +122,7 @@ func GetKnownStorageTypes(
 func getGeneratedStorageTypes(
 	mgr ctrl.Manager,
 	armConnectionFactory arm.ARMConnectionFactory,
+	credentialProvider identity.CredentialProvider,
 	kubeClient kubeclient.Client,
 	resourceResolver *resolver.Resolver,
 	positiveConditions *conditions.PositiveConditionBuilder,
@@ -146,6 +149,18 @@ func getGeneratedStorageTypes(
 		}
 		extension := extensions[gvk]
 
+		// Data plane resources aren't managed through ARM, so need a different reconciler
+		if _, ok := t.Obj.(genruntime.DataPlaneResource); ok {
+			augmentWithDataPlaneReconciler(
+				credentialProvider,
+				kubeClient,
+				resourceResolver,
+				positiveConditions,
+				options,
+				t)
+			continue
+		}
+
 		augmentWithARMReconciler(
 			armConnectionFactory,
 			kubeClient,
@@ -173,7 +188,26 @@ func augmentWithARMReconciler(
 		resourceResolver,
 		positiveConditions,
 		options.Config,
-		extension)
+		extension,
+		options.ResourceMetrics,
+		options.Policy,
+		options.Capacity)
+}
+
+func augmentWithDataPlaneReconciler(
+	credentialProvider identity.CredentialProvider,
+	kubeClient kubeclient.Client,
+	resourceResolver *resolver.Resolver,
+	positiveConditions *conditions.PositiveConditionBuilder,
+	options generic.Options,
+	t *registration.StorageType) {
+	t.Reconciler = dataplane.NewDataPlaneReconciler(
+		kubeClient,
+		resourceResolver,
+		positiveConditions,
+		credentialProvider,
+		options.Config,
+		nil)
 }
 
 func augmentWithPredicate(t *registration.StorageType) {
@@ -227,6 +261,7 @@ func CreateScheme() *runtime.Scheme {
 	scheme := createScheme()
 	_ = mysqlv1.AddToScheme(scheme)
 	_ = postgresqlv1.AddToScheme(scheme)
+	_ = serviceoperatorv1.AddToScheme(scheme)
 	scheme.AllKnownTypes()
 	return scheme
 }
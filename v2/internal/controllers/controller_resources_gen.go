@@ -300,6 +300,7 @@ func getKnownStorageTypes() []*registration.StorageType {
 	result = append(result, &registration.StorageType{Obj: new(cache_v20230401s.RedisFirewallRule)})
 	result = append(result, &registration.StorageType{Obj: new(cache_v20230401s.RedisLinkedServer)})
 	result = append(result, &registration.StorageType{Obj: new(cache_v20230401s.RedisPatchSchedule)})
+	result = append(result, &registration.StorageType{Obj: new(cache_v20230401s.RedisRegenerateKey)})
 	result = append(result, &registration.StorageType{Obj: new(cache_v20230701s.RedisEnterprise)})
 	result = append(result, &registration.StorageType{Obj: new(cache_v20230701s.RedisEnterpriseDatabase)})
 	result = append(result, &registration.StorageType{Obj: new(cdn_v20210601s.Profile)})
@@ -1020,13 +1021,15 @@ func getKnownTypes() []client.Object {
 		new(cache_v20230401.Redis),
 		new(cache_v20230401.RedisFirewallRule),
 		new(cache_v20230401.RedisLinkedServer),
-		new(cache_v20230401.RedisPatchSchedule))
+		new(cache_v20230401.RedisPatchSchedule),
+		new(cache_v20230401.RedisRegenerateKey))
 	result = append(
 		result,
 		new(cache_v20230401s.Redis),
 		new(cache_v20230401s.RedisFirewallRule),
 		new(cache_v20230401s.RedisLinkedServer),
-		new(cache_v20230401s.RedisPatchSchedule))
+		new(cache_v20230401s.RedisPatchSchedule),
+		new(cache_v20230401s.RedisRegenerateKey))
 	result = append(result, new(cache_v20230701.RedisEnterprise), new(cache_v20230701.RedisEnterpriseDatabase))
 	result = append(result, new(cache_v20230701s.RedisEnterprise), new(cache_v20230701s.RedisEnterpriseDatabase))
 	result = append(result, new(cdn_v20210601.Profile), new(cdn_v20210601.ProfilesEndpoint))
@@ -1634,6 +1637,7 @@ func getResourceExtensions() []genruntime.ResourceExtension {
 	result = append(result, &cache_customizations.RedisFirewallRuleExtension{})
 	result = append(result, &cache_customizations.RedisLinkedServerExtension{})
 	result = append(result, &cache_customizations.RedisPatchScheduleExtension{})
+	result = append(result, &cache_customizations.RedisRegenerateKeyExtension{})
 	result = append(result, &cdn_customizations.ProfileExtension{})
 	result = append(result, &cdn_customizations.ProfilesEndpointExtension{})
 	result = append(result, &compute_customizations.DiskEncryptionSetExtension{})
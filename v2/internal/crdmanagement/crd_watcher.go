@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package crdmanagement
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/internal/util/match"
+)
+
+// ControllerRegistry starts controllers for kinds of resource on demand
+type ControllerRegistry interface {
+	// RegisterAfterStart starts the controllers for the given kinds once the manager is running, returning the kinds
+	// for which controllers were newly started
+	RegisterAfterStart(kinds ...schema.GroupKind) ([]schema.GroupKind, error)
+
+	// StorageVersion returns the version of the storage type for the given kind, if known
+	StorageVersion(gk schema.GroupKind) (string, bool)
+}
+
+// CRDWatcher starts the controller for a kind of resource once its CRD becomes ready, so that CRDs installed after the
+// operator has started (say, by Helm or another tool) are reconciled without requiring a restart of the operator.
+type CRDWatcher struct {
+	kubeClient kubeclient.Client
+	registry   ControllerRegistry
+	patterns   string
+	recorder   record.EventRecorder
+	log        logr.Logger
+}
+
+var _ reconcile.Reconciler = &CRDWatcher{}
+
+// NewCRDWatcher creates a new CRDWatcher.
+// patterns is a ';' delimited list of CRD patterns; if set, only CRDs matching one of them will have their controller
+// started. If empty, controllers are started for all ASO CRDs.
+func NewCRDWatcher(
+	kubeClient kubeclient.Client,
+	registry ControllerRegistry,
+	patterns string,
+	recorder record.EventRecorder,
+	log logr.Logger,
+) *CRDWatcher {
+	return &CRDWatcher{
+		kubeClient: kubeClient,
+		registry:   registry,
+		patterns:   patterns,
+		recorder:   recorder,
+		log:        log,
+	}
+}
+
+// SetupWithManager registers the CRDWatcher to watch ASO CRDs
+func (w *CRDWatcher) SetupWithManager(mgr ctrl.Manager) error {
+	isOperatorCRD := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[ServiceOperatorAppLabel] == ServiceOperatorAppValue
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("crd-watcher").
+		For(&apiextensions.CustomResourceDefinition{}, builder.WithPredicates(isOperatorCRD)).
+		Complete(w)
+}
+
+// Reconcile starts the controller for the kind defined by a CRD, if the CRD is ready
+func (w *CRDWatcher) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var crd apiextensions.CustomResourceDefinition
+	err := w.kubeClient.Get(ctx, req.NamespacedName, &crd)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	gk := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+	log := w.log.WithValues("crd", crd.Name)
+
+	if !w.matchesPatterns(crd) {
+		log.V(Debug).Info("Ignoring CRD not matched by CRD patterns", "patterns", w.patterns)
+		return ctrl.Result{}, nil
+	}
+
+	// If the CRD isn't ready yet, we'll be triggered again when it's updated
+	ready, reason := w.isReady(crd, gk)
+	if !ready {
+		log.V(Verbose).Info("CRD is not ready for its controller to be started", "reason", reason)
+		return ctrl.Result{}, nil
+	}
+
+	started, err := w.registry.RegisterAfterStart(gk)
+	if err != nil {
+		w.recorder.Eventf(&crd, corev1.EventTypeWarning, "ControllerStartFailed", "Unable to start controller for %s: %s", gk, err)
+		return ctrl.Result{}, err
+	}
+
+	if len(started) > 0 {
+		log.V(Status).Info("Started controller for newly ready CRD", "kind", gk)
+		w.recorder.Eventf(&crd, corev1.EventTypeNormal, "ControllerStarted", "Started controller for %s", gk)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (w *CRDWatcher) matchesPatterns(crd apiextensions.CustomResourceDefinition) bool {
+	if w.patterns == "" {
+		return true
+	}
+
+	matcher := match.NewStringMatcher(w.patterns)
+	return matcher.Matches(makeMatchString(crd)).Matched
+}
+
+// isReady returns true if the controller for the kind defined by crd can be started, or a reason if it can't
+func (w *CRDWatcher) isReady(crd apiextensions.CustomResourceDefinition, gk schema.GroupKind) (bool, string) {
+	if !IsCRDEstablished(crd) {
+		return false, "CRD is not established"
+	}
+
+	version, ok := w.registry.StorageVersion(gk)
+	if !ok {
+		// Most likely a CRD from a newer version of the operator
+		return false, "kind is not supported by this version of the operator"
+	}
+
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Served {
+			return true, ""
+		}
+	}
+
+	// Most likely a CRD from an older version of the operator, which will need to be upgraded first
+	return false, "CRD does not serve storage version " + version
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package crdmanagement_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/Azure/azure-service-operator/v2/internal/crdmanagement"
+	"github.com/Azure/azure-service-operator/v2/internal/testcommon"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+)
+
+// fakeRegistry is a ControllerRegistry that records which kinds have been registered
+type fakeRegistry struct {
+	versions map[schema.GroupKind]string
+	active   map[schema.GroupKind]bool
+}
+
+var _ crdmanagement.ControllerRegistry = &fakeRegistry{}
+
+func (r *fakeRegistry) RegisterAfterStart(kinds ...schema.GroupKind) ([]schema.GroupKind, error) {
+	var result []schema.GroupKind
+	for _, gk := range kinds {
+		if _, ok := r.versions[gk]; !ok || r.active[gk] {
+			continue
+		}
+
+		r.active[gk] = true
+		result = append(result, gk)
+	}
+
+	return result, nil
+}
+
+func (r *fakeRegistry) StorageVersion(gk schema.GroupKind) (string, bool) {
+	version, ok := r.versions[gk]
+	return version, ok
+}
+
+type crdWatcherTestContext struct {
+	kubeClient kubeclient.Client
+	registry   *fakeRegistry
+	recorder   *record.FakeRecorder
+	watcher    *crdmanagement.CRDWatcher
+}
+
+var testCRDKind = schema.GroupKind{Group: "testrp.azure.com", Kind: "test"}
+
+func newCRDWatcherTestContext(t *testing.T, patterns string) *crdWatcherTestContext {
+	result := &crdWatcherTestContext{
+		kubeClient: newFakeKubeClient(newTestScheme()),
+		registry: &fakeRegistry{
+			versions: map[schema.GroupKind]string{
+				testCRDKind: "v1alpha1api20181130",
+			},
+			active: map[schema.GroupKind]bool{},
+		},
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	result.watcher = crdmanagement.NewCRDWatcher(
+		result.kubeClient,
+		result.registry,
+		patterns,
+		result.recorder,
+		testcommon.NewTestLogger(t))
+
+	return result
+}
+
+func (tc *crdWatcherTestContext) reconcile(g *WithT, crd apiextensions.CustomResourceDefinition) {
+	g.Expect(tc.kubeClient.Create(context.Background(), &crd)).To(Succeed())
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: crd.Name}}
+	result, err := tc.watcher.Reconcile(context.Background(), req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+}
+
+func makeEstablishedCRD(name string) apiextensions.CustomResourceDefinition {
+	crd := makeOperatorCRD(name)
+	crd.Status.Conditions = []apiextensions.CustomResourceDefinitionCondition{
+		{Type: apiextensions.Established, Status: apiextensions.ConditionTrue},
+	}
+
+	return crd
+}
+
+func Test_CRDWatcher_EstablishedCRD_StartsController(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tc := newCRDWatcherTestContext(t, "")
+	tc.reconcile(g, makeEstablishedCRD("test"))
+
+	g.Expect(tc.registry.active).To(HaveKey(testCRDKind))
+	g.Expect(tc.recorder.Events).To(Receive(ContainSubstring("ControllerStarted")))
+}
+
+func Test_CRDWatcher_CRDNotEstablished_DoesNotStartController(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tc := newCRDWatcherTestContext(t, "")
+	tc.reconcile(g, makeOperatorCRD("test"))
+
+	g.Expect(tc.registry.active).To(BeEmpty())
+	g.Expect(tc.recorder.Events).ToNot(Receive())
+}
+
+func Test_CRDWatcher_CRDNotServingStorageVersion_DoesNotStartController(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tc := newCRDWatcherTestContext(t, "")
+	tc.registry.versions[testCRDKind] = "v1api20230101storage"
+	tc.reconcile(g, makeEstablishedCRD("test"))
+
+	g.Expect(tc.registry.active).To(BeEmpty())
+}
+
+func Test_CRDWatcher_CRDNotMatchingPatterns_DoesNotStartController(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tc := newCRDWatcherTestContext(t, "otherrp.azure.com/*")
+	tc.reconcile(g, makeEstablishedCRD("test"))
+
+	g.Expect(tc.registry.active).To(BeEmpty())
+}
+
+func Test_CRDWatcher_CRDMatchingPatterns_StartsController(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tc := newCRDWatcherTestContext(t, "otherrp.azure.com/*;testrp.azure.com/*")
+	tc.reconcile(g, makeEstablishedCRD("test"))
+
+	g.Expect(tc.registry.active).To(HaveKey(testCRDKind))
+}
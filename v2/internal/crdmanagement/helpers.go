@@ -100,6 +100,17 @@ func FilterKnownTypesByReadyCRDs(
 	return result, nil
 }
 
+// IsCRDEstablished returns true if the API server has accepted crd and is serving the resources it defines
+func IsCRDEstablished(crd apiextensions.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensions.Established {
+			return condition.Status == apiextensions.ConditionTrue
+		}
+	}
+
+	return false
+}
+
 func makeMatchString(crd apiextensions.CustomResourceDefinition) string {
 	group := crd.Spec.Group
 	kind := crd.Spec.Names.Kind
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package crdmanagement
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+// establishedPollInterval is how often we check whether newly installed CRDs have been established
+const establishedPollInterval = 5 * time.Second
+
+// ControllerStarter starts the controllers for the given kinds, returning the kinds for which controllers were newly
+// started. It's called once the CRDs for those kinds have been established.
+type ControllerStarter func(ctx context.Context, kinds []schema.GroupKind) ([]schema.GroupKind, error)
+
+// LazyInstaller installs CRDs on demand, when a namespace requests them with the CRDPattern annotation, and starts
+// their controllers without requiring a restart of the operator.
+type LazyInstaller struct {
+	crdManager       *Manager
+	kubeClient       kubeclient.Client
+	goalCRDs         []apiextensions.CustomResourceDefinition
+	recorder         record.EventRecorder
+	startControllers ControllerStarter
+	log              logr.Logger
+}
+
+var _ reconcile.Reconciler = &LazyInstaller{}
+
+func NewLazyInstaller(
+	crdManager *Manager,
+	kubeClient kubeclient.Client,
+	goalCRDs []apiextensions.CustomResourceDefinition,
+	recorder record.EventRecorder,
+	startControllers ControllerStarter,
+	log logr.Logger,
+) *LazyInstaller {
+	return &LazyInstaller{
+		crdManager:       crdManager,
+		kubeClient:       kubeClient,
+		goalCRDs:         goalCRDs,
+		recorder:         recorder,
+		startControllers: startControllers,
+		log:              log,
+	}
+}
+
+// SetupWithManager registers the LazyInstaller to watch for namespaces requesting CRDs
+func (l *LazyInstaller) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("crd-lazy-installer").
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		Complete(l)
+}
+
+// Reconcile installs the CRDs requested by a namespace, then starts their controllers
+func (l *LazyInstaller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var namespace corev1.Namespace
+	err := l.kubeClient.Get(ctx, req.NamespacedName, &namespace)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	patterns := namespace.Annotations[annotations.CRDPattern]
+	if patterns == "" {
+		return ctrl.Result{}, nil
+	}
+
+	log := l.log.WithValues("namespace", namespace.Name, "patterns", patterns)
+
+	existingCRDs, err := l.crdManager.ListOperatorCRDs(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	instructions, err := l.crdManager.DetermineCRDsToInstallOrUpgrade(l.goalCRDs, existingCRDs, patterns)
+	if err != nil {
+		// Most likely a pattern that doesn't match any CRD; retrying won't help until the annotation is changed
+		log.Error(err, "Invalid CRD pattern")
+		l.recorder.Eventf(&namespace, corev1.EventTypeWarning, "InvalidCRDPattern", "Unable to install CRDs matching %q: %s", patterns, err)
+		return ctrl.Result{}, nil
+	}
+
+	requested := matchedByPattern(instructions)
+	err = l.crdManager.InstallCRDs(ctx, requested)
+	if err != nil {
+		l.recorder.Eventf(&namespace, corev1.EventTypeWarning, "CRDInstallationFailed", "Unable to install CRDs matching %q: %s", patterns, err)
+		return ctrl.Result{}, err
+	}
+
+	// Controllers can't be started until the API server is serving the new kinds
+	kinds := make([]schema.GroupKind, 0, len(requested))
+	for _, instruction := range requested {
+		var crd apiextensions.CustomResourceDefinition
+		err = l.kubeClient.Get(ctx, types.NamespacedName{Name: instruction.CRD.Name}, &crd)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if !IsCRDEstablished(crd) {
+			log.V(Verbose).Info("Waiting for CRD to be established", "crd", crd.Name)
+			return ctrl.Result{RequeueAfter: establishedPollInterval}, nil
+		}
+
+		kinds = append(kinds, schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind})
+	}
+
+	started, err := l.startControllers(ctx, kinds)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(started) > 0 {
+		names := make([]string, 0, len(started))
+		for _, gk := range started {
+			names = append(names, gk.String())
+		}
+
+		log.V(Status).Info("Installed CRDs and started controllers", "kinds", names)
+		l.recorder.Eventf(
+			&namespace,
+			corev1.EventTypeNormal,
+			"CRDsInstalled",
+			"Installed CRDs and started controllers for %s",
+			strings.Join(names, ", "))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// matchedByPattern returns the instructions for CRDs that were matched by a pattern, excluding any other existing CRDs
+func matchedByPattern(instructions []*CRDInstallationInstruction) []*CRDInstallationInstruction {
+	var result []*CRDInstallationInstruction
+	for _, instruction := range instructions {
+		if instruction.FilterResult == MatchedPattern {
+			result = append(result, instruction)
+		}
+	}
+
+	return result
+}
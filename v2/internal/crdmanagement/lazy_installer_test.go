@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package crdmanagement_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Azure/azure-service-operator/v2/internal/crdmanagement"
+	"github.com/Azure/azure-service-operator/v2/internal/testcommon"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+type lazyInstallerTestContext struct {
+	kubeClient kubeclient.Client
+	recorder   *record.FakeRecorder
+	installer  *crdmanagement.LazyInstaller
+	started    []schema.GroupKind
+}
+
+func newLazyInstallerTestContext(t *testing.T, goalCRDs []apiextensions.CustomResourceDefinition) *lazyInstallerTestContext {
+	s := newTestScheme()
+	_ = corev1.AddToScheme(s)
+
+	// The CRD status is a subresource, so (re)applying a CRD doesn't reset whether it's established
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithStatusSubresource(&apiextensions.CustomResourceDefinition{}).
+		Build()
+
+	result := &lazyInstallerTestContext{
+		kubeClient: kubeclient.NewClient(fakeClient),
+		recorder:   record.NewFakeRecorder(10),
+	}
+
+	crdManager := crdmanagement.NewManager(testcommon.NewTestLogger(t), result.kubeClient)
+	result.installer = crdmanagement.NewLazyInstaller(
+		crdManager,
+		result.kubeClient,
+		goalCRDs,
+		result.recorder,
+		func(_ context.Context, kinds []schema.GroupKind) ([]schema.GroupKind, error) {
+			result.started = append(result.started, kinds...)
+			return kinds, nil
+		},
+		testcommon.NewTestLogger(t))
+
+	return result
+}
+
+func (tc *lazyInstallerTestContext) createNamespace(g *WithT, patterns string) ctrl.Request {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+		},
+	}
+
+	if patterns != "" {
+		namespace.Annotations = map[string]string{
+			annotations.CRDPattern: patterns,
+		}
+	}
+
+	g.Expect(tc.kubeClient.Create(context.Background(), namespace)).To(Succeed())
+	return ctrl.Request{NamespacedName: types.NamespacedName{Name: namespace.Name}}
+}
+
+func (tc *lazyInstallerTestContext) establishCRD(g *WithT, name string) {
+	ctx := context.Background()
+
+	var crd apiextensions.CustomResourceDefinition
+	g.Expect(tc.kubeClient.Get(ctx, types.NamespacedName{Name: name}, &crd)).To(Succeed())
+
+	crd.Status.Conditions = append(crd.Status.Conditions, apiextensions.CustomResourceDefinitionCondition{
+		Type:   apiextensions.Established,
+		Status: apiextensions.ConditionTrue,
+	})
+	g.Expect(tc.kubeClient.Status().Update(ctx, &crd)).To(Succeed())
+}
+
+func makeOperatorCRD(name string) apiextensions.CustomResourceDefinition {
+	crd := makeBasicCRD(name)
+	crd.Labels = map[string]string{
+		crdmanagement.ServiceOperatorAppLabel: crdmanagement.ServiceOperatorAppValue,
+	}
+
+	return crd
+}
+
+func Test_LazyInstaller_NamespaceWithoutPattern_InstallsNothing(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	tc := newLazyInstallerTestContext(t, []apiextensions.CustomResourceDefinition{makeOperatorCRD("test")})
+	req := tc.createNamespace(g, "")
+
+	result, err := tc.installer.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+
+	var crds apiextensions.CustomResourceDefinitionList
+	g.Expect(tc.kubeClient.List(ctx, &crds)).To(Succeed())
+	g.Expect(crds.Items).To(BeEmpty())
+	g.Expect(tc.started).To(BeEmpty())
+}
+
+func Test_LazyInstaller_NamespaceWithPattern_InstallsCRDsAndStartsControllers(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	goal := []apiextensions.CustomResourceDefinition{
+		makeOperatorCRD("test1"),
+		makeOperatorCRD("test2"),
+	}
+	goal[1].Spec.Group = "otherrp.azure.com"
+	goal[1].Name = "test2.otherrp.azure.com"
+
+	tc := newLazyInstallerTestContext(t, goal)
+	req := tc.createNamespace(g, "testrp.azure.com/*")
+
+	// Controllers aren't started until the CRD is established
+	result, err := tc.installer.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).ToNot(BeZero())
+	g.Expect(tc.started).To(BeEmpty())
+
+	var crds apiextensions.CustomResourceDefinitionList
+	g.Expect(tc.kubeClient.List(ctx, &crds)).To(Succeed())
+	g.Expect(crds.Items).To(HaveLen(1))
+	g.Expect(crds.Items[0].Name).To(Equal("test1.testrp.azure.com"))
+
+	tc.establishCRD(g, "test1.testrp.azure.com")
+
+	result, err = tc.installer.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+	g.Expect(tc.started).To(ConsistOf(schema.GroupKind{Group: "testrp.azure.com", Kind: "test1"}))
+	g.Expect(tc.recorder.Events).To(Receive(ContainSubstring("CRDsInstalled")))
+}
+
+func Test_LazyInstaller_PatternMatchingNothing_RecordsWarning(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	tc := newLazyInstallerTestContext(t, []apiextensions.CustomResourceDefinition{makeOperatorCRD("test")})
+	req := tc.createNamespace(g, "unknownrp.azure.com/*")
+
+	result, err := tc.installer.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+	g.Expect(tc.started).To(BeEmpty())
+	g.Expect(tc.recorder.Events).To(Receive(ContainSubstring("InvalidCRDPattern")))
+}
+
+func Test_IsCRDEstablished(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	crd := makeBasicCRD("test")
+	g.Expect(crdmanagement.IsCRDEstablished(crd)).To(BeFalse())
+
+	crd.Status.Conditions = []apiextensions.CustomResourceDefinitionCondition{
+		{Type: apiextensions.Established, Status: apiextensions.ConditionFalse},
+	}
+	g.Expect(crdmanagement.IsCRDEstablished(crd)).To(BeFalse())
+
+	crd.Status.Conditions[0].Status = apiextensions.ConditionTrue
+	g.Expect(crdmanagement.IsCRDEstablished(crd)).To(BeTrue())
+}
@@ -226,10 +226,46 @@ func (m *Manager) DetermineCRDsToInstallOrUpgrade(
 	return results, nil
 }
 
+// ApplyCRDs applies the CRDs that need installing or upgrading, then restarts the operator so that the new CRDs can
+// be reconciled.
 func (m *Manager) ApplyCRDs(
 	ctx context.Context,
 	instructions []*CRDInstallationInstruction,
 ) error {
+	applied, err := m.applyCRDs(ctx, instructions)
+	if err != nil {
+		return err
+	}
+
+	if applied == 0 {
+		m.logger.V(Status).Info("Successfully reconciled CRDs because there were no CRDs to update.")
+		return nil
+	}
+
+	// If we make it to here, we have successfully updated all the CRDs we needed to. We need to kill the pod and let it restart so
+	// that the new shape CRDs can be reconciled.
+	m.logger.V(Status).Info("Restarting operator pod after updating CRDs", "count", applied)
+	os.Exit(0)
+
+	// Will never get here
+	return nil
+}
+
+// InstallCRDs applies the CRDs that need installing or upgrading without restarting the operator.
+// Callers are responsible for starting controllers for the CRDs once they are established; see IsCRDEstablished.
+func (m *Manager) InstallCRDs(
+	ctx context.Context,
+	instructions []*CRDInstallationInstruction,
+) error {
+	_, err := m.applyCRDs(ctx, instructions)
+	return err
+}
+
+// applyCRDs applies the CRDs that need installing or upgrading, returning the number applied
+func (m *Manager) applyCRDs(
+	ctx context.Context,
+	instructions []*CRDInstallationInstruction,
+) (int, error) {
 	var instructionsToApply []*CRDInstallationInstruction
 
 	for _, item := range instructions {
@@ -251,8 +287,7 @@ func (m *Manager) ApplyCRDs(
 	}
 
 	if len(instructionsToApply) == 0 {
-		m.logger.V(Status).Info("Successfully reconciled CRDs because there were no CRDs to update.")
-		return nil
+		return 0, nil
 	}
 
 	m.logger.V(Status).Info("Will apply CRDs", "count", len(instructionsToApply))
@@ -280,19 +315,13 @@ func (m *Manager) ApplyCRDs(
 			return nil
 		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to apply CRD %s", instruction.CRD.Name)
+			return i - 1, errors.Wrapf(err, "failed to apply CRD %s", instruction.CRD.Name)
 		}
 
 		m.logger.V(Debug).Info("Successfully applied CRD", "name", instruction.CRD.Name, "result", result)
 	}
 
-	// If we make it to here, we have successfully updated all the CRDs we needed to. We need to kill the pod and let it restart so
-	// that the new shape CRDs can be reconciled.
-	m.logger.V(Status).Info("Restarting operator pod after updating CRDs", "count", len(instructionsToApply))
-	os.Exit(0)
-
-	// Will never get here
-	return nil
+	return len(instructionsToApply), nil
 }
 
 func (m *Manager) loadCRDs(path string) ([]apiextensions.CustomResourceDefinition, error) {
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+// DefaultAggregationWindow is how long identical events are suppressed for after one has been emitted
+const DefaultAggregationWindow = 5 * time.Minute
+
+// AggregatingRecorder is a record.EventRecorder that emits at most one of each distinct event per resource in each
+// aggregation window. Resources that fail repeatedly are requeued many times, and without aggregation each attempt
+// would add another event. The number of events suppressed is included on the next event emitted.
+// Events are distinct if they differ in reason or ARM error code, so a resource failing in a new way is reported
+// straight away. Messages aren't compared, as ARM error messages often include request IDs, URLs or response bodies
+// that change on every attempt; the event emitted carries the latest message.
+type AggregatingRecorder struct {
+	recorder record.EventRecorder
+	clock    clock.Clock
+	window   time.Duration
+
+	lock      sync.Mutex
+	seen      map[aggregationKey]*aggregation
+	lastSweep time.Time
+}
+
+var _ record.EventRecorder = &AggregatingRecorder{}
+
+type aggregationKey struct {
+	uid       types.UID
+	reason    string
+	errorCode string // ARM error code, if any
+}
+
+type aggregation struct {
+	emitted    time.Time // When the last event was emitted
+	suppressed int       // Number of events suppressed since then
+}
+
+// NewAggregatingRecorder returns a recorder that aggregates the events passed to recorder over the given window
+func NewAggregatingRecorder(recorder record.EventRecorder, clk clock.Clock, window time.Duration) *AggregatingRecorder {
+	return &AggregatingRecorder{
+		recorder:  recorder,
+		clock:     clk,
+		window:    window,
+		seen:      make(map[aggregationKey]*aggregation),
+		lastSweep: clk.Now(),
+	}
+}
+
+// Event implements record.EventRecorder
+func (r *AggregatingRecorder) Event(object runtime.Object, eventtype string, reason string, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+// Eventf implements record.EventRecorder
+func (r *AggregatingRecorder) Eventf(object runtime.Object, eventtype string, reason string, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedEventf implements record.EventRecorder
+func (r *AggregatingRecorder) AnnotatedEventf(
+	object runtime.Object,
+	eventAnnotations map[string]string,
+	eventtype string,
+	reason string,
+	messageFmt string,
+	args ...interface{},
+) {
+	objMeta, err := meta.Accessor(object)
+	if err != nil {
+		// Can't aggregate without identifying the object; the underlying recorder will report the problem
+		r.recorder.AnnotatedEventf(object, eventAnnotations, eventtype, reason, messageFmt, args...)
+		return
+	}
+
+	message := fmt.Sprintf(messageFmt, args...)
+	key := aggregationKey{
+		uid:       objMeta.GetUID(),
+		reason:    reason,
+		errorCode: eventAnnotations[annotations.EventErrorCode],
+	}
+
+	suppressed, emit := r.admit(key)
+	if !emit {
+		return
+	}
+
+	if suppressed == 0 {
+		r.recorder.AnnotatedEventf(object, eventAnnotations, eventtype, reason, messageFmt, args...)
+		return
+	}
+
+	withCount := make(map[string]string, len(eventAnnotations)+1)
+	for k, v := range eventAnnotations {
+		withCount[k] = v
+	}
+	withCount[annotations.EventSuppressedCount] = strconv.Itoa(suppressed)
+
+	r.recorder.AnnotatedEventf(object, withCount, eventtype, reason, "%s", message+describeSuppressed(suppressed))
+}
+
+// admit returns true if an event with the given key should be emitted now, along with the number of similar events
+// suppressed since the last one was emitted.
+func (r *AggregatingRecorder) admit(key aggregationKey) (int, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Now()
+	r.sweep(now)
+
+	entry, ok := r.seen[key]
+	if ok && now.Sub(entry.emitted) < r.window {
+		entry.suppressed++
+		return 0, false
+	}
+
+	suppressed := 0
+	if ok {
+		suppressed = entry.suppressed
+	}
+
+	r.seen[key] = &aggregation{emitted: now}
+	return suppressed, true
+}
+
+// sweep removes entries that no longer have any effect, so that we don't retain entries for deleted resources.
+// Entries with suppressed events are kept for an additional window, in case another event arrives to report them.
+// Must be called with the lock held.
+func (r *AggregatingRecorder) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.window {
+		return
+	}
+
+	for key, entry := range r.seen {
+		age := now.Sub(entry.emitted)
+		if age >= 2*r.window || (age >= r.window && entry.suppressed == 0) {
+			delete(r.seen, key)
+		}
+	}
+
+	r.lastSweep = now
+}
+
+// describeSuppressed returns the suffix added to the message of an event when earlier similar events were suppressed
+func describeSuppressed(count int) string {
+	if count == 1 {
+		return " (1 similar event suppressed)"
+	}
+
+	return fmt.Sprintf(" (%d similar events suppressed)", count)
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+)
+
+type recordedEvent struct {
+	annotations map[string]string
+	eventType   string
+	reason      string
+	message     string
+}
+
+// capturingRecorder is a record.EventRecorder that captures events, including their annotations
+type capturingRecorder struct {
+	events []recordedEvent
+}
+
+func (c *capturingRecorder) Event(object runtime.Object, eventtype string, reason string, message string) {
+	c.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+func (c *capturingRecorder) Eventf(object runtime.Object, eventtype string, reason string, messageFmt string, args ...interface{}) {
+	c.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+func (c *capturingRecorder) AnnotatedEventf(_ runtime.Object, eventAnnotations map[string]string, eventtype string, reason string, messageFmt string, args ...interface{}) {
+	c.events = append(c.events, recordedEvent{
+		annotations: eventAnnotations,
+		eventType:   eventtype,
+		reason:      reason,
+		message:     fmt.Sprintf(messageFmt, args...),
+	})
+}
+
+func newTestObject(name string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID("uid-" + name),
+		},
+	}
+}
+
+func TestAggregatingRecorder_SuppressesRepeatsWithinWindow(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	inner := &capturingRecorder{}
+	clk := clock.NewMock()
+	recorder := NewAggregatingRecorder(inner, clk, time.Minute)
+	obj := newTestObject("a")
+	conflict := map[string]string{annotations.EventErrorCode: "Conflict"}
+
+	for i := 0; i < 4; i++ {
+		recorder.AnnotatedEventf(obj, conflict, v1.EventTypeWarning, ReasonAzureRequestFailed, "resource %s is busy", "a")
+		clk.Add(10 * time.Second)
+	}
+
+	g.Expect(inner.events).To(HaveLen(1))
+	g.Expect(inner.events[0].message).To(Equal("resource a is busy"))
+
+	// Once the window has passed, the next event is emitted along with the number suppressed
+	clk.Add(time.Minute)
+	recorder.AnnotatedEventf(obj, conflict, v1.EventTypeWarning, ReasonAzureRequestFailed, "resource %s is busy", "a")
+
+	g.Expect(inner.events).To(HaveLen(2))
+	g.Expect(inner.events[1].message).To(Equal("resource a is busy (3 similar events suppressed)"))
+	g.Expect(inner.events[1].annotations).To(Equal(map[string]string{
+		annotations.EventErrorCode:       "Conflict",
+		annotations.EventSuppressedCount: "3",
+	}))
+}
+
+func TestAggregatingRecorder_KeysByResourceAndReason(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	inner := &capturingRecorder{}
+	recorder := NewAggregatingRecorder(inner, clock.NewMock(), time.Minute)
+
+	recorder.Event(newTestObject("a"), v1.EventTypeWarning, ReasonAzureRequestFailed, "failed")
+	recorder.Event(newTestObject("a"), v1.EventTypeWarning, ReasonCreateOrUpdateFailed, "failed")
+	recorder.Event(newTestObject("b"), v1.EventTypeWarning, ReasonAzureRequestFailed, "failed")
+	recorder.Event(newTestObject("b"), v1.EventTypeWarning, ReasonAzureRequestFailed, "failed")
+
+	g.Expect(inner.events).To(HaveLen(3))
+}
+
+func TestAggregatingRecorder_WhenErrorChanges_EmitsImmediately(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	inner := &capturingRecorder{}
+	recorder := NewAggregatingRecorder(inner, clock.NewMock(), time.Minute)
+	obj := newTestObject("a")
+
+	conflict := map[string]string{annotations.EventErrorCode: "Conflict"}
+	quota := map[string]string{annotations.EventErrorCode: "QuotaExceeded"}
+
+	recorder.AnnotatedEventf(obj, conflict, v1.EventTypeWarning, ReasonAzureRequestFailed, "%s", "request failed")
+	recorder.AnnotatedEventf(obj, conflict, v1.EventTypeWarning, ReasonAzureRequestFailed, "%s", "request failed")
+
+	// A different error code is a new failure, even if the message is the same
+	recorder.AnnotatedEventf(obj, quota, v1.EventTypeWarning, ReasonAzureRequestFailed, "%s", "request failed")
+
+	g.Expect(inner.events).To(HaveLen(2))
+	g.Expect(inner.events[1].annotations).To(Equal(quota))
+}
+
+func TestAggregatingRecorder_WhenOnlyMessageChanges_SuppressesAndEmitsLatestMessage(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	inner := &capturingRecorder{}
+	clk := clock.NewMock()
+	recorder := NewAggregatingRecorder(inner, clk, time.Minute)
+	obj := newTestObject("a")
+	conflict := map[string]string{annotations.EventErrorCode: "Conflict"}
+
+	// ARM error messages vary between attempts, such as by including a request ID
+	for i := 0; i < 3; i++ {
+		recorder.AnnotatedEventf(obj, conflict, v1.EventTypeWarning, ReasonAzureRequestFailed, "request %d failed", i)
+		clk.Add(10 * time.Second)
+	}
+
+	g.Expect(inner.events).To(HaveLen(1))
+	g.Expect(inner.events[0].message).To(Equal("request 0 failed"))
+
+	clk.Add(time.Minute)
+	recorder.AnnotatedEventf(obj, conflict, v1.EventTypeWarning, ReasonAzureRequestFailed, "request %d failed", 3)
+
+	g.Expect(inner.events).To(HaveLen(2))
+	g.Expect(inner.events[1].message).To(Equal("request 3 failed (2 similar events suppressed)"))
+}
+
+func TestAggregatingRecorder_SweepsExpiredEntries(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	inner := &capturingRecorder{}
+	clk := clock.NewMock()
+	recorder := NewAggregatingRecorder(inner, clk, time.Minute)
+
+	recorder.Event(newTestObject("a"), v1.EventTypeNormal, ReasonBeginCreateOrUpdate, "created")
+	recorder.Event(newTestObject("b"), v1.EventTypeWarning, ReasonAzureRequestFailed, "failed")
+	recorder.Event(newTestObject("b"), v1.EventTypeWarning, ReasonAzureRequestFailed, "failed")
+
+	// Entries without suppressed events are dropped once they expire, others are kept to report their count
+	clk.Add(time.Minute)
+	recorder.Event(newTestObject("c"), v1.EventTypeNormal, ReasonBeginCreateOrUpdate, "created")
+	g.Expect(recorder.seen).To(HaveLen(2))
+
+	clk.Add(time.Minute)
+	recorder.Event(newTestObject("c"), v1.EventTypeNormal, ReasonBeginCreateOrUpdate, "created")
+	g.Expect(recorder.seen).To(HaveLen(1))
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package events
+
+import (
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// Reasons for Normal events. These are a stable contract, documented in the events guide; don't change them.
+const (
+	ReasonCredentialFrom      = "CredentialFrom"
+	ReasonBeginCreateOrUpdate = "BeginCreateOrUpdate"
+	ReasonBeginAction         = "BeginAction"
+	ReasonBeginDelete         = "BeginDelete"
+	ReasonMonitorDelete       = "MonitorDelete"
+)
+
+// Reasons for Warning events, in addition to the well known condition reasons (see ReasonForError).
+// These are a stable contract, documented in the events guide; don't change them.
+const (
+	ReasonOverlap              = "Overlap"
+	ReasonAzureRequestFailed   = "AzureRequestFailed"
+	ReasonCreateOrUpdateFailed = "CreateOrUpdateFailed"
+	ReasonDeleteFailed         = "DeleteFailed"
+)
+
+// Operation identifies what the operator was doing when an error occurred
+type Operation string
+
+const (
+	OperationCreateOrUpdate = Operation("CreateOrUpdate")
+	OperationDelete         = Operation("Delete")
+)
+
+// conditionReasons are the well known condition reasons, each of which is also used as the reason of Warning events.
+// Condition reasons not included here are ARM error codes, which are unbounded, so are reported as
+// ReasonAzureRequestFailed with the code in an annotation.
+var conditionReasons = map[string]struct{}{
+	conditions.ReasonSubscriptionMismatch.Name:                {},
+	conditions.ReasonSecretNotFound.Name:                      {},
+	conditions.ReasonConfigMapNotFound.Name:                   {},
+	conditions.ReasonReferenceNotFound.Name:                   {},
+	conditions.ReasonWaitingForOwner.Name:                     {},
+	conditions.ReasonAzureResourceNotFound.Name:               {},
+	conditions.ReasonAdditionalKubernetesObjWriteFailure.Name: {},
+	conditions.ReasonReconciliationFailedPermanently.Name:     {},
+	conditions.ReasonReconcileBlocked.Name:                    {},
+	conditions.ReasonReconcilePostponed.Name:                  {},
+	conditions.ReasonPostReconcileFailure.Name:                {},
+	conditions.ReasonPolicyViolation.Name:                     {},
+	conditions.ReasonFailed.Name:                              {},
+}
+
+// ReasonForError returns the reason of the Warning event for an error encountered during the given operation.
+// Errors that impact the Ready condition use the condition reason, if it's well known, or ReasonAzureRequestFailed
+// if it's an ARM error code; other errors use a reason specific to the operation.
+func ReasonForError(operation Operation, err error) string {
+	if readyErr, ok := conditions.AsReadyConditionImpactingError(err); ok {
+		if _, known := conditionReasons[readyErr.Reason]; known {
+			return readyErr.Reason
+		}
+
+		return ReasonAzureRequestFailed
+	}
+
+	if _, ok := asCloudError(err); ok {
+		return ReasonAzureRequestFailed
+	}
+
+	if operation == OperationDelete {
+		return ReasonDeleteFailed
+	}
+
+	return ReasonCreateOrUpdateFailed
+}
+
+// AnnotationsForError returns the annotations to attach to the Warning event for an error, capturing the details
+// of any ARM error so that tooling doesn't need to parse the event message.
+func AnnotationsForError(operation Operation, err error) map[string]string {
+	result := map[string]string{
+		annotations.EventOperation: string(operation),
+	}
+
+	if readyErr, ok := conditions.AsReadyConditionImpactingError(err); ok {
+		result[annotations.EventConditionReason] = readyErr.Reason
+	}
+
+	if cloudError, ok := asCloudError(err); ok {
+		result[annotations.EventErrorCode] = cloudError.Code()
+		if target := cloudError.Target(); target != "" {
+			result[annotations.EventErrorTarget] = target
+		}
+
+		if correlationID := cloudError.CorrelationID(); correlationID != "" {
+			result[annotations.EventCorrelationID] = correlationID
+		}
+	}
+
+	return result
+}
+
+// RecordError emits a Warning event for an error encountered during the given operation
+func RecordError(recorder record.EventRecorder, obj runtime.Object, operation Operation, err error) {
+	recorder.AnnotatedEventf(
+		obj,
+		AnnotationsForError(operation, err),
+		v1.EventTypeWarning,
+		ReasonForError(operation, err),
+		"%s",
+		err.Error())
+}
+
+// asCloudError finds the ARM error in the chain of err, if any.
+// ReadyConditionImpactingError only supports Cause(), so we check the root cause as well as the unwrap chain.
+func asCloudError(err error) (*genericarmclient.CloudError, bool) {
+	var cloudError *genericarmclient.CloudError
+	if errors.As(err, &cloudError) {
+		return cloudError, true
+	}
+
+	if errors.As(errors.Cause(err), &cloudError) {
+		return cloudError, true
+	}
+
+	return nil, false
+}
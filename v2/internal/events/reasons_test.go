@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func newCloudError(g *WithT, body string) *genericarmclient.CloudError {
+	resp := &http.Response{
+		StatusCode: http.StatusConflict,
+		Header:     http.Header{},
+	}
+	resp.Header.Set("x-ms-correlation-request-id", "00000000-1111-2222-3333-444444444444")
+
+	result := genericarmclient.NewCloudError(&azcore.ResponseError{RawResponse: resp})
+	g.Expect(json.Unmarshal([]byte(body), result)).To(Succeed())
+	return result
+}
+
+func TestReasonForError(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		operation Operation
+		err       error
+		expected  string
+	}{
+		"Well known condition reason": {
+			operation: OperationCreateOrUpdate,
+			err:       conditions.NewReadyConditionImpactingError(errors.New("boom"), conditions.ConditionSeverityWarning, conditions.ReasonReconcileBlocked),
+			expected:  conditions.ReasonReconcileBlocked.Name,
+		},
+		"ARM error code": {
+			operation: OperationCreateOrUpdate,
+			err:       conditions.NewReadyConditionImpactingError(errors.New("boom"), conditions.ConditionSeverityWarning, conditions.MakeReason("InvalidResourceLocation")),
+			expected:  ReasonAzureRequestFailed,
+		},
+		"Unclassified ARM error": {
+			operation: OperationDelete,
+			err:       errors.Wrap(genericarmclient.NewTestCloudError("Conflict", "busy"), "deleting"),
+			expected:  ReasonAzureRequestFailed,
+		},
+		"Other error during create": {
+			operation: OperationCreateOrUpdate,
+			err:       errors.New("boom"),
+			expected:  ReasonCreateOrUpdateFailed,
+		},
+		"Other error during delete": {
+			operation: OperationDelete,
+			err:       errors.New("boom"),
+			expected:  ReasonDeleteFailed,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(ReasonForError(c.operation, c.err)).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestAnnotationsForError_WithARMError_IncludesErrorDetails(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cloudError := newCloudError(g, `{"error": {"code": "InvalidResourceLocation", "message": "Nope", "target": "location"}}`)
+	err := conditions.NewReadyConditionImpactingError(
+		errors.Wrap(cloudError, "Nope"),
+		conditions.ConditionSeverityWarning,
+		conditions.MakeReason(cloudError.Code()))
+
+	g.Expect(AnnotationsForError(OperationCreateOrUpdate, err)).To(Equal(map[string]string{
+		annotations.EventOperation:       "CreateOrUpdate",
+		annotations.EventConditionReason: "InvalidResourceLocation",
+		annotations.EventErrorCode:       "InvalidResourceLocation",
+		annotations.EventErrorTarget:     "location",
+		annotations.EventCorrelationID:   "00000000-1111-2222-3333-444444444444",
+	}))
+}
+
+func TestAnnotationsForError_WithOtherError_IncludesOperation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(AnnotationsForError(OperationDelete, errors.New("boom"))).To(Equal(map[string]string{
+		annotations.EventOperation: "Delete",
+	}))
+}
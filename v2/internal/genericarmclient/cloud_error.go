@@ -8,8 +8,10 @@ package genericarmclient
 import (
 	"encoding/json"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/pkg/errors"
 
+	"github.com/Azure/azure-service-operator/v2/internal/metrics"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
 )
 
@@ -78,6 +80,16 @@ func (e CloudError) Target() string {
 	return ""
 }
 
+// CorrelationID returns the ARM correlation ID of the failed request, if available, or an empty string if not.
+func (e CloudError) CorrelationID() string {
+	var responseError *azcore.ResponseError
+	if errors.As(e.error, &responseError) && responseError.RawResponse != nil {
+		return responseError.RawResponse.Header.Get(metrics.CorrelationIDHeader)
+	}
+
+	return ""
+}
+
 // Details returns the details of the error, if present, or an empty slice if not
 func (e CloudError) Details() []*ErrorResponse {
 	return e.details
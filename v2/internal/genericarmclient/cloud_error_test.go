@@ -7,8 +7,10 @@ package genericarmclient
 
 import (
 	"encoding/json"
+	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	. "github.com/onsi/gomega"
 )
 
@@ -73,3 +75,20 @@ func TestCloudError_WhenLoadedFromJSON_ReturnsExpectedResults(t *testing.T) {
 		})
 	}
 }
+
+func TestCloudError_CorrelationID_ReturnsHeaderFromResponse(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	resp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{},
+	}
+	resp.Header.Set("x-ms-correlation-request-id", "00000000-1111-2222-3333-444444444444")
+
+	cloudError := NewCloudError(&azcore.ResponseError{RawResponse: resp})
+	g.Expect(cloudError.CorrelationID()).To(Equal("00000000-1111-2222-3333-444444444444"))
+
+	// Errors without a response have no correlation ID
+	g.Expect(NewTestCloudError("broken", "It's dead, Jim").CorrelationID()).To(BeEmpty())
+}
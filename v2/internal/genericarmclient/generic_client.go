@@ -8,6 +8,7 @@ package genericarmclient
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 
 const CreatePollerID = "GenericClient.CreateOrUpdateByID"
 const DeletePollerID = "GenericClient.DeleteByID"
+const ActionPollerID = "GenericClient.PostByID"
 
 // NOTE: All of these methods (and types) were adapted from
 // https://github.com/Azure/azure-sdk-for-go/blob/sdk/resources/armresources/v0.3.0/sdk/resources/armresources/zz_generated_resources_client.go
@@ -101,6 +103,7 @@ func NewGenericClient(
 	}
 
 	opts.PerCallPolicies = append([]policy.Policy{rpRegistrationPolicy}, opts.PerCallPolicies...)
+	opts.PerCallPolicies = append(opts.PerCallPolicies, metrics.NewTracingPolicy())
 	if options.Metrics != nil {
 		opts.PerCallPolicies = append(opts.PerCallPolicies, metrics.NewMetricsPolicy(options.Metrics))
 	}
@@ -338,11 +341,12 @@ func (p *listPageResponse[T]) NextPage(
 	client *GenericClient,
 	containerID string,
 	apiVersion string,
+	query url.Values,
 ) (*listPageResponse[T], error) {
 	var req *policy.Request
 	var err error
 	if p == nil {
-		req, err = client.listByContainerIDCreateRequest(ctx, containerID, apiVersion)
+		req, err = client.listByContainerIDCreateRequest(ctx, containerID, apiVersion, query)
 	} else {
 		req, err = runtime.NewRequest(ctx, http.MethodGet, *p.NextLink)
 	}
@@ -383,6 +387,24 @@ func ListByContainerID[T any](
 	client *GenericClient,
 	containerID string,
 	apiVersion string,
+) ([]T, error) {
+	return ListByContainerIDWithQuery[T](ctx, client, containerID, apiVersion, nil)
+}
+
+// ListByContainerIDWithQuery returns all the resources of a given type under a specified parent, passing additional
+// query parameters (such as an OData $filter) with the request for the first page.
+// If the operation fails it returns the *CloudError error type.
+// ctx is the context of the request.
+// client is the GenericClient to use for the request (can't declare generic methods, so this is standalone).
+// containerID is the unique ID of the container in which the resources are contained.
+// apiVersion is the API version to use for the request.
+// query holds additional query parameters; may be nil.
+func ListByContainerIDWithQuery[T any](
+	ctx context.Context,
+	client *GenericClient,
+	containerID string,
+	apiVersion string,
+	query url.Values,
 ) ([]T, error) {
 	pager := runtime.NewPager(
 		runtime.PagingHandler[listPageResponse[T]]{
@@ -391,7 +413,7 @@ func ListByContainerID[T any](
 				return page.More()
 			},
 			Fetcher: func(ctx context.Context, page *listPageResponse[T]) (listPageResponse[T], error) {
-				nextPage, err := page.NextPage(ctx, client, containerID, apiVersion)
+				nextPage, err := page.NextPage(ctx, client, containerID, apiVersion, query)
 				if err != nil {
 					return listPageResponse[T]{}, err
 				}
@@ -417,10 +439,12 @@ func ListByContainerID[T any](
 // ctx is the context of the request.
 // containerID is the unique ID of the container in which the resources are contained.
 // apiVersion is the API version to use for the request.
+// query holds additional query parameters; may be nil.
 func (client *GenericClient) listByContainerIDCreateRequest(
 	ctx context.Context,
 	containerID string,
 	apiVersion string,
+	query url.Values,
 ) (*policy.Request, error) {
 	urlPath := "/{containerId}"
 	if containerID == "" {
@@ -432,12 +456,93 @@ func (client *GenericClient) listByContainerIDCreateRequest(
 		return nil, err
 	}
 	reqQP := req.Raw().URL.Query()
+	for key, values := range query {
+		for _, value := range values {
+			reqQP.Add(key, value)
+		}
+	}
 	reqQP.Set("api-version", apiVersion)
 	req.Raw().URL.RawQuery = reqQP.Encode()
 	req.Raw().Header.Set("Accept", "application/json")
 	return req, nil
 }
 
+// BeginPostByID - Invokes an action on a resource by issuing a POST to the resource ID of the action.
+// body may be nil if the action takes no parameters.
+// If the operation fails it returns the *CloudError error type.
+func (client *GenericClient) BeginPostByID(
+	ctx context.Context,
+	resourceID string,
+	apiVersion string,
+	body interface{}) (*PollerResponse[GenericActionResponse], error) {
+	// The linter doesn't realize that the response is closed in the course of
+	// the autorest.NewPoller call below. Suppressing it as it is a false positive.
+	// nolint:bodyclose
+	resp, err := client.postByID(ctx, resourceID, apiVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := PollerResponse[GenericActionResponse]{
+		RawResponse:  resp,
+		ID:           ActionPollerID,
+		ErrorHandler: client.handleError,
+	}
+	pt, err := azcoreruntime.NewPoller[GenericActionResponse](resp, client.pl, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.Poller = pt
+	return &result, nil
+}
+
+func (client *GenericClient) postByID(
+	ctx context.Context,
+	resourceID string,
+	apiVersion string,
+	body interface{}) (*http.Response, error) {
+	req, err := client.postByIDCreateRequest(ctx, resourceID, apiVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusAccepted, http.StatusNoContent) {
+		return nil, client.handleError(resp)
+	}
+
+	return resp, nil
+}
+
+// postByIDCreateRequest creates the PostByID request.
+func (client *GenericClient) postByIDCreateRequest(
+	ctx context.Context,
+	resourceID string,
+	apiVersion string,
+	body interface{}) (*policy.Request, error) {
+	if resourceID == "" {
+		return nil, errors.New("parameter resourceID cannot be empty")
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, runtime.JoinPaths(client.endpoint, resourceID))
+	if err != nil {
+		return nil, err
+	}
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+	if body == nil {
+		return req, nil
+	}
+
+	return req, runtime.MarshalAsJSON(req, body)
+}
+
 // BeginDeleteByID - Deletes a resource by ID.
 // If the operation fails it returns the *CloudError error type.
 func (client *GenericClient) BeginDeleteByID(ctx context.Context, resourceID string, apiVersion string) (*PollerResponse[GenericDeleteResponse], error) {
@@ -535,3 +640,7 @@ func (client *GenericClient) ResumeDeletePoller(id string) *PollerResponse[Gener
 func (client *GenericClient) ResumeCreatePoller(id string) *PollerResponse[GenericResource] {
 	return &PollerResponse[GenericResource]{ID: id, ErrorHandler: client.handleError}
 }
+
+func (client *GenericClient) ResumeActionPoller(id string) *PollerResponse[GenericActionResponse] {
+	return &PollerResponse[GenericActionResponse]{ID: id, ErrorHandler: client.handleError}
+}
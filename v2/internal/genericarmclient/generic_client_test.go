@@ -7,6 +7,7 @@ package genericarmclient_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -216,3 +217,52 @@ func Test_NewResourceGroup_SubscriptionNotRegisteredError(t *testing.T) {
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(Equal("registering Resource Provider Microsoft.Fake with subscription. Try again later"))
 }
+
+func Test_BeginPostByID_ReturnsActionResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	actionPath := "/subscriptions/12345/resourceGroups/myrg/providers/Microsoft.Fake/fakeResource/fake/regenerateKey"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == actionPath {
+			g.Expect(r.URL.Query().Get("api-version")).To(Equal("2019-01-01"))
+
+			var body map[string]string
+			g.Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			g.Expect(body).To(HaveKeyWithValue("keyName", "primary"))
+
+			w.WriteHeader(http.StatusOK)
+			g.Expect(w.Write([]byte(`{"primaryKey":"abc"}`))).ToNot(BeZero())
+			return
+		}
+
+		g.Fail(fmt.Sprintf("unknown request attempted. Method: %s, URL: %s", r.Method, r.URL))
+	}))
+	defer server.Close()
+
+	cfg := cloud.Configuration{
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Endpoint: server.URL,
+				Audience: cloud.AzurePublic.Services[cloud.ResourceManager].Audience,
+			},
+		},
+	}
+
+	options := &genericarmclient.GenericClientOptions{
+		HttpClient: server.Client(),
+		Metrics:    asometrics.NewARMClientMetrics(),
+	}
+	client, err := genericarmclient.NewGenericClient(cfg, testcommon.MockTokenCredential{}, options)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	poller, err := client.BeginPostByID(ctx, actionPath, "2019-01-01", map[string]string{"keyName": "primary"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(poller.ID).To(Equal(genericarmclient.ActionPollerID))
+	g.Expect(poller.Poller.Done()).To(BeTrue())
+
+	result, err := poller.Poller.Result(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(result.Raw)).To(MatchJSON(`{"primaryKey":"abc"}`))
+}
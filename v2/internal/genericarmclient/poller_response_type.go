@@ -5,6 +5,8 @@ Licensed under the MIT license.
 
 package genericarmclient
 
+import "encoding/json"
+
 // GenericResource is used as a response type for poller. This is a shortened version of
 // https://github.com/Azure/azure-sdk-for-go/blob/5659d929cb5966c1296568cb33410d12e0ee06c6/sdk/resourcemanager/resources/armresources/zz_generated_models.go#L922
 type GenericResource struct {
@@ -24,3 +26,19 @@ type GenericResource struct {
 type GenericDeleteResponse struct {
 	// Empty, for extension later
 }
+
+// GenericActionResponse is used as a response type for the poller of an ARM action (POST).
+// Actions return arbitrary payloads, so we capture the raw JSON for later deserialization into the
+// status of the action resource.
+type GenericActionResponse struct {
+	// Raw contains the JSON returned by the action, if any
+	Raw json.RawMessage
+}
+
+var _ json.Unmarshaler = &GenericActionResponse{}
+
+// UnmarshalJSON captures the raw JSON of the response
+func (r *GenericActionResponse) UnmarshalJSON(data []byte) error {
+	r.Raw = append(r.Raw[:0], data...)
+	return nil
+}
@@ -34,11 +34,24 @@ const (
 	FederatedTokenFilePath = "/var/run/secrets/tokens/azure-identity"
 )
 
+// CredentialSource describes where a Credential was found
+type CredentialSource string
+
+const (
+	// CredentialSourceResource is a per-resource credential, named by an annotation on the resource
+	CredentialSourceResource = CredentialSource("resource")
+	// CredentialSourceNamespace is a per-namespace credential, from the aso-credential secret
+	CredentialSourceNamespace = CredentialSource("namespace")
+	// CredentialSourceGlobal is the global credential of the operator
+	CredentialSourceGlobal = CredentialSource("global")
+)
+
 // Credential describes a credential used to connect to Azure
 type Credential struct {
 	tokenCredential azcore.TokenCredential
 	credentialFrom  types.NamespacedName
 	subscriptionID  string
+	source          CredentialSource
 
 	// secretData contains the secret
 	secretData map[string][]byte
@@ -52,6 +65,11 @@ func (c *Credential) CredentialFrom() types.NamespacedName {
 	return c.credentialFrom
 }
 
+// Source returns where the credential was found
+func (c *Credential) Source() CredentialSource {
+	return c.source
+}
+
 func (c *Credential) SubscriptionID() string {
 	return c.subscriptionID
 }
@@ -65,6 +83,7 @@ func NewDefaultCredential(tokenCred azcore.TokenCredential, namespace string, su
 		tokenCredential: tokenCred,
 		subscriptionID:  subscriptionID,
 		credentialFrom:  types.NamespacedName{Namespace: namespace, Name: globalCredentialSecretName},
+		source:          CredentialSourceGlobal,
 	}
 }
 
@@ -103,6 +122,7 @@ func (c *credentialProvider) GetCredential(ctx context.Context, obj genruntime.M
 		return nil, err
 	}
 	if cred != nil {
+		cred.source = CredentialSourceResource
 		return cred, nil
 	}
 
@@ -112,6 +132,7 @@ func (c *credentialProvider) GetCredential(ctx context.Context, obj genruntime.M
 		return nil, err
 	}
 	if cred != nil {
+		cred.source = CredentialSourceNamespace
 		return cred, nil
 	}
 
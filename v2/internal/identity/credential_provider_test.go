@@ -111,6 +111,7 @@ func TestCredentialProvider_ResourceScopeCredentialAndNamespaceCredential_Prefer
 	g.Expect(err).ToNot(HaveOccurred())
 
 	g.Expect(cred.CredentialFrom()).To(BeEquivalentTo(perResourceCredentialName))
+	g.Expect(cred.Source()).To(Equal(CredentialSourceResource))
 	g.Expect(cred.SubscriptionID()).To(BeEquivalentTo(fakeID))
 }
 
@@ -163,6 +164,7 @@ func TestCredentialProvider_NamespaceCredential_IsReturned(t *testing.T) {
 	g.Expect(err).ToNot(HaveOccurred())
 
 	g.Expect(cred.CredentialFrom()).To(BeEquivalentTo(credentialNamespacedName))
+	g.Expect(cred.Source()).To(Equal(CredentialSourceNamespace))
 	g.Expect(cred.SubscriptionID()).To(BeEquivalentTo(fakeID))
 }
 
@@ -183,6 +185,7 @@ func TestCredentialProvider_GlobalCredential_IsReturned(t *testing.T) {
 
 	g.Expect(cred.SubscriptionID()).To(BeEquivalentTo(testSubscriptionID))
 	g.Expect(cred.CredentialFrom()).To(BeEquivalentTo(types.NamespacedName{Namespace: testPodNamespace, Name: globalCredentialSecretName}))
+	g.Expect(cred.Source()).To(Equal(CredentialSourceGlobal))
 }
 
 func newResourceGroup(namespace string) *resources.ResourceGroup {
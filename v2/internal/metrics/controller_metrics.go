@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// ControllerMetrics records which kinds of resource the operator is currently reconciling.
+type ControllerMetrics struct {
+	activeControllers  *prometheus.GaugeVec
+	controllerFailures *prometheus.CounterVec
+}
+
+var _ Metrics = &ControllerMetrics{}
+
+func NewControllerMetrics() *ControllerMetrics {
+	labels := []string{"group", "kind"}
+
+	activeControllers := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aso_controller_active",
+		Help: "Set to 1 for each kind of resource with a running controller",
+	}, labels)
+
+	controllerFailures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aso_controller_start_failures_total",
+		Help: "Total number of failed attempts to start the controller for a kind of resource",
+	}, labels)
+
+	return &ControllerMetrics{
+		activeControllers:  activeControllers,
+		controllerFailures: controllerFailures,
+	}
+}
+
+// RegisterMetrics registers the collectors with prometheus server.
+func (m *ControllerMetrics) RegisterMetrics() {
+	metrics.Registry.MustRegister(m.activeControllers, m.controllerFailures)
+}
+
+// RecordControllerStarted records that the controller for gk is now running.
+func (m *ControllerMetrics) RecordControllerStarted(gk schema.GroupKind) {
+	m.activeControllers.WithLabelValues(gk.Group, gk.Kind).Set(1)
+}
+
+// RecordControllerFailed records a failed attempt to start the controller for gk.
+func (m *ControllerMetrics) RecordControllerFailed(gk schema.GroupKind) {
+	m.controllerFailures.WithLabelValues(gk.Group, gk.Kind).Inc()
+}
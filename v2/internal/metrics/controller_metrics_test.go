@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestControllerMetrics_RecordControllerStarted_MarksKindActive(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	m := NewControllerMetrics()
+	m.RecordControllerStarted(testGroupKind)
+	m.RecordControllerStarted(testGroupKind)
+
+	g.Expect(testutil.CollectAndCount(m.activeControllers)).To(Equal(1))
+	g.Expect(testutil.ToFloat64(m.activeControllers.WithLabelValues(testGroupKind.Group, testGroupKind.Kind))).To(Equal(1.0))
+}
+
+func TestControllerMetrics_RecordControllerFailed_CountsFailures(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	m := NewControllerMetrics()
+	m.RecordControllerFailed(testGroupKind)
+	m.RecordControllerFailed(testGroupKind)
+
+	g.Expect(testutil.ToFloat64(m.controllerFailures.WithLabelValues(testGroupKind.Group, testGroupKind.Kind))).To(Equal(2.0))
+	g.Expect(testutil.CollectAndCount(m.activeControllers)).To(Equal(0))
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// ResourceMetrics records controller level metrics about the resources being reconciled, labelled by the group, kind
+// and namespace of each resource.
+// Gauges are maintained per resource, so a resource moving between states (or being deleted) is reflected correctly.
+type ResourceMetrics struct {
+	lock      sync.Mutex
+	resources map[resourceKey]*resourceState
+
+	timeToFirstReady          *prometheus.HistogramVec
+	resourcesByReadyCondition *prometheus.GaugeVec
+	operationsInFlight        *prometheus.GaugeVec
+	operationDuration         *prometheus.HistogramVec
+	requeuesTotal             *prometheus.CounterVec
+	resourcesByCredential     *prometheus.GaugeVec
+}
+
+// resourceKey uniquely identifies a resource
+type resourceKey struct {
+	groupKind schema.GroupKind
+	name      types.NamespacedName
+}
+
+// resourceState captures what we last recorded about a resource, so that gauges can be adjusted when it changes
+type resourceState struct {
+	readyLabels      []string  // Labels last used for resourcesByReadyCondition, if any
+	credentialLabels []string  // Labels last used for resourcesByCredential, if any
+	operationStart   time.Time // When we first observed the current long-running operation, if any
+	seenReady        bool      // True once we've recorded the time to first Ready
+}
+
+// ConditionedObject is a Kubernetes resource with conditions
+type ConditionedObject interface {
+	client.Object
+	conditions.Conditioner
+}
+
+var _ Metrics = &ResourceMetrics{}
+
+func NewResourceMetrics() *ResourceMetrics {
+	labels := []string{"group", "kind", "namespace"}
+
+	timeToFirstReady := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "aso_resource_time_to_first_ready_seconds",
+		Help:    "Time from creation of a resource until it first became Ready",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16), // 1s to ~9h
+	}, labels)
+
+	resourcesByReadyCondition := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aso_resources",
+		Help: "Number of resources, by the status, severity and reason of their Ready condition",
+	}, append(labels, "status", "severity", "reason"))
+
+	operationsInFlight := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aso_long_running_operations_in_flight",
+		Help: "Number of long-running ARM operations currently being monitored",
+	}, labels)
+
+	operationDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "aso_long_running_operation_duration_seconds",
+		Help:    "Duration of long-running ARM operations, as observed by the operator",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16), // 1s to ~9h
+	}, labels)
+
+	requeuesTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aso_resource_requeues_total",
+		Help: "Total number of times a resource was requeued for reconciliation, by reason",
+	}, append(labels, "reason"))
+
+	resourcesByCredential := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aso_resources_by_credential_source",
+		Help: "Number of resources, by the source (resource, namespace or global) of the credential used to reconcile them",
+	}, append(labels, "source"))
+
+	return &ResourceMetrics{
+		resources:                 make(map[resourceKey]*resourceState),
+		timeToFirstReady:          timeToFirstReady,
+		resourcesByReadyCondition: resourcesByReadyCondition,
+		operationsInFlight:        operationsInFlight,
+		operationDuration:         operationDuration,
+		requeuesTotal:             requeuesTotal,
+		resourcesByCredential:     resourcesByCredential,
+	}
+}
+
+// RegisterMetrics registers the collectors with prometheus server.
+func (m *ResourceMetrics) RegisterMetrics() {
+	metrics.Registry.MustRegister(
+		m.timeToFirstReady,
+		m.resourcesByReadyCondition,
+		m.operationsInFlight,
+		m.operationDuration,
+		m.requeuesTotal,
+		m.resourcesByCredential)
+}
+
+// RecordReadyCondition records the current Ready condition of obj.
+// wasReady indicates whether the resource was Ready before the current reconcile; if it has now become Ready for the
+// first time (as far as this operator knows), the time since creation of the resource is recorded.
+func (m *ResourceMetrics) RecordReadyCondition(gk schema.GroupKind, obj ConditionedObject, wasReady bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	state := m.stateFor(gk, obj)
+
+	labels := []string{gk.Group, gk.Kind, obj.GetNamespace(), string(metav1.ConditionUnknown), "", ""}
+	ready, ok := conditions.GetCondition(obj, conditions.ConditionTypeReady)
+	if ok {
+		labels[3] = string(ready.Status)
+		labels[4] = string(ready.Severity)
+		labels[5] = ready.Reason
+	}
+
+	state.readyLabels = m.moveGauge(m.resourcesByReadyCondition, state.readyLabels, labels)
+
+	isReady := ok && ready.Status == metav1.ConditionTrue
+	if isReady && !wasReady && !state.seenReady {
+		elapsed := ready.LastTransitionTime.Sub(obj.GetCreationTimestamp().Time)
+		m.timeToFirstReady.WithLabelValues(gk.Group, gk.Kind, obj.GetNamespace()).Observe(elapsed.Seconds())
+	}
+
+	state.seenReady = state.seenReady || isReady
+}
+
+// RecordLongRunningOperation records whether a long-running operation is in progress for obj.
+// Durations are measured from when the operator first observed the operation, so operations that were already
+// in progress when the operator started will be under-reported.
+func (m *ResourceMetrics) RecordLongRunningOperation(gk schema.GroupKind, obj client.Object, inProgress bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	state := m.stateFor(gk, obj)
+	tracking := !state.operationStart.IsZero()
+	if inProgress == tracking {
+		// Nothing has changed
+		return
+	}
+
+	gauge := m.operationsInFlight.WithLabelValues(gk.Group, gk.Kind, obj.GetNamespace())
+	if inProgress {
+		state.operationStart = time.Now()
+		gauge.Inc()
+		return
+	}
+
+	m.operationDuration.WithLabelValues(gk.Group, gk.Kind, obj.GetNamespace()).Observe(time.Since(state.operationStart).Seconds())
+	state.operationStart = time.Time{}
+	gauge.Dec()
+}
+
+// RecordCredentialSource records the source of the credential used to reconcile obj
+func (m *ResourceMetrics) RecordCredentialSource(gk schema.GroupKind, obj client.Object, source string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	state := m.stateFor(gk, obj)
+	labels := []string{gk.Group, gk.Kind, obj.GetNamespace(), source}
+	state.credentialLabels = m.moveGauge(m.resourcesByCredential, state.credentialLabels, labels)
+}
+
+// RecordRequeue records that a resource in the given namespace was requeued for the specified reason
+func (m *ResourceMetrics) RecordRequeue(gk schema.GroupKind, namespace string, reason string) {
+	m.requeuesTotal.WithLabelValues(gk.Group, gk.Kind, namespace, reason).Inc()
+}
+
+// ForgetResource removes a resource that no longer exists from all gauges
+func (m *ResourceMetrics) ForgetResource(gk schema.GroupKind, name types.NamespacedName) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := resourceKey{groupKind: gk, name: name}
+	state, ok := m.resources[key]
+	if !ok {
+		return
+	}
+
+	m.moveGauge(m.resourcesByReadyCondition, state.readyLabels, nil)
+	m.moveGauge(m.resourcesByCredential, state.credentialLabels, nil)
+	if !state.operationStart.IsZero() {
+		m.operationsInFlight.WithLabelValues(gk.Group, gk.Kind, name.Namespace).Dec()
+	}
+
+	delete(m.resources, key)
+}
+
+// stateFor returns the state we hold for obj, creating it if required.
+// Must be called while holding the lock.
+func (m *ResourceMetrics) stateFor(gk schema.GroupKind, obj client.Object) *resourceState {
+	key := resourceKey{groupKind: gk, name: client.ObjectKeyFromObject(obj)}
+	state, ok := m.resources[key]
+	if !ok {
+		state = &resourceState{}
+		m.resources[key] = state
+	}
+
+	return state
+}
+
+// moveGauge moves a resource from one set of gauge labels to another, returning the labels now in use.
+// Either set of labels may be nil.
+func (m *ResourceMetrics) moveGauge(gauge *prometheus.GaugeVec, from []string, to []string) []string {
+	if equalLabels(from, to) {
+		return to
+	}
+
+	if from != nil {
+		gauge.WithLabelValues(from...).Dec()
+	}
+
+	if to != nil {
+		gauge.WithLabelValues(to...).Inc()
+	}
+
+	return to
+}
+
+func equalLabels(left []string, right []string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+var testGroupKind = schema.GroupKind{Group: "resources.azure.com", Kind: "ResourceGroup"}
+
+// testObject is a minimal resource with conditions
+type testObject struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	conditions conditions.Conditions
+}
+
+var _ ConditionedObject = &testObject{}
+
+func newTestObject(created time.Time) *testObject {
+	return &testObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "my-rg",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+}
+
+func (o *testObject) DeepCopyObject() runtime.Object {
+	result := *o
+	result.conditions = append(conditions.Conditions(nil), o.conditions...)
+	return &result
+}
+
+func (o *testObject) GetConditions() conditions.Conditions {
+	return o.conditions
+}
+
+func (o *testObject) SetConditions(conditions conditions.Conditions) {
+	o.conditions = conditions
+}
+
+func (o *testObject) setReady(status metav1.ConditionStatus, reason string, at time.Time) {
+	o.conditions = conditions.Conditions{
+		{
+			Type:               conditions.ConditionTypeReady,
+			Status:             status,
+			Reason:             reason,
+			LastTransitionTime: metav1.NewTime(at),
+		},
+	}
+}
+
+func readyGauge(m *ResourceMetrics, status metav1.ConditionStatus, reason string) prometheus.Gauge {
+	return m.resourcesByReadyCondition.WithLabelValues(testGroupKind.Group, testGroupKind.Kind, "default", string(status), "", reason)
+}
+
+func TestResourceMetrics_RecordReadyCondition_TracksResourceThroughLifecycle(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	m := NewResourceMetrics()
+	created := time.Now().Add(-time.Hour)
+	obj := newTestObject(created)
+
+	obj.setReady(metav1.ConditionFalse, conditions.ReasonReconciling.Name, created)
+	m.RecordReadyCondition(testGroupKind, obj, false)
+	g.Expect(testutil.ToFloat64(readyGauge(m, metav1.ConditionFalse, conditions.ReasonReconciling.Name))).To(Equal(1.0))
+
+	obj.setReady(metav1.ConditionTrue, "Succeeded", created.Add(10*time.Minute))
+	m.RecordReadyCondition(testGroupKind, obj, false)
+	g.Expect(testutil.ToFloat64(readyGauge(m, metav1.ConditionFalse, conditions.ReasonReconciling.Name))).To(Equal(0.0))
+	g.Expect(testutil.ToFloat64(readyGauge(m, metav1.ConditionTrue, "Succeeded"))).To(Equal(1.0))
+
+	// Only the first transition to Ready is recorded
+	m.RecordReadyCondition(testGroupKind, obj, false)
+	g.Expect(testutil.CollectAndCount(m.timeToFirstReady)).To(Equal(1))
+	g.Expect(testutil.ToFloat64(readyGauge(m, metav1.ConditionTrue, "Succeeded"))).To(Equal(1.0))
+
+	m.ForgetResource(testGroupKind, client.ObjectKeyFromObject(obj))
+	g.Expect(testutil.ToFloat64(readyGauge(m, metav1.ConditionTrue, "Succeeded"))).To(Equal(0.0))
+}
+
+func TestResourceMetrics_RecordReadyCondition_WhenAlreadyReady_DoesNotRecordTimeToFirstReady(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	m := NewResourceMetrics()
+	created := time.Now().Add(-time.Hour)
+	obj := newTestObject(created)
+
+	obj.setReady(metav1.ConditionTrue, "Succeeded", created)
+	m.RecordReadyCondition(testGroupKind, obj, true)
+
+	g.Expect(testutil.CollectAndCount(m.timeToFirstReady)).To(Equal(0))
+}
+
+func TestResourceMetrics_RecordLongRunningOperation_TracksOperationsInFlight(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	m := NewResourceMetrics()
+	obj := newTestObject(time.Now())
+	inFlight := m.operationsInFlight.WithLabelValues(testGroupKind.Group, testGroupKind.Kind, "default")
+
+	m.RecordLongRunningOperation(testGroupKind, obj, true)
+	m.RecordLongRunningOperation(testGroupKind, obj, true)
+	g.Expect(testutil.ToFloat64(inFlight)).To(Equal(1.0))
+
+	m.RecordLongRunningOperation(testGroupKind, obj, false)
+	g.Expect(testutil.ToFloat64(inFlight)).To(Equal(0.0))
+	g.Expect(testutil.CollectAndCount(m.operationDuration)).To(Equal(1))
+}
+
+func TestResourceMetrics_RecordCredentialSource_MovesResourceBetweenSources(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	m := NewResourceMetrics()
+	obj := newTestObject(time.Now())
+	global := m.resourcesByCredential.WithLabelValues(testGroupKind.Group, testGroupKind.Kind, "default", "global")
+	namespace := m.resourcesByCredential.WithLabelValues(testGroupKind.Group, testGroupKind.Kind, "default", "namespace")
+
+	m.RecordCredentialSource(testGroupKind, obj, "global")
+	g.Expect(testutil.ToFloat64(global)).To(Equal(1.0))
+
+	m.RecordCredentialSource(testGroupKind, obj, "namespace")
+	g.Expect(testutil.ToFloat64(global)).To(Equal(0.0))
+	g.Expect(testutil.ToFloat64(namespace)).To(Equal(1.0))
+}
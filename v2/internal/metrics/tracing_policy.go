@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
+)
+
+// CorrelationIDHeader is the header ARM uses to return the correlation ID of a request
+const CorrelationIDHeader = "x-ms-correlation-request-id"
+
+var _ policy.Policy = &TracingPolicy{}
+
+// TracingPolicy is an Azure SDK Track 2 policy for creating a span for each HTTP request made to ARM
+type TracingPolicy struct {
+}
+
+func NewTracingPolicy() policy.Policy {
+	return &TracingPolicy{}
+}
+
+func (t *TracingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+
+	attributes := []attribute.KeyValue{
+		semconv.HTTPMethod(raw.Method),
+		semconv.HTTPURL(raw.URL.String()),
+	}
+
+	if id, err := arm.ParseResourceID(raw.URL.Path); err == nil {
+		attributes = append(attributes, tracing.ARMResourceTypeKey.String(id.ResourceType.String()))
+	}
+
+	ctx, span := tracing.StartSpan(raw.Context(), "HTTP "+raw.Method, attributes...)
+	resp, err := req.WithContext(ctx).Next()
+	if resp != nil {
+		span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+		if correlationID := resp.Header.Get(CorrelationIDHeader); correlationID != "" {
+			span.SetAttributes(tracing.ARMCorrelationIDKey.String(correlationID))
+		}
+
+		// ARM failures come back as responses rather than errors
+		if err == nil && resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+
+	tracing.EndSpan(span, err)
+	return resp, err
+}
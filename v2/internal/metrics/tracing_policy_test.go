@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
+)
+
+const testResourceURL = "https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/account"
+
+type fakeTransport struct {
+	statusCode int
+}
+
+func (f fakeTransport) Do(req *http.Request) (*http.Response, error) {
+	header := make(http.Header)
+	header.Set(CorrelationIDHeader, "correlation-1234")
+
+	return &http.Response{
+		StatusCode: f.statusCode,
+		Status:     http.StatusText(f.statusCode),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestTracingPolicy_WhenRequestSent_RecordsSpanWithCorrelationID(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		statusCode int
+		expected   codes.Code
+	}{
+		"Success": {statusCode: http.StatusOK, expected: codes.Unset},
+		"Failure": {statusCode: http.StatusConflict, expected: codes.Error},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			provider, exporter := tracing.NewInMemoryTracerProvider()
+			ctx, root := provider.Tracer("test").Start(context.Background(), "root")
+
+			pipeline := runtime.NewPipeline(
+				"test",
+				"v1",
+				runtime.PipelineOptions{PerCall: []policy.Policy{NewTracingPolicy()}},
+				&policy.ClientOptions{Transport: fakeTransport{statusCode: c.statusCode}})
+
+			req, err := runtime.NewRequest(ctx, http.MethodPut, testResourceURL)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			resp, err := pipeline.Do(req)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(resp.Body.Close()).To(Succeed())
+			root.End()
+
+			spans := exporter.GetSpans()
+			g.Expect(spans).To(HaveLen(2))
+
+			span := spans[0]
+			g.Expect(span.Name).To(Equal("HTTP PUT"))
+			g.Expect(span.Parent.SpanID()).To(Equal(spans[1].SpanContext.SpanID()))
+			g.Expect(span.Status.Code).To(Equal(c.expected))
+			g.Expect(span.Attributes).To(ContainElements(
+				semconv.HTTPMethod(http.MethodPut),
+				semconv.HTTPStatusCode(c.statusCode),
+				tracing.ARMCorrelationIDKey.String("correlation-1234"),
+				tracing.ARMResourceTypeKey.String("Microsoft.Storage/storageAccounts")))
+		})
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// admissionTimeout bounds the time taken to evaluate policies in a webhook
+const admissionTimeout = 5 * time.Second
+
+// Enforcer checks resources against the ASOPolicy resources in the cluster. It's used both by the validating webhooks
+// and by the reconciler, so that bypassing the webhooks doesn't bypass the policies.
+type Enforcer struct {
+	kubeClient client.Reader
+	scheme     *runtime.Scheme
+}
+
+// NewEnforcer creates a new Enforcer reading policies and resources with the given client
+func NewEnforcer(kubeClient client.Reader, scheme *runtime.Scheme) *Enforcer {
+	return &Enforcer{
+		kubeClient: kubeClient,
+		scheme:     scheme,
+	}
+}
+
+// ValidateCreate checks a new resource against the policies for its namespace, including the limits on counts
+func (e *Enforcer) ValidateCreate(ctx context.Context, obj genruntime.MetaObject) error {
+	ctx, cancel := context.WithTimeout(ctx, admissionTimeout)
+	defer cancel()
+
+	return e.Validate(ctx, obj, true)
+}
+
+// ValidateUpdate checks the changes made to the spec of a resource against the policies for its namespace.
+// Updates that don't change the spec (such as those made by the operator to annotations or finalizers) and updates
+// of resources being deleted are always allowed, so that a resource created before a policy can still be reconciled
+// and deleted. Limits on counts aren't checked, as an update doesn't add another resource.
+func (e *Enforcer) ValidateUpdate(ctx context.Context, old genruntime.MetaObject, obj genruntime.MetaObject) error {
+	if obj.GetDeletionTimestamp() != nil || old.GetGeneration() == obj.GetGeneration() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, admissionTimeout)
+	defer cancel()
+
+	oldProps, err := readProperties(old)
+	if err != nil {
+		return err
+	}
+
+	props, err := readProperties(obj)
+	if err != nil {
+		return err
+	}
+
+	violations, err := e.violations(ctx, obj, changedProperties(oldProps, props), false)
+	if err != nil {
+		return err
+	}
+
+	return violationsError(obj, violations)
+}
+
+// Validate returns an error describing every way in which obj violates the policies for its namespace, or nil if it
+// complies with them. If checkCount is true, the limits on the number of resources in the namespace are checked too.
+func (e *Enforcer) Validate(ctx context.Context, obj genruntime.MetaObject, checkCount bool) error {
+	violations, err := e.Violations(ctx, obj, checkCount)
+	if err != nil {
+		return err
+	}
+
+	return violationsError(obj, violations)
+}
+
+// violationsError returns an error describing the violations, or nil if there are none
+func violationsError(obj genruntime.MetaObject, violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return errors.Errorf("resource %q violates policy: %s", obj.GetName(), strings.Join(violations, "; "))
+}
+
+// Violations returns a description of each way in which obj violates the policies for its namespace.
+// If checkCount is true, the limits on the number of resources in the namespace are checked too. Resources count
+// towards the limit in the order they were created, so once a namespace is at its limit, only newer resources violate
+// the policy.
+func (e *Enforcer) Violations(ctx context.Context, obj genruntime.MetaObject, checkCount bool) ([]string, error) {
+	props, err := readProperties(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.violations(ctx, obj, props, checkCount)
+}
+
+// violations returns a description of each way in which a resource with the given properties violates the policies
+// for the namespace of obj
+func (e *Enforcer) violations(
+	ctx context.Context,
+	obj genruntime.MetaObject,
+	props resourceProperties,
+	checkCount bool,
+) ([]string, error) {
+	if obj.GetNamespace() == "" {
+		return nil, nil
+	}
+
+	policies, err := e.policiesFor(ctx, obj.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, e.scheme)
+	if err != nil {
+		return nil, errors.Wrapf(err, "determining GVK of %T", obj)
+	}
+
+	// Only count resources once, however many policies limit them
+	count := -1
+	countEarlier := func() (int, error) {
+		if count < 0 {
+			n, countErr := e.countCreatedBefore(ctx, obj, gvk)
+			if countErr != nil {
+				return 0, countErr
+			}
+
+			count = n
+		}
+
+		return count, nil
+	}
+
+	var violations []string
+	for _, policy := range policies {
+		violations = append(violations, checkProperties(policy, gvk.GroupKind(), props)...)
+
+		if !checkCount {
+			continue
+		}
+
+		for _, rule := range policy.Spec.Resources {
+			if rule.MaxCount == nil || !appliesTo(rule, gvk.GroupKind()) {
+				continue
+			}
+
+			existing, err := countEarlier()
+			if err != nil {
+				return nil, err
+			}
+
+			if existing >= *rule.MaxCount {
+				violations = append(
+					violations,
+					fmt.Sprintf(
+						"namespace %q already has %d %s resources, the maximum allowed by ASOPolicy %q",
+						obj.GetNamespace(),
+						existing,
+						gvk.GroupKind(),
+						policy.Name))
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+// policiesFor returns the policies that apply to the given namespace
+func (e *Enforcer) policiesFor(ctx context.Context, namespace string) ([]serviceoperator.ASOPolicy, error) {
+	var list serviceoperator.ASOPolicyList
+	err := e.kubeClient.List(ctx, &list)
+	if meta.IsNoMatchError(err) {
+		// The ASOPolicy CRD isn't installed, so there can't be any policies
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "listing ASOPolicies")
+	}
+
+	var namespaceLabels labels.Set
+	var result []serviceoperator.ASOPolicy
+	for _, policy := range list.Items {
+		if policy.Spec.NamespaceSelector == nil {
+			result = append(result, policy)
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing namespace selector of ASOPolicy %q", policy.Name)
+		}
+
+		if namespaceLabels == nil {
+			var ns corev1.Namespace
+			err = e.kubeClient.Get(ctx, types.NamespacedName{Name: namespace}, &ns)
+			if err != nil {
+				return nil, errors.Wrapf(err, "getting namespace %q", namespace)
+			}
+
+			namespaceLabels = labels.Set(ns.Labels)
+		}
+
+		if selector.Matches(namespaceLabels) {
+			result = append(result, policy)
+		}
+	}
+
+	return result, nil
+}
+
+// countCreatedBefore returns the number of resources of the same kind in the namespace of obj that were created
+// before it. If obj hasn't been created yet, all of them are counted.
+func (e *Enforcer) countCreatedBefore(ctx context.Context, obj genruntime.MetaObject, gvk schema.GroupVersionKind) (int, error) {
+	var list metav1.PartialObjectMetadataList
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err := e.kubeClient.List(ctx, &list, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return 0, errors.Wrapf(err, "listing %s resources in namespace %q", gvk.GroupKind(), obj.GetNamespace())
+	}
+
+	result := 0
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.Name == obj.GetName() || item.DeletionTimestamp != nil {
+			// Resources being deleted will soon no longer count
+			continue
+		}
+
+		if createdBefore(item, obj) {
+			result++
+		}
+	}
+
+	return result, nil
+}
+
+// createdBefore returns true if other was created before obj, using the name to order resources created at the
+// same time. Everything is created before a resource that hasn't been created yet.
+func createdBefore(other metav1.Object, obj metav1.Object) bool {
+	created := obj.GetCreationTimestamp()
+	if created.IsZero() {
+		return true
+	}
+
+	otherCreated := other.GetCreationTimestamp()
+	if !otherCreated.Equal(&created) {
+		return otherCreated.Before(&created)
+	}
+
+	return other.GetName() < obj.GetName()
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cache "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230401"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+var baseTime = metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+func newTestEnforcer(g *WithT, objs ...client.Object) *Enforcer {
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(serviceoperator.AddToScheme(scheme)).To(Succeed())
+	g.Expect(cache.AddToScheme(scheme)).To(Succeed())
+	g.Expect(resources.AddToScheme(scheme)).To(Succeed())
+
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return NewEnforcer(kubeClient, scheme)
+}
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+func newPolicy(name string, spec serviceoperator.ASOPolicySpec) *serviceoperator.ASOPolicy {
+	return &serviceoperator.ASOPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: spec,
+	}
+}
+
+func newRedis(name string, sku cache.Sku_Name, created int) *cache.Redis {
+	redis := &cache.Redis{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "sandbox",
+			Name:      name,
+		},
+		Spec: cache.Redis_Spec{
+			Location: to.Ptr("westus2"),
+			Sku: &cache.Sku{
+				Capacity: to.Ptr(1),
+				Family:   to.Ptr(cache.Sku_Family_C),
+				Name:     to.Ptr(sku),
+			},
+			Tags: map[string]string{
+				"CostCenter": "1234",
+			},
+		},
+	}
+
+	// Resources that haven't been created yet have no creation timestamp
+	if created >= 0 {
+		redis.CreationTimestamp = metav1.NewTime(baseTime.Add(time.Duration(created) * time.Minute))
+	}
+
+	return redis
+}
+
+func TestEnforcer_WithoutPolicies_AllowsEverything(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	enforcer := newTestEnforcer(g)
+
+	err := enforcer.ValidateCreate(context.Background(), newRedis("redis", cache.Sku_Name_Premium, -1))
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestEnforcer_Violations(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		spec     serviceoperator.ASOPolicySpec
+		obj      func() genruntime.MetaObject
+		expected []string
+	}{
+		"Allowed location": {
+			spec: serviceoperator.ASOPolicySpec{AllowedLocations: []string{"West US 2"}},
+		},
+		"Disallowed location": {
+			spec:     serviceoperator.ASOPolicySpec{AllowedLocations: []string{"eastus"}},
+			expected: []string{`location "westus2" is not allowed by ASOPolicy "policy", allowed locations are eastus`},
+		},
+		"Allowed SKU": {
+			spec: serviceoperator.ASOPolicySpec{
+				Resources: []serviceoperator.ResourcePolicy{
+					{Group: "cache.azure.com", Kind: "Redis", AllowedSKUs: []string{"basic", "standard"}},
+				},
+			},
+			obj: func() genruntime.MetaObject { return newRedis("redis", cache.Sku_Name_Standard, -1) },
+		},
+		"Disallowed SKU": {
+			spec: serviceoperator.ASOPolicySpec{
+				Resources: []serviceoperator.ResourcePolicy{
+					{Group: "cache.azure.com", Kind: "Redis", AllowedSKUs: []string{"Basic", "Standard"}},
+				},
+			},
+			expected: []string{`SKU "Premium" is not allowed for Redis.cache.azure.com by ASOPolicy "policy", allowed SKUs are Basic, Standard`},
+		},
+		"SKU restricted for other kind": {
+			spec: serviceoperator.ASOPolicySpec{
+				Resources: []serviceoperator.ResourcePolicy{
+					{Group: "cache.azure.com", Kind: "RedisEnterprise", AllowedSKUs: []string{"Enterprise_E10"}},
+				},
+			},
+		},
+		"Required tag present": {
+			spec: serviceoperator.ASOPolicySpec{RequiredTags: []string{"costcenter"}},
+		},
+		"Required tag missing": {
+			spec:     serviceoperator.ASOPolicySpec{RequiredTags: []string{"CostCenter", "Owner"}},
+			expected: []string{`tag "Owner" is required by ASOPolicy "policy"`},
+		},
+		"Namespace not selected": {
+			spec: serviceoperator.ASOPolicySpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"environment": "production"}},
+				AllowedLocations:  []string{"eastus"},
+			},
+		},
+		"Namespace selected": {
+			spec: serviceoperator.ASOPolicySpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"environment": "sandbox"}},
+				AllowedLocations:  []string{"eastus"},
+			},
+			expected: []string{`location "westus2" is not allowed by ASOPolicy "policy", allowed locations are eastus`},
+		},
+		"Resource without tags or SKU": {
+			spec: serviceoperator.ASOPolicySpec{
+				AllowedLocations: []string{"westus2"},
+				RequiredTags:     []string{"Owner"},
+			},
+			obj: func() genruntime.MetaObject {
+				return &resources.ResourceGroup{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "sandbox", Name: "rg"},
+					Spec:       resources.ResourceGroup_Spec{Location: to.Ptr("westus2")},
+				}
+			},
+			expected: []string{`tag "Owner" is required by ASOPolicy "policy"`},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			enforcer := newTestEnforcer(
+				g,
+				newNamespace("sandbox", map[string]string{"environment": "sandbox"}),
+				newPolicy("policy", c.spec))
+
+			var obj genruntime.MetaObject = newRedis("redis", cache.Sku_Name_Premium, -1)
+			if c.obj != nil {
+				obj = c.obj()
+			}
+
+			violations, err := enforcer.Violations(context.Background(), obj, false)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(violations).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestEnforcer_MaxCount_LimitsResourcesInOrderOfCreation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	policy := newPolicy("policy", serviceoperator.ASOPolicySpec{
+		Resources: []serviceoperator.ResourcePolicy{
+			{Group: "cache.azure.com", Kind: "Redis", MaxCount: to.Ptr(2)},
+		},
+	})
+
+	first := newRedis("first", cache.Sku_Name_Basic, 0)
+	second := newRedis("second", cache.Sku_Name_Basic, 1)
+	third := newRedis("third", cache.Sku_Name_Basic, 2) // Created while the webhook wasn't enforcing policies
+	enforcer := newTestEnforcer(g, newNamespace("sandbox", nil), policy, first, second, third)
+
+	// A new resource is rejected by the webhook
+	err := enforcer.ValidateCreate(ctx, newRedis("fourth", cache.Sku_Name_Basic, -1))
+	g.Expect(err).To(MatchError(ContainSubstring(`namespace "sandbox" already has 3 Redis.cache.azure.com resources, the maximum allowed by ASOPolicy "policy"`)))
+
+	// Updates don't add resources, so aren't limited
+	updated := third.DeepCopy()
+	updated.Generation = third.Generation + 1
+	err = enforcer.ValidateUpdate(ctx, third, updated)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// When reconciling, only resources beyond the limit are blocked
+	for _, obj := range []*cache.Redis{first, second} {
+		violations, err := enforcer.Violations(ctx, obj, true)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(violations).To(BeEmpty(), obj.Name)
+	}
+
+	violations, err := enforcer.Violations(ctx, third, true)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(violations).To(HaveLen(1))
+}
+
+func TestEnforcer_ValidateUpdate_OnlyChecksChangesToSpec(t *testing.T) {
+	t.Parallel()
+
+	policy := newPolicy("policy", serviceoperator.ASOPolicySpec{
+		AllowedLocations: []string{"westus2"},
+		RequiredTags:     []string{"Owner"},
+		Resources: []serviceoperator.ResourcePolicy{
+			{Group: "cache.azure.com", Kind: "Redis", AllowedSKUs: []string{"Basic"}},
+		},
+	})
+
+	// Created before the policy, so violates it by having a Premium SKU and no Owner tag
+	existing := newRedis("redis", cache.Sku_Name_Premium, 0)
+	existing.Generation = 1
+
+	cases := map[string]struct {
+		update   func(redis *cache.Redis)
+		expected string
+	}{
+		"Metadata only": {
+			update: func(redis *cache.Redis) {
+				redis.Annotations = map[string]string{"serviceoperator.azure.com/resource-id": "/subscriptions/..."}
+				redis.Finalizers = nil
+			},
+		},
+		"Being deleted": {
+			update: func(redis *cache.Redis) {
+				redis.Generation++
+				redis.DeletionTimestamp = &baseTime
+				redis.Spec.Location = to.Ptr("eastus")
+			},
+		},
+		"Unrelated spec change": {
+			update: func(redis *cache.Redis) {
+				redis.Generation++
+				redis.Spec.EnableNonSslPort = to.Ptr(true)
+			},
+		},
+		"Location changed": {
+			update: func(redis *cache.Redis) {
+				redis.Generation++
+				redis.Spec.Location = to.Ptr("eastus")
+			},
+			expected: `location "eastus" is not allowed by ASOPolicy "policy"`,
+		},
+		"Tags changed": {
+			update: func(redis *cache.Redis) {
+				redis.Generation++
+				redis.Spec.Tags["Team"] = "cache"
+			},
+			expected: `tag "Owner" is required by ASOPolicy "policy"`,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			enforcer := newTestEnforcer(g, newNamespace("sandbox", nil), policy)
+
+			updated := existing.DeepCopy()
+			c.update(updated)
+
+			err := enforcer.ValidateUpdate(context.Background(), existing, updated)
+			if c.expected == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).To(MatchError(ContainSubstring(c.expected)))
+			g.Expect(err).ToNot(MatchError(ContainSubstring("SKU")))
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+)
+
+// resourceProperties are the properties of a resource restricted by policies. Properties the resource doesn't have,
+// or that aren't set, are empty.
+type resourceProperties struct {
+	location     string
+	skuName      string
+	skuTier      string
+	tags         map[string]string
+	supportsTags bool
+}
+
+// readProperties reads the properties restricted by policies from the spec of obj
+func readProperties(obj runtime.Object) (resourceProperties, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return resourceProperties{}, errors.Wrapf(err, "converting %T to unstructured", obj)
+	}
+
+	// Errors here mean the property has a different shape on this resource, so the policy can't apply to it
+	location, _, _ := unstructured.NestedString(content, "spec", "location")
+	skuName, _, _ := unstructured.NestedString(content, "spec", "sku", "name")
+	skuTier, _, _ := unstructured.NestedString(content, "spec", "sku", "tier")
+	tags, _, _ := unstructured.NestedStringMap(content, "spec", "tags")
+
+	return resourceProperties{
+		location:     location,
+		skuName:      skuName,
+		skuTier:      skuTier,
+		tags:         tags,
+		supportsTags: specHasProperty(obj, "tags"),
+	}, nil
+}
+
+// changedProperties returns the properties of a resource that have changed from old to props, leaving the others empty
+// so that they aren't checked
+func changedProperties(old resourceProperties, props resourceProperties) resourceProperties {
+	result := props
+	if normalizeLocation(old.location) == normalizeLocation(props.location) {
+		result.location = ""
+	}
+
+	if strings.EqualFold(old.skuName, props.skuName) {
+		result.skuName = ""
+	}
+
+	if strings.EqualFold(old.skuTier, props.skuTier) {
+		result.skuTier = ""
+	}
+
+	if reflect.DeepEqual(old.tags, props.tags) {
+		result.supportsTags = false
+	}
+
+	return result
+}
+
+// checkProperties returns a description of each way in which a resource with the given properties violates the policy
+func checkProperties(policy serviceoperator.ASOPolicy, gk schema.GroupKind, props resourceProperties) []string {
+	var result []string
+
+	if props.location != "" && len(policy.Spec.AllowedLocations) > 0 && !containsLocation(policy.Spec.AllowedLocations, props.location) {
+		result = append(
+			result,
+			fmt.Sprintf(
+				"location %q is not allowed by ASOPolicy %q, allowed locations are %s",
+				props.location,
+				policy.Name,
+				strings.Join(policy.Spec.AllowedLocations, ", ")))
+	}
+
+	if props.supportsTags {
+		for _, tag := range policy.Spec.RequiredTags {
+			if !hasTag(props.tags, tag) {
+				result = append(result, fmt.Sprintf("tag %q is required by ASOPolicy %q", tag, policy.Name))
+			}
+		}
+	}
+
+	for _, rule := range policy.Spec.Resources {
+		if !appliesTo(rule, gk) {
+			continue
+		}
+
+		if props.skuName != "" && len(rule.AllowedSKUs) > 0 && !containsFold(rule.AllowedSKUs, props.skuName) {
+			result = append(
+				result,
+				fmt.Sprintf(
+					"SKU %q is not allowed for %s by ASOPolicy %q, allowed SKUs are %s",
+					props.skuName,
+					gk,
+					policy.Name,
+					strings.Join(rule.AllowedSKUs, ", ")))
+		}
+
+		if props.skuTier != "" && len(rule.AllowedTiers) > 0 && !containsFold(rule.AllowedTiers, props.skuTier) {
+			result = append(
+				result,
+				fmt.Sprintf(
+					"SKU tier %q is not allowed for %s by ASOPolicy %q, allowed tiers are %s",
+					props.skuTier,
+					gk,
+					policy.Name,
+					strings.Join(rule.AllowedTiers, ", ")))
+		}
+	}
+
+	return result
+}
+
+// appliesTo returns true if the rule restricts resources of the given kind
+func appliesTo(rule serviceoperator.ResourcePolicy, gk schema.GroupKind) bool {
+	return rule.Group == gk.Group && rule.Kind == gk.Kind
+}
+
+// containsLocation returns true if location is one of the allowed locations. Azure accepts locations in either their
+// name ("westus2") or display name ("West US 2") form, so we compare ignoring case and spaces.
+func containsLocation(allowed []string, location string) bool {
+	normalized := normalizeLocation(location)
+	for _, l := range allowed {
+		if normalizeLocation(l) == normalized {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+// containsFold returns true if value is one of the allowed values, ignoring case
+func containsFold(allowed []string, value string) bool {
+	for _, v := range allowed {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasTag returns true if the tag is set. Tag names are case-insensitive in Azure.
+func hasTag(tags map[string]string, name string) bool {
+	for tag := range tags {
+		if strings.EqualFold(tag, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// specHasProperty returns true if the spec of obj has a property with the given JSON name
+func specHasProperty(obj runtime.Object, name string) bool {
+	value := reflect.Indirect(reflect.ValueOf(obj))
+	if value.Kind() != reflect.Struct {
+		return false
+	}
+
+	spec := value.FieldByName("Spec")
+	if !spec.IsValid() || spec.Kind() != reflect.Struct {
+		return false
+	}
+
+	specType := spec.Type()
+	for i := 0; i < specType.NumField(); i++ {
+		jsonName, _, _ := strings.Cut(specType.Field(i).Tag.Get("json"), ",")
+		if jsonName == name {
+			return true
+		}
+	}
+
+	return false
+}
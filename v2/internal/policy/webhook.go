@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package policy
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// WebhookValidator is the validating webhook for resources when policies are enforced. It runs the validations of
+// the resource itself, then checks the resource against the policies for its namespace.
+type WebhookValidator struct {
+	enforcer *Enforcer
+}
+
+var _ admission.CustomValidator = &WebhookValidator{}
+
+// NewWebhookValidator creates a new WebhookValidator checking resources with the given Enforcer
+func NewWebhookValidator(enforcer *Enforcer) *WebhookValidator {
+	return &WebhookValidator{
+		enforcer: enforcer,
+	}
+}
+
+// ValidateCreate validates a new resource, then checks it against the policies for its namespace
+func (v *WebhookValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	if validator, ok := obj.(admission.Validator); ok {
+		var err error
+		warnings, err = validator.ValidateCreate()
+		if err != nil {
+			return warnings, err
+		}
+	}
+
+	metaObj, ok := obj.(genruntime.MetaObject)
+	if !ok {
+		return warnings, nil
+	}
+
+	return warnings, v.enforcer.ValidateCreate(ctx, metaObj)
+}
+
+// ValidateUpdate validates an updated resource, then checks the changes to it against the policies for its namespace
+func (v *WebhookValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	if validator, ok := newObj.(admission.Validator); ok {
+		var err error
+		warnings, err = validator.ValidateUpdate(oldObj)
+		if err != nil {
+			return warnings, err
+		}
+	}
+
+	oldMetaObj, oldOk := oldObj.(genruntime.MetaObject)
+	metaObj, ok := newObj.(genruntime.MetaObject)
+	if !oldOk || !ok {
+		return warnings, nil
+	}
+
+	return warnings, v.enforcer.ValidateUpdate(ctx, oldMetaObj, metaObj)
+}
+
+// ValidateDelete validates the deletion of a resource. Policies never prevent a resource from being deleted.
+func (v *WebhookValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	if validator, ok := obj.(admission.Validator); ok {
+		return validator.ValidateDelete()
+	}
+
+	return nil, nil
+}
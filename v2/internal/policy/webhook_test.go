@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	cache "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230401"
+	serviceoperator "github.com/Azure/azure-service-operator/v2/api/serviceoperator/v1"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+func TestWebhookValidator_ValidateCreate_RunsResourceValidationsThenPolicies(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	policy := newPolicy("policy", serviceoperator.ASOPolicySpec{
+		Resources: []serviceoperator.ResourcePolicy{
+			{Group: "cache.azure.com", Kind: "Redis", AllowedSKUs: []string{"Basic"}},
+		},
+	})
+
+	validator := NewWebhookValidator(newTestEnforcer(g, newNamespace("sandbox", nil), policy))
+
+	basic := newRedis("basic", cache.Sku_Name_Basic, -1)
+	basic.Spec.Owner = &genruntime.KnownResourceReference{Name: "rg"}
+	_, err := validator.ValidateCreate(ctx, basic)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	premium := newRedis("premium", cache.Sku_Name_Premium, -1)
+	premium.Spec.Owner = &genruntime.KnownResourceReference{Name: "rg"}
+	_, err = validator.ValidateCreate(ctx, premium)
+	g.Expect(err).To(MatchError(ContainSubstring(`SKU "Premium" is not allowed`)))
+
+	// The resource's own validations still apply
+	invalid := newRedis("invalid", cache.Sku_Name_Basic, -1)
+	invalid.Spec.Owner = &genruntime.KnownResourceReference{Name: "rg", ARMID: "/subscriptions/123/resourceGroups/rg"}
+	_, err = validator.ValidateCreate(ctx, invalid)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err).ToNot(MatchError(ContainSubstring("violates policy")))
+}
@@ -15,6 +15,7 @@ const (
 	PollerResumeTokenAnnotation = "serviceoperator.azure.com/poller-resume-token"
 	PollerResumeIDAnnotation    = "serviceoperator.azure.com/poller-resume-id"
 	LatestReconciledGeneration  = "serviceoperator.azure.com/latest-reconciled-generation"
+	ActionCompletedGeneration   = "serviceoperator.azure.com/action-completed-generation"
 )
 
 // GetPollerResumeToken returns a poller ID and the poller token
@@ -48,3 +49,23 @@ func GetLatestReconciledGeneration(obj genruntime.MetaObject) (int64, bool) {
 	}
 	return int64(gen), hasGeneration
 }
+
+// SetActionCompletedGeneration records that the action has been successfully invoked for the current generation
+func SetActionCompletedGeneration(obj genruntime.MetaObject) {
+	genruntime.AddAnnotation(obj, ActionCompletedGeneration, strconv.FormatInt(obj.GetGeneration(), 10))
+}
+
+// IsActionCompleted returns true if the action has already been successfully invoked for the current generation
+func IsActionCompleted(obj genruntime.MetaObject) bool {
+	val, ok := obj.GetAnnotations()[ActionCompletedGeneration]
+	if !ok {
+		return false
+	}
+
+	gen, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return gen == obj.GetGeneration()
+}
@@ -41,6 +41,10 @@ func (c *armClient) CredentialFrom() types.NamespacedName {
 	return c.credential.CredentialFrom()
 }
 
+func (c *armClient) CredentialSource() identity.CredentialSource {
+	return c.credential.Source()
+}
+
 func (c *armClient) SubscriptionID() string {
 	return c.credential.SubscriptionID()
 }
@@ -48,5 +52,6 @@ func (c *armClient) SubscriptionID() string {
 type Connection interface {
 	Client() *genericarmclient.GenericClient
 	CredentialFrom() types.NamespacedName
+	CredentialSource() identity.CredentialSource
 	SubscriptionID() string
 }
@@ -11,10 +11,16 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/events"
+	"github.com/Azure/azure-service-operator/v2/internal/metrics"
+	"github.com/Azure/azure-service-operator/v2/internal/policy"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
@@ -56,6 +62,9 @@ type AzureDeploymentReconciler struct {
 	PositiveConditions   *conditions.PositiveConditionBuilder
 	Config               config.Values
 	Extension            genruntime.ResourceExtension
+	Metrics              *metrics.ResourceMetrics // Optional
+	Policy               *policy.Enforcer         // Optional
+	Capacity             *capacity.Checker        // Optional
 }
 
 func NewAzureDeploymentReconciler(
@@ -64,7 +73,10 @@ func NewAzureDeploymentReconciler(
 	resourceResolver *resolver.Resolver,
 	positiveConditions *conditions.PositiveConditionBuilder,
 	cfg config.Values,
-	extension genruntime.ResourceExtension) *AzureDeploymentReconciler {
+	extension genruntime.ResourceExtension,
+	resourceMetrics *metrics.ResourceMetrics,
+	policyEnforcer *policy.Enforcer,
+	capacityChecker *capacity.Checker) *AzureDeploymentReconciler {
 
 	return &AzureDeploymentReconciler{
 		ARMConnectionFactory: armConnectionFactory,
@@ -73,6 +85,9 @@ func NewAzureDeploymentReconciler(
 		PositiveConditions:   positiveConditions,
 		Config:               cfg,
 		Extension:            extension,
+		Metrics:              resourceMetrics,
+		Policy:               policyEnforcer,
+		Capacity:             capacityChecker,
 		ARMOwnedResourceReconcilerCommon: reconcilers.ARMOwnedResourceReconcilerCommon{
 			ResourceResolver: resourceResolver,
 			ReconcilerCommon: reconcilers.ReconcilerCommon{
@@ -110,7 +125,10 @@ func (r *AzureDeploymentReconciler) makeInstance(
 		return nil, err
 	}
 
-	eventRecorder.Eventf(obj, v1.EventTypeNormal, "CredentialFrom", "Using credential from %q", clientDetails.CredentialFrom().String())
+	eventRecorder.Eventf(obj, v1.EventTypeNormal, events.ReasonCredentialFrom, "Using credential from %q", clientDetails.CredentialFrom().String())
+	if r.Metrics != nil {
+		r.Metrics.RecordCredentialSource(r.groupKind(obj), obj, string(clientDetails.CredentialSource()))
+	}
 
 	// TODO: The line between AzureDeploymentReconciler and azureDeploymentReconcilerInstance is still pretty blurry
 	return newAzureDeploymentReconcilerInstance(typedObj, log, eventRecorder, clientDetails, *r), nil
@@ -126,7 +144,10 @@ func (r *AzureDeploymentReconciler) CreateOrUpdate(
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	return instance.CreateOrUpdate(ctx)
+
+	result, err := instance.CreateOrUpdate(ctx)
+	r.recordLongRunningOperation(obj)
+	return result, err
 }
 
 func (r *AzureDeploymentReconciler) Delete(
@@ -139,7 +160,10 @@ func (r *AzureDeploymentReconciler) Delete(
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	return instance.Delete(ctx)
+
+	result, err := instance.Delete(ctx)
+	r.recordLongRunningOperation(obj)
+	return result, err
 }
 
 func (r *AzureDeploymentReconciler) Claim(
@@ -183,5 +207,32 @@ func (r *AzureDeploymentReconciler) UpdateStatus(
 		return err
 	}
 
+	if genruntime.IsActionResource(instance.Obj) {
+		// Actions have no state in Azure to watch
+		return nil
+	}
+
 	return instance.handleCreateOrUpdateSuccess(ctx, WatchResource)
 }
+
+// recordLongRunningOperation records whether obj has a long-running operation in progress in Azure
+func (r *AzureDeploymentReconciler) recordLongRunningOperation(obj genruntime.MetaObject) {
+	if r.Metrics == nil {
+		return
+	}
+
+	_, _, hasPollerResumeToken := GetPollerResumeToken(obj)
+	r.Metrics.RecordLongRunningOperation(r.groupKind(obj), obj, hasPollerResumeToken)
+}
+
+// groupKind returns the GroupKind of obj, used to label metrics.
+// We use the scheme rather than the TypeMeta of obj as the latter is not always populated.
+func (r *AzureDeploymentReconciler) groupKind(obj genruntime.MetaObject) schema.GroupKind {
+	gvk, err := apiutil.GVKForObject(obj, r.KubeClient.Scheme())
+	if err != nil {
+		// Should never happen, as we only reconcile types registered with the scheme
+		return obj.GetObjectKind().GroupVersionKind().GroupKind()
+	}
+
+	return gvk.GroupKind()
+}
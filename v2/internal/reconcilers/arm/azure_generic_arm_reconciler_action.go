@@ -0,0 +1,365 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package arm
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Azure/azure-service-operator/v2/internal/events"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/secrets"
+)
+
+// Action resources represent a single ARM POST operation (such as regenerateKey or failover) on their owner.
+// The action is invoked once for each generation of the resource; its result (if any) is recorded in the status,
+// except for any secrets, which are written to the destinations given in operatorSpec.secrets instead.
+// Deleting an action resource has no effect in Azure.
+
+const (
+	CreateOrUpdateActionBeginAction   = CreateOrUpdateAction("BeginAction")
+	CreateOrUpdateActionMonitorAction = CreateOrUpdateAction("MonitorAction")
+	DeleteActionNoAction              = DeleteAction("NoAction")
+)
+
+func (r *azureDeploymentReconcilerInstance) determineActionCreateOrUpdateAction() (CreateOrUpdateAction, CreateOrUpdateActionFunc, error) {
+	pollerID, _, hasPollerResumeToken := GetPollerResumeToken(r.Obj)
+	if hasPollerResumeToken && pollerID == genericarmclient.ActionPollerID {
+		return CreateOrUpdateActionMonitorAction, r.MonitorAction, nil
+	}
+
+	if IsActionCompleted(r.Obj) {
+		return CreateOrUpdateActionNoAction, NoAction, nil
+	}
+
+	return CreateOrUpdateActionBeginAction, r.BeginAction, nil
+}
+
+// BeginAction invokes the action in Azure by issuing a POST
+func (r *azureDeploymentReconcilerInstance) BeginAction(ctx context.Context) (ctrl.Result, error) {
+	// We want to set the latest reconciled generation annotation to keep a track of reconciles per generation.
+	SetLatestReconciledGeneration(r.Obj)
+
+	actionID, body, err := r.ConvertResourceToARMAction(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	apiVersion, err := r.GetAPIVersion()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	conditions.SetConditionReasonAware(r.Obj, r.PositiveConditions.Ready.Reconciling(r.Obj.GetGeneration()))
+
+	r.Log.V(Status).Info("About to invoke action in Azure", "id", actionID)
+
+	pollerResp, err := r.ARMConnection.Client().BeginPostByID(ctx, actionID, apiVersion, body)
+	if err != nil {
+		return ctrl.Result{}, r.handleCreateOrUpdateFailed(err)
+	}
+
+	r.Log.V(Status).Info("Successfully invoked action in Azure", "id", actionID)
+	r.Recorder.Eventf(r.Obj, v1.EventTypeNormal, events.ReasonBeginAction, "Successfully invoked action %q", actionID)
+
+	if pollerResp.Poller.Done() {
+		return ctrl.Result{}, r.handleActionSuccess(ctx, pollerResp)
+	}
+
+	resumeToken, err := pollerResp.Poller.ResumeToken()
+	if err != nil {
+		return ctrl.Result{},
+			errors.Wrapf(err, "couldn't create POST resume token for action %q", actionID)
+	}
+
+	SetPollerResumeToken(r.Obj, pollerResp.ID, resumeToken)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// MonitorAction checks whether a long-running action has completed
+func (r *azureDeploymentReconcilerInstance) MonitorAction(ctx context.Context) (ctrl.Result, error) {
+	pollerID, pollerResumeToken, hasToken := GetPollerResumeToken(r.Obj)
+	if !hasToken {
+		return ctrl.Result{}, errors.New("cannot MonitorAction with empty pollerResumeToken or pollerID")
+	}
+
+	if pollerID != genericarmclient.ActionPollerID {
+		return ctrl.Result{}, errors.Errorf("cannot MonitorAction with pollerID=%s", pollerID)
+	}
+
+	poller := r.ARMConnection.Client().ResumeActionPoller(pollerID)
+	err := poller.Resume(ctx, r.ARMConnection.Client(), pollerResumeToken)
+	if err != nil {
+		return ctrl.Result{}, r.handleCreateOrUpdateFailed(err)
+	}
+
+	if poller.Poller.Done() {
+		return ctrl.Result{}, r.handleActionSuccess(ctx, poller)
+	}
+
+	// Requeue to check again later
+	retryAfter := genericarmclient.GetRetryAfter(poller.RawResponse)
+	r.Log.V(Debug).Info("Action not complete yet, will check again", "requeueAfter", retryAfter)
+	return ctrl.Result{Requeue: true, RequeueAfter: retryAfter}, nil
+}
+
+func (r *azureDeploymentReconcilerInstance) handleActionSuccess(
+	ctx context.Context,
+	poller *genericarmclient.PollerResponse[genericarmclient.GenericActionResponse],
+) error {
+	result, err := poller.Poller.Result(ctx)
+	if err != nil {
+		return r.handleCreateOrUpdateFailed(err)
+	}
+
+	r.Log.V(Status).Info("Action successfully completed", "resourceID", genruntime.GetResourceIDOrDefault(r.Obj))
+
+	if len(result.Raw) > 0 {
+		raw, actionSecrets, err := extractActionSecrets(r.Obj, result.Raw)
+		if err != nil {
+			return err
+		}
+
+		if len(actionSecrets) > 0 {
+			_, err = genruntime.ApplyObjsAndEnsureOwner(ctx, r.KubeClient, r.Obj, actionSecrets)
+			if err != nil {
+				return err
+			}
+		}
+
+		status, err := r.actionResultToStatus(raw)
+		if err != nil {
+			return err
+		}
+
+		err = r.setStatus(status)
+		if err != nil {
+			return err
+		}
+	}
+
+	SetActionCompletedGeneration(r.Obj)
+	ClearPollerResumeToken(r.Obj)
+	return nil
+}
+
+// actionResultToStatus converts the raw result of an action into the status of the resource
+func (r *azureDeploymentReconcilerInstance) actionResultToStatus(raw json.RawMessage) (genruntime.ConvertibleStatus, error) {
+	armStatus, err := genruntime.NewEmptyARMStatus(r.Obj, r.ResourceResolver.Scheme())
+	if err != nil {
+		return nil, errors.Wrapf(err, "constructing ARM status for action %s", r.Obj.GetName())
+	}
+
+	err = json.Unmarshal(raw, armStatus)
+	if err != nil {
+		return nil, errors.Wrapf(err, "deserializing result of action %s", r.Obj.GetName())
+	}
+
+	status, err := genruntime.NewEmptyVersionedStatus(r.Obj, r.ResourceResolver.Scheme())
+	if err != nil {
+		return nil, errors.Wrapf(err, "constructing Kube status for action %s", r.Obj.GetName())
+	}
+
+	s, ok := status.(genruntime.FromARMConverter)
+	if !ok {
+		return nil, errors.Errorf("expected status %T to implement genruntime.FromARMConverter", status)
+	}
+
+	err = s.PopulateFromARM(genruntime.ArbitraryOwnerReference{}, reflecthelpers.ValueOfPtr(armStatus))
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting result of action %s to Kubernetes status", r.Obj.GetName())
+	}
+
+	return status, nil
+}
+
+// extractActionSecrets removes the secrets named in operatorSpec.secrets from the raw result of an action, returning
+// the remaining result along with Kubernetes secrets containing the values of those with a destination. Secrets are
+// matched to the top level properties of the result by name, ignoring case. Every secret is removed, whether or not it has
+// a destination, as the generated status still has properties for them.
+func extractActionSecrets(obj genruntime.ARMMetaObject, raw json.RawMessage) (json.RawMessage, []client.Object, error) {
+	destinations := actionSecretDestinations(obj)
+	if len(destinations) == 0 {
+		return raw, nil, nil
+	}
+
+	var result map[string]json.RawMessage
+	if json.Unmarshal(raw, &result) != nil {
+		// Not an object, so there can't be any named secrets
+		return raw, nil, nil
+	}
+
+	collector := secrets.NewCollector(obj.GetNamespace())
+	for property, value := range result {
+		for name, dest := range destinations {
+			if !strings.EqualFold(property, name) {
+				continue
+			}
+
+			var secret string
+			err := json.Unmarshal(value, &secret)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "reading secret %s from result of action %s", name, obj.GetName())
+			}
+
+			collector.AddValue(dest, secret)
+			delete(result, property)
+		}
+	}
+
+	values, err := collector.Values()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remaining, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "serializing result of action %s", obj.GetName())
+	}
+
+	return remaining, secrets.SliceToClientObjectSlice(values), nil
+}
+
+// actionSecretDestinations returns the destinations in operatorSpec.secrets of the action, keyed by the name of the
+// property in operatorSpec.secrets. Secrets without a destination have a nil value.
+func actionSecretDestinations(obj genruntime.ARMMetaObject) map[string]*genruntime.SecretDestination {
+	value := reflect.Indirect(reflect.ValueOf(obj))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	spec := value.FieldByName("Spec")
+	if !spec.IsValid() {
+		return nil
+	}
+
+	operatorSpec := reflect.Indirect(spec.FieldByName("OperatorSpec"))
+	if !operatorSpec.IsValid() {
+		return nil
+	}
+
+	secretsValue := reflect.Indirect(operatorSpec.FieldByName("Secrets"))
+	if !secretsValue.IsValid() || secretsValue.Kind() != reflect.Struct {
+		return nil
+	}
+
+	result := make(map[string]*genruntime.SecretDestination)
+	for i := 0; i < secretsValue.NumField(); i++ {
+		dest, ok := secretsValue.Field(i).Interface().(*genruntime.SecretDestination)
+		if ok {
+			result[secretsValue.Type().Field(i).Name] = dest
+		}
+	}
+
+	return result
+}
+
+// ConvertResourceToARMAction returns the ARM ID of the action to invoke, along with the body to POST (if any)
+func (r *azureDeploymentReconcilerInstance) ConvertResourceToARMAction(ctx context.Context) (string, any, error) {
+	return ConvertToARMActionImpl(ctx, r.Obj, r.ResourceResolver.Scheme(), r.ResourceResolver, r.ARMConnection.SubscriptionID())
+}
+
+// ConvertToARMActionImpl factored out of ConvertResourceToARMAction to allow for testing
+func ConvertToARMActionImpl(
+	ctx context.Context,
+	metaObject genruntime.ARMMetaObject,
+	scheme *runtime.Scheme,
+	resolver *resolver.Resolver,
+	subscriptionID string,
+) (string, any, error) {
+	spec, err := genruntime.GetVersionedSpec(metaObject, scheme)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "unable to get spec from %s", metaObject.GetObjectKind().GroupVersionKind())
+	}
+
+	armTransformer, ok := spec.(genruntime.ARMTransformer)
+	if !ok {
+		return "", nil, errors.Errorf("spec was of type %T which doesn't implement genruntime.ArmTransformer", spec)
+	}
+
+	resourceHierarchy, resolvedDetails, err := resolver.ResolveAll(ctx, metaObject)
+	if err != nil {
+		return "", nil, reconcilers.ClassifyResolverError(err)
+	}
+
+	armSpec, err := armTransformer.ConvertToARM(resolvedDetails)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "transforming action %s to ARM", metaObject.GetName())
+	}
+
+	body, err := actionBody(armSpec)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "creating body for action %s", metaObject.GetName())
+	}
+
+	actionID, err := actionARMID(resourceHierarchy, subscriptionID)
+	if err != nil {
+		return "", nil, reconcilers.ClassifyResolverError(err)
+	}
+
+	return actionID, body, nil
+}
+
+// actionARMID returns the ID of the action, which is the ID of the owner followed by the name of the action
+func actionARMID(hierarchy resolver.ResourceHierarchy, subscriptionID string) (string, error) {
+	action := hierarchy[len(hierarchy)-1]
+
+	var ownerID string
+	if owner := action.Owner(); owner != nil && owner.IsDirectARMReference() {
+		ownerID = strings.TrimRight(owner.ARMID, "/")
+	} else if len(hierarchy) > 1 {
+		var err error
+		ownerID, err = hierarchy[:len(hierarchy)-1].FullyQualifiedARMID(subscriptionID)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		return "", errors.Errorf("action %s must have an owner", action.GetName())
+	}
+
+	_, types, err := genruntime.GetResourceTypeAndProvider(action)
+	if err != nil {
+		return "", err
+	}
+
+	return ownerID + "/" + types[len(types)-1], nil
+}
+
+// actionBody returns the body to POST for an action, or nil if there are no parameters.
+// ARM specs always carry the name of the resource; for actions the name is implied by the URL, so we remove it.
+func actionBody(armSpec any) (any, error) {
+	data, err := json.Marshal(armSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var body map[string]json.RawMessage
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(body, "name")
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	return body, nil
+}
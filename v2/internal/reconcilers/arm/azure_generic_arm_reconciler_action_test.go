@@ -0,0 +1,111 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package arm
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cache "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230401"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+type fakeActionSpec struct {
+	Name    string `json:"name,omitempty"`
+	KeyName string `json:"keyName,omitempty"`
+}
+
+func Test_ActionBody_RemovesName(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	body, err := actionBody(&fakeActionSpec{Name: "regenerateKey", KeyName: "primary"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	data, err := json.Marshal(body)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(MatchJSON(`{"keyName":"primary"}`))
+}
+
+func Test_ActionBody_WhenNoParameters_ReturnsNil(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	body, err := actionBody(&fakeActionSpec{Name: "restart"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(body).To(BeNil())
+}
+
+func Test_IsActionCompleted_TracksGeneration(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "name",
+			Generation: 1,
+		},
+	}
+	g.Expect(IsActionCompleted(obj)).To(BeFalse())
+
+	SetActionCompletedGeneration(obj)
+	g.Expect(IsActionCompleted(obj)).To(BeTrue())
+
+	// A change to the spec means the action should be invoked again
+	obj.Generation = 2
+	g.Expect(IsActionCompleted(obj)).To(BeFalse())
+}
+
+func Test_ExtractActionSecrets_MovesSecretsOutOfResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := &cache.RedisRegenerateKey{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "regenerate",
+		},
+		Spec: cache.Redis_RegenerateKey_Spec{
+			OperatorSpec: &cache.RedisRegenerateKeyOperatorSpec{
+				Secrets: &cache.RedisRegenerateKeyOperatorSecrets{
+					PrimaryKey: &genruntime.SecretDestination{Name: "keys", Key: "primary"},
+				},
+			},
+		},
+	}
+
+	raw := json.RawMessage(`{"primaryKey": "s3cr3t", "secondaryKey": "0th3r", "unknown": []}`)
+	remaining, secrets, err := extractActionSecrets(obj, raw)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Only secrets with a destination are written, but every secret is removed from the result
+	g.Expect(string(remaining)).To(MatchJSON(`{"unknown": []}`))
+	g.Expect(secrets).To(HaveLen(1))
+
+	secret, ok := secrets[0].(*v1.Secret)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(secret.Name).To(Equal("keys"))
+	g.Expect(secret.Namespace).To(Equal("default"))
+	g.Expect(secret.StringData).To(HaveKeyWithValue("primary", "s3cr3t"))
+}
+
+func Test_ExtractActionSecrets_WithoutDestinations_ReturnsResultUnchanged(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := &resources.ResourceGroup{}
+	raw := json.RawMessage(`{"primaryKey": "s3cr3t"}`)
+
+	remaining, secrets, err := extractActionSecrets(obj, raw)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remaining).To(Equal(raw))
+	g.Expect(secrets).To(BeEmpty())
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -21,11 +22,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
+	"github.com/Azure/azure-service-operator/v2/internal/events"
 	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/policy"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
 	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
 	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
@@ -40,6 +45,8 @@ type azureDeploymentReconcilerInstance struct {
 	Recorder      record.EventRecorder
 	Extension     genruntime.ResourceExtension
 	ARMConnection Connection
+	Policy        *policy.Enforcer
+	Capacity      *capacity.Checker
 }
 
 func newAzureDeploymentReconcilerInstance(
@@ -55,6 +62,8 @@ func newAzureDeploymentReconcilerInstance(
 		Recorder:                         recorder,
 		ARMConnection:                    connection,
 		Extension:                        reconciler.Extension,
+		Policy:                           reconciler.Policy,
+		Capacity:                         reconciler.Capacity,
 		ARMOwnedResourceReconcilerCommon: reconciler.ARMOwnedResourceReconcilerCommon,
 	}
 }
@@ -63,7 +72,7 @@ func (r *azureDeploymentReconcilerInstance) CreateOrUpdate(ctx context.Context)
 	action, actionFunc, err := r.DetermineCreateOrUpdateAction()
 	if err != nil {
 		r.Log.Error(err, "error determining create or update action")
-		r.Recorder.Event(r.Obj, v1.EventTypeWarning, "DetermineCreateOrUpdateActionError", err.Error())
+		events.RecordError(r.Recorder, r.Obj, events.OperationCreateOrUpdate, err)
 
 		return ctrl.Result{}, err
 	}
@@ -72,7 +81,7 @@ func (r *azureDeploymentReconcilerInstance) CreateOrUpdate(ctx context.Context)
 
 	result, err := actionFunc(ctx)
 	if err != nil {
-		r.Recorder.Event(r.Obj, v1.EventTypeWarning, "CreateOrUpdateActionError", err.Error())
+		events.RecordError(r.Recorder, r.Obj, events.OperationCreateOrUpdate, err)
 
 		return ctrl.Result{}, err
 	}
@@ -83,7 +92,7 @@ func (r *azureDeploymentReconcilerInstance) CreateOrUpdate(ctx context.Context)
 func (r *azureDeploymentReconcilerInstance) Delete(ctx context.Context) (ctrl.Result, error) {
 	action, actionFunc, err := r.DetermineDeleteAction()
 	if err != nil {
-		r.Recorder.Event(r.Obj, v1.EventTypeWarning, "DetermineDeleteActionError", err.Error())
+		events.RecordError(r.Recorder, r.Obj, events.OperationDelete, err)
 
 		return ctrl.Result{}, err
 	}
@@ -93,7 +102,7 @@ func (r *azureDeploymentReconcilerInstance) Delete(ctx context.Context) (ctrl.Re
 	result, err := actionFunc(ctx)
 	if err != nil {
 		r.Log.Error(err, "Error during Delete", "action", action)
-		r.Recorder.Event(r.Obj, v1.EventTypeWarning, "DeleteActionError", err.Error())
+		events.RecordError(r.Recorder, r.Obj, events.OperationDelete, err)
 
 		return ctrl.Result{}, err
 	}
@@ -157,6 +166,15 @@ func (r *azureDeploymentReconcilerInstance) MakeReadyConditionImpactingErrorFrom
 }
 
 func (r *azureDeploymentReconcilerInstance) AddInitialResourceState(ctx context.Context) error {
+	if genruntime.IsActionResource(r.Obj) {
+		actionID, _, err := r.ConvertResourceToARMAction(ctx)
+		if err != nil {
+			return err
+		}
+		genruntime.SetResourceID(r.Obj, actionID)
+		return nil
+	}
+
 	armResource, err := r.ConvertResourceToARMResource(ctx)
 	if err != nil {
 		return err
@@ -166,6 +184,11 @@ func (r *azureDeploymentReconcilerInstance) AddInitialResourceState(ctx context.
 }
 
 func (r *azureDeploymentReconcilerInstance) DetermineDeleteAction() (DeleteAction, DeleteActionFunc, error) {
+	if genruntime.IsActionResource(r.Obj) {
+		// There's nothing in Azure to delete for an action
+		return DeleteActionNoAction, NoAction, nil
+	}
+
 	pollerID, _, hasPollerResumeToken := GetPollerResumeToken(r.Obj)
 
 	if hasPollerResumeToken && pollerID == genericarmclient.DeletePollerID {
@@ -183,6 +206,10 @@ func (r *azureDeploymentReconcilerInstance) DetermineCreateOrUpdateAction() (Cre
 		return CreateOrUpdateActionNoAction, NoAction, errors.Errorf("resource is currently deleting; it can not be applied")
 	}
 
+	if genruntime.IsActionResource(r.Obj) {
+		return r.determineActionCreateOrUpdateAction()
+	}
+
 	if hasPollerResumeToken {
 		return CreateOrUpdateActionMonitorCreation, r.MonitorResourceCreation, nil
 	}
@@ -203,7 +230,7 @@ func NoAction(_ context.Context) (ctrl.Result, error) {
 func (r *azureDeploymentReconcilerInstance) StartDeleteOfResource(ctx context.Context) (ctrl.Result, error) {
 	msg := "Starting delete of resource"
 	r.Log.V(Status).Info(msg)
-	r.Recorder.Event(r.Obj, v1.EventTypeNormal, string(DeleteActionBeginDelete), msg)
+	r.Recorder.Event(r.Obj, v1.EventTypeNormal, events.ReasonBeginDelete, msg)
 
 	deleter := extensions.CreateDeleter(r.Extension, r.deleteResource)
 	result, err := deleter(ctx, r.Log, r.ResourceResolver, r.ARMConnection.Client(), r.Obj)
@@ -215,7 +242,7 @@ func (r *azureDeploymentReconcilerInstance) StartDeleteOfResource(ctx context.Co
 func (r *azureDeploymentReconcilerInstance) MonitorDelete(ctx context.Context) (ctrl.Result, error) {
 	msg := "Continue monitoring deletion"
 	r.Log.V(Verbose).Info(msg)
-	r.Recorder.Event(r.Obj, v1.EventTypeNormal, string(DeleteActionMonitorDelete), msg)
+	r.Recorder.Event(r.Obj, v1.EventTypeNormal, events.ReasonMonitorDelete, msg)
 	//
 	//// Technically we don't need the resource ID anymore to monitor delete
 	//_, hasResourceID := genruntime.GetResourceID(r.Obj)
@@ -261,6 +288,11 @@ func (r *azureDeploymentReconcilerInstance) BeginCreateOrUpdateResource(
 	// We want to set the latest reconciled generation annotation to keep a track of reconciles per generation.
 	SetLatestReconciledGeneration(r.Obj)
 
+	err := r.checkPolicy(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	check, err := r.preReconciliationCheck(ctx)
 	if err != nil {
 		// Failed to do the pre-reconciliation check, this is a serious but non-fatal error
@@ -318,7 +350,7 @@ func (r *azureDeploymentReconcilerInstance) BeginCreateOrUpdateResource(
 	}
 
 	r.Log.V(Status).Info("Successfully sent resource to Azure", "id", armResource.GetID())
-	r.Recorder.Eventf(r.Obj, v1.EventTypeNormal, string(CreateOrUpdateActionBeginCreation), "Successfully sent resource to Azure with ID %q", armResource.GetID())
+	r.Recorder.Eventf(r.Obj, v1.EventTypeNormal, events.ReasonBeginCreateOrUpdate, "Successfully sent resource to Azure with ID %q", armResource.GetID())
 
 	// If we are done here it means the deployment succeeded immediately. It can't have failed because if it did
 	// we would have taken the error path above.
@@ -336,6 +368,30 @@ func (r *azureDeploymentReconcilerInstance) BeginCreateOrUpdateResource(
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// checkPolicy checks the resource against the ASOPolicies for its namespace, if enabled. The webhooks also check them,
+// but a resource may have been created while the webhooks weren't running, or before a policy was created.
+// Limits on counts are only checked until the resource has been created in Azure, so that tightening a limit doesn't
+// block updates to existing resources, and so that we don't list every resource of the kind on each reconcile.
+func (r *azureDeploymentReconcilerInstance) checkPolicy(ctx context.Context) error {
+	if r.Policy == nil {
+		return nil
+	}
+
+	_, created := genruntime.GetResourceID(r.Obj)
+	violations, err := r.Policy.Violations(ctx, r.Obj, !created)
+	if err != nil {
+		return errors.Wrap(err, "checking ASOPolicies")
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	r.Log.V(Status).Info("Resource violates policy", "violations", violations)
+	err = errors.Errorf("resource violates policy: %s", strings.Join(violations, "; "))
+	return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityError, conditions.ReasonPolicyViolation)
+}
+
 func (r *azureDeploymentReconcilerInstance) preReconciliationCheck(ctx context.Context) (extensions.PreReconcileCheckResult, error) {
 	// Create a checker for access to the extension point, if required
 	checker, extensionFound := extensions.CreatePreReconciliationChecker(r.Extension)
@@ -360,7 +416,14 @@ func (r *azureDeploymentReconcilerInstance) preReconciliationCheck(ctx context.C
 	}
 
 	// Run our pre-reconciliation checker
-	check, checkErr := checker(ctx, r.Obj, ownerDetails.Owner, r.ResourceResolver, r.ARMConnection.Client(), r.Log)
+	checkCtx, span := tracing.StartSpan(ctx, "PreReconciliationChecker", tracing.ObjectAttributes(r.Obj)...)
+	if r.Capacity != nil {
+		// Extensions for resources that need capacity in Azure check it using the checker
+		checkCtx = capacity.WithChecker(checkCtx, r.Capacity, r.ARMConnection.SubscriptionID())
+	}
+
+	check, checkErr := checker(checkCtx, r.Obj, ownerDetails.Owner, r.ResourceResolver, r.ARMConnection.Client(), r.Log)
+	tracing.EndSpan(span, checkErr)
 	if checkErr != nil {
 		// Something went wrong running the check.
 		return extensions.PreReconcileCheckResult{}, checkErr
@@ -490,7 +553,9 @@ func (r *azureDeploymentReconcilerInstance) postReconciliationCheck(ctx context.
 	}
 
 	// Run our post-reconciliation checker
-	check, checkErr := checker(ctx, r.Obj, ownerDetails.Owner, r.ResourceResolver, r.ARMConnection.Client(), r.Log)
+	checkCtx, span := tracing.StartSpan(ctx, "PostReconciliationChecker", tracing.ObjectAttributes(r.Obj)...)
+	check, checkErr := checker(checkCtx, r.Obj, ownerDetails.Owner, r.ResourceResolver, r.ARMConnection.Client(), r.Log)
+	tracing.EndSpan(span, checkErr)
 	if checkErr != nil {
 		// Something went wrong running the check.
 		return extensions.PostReconcileCheckResult{}, checkErr
@@ -657,8 +722,10 @@ func (r *azureDeploymentReconcilerInstance) updateStatus(ctx context.Context) er
 // If there are no resources to save this method is a no-op.
 func (r *azureDeploymentReconcilerInstance) saveAssociatedKubernetesResources(ctx context.Context) error {
 	// Check if this resource has a handcrafted extension for exporting
-	retriever := extensions.CreateKubernetesExporter(ctx, r.Extension, r.ARMConnection.Client(), r.Log)
+	exportCtx, span := tracing.StartSpan(ctx, "KubernetesExporter", tracing.ObjectAttributes(r.Obj)...)
+	retriever := extensions.CreateKubernetesExporter(exportCtx, r.Extension, r.ARMConnection.Client(), r.Log)
 	resources, err := retriever(r.Obj)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		return errors.Wrap(err, "extension failed to produce resources for export")
 	}
@@ -667,7 +734,9 @@ func (r *azureDeploymentReconcilerInstance) saveAssociatedKubernetesResources(ct
 	exporter, ok := r.ObjAsKubernetesExporter()
 	if ok {
 		var additionalResources []client.Object
-		additionalResources, err = exporter.ExportKubernetesResources(ctx, r.Obj, r.ARMConnection.Client(), r.Log)
+		exportCtx, span = tracing.StartSpan(ctx, "ExportKubernetesResources", tracing.ObjectAttributes(r.Obj)...)
+		additionalResources, err = exporter.ExportKubernetesResources(exportCtx, r.Obj, r.ARMConnection.Client(), r.Log)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			return errors.Wrap(err, "failed to produce resources for export")
 		}
@@ -758,11 +827,26 @@ func (r *azureDeploymentReconcilerInstance) ConvertResourceToARMResource(ctx con
 
 	// Run any resource-specific extensions
 	modifier := extensions.CreateARMResourceModifier(r.Extension, r.ARMConnection.Client(), r.KubeClient, r.ResourceResolver, r.Log)
-	return modifier(ctx, metaObject, result)
+	modifyCtx, span := tracing.StartSpan(ctx, "ModifyARMResource", tracing.ObjectAttributes(metaObject)...)
+	result, err = modifier(modifyCtx, metaObject, result)
+	tracing.EndSpan(span, err)
+	return result, err
 }
 
 // ConvertToARMResourceImpl factored out of AzureDeploymentReconciler.ConvertResourceToARMResource to allow for testing
 func ConvertToARMResourceImpl(
+	ctx context.Context,
+	metaObject genruntime.ARMMetaObject,
+	scheme *runtime.Scheme,
+	resolver *resolver.Resolver,
+	subscriptionID string) (genruntime.ARMResource, error) {
+	ctx, span := tracing.StartSpan(ctx, "ConvertToARMResourceImpl", tracing.ObjectAttributes(metaObject)...)
+	result, err := convertToARMResource(ctx, metaObject, scheme, resolver, subscriptionID)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func convertToARMResource(
 	ctx context.Context,
 	metaObject genruntime.ARMMetaObject,
 	scheme *runtime.Scheme,
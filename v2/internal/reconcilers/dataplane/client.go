@@ -0,0 +1,178 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package dataplane
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/version"
+)
+
+// Client is a minimal client for the data plane of an Azure service. Unlike ARM, data plane operations are
+// synchronous and are addressed by URL rather than by resource ID.
+type Client struct {
+	pl runtime.Pipeline
+}
+
+type ClientOptions struct {
+	HttpClient *http.Client
+	UserAgent  string
+}
+
+// NewClient creates a new Client which authenticates using tokens for the specified audience
+func NewClient(
+	creds azcore.TokenCredential,
+	audience string,
+	options *ClientOptions,
+) *Client {
+	if options == nil {
+		options = &ClientOptions{}
+	}
+
+	ua := options.UserAgent
+	if ua == "" {
+		ua = "aso-controller/" + version.BuildVersion
+	}
+
+	opts := &policy.ClientOptions{
+		Retry: policy.RetryOptions{
+			MaxRetries: -1, // Have to use a value less than 0 means no retries (0 does NOT, 0 gets you 3...)
+		},
+		PerCallPolicies: []policy.Policy{
+			genericarmclient.NewUserAgentPolicy(ua),
+		},
+	}
+
+	// See NewGenericClient for why we only assign the HttpClient when it's present
+	if options.HttpClient != nil {
+		opts.Transport = options.HttpClient
+	}
+
+	scope := strings.TrimRight(audience, "/") + "/.default"
+	authPolicy := runtime.NewBearerTokenPolicy(creds, []string{scope}, nil)
+	pipeline := runtime.NewPipeline(
+		"dataplane",
+		version.BuildVersion,
+		runtime.PipelineOptions{
+			PerRetry: []policy.Policy{authPolicy},
+		},
+		opts)
+
+	return &Client{
+		pl: pipeline,
+	}
+}
+
+// PutByURL creates or updates the resource at the specified URL, deserializing the response into result.
+// If the operation fails it returns the *genericarmclient.CloudError error type.
+func (client *Client) PutByURL(
+	ctx context.Context,
+	url string,
+	apiVersion string,
+	body any,
+	result any,
+) error {
+	req, err := client.createRequest(ctx, http.MethodPut, url, apiVersion)
+	if err != nil {
+		return err
+	}
+
+	err = runtime.MarshalAsJSON(req, body)
+	if err != nil {
+		return err
+	}
+
+	return client.do(req, result, http.StatusOK, http.StatusCreated)
+}
+
+// GetByURL gets the resource at the specified URL, deserializing the response into result.
+// If the operation fails it returns the *genericarmclient.CloudError error type.
+func (client *Client) GetByURL(
+	ctx context.Context,
+	url string,
+	apiVersion string,
+	result any,
+) error {
+	req, err := client.createRequest(ctx, http.MethodGet, url, apiVersion)
+	if err != nil {
+		return err
+	}
+
+	return client.do(req, result, http.StatusOK)
+}
+
+// DeleteByURL deletes the resource at the specified URL.
+// If the operation fails it returns the *genericarmclient.CloudError error type.
+func (client *Client) DeleteByURL(
+	ctx context.Context,
+	url string,
+	apiVersion string,
+) error {
+	req, err := client.createRequest(ctx, http.MethodDelete, url, apiVersion)
+	if err != nil {
+		return err
+	}
+
+	return client.do(req, nil, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+}
+
+func (client *Client) createRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	apiVersion string,
+) (*policy.Request, error) {
+	if url == "" {
+		return nil, errors.New("parameter url cannot be empty")
+	}
+
+	req, err := runtime.NewRequest(ctx, method, url)
+	if err != nil {
+		return nil, err
+	}
+
+	reqQP := req.Raw().URL.Query()
+	reqQP.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = reqQP.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+func (client *Client) do(req *policy.Request, result any, statusCodes ...int) error {
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(resp, statusCodes...) {
+		return client.handleError(resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return runtime.UnmarshalAsJSON(resp, result)
+}
+
+// handleError handles an error response. Many data plane services use the same error format as ARM, but not all
+// of them do; if the body isn't understood we return the raw response error instead.
+func (client *Client) handleError(resp *http.Response) error {
+	errType := genericarmclient.NewCloudError(runtime.NewResponseError(resp))
+	if err := runtime.UnmarshalAsJSON(resp, errType); err != nil {
+		return runtime.NewResponseError(resp)
+	}
+
+	return errType
+}
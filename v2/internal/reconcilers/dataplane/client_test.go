@@ -0,0 +1,96 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package dataplane_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/dataplane"
+	"github.com/Azure/azure-service-operator/v2/internal/testcommon"
+)
+
+type secret struct {
+	Value string `json:"value,omitempty"`
+	ID    string `json:"id,omitempty"`
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*dataplane.Client, string) {
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	client := dataplane.NewClient(
+		testcommon.MockTokenCredential{},
+		"https://vault.azure.net",
+		&dataplane.ClientOptions{
+			HttpClient: server.Client(),
+		})
+
+	return client, server.URL
+}
+
+func Test_PutByURL_SendsAuthorizedRequest(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client, serverURL := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.Method).To(Equal(http.MethodPut))
+		g.Expect(r.URL.Path).To(Equal("/secrets/mysecret"))
+		g.Expect(r.URL.Query().Get("api-version")).To(Equal("7.4"))
+		g.Expect(r.Header.Get("Authorization")).To(Equal("Bearer abc123"))
+
+		var body secret
+		g.Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		g.Expect(body.Value).To(Equal("hunter2"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"https://myvault.vault.azure.net/secrets/mysecret/abc"}`))
+	})
+
+	var result secret
+	err := client.PutByURL(context.Background(), serverURL+"/secrets/mysecret", "7.4", secret{Value: "hunter2"}, &result)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.ID).To(Equal("https://myvault.vault.azure.net/secrets/mysecret/abc"))
+}
+
+func Test_GetByURL_WhenNotFound_ReturnsNotFoundError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client, serverURL := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":"SecretNotFound","message":"A secret with (name/id) mysecret was not found in this key vault."}}`))
+	})
+
+	var result secret
+	err := client.GetByURL(context.Background(), serverURL+"/secrets/mysecret", "7.4", &result)
+	g.Expect(genericarmclient.IsNotFoundError(err)).To(BeTrue())
+
+	var cloudError *genericarmclient.CloudError
+	g.Expect(errors.As(err, &cloudError)).To(BeTrue())
+	g.Expect(cloudError.Code()).To(Equal("SecretNotFound"))
+}
+
+func Test_DeleteByURL_AcceptsNoContent(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	client, serverURL := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.Method).To(Equal(http.MethodDelete))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := client.DeleteByURL(context.Background(), serverURL+"/kv/mykey", "1.0")
+	g.Expect(err).ToNot(HaveOccurred())
+}
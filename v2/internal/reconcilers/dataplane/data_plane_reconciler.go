@@ -0,0 +1,381 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package dataplane
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/identity"
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	armreconciler "github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm"
+	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
+)
+
+const (
+	// OwnerPlaceholder is replaced in the data plane endpoint of a resource with the Azure name of its owner
+	OwnerPlaceholder = "{owner}"
+
+	// NamePlaceholder is replaced in the data plane path of a resource with its Azure name
+	NamePlaceholder = "{name}"
+)
+
+var _ genruntime.Reconciler = &DataPlaneReconciler{}
+
+// DataPlaneReconciler reconciles resources that live in the data plane of an Azure service (such as Key Vault secrets
+// or App Configuration key-values). These resources are always owned by an ARM resource, which supplies the endpoint
+// of the data plane.
+type DataPlaneReconciler struct {
+	reconcilers.ARMOwnedResourceReconcilerCommon
+	ResourceResolver   *resolver.Resolver
+	CredentialProvider identity.CredentialProvider
+	Config             config.Values
+	ClientOptions      *ClientOptions
+}
+
+func NewDataPlaneReconciler(
+	kubeClient kubeclient.Client,
+	resourceResolver *resolver.Resolver,
+	positiveConditions *conditions.PositiveConditionBuilder,
+	credentialProvider identity.CredentialProvider,
+	cfg config.Values,
+	clientOptions *ClientOptions) *DataPlaneReconciler {
+
+	return &DataPlaneReconciler{
+		ResourceResolver:   resourceResolver,
+		CredentialProvider: credentialProvider,
+		Config:             cfg,
+		ClientOptions:      clientOptions,
+		ARMOwnedResourceReconcilerCommon: reconcilers.ARMOwnedResourceReconcilerCommon{
+			ResourceResolver: resourceResolver,
+			ReconcilerCommon: reconcilers.ReconcilerCommon{
+				KubeClient:         kubeClient,
+				PositiveConditions: positiveConditions,
+			},
+		},
+	}
+}
+
+func (r *DataPlaneReconciler) asDataPlaneResource(obj genruntime.MetaObject) (genruntime.DataPlaneResource, error) {
+	typedObj, ok := obj.(genruntime.DataPlaneResource)
+	if !ok {
+		return nil, errors.Errorf("cannot modify resource that is not a genruntime.DataPlaneResource. Type is %T", obj)
+	}
+
+	return typedObj, nil
+}
+
+func (r *DataPlaneReconciler) CreateOrUpdate(ctx context.Context, log logr.Logger, eventRecorder record.EventRecorder, obj genruntime.MetaObject) (ctrl.Result, error) {
+	typedObj, err := r.asDataPlaneResource(obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Augment Log
+	log = log.WithValues("azureName", typedObj.AzureName())
+
+	client, resourceURL, apiVersion, err := r.connect(ctx, typedObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	body, err := r.convertToPayload(ctx, typedObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	conditions.SetConditionReasonAware(typedObj, r.PositiveConditions.Ready.Reconciling(typedObj.GetGeneration()))
+
+	log.V(Status).Info("About to send resource to Azure data plane", "url", resourceURL)
+
+	var result json.RawMessage
+	err = client.PutByURL(ctx, resourceURL, apiVersion, body, &result)
+	if err != nil {
+		return ctrl.Result{}, r.classifyError(err)
+	}
+
+	log.V(Status).Info("Successfully sent resource to Azure data plane", "url", resourceURL)
+	eventRecorder.Eventf(typedObj, v1.EventTypeNormal, "CreateOrUpdate", "Successfully sent resource to Azure with URL %q", resourceURL)
+
+	genruntime.SetDataPlaneURL(typedObj, resourceURL)
+	err = r.setStatus(typedObj, result)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *DataPlaneReconciler) Delete(ctx context.Context, log logr.Logger, eventRecorder record.EventRecorder, obj genruntime.MetaObject) (ctrl.Result, error) {
+	typedObj, err := r.asDataPlaneResource(obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Augment Log
+	log = log.WithValues("azureName", typedObj.AzureName())
+
+	log.V(Status).Info("Starting delete of resource")
+
+	// Check that this objects owner still exists
+	// This is an optimization to avoid excess requests to Azure.
+	_, err = r.ResourceResolver.ResolveOwner(ctx, typedObj)
+	if err != nil {
+		var typedErr *core.ReferenceNotFound
+		if errors.As(err, &typedErr) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	client, resourceURL, apiVersion, err := r.connect(ctx, typedObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = client.DeleteByURL(ctx, resourceURL, apiVersion)
+	if err != nil && !genericarmclient.IsNotFoundError(err) {
+		return ctrl.Result{}, r.classifyError(err)
+	}
+
+	log.V(Status).Info("Successfully deleted resource from Azure data plane", "url", resourceURL)
+	return ctrl.Result{}, nil
+}
+
+func (r *DataPlaneReconciler) Claim(ctx context.Context, log logr.Logger, eventRecorder record.EventRecorder, obj genruntime.MetaObject) error {
+	typedObj, err := r.asDataPlaneResource(obj)
+	if err != nil {
+		return err
+	}
+
+	err = r.ARMOwnedResourceReconcilerCommon.ClaimResource(ctx, log, typedObj)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *DataPlaneReconciler) UpdateStatus(ctx context.Context, log logr.Logger, eventRecorder record.EventRecorder, obj genruntime.MetaObject) error {
+	typedObj, err := r.asDataPlaneResource(obj)
+	if err != nil {
+		return err
+	}
+
+	client, resourceURL, apiVersion, err := r.connect(ctx, typedObj)
+	if err != nil {
+		return err
+	}
+
+	var result json.RawMessage
+	err = client.GetByURL(ctx, resourceURL, apiVersion, &result)
+	if err != nil {
+		if genericarmclient.IsNotFoundError(err) {
+			err = errors.Wrapf(err, "%s %s does not exist", typedObj.GetType(), typedObj.AzureName())
+			return conditions.NewReadyConditionImpactingError(err, conditions.ConditionSeverityWarning, conditions.ReasonAzureResourceNotFound)
+		}
+
+		return r.classifyError(err)
+	}
+
+	return r.setStatus(typedObj, result)
+}
+
+// connect returns a client for the data plane of the resource, along with the URL and API version to use
+func (r *DataPlaneReconciler) connect(
+	ctx context.Context,
+	obj genruntime.DataPlaneResource,
+) (*Client, string, string, error) {
+	ownerName, err := r.ownerName(ctx, obj)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	resourceURL, err := ResourceURL(obj, ownerName)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	apiVersion, err := genruntime.GetAPIVersion(obj, r.ResourceResolver.Scheme())
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	credential, err := r.CredentialProvider.GetCredential(ctx, obj)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	client := NewClient(credential.TokenCredential(), obj.GetDataPlaneAudience(), r.ClientOptions)
+	return client, resourceURL, apiVersion, nil
+}
+
+// ownerName returns the Azure name of the owner of the resource, which identifies the instance of the data plane
+func (r *DataPlaneReconciler) ownerName(ctx context.Context, obj genruntime.DataPlaneResource) (string, error) {
+	ownerDetails, err := r.ResourceResolver.ResolveOwner(ctx, obj)
+	if err != nil {
+		return "", reconcilers.ClassifyResolverError(err)
+	}
+
+	switch ownerDetails.Result {
+	case resolver.OwnerFoundKubernetes:
+		return ownerDetails.Owner.AzureName(), nil
+	case resolver.OwnerFoundARM:
+		id, err := arm.ParseResourceID(ownerDetails.ARMID)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing owner ARM ID %q", ownerDetails.ARMID)
+		}
+		return id.Name, nil
+	default:
+		return "", errors.Errorf("data plane resource %s must have an owner", obj.GetName())
+	}
+}
+
+// ResourceURL returns the URL of the resource in the data plane, given the Azure name of its owner; for example,
+// https://myvault.vault.azure.net/secrets/mysecret for a Key Vault secret.
+func ResourceURL(obj genruntime.DataPlaneResource, ownerName string) (string, error) {
+	endpoint := obj.GetDataPlaneEndpoint()
+	if !strings.Contains(endpoint, OwnerPlaceholder) {
+		return "", errors.Errorf("data plane endpoint %q of %s doesn't contain %s", endpoint, obj.GetName(), OwnerPlaceholder)
+	}
+
+	path := obj.GetDataPlanePath()
+	if !strings.Contains(path, NamePlaceholder) {
+		return "", errors.Errorf("data plane path %q of %s doesn't contain %s", path, obj.GetName(), NamePlaceholder)
+	}
+
+	endpoint = strings.ReplaceAll(endpoint, OwnerPlaceholder, ownerName)
+	path = strings.ReplaceAll(path, NamePlaceholder, url.PathEscape(obj.AzureName()))
+
+	return strings.TrimRight(endpoint, "/") + "/" + strings.TrimLeft(path, "/"), nil
+}
+
+// convertToPayload converts the spec of the resource into the body to send to the data plane.
+// Data plane resources are identified by their URL, so the name is removed from the body.
+func (r *DataPlaneReconciler) convertToPayload(ctx context.Context, obj genruntime.DataPlaneResource) (map[string]json.RawMessage, error) {
+	spec, err := genruntime.GetVersionedSpec(obj, r.ResourceResolver.Scheme())
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get spec from %s", obj.GetObjectKind().GroupVersionKind())
+	}
+
+	armTransformer, ok := spec.(genruntime.ARMTransformer)
+	if !ok {
+		return nil, errors.Errorf("spec was of type %T which doesn't implement genruntime.ArmTransformer", spec)
+	}
+
+	_, resolvedDetails, err := r.ResourceResolver.ResolveAll(ctx, obj)
+	if err != nil {
+		return nil, reconcilers.ClassifyResolverError(err)
+	}
+
+	armSpec, err := armTransformer.ConvertToARM(resolvedDetails)
+	if err != nil {
+		return nil, errors.Wrapf(err, "transforming resource %s to ARM", obj.GetName())
+	}
+
+	data, err := json.Marshal(armSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "serializing resource %s", obj.GetName())
+	}
+
+	var body map[string]json.RawMessage
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "deserializing resource %s", obj.GetName())
+	}
+
+	delete(body, "name")
+	return body, nil
+}
+
+// setStatus converts the response from the data plane into the status of the resource
+func (r *DataPlaneReconciler) setStatus(obj genruntime.DataPlaneResource, raw json.RawMessage) error {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	armStatus, err := genruntime.NewEmptyARMStatus(obj, r.ResourceResolver.Scheme())
+	if err != nil {
+		return errors.Wrapf(err, "constructing ARM status for %s", obj.GetName())
+	}
+
+	err = json.Unmarshal(raw, armStatus)
+	if err != nil {
+		return errors.Wrapf(err, "deserializing status of %s", obj.GetName())
+	}
+
+	status, err := genruntime.NewEmptyVersionedStatus(obj, r.ResourceResolver.Scheme())
+	if err != nil {
+		return errors.Wrapf(err, "constructing Kube status for %s", obj.GetName())
+	}
+
+	s, ok := status.(genruntime.FromARMConverter)
+	if !ok {
+		return errors.Errorf("expected status %T to implement genruntime.FromARMConverter", status)
+	}
+
+	err = s.PopulateFromARM(genruntime.ArbitraryOwnerReference{}, reflecthelpers.ValueOfPtr(armStatus))
+	if err != nil {
+		return errors.Wrapf(err, "converting status of %s to Kubernetes", obj.GetName())
+	}
+
+	// SetStatus() takes care of any required conversion to the right version
+	err = obj.SetStatus(status)
+	if err != nil {
+		return errors.Wrapf(err, "setting status on %s", obj.GetObjectKind().GroupVersionKind())
+	}
+
+	return nil
+}
+
+// classifyError converts an error from the data plane into one that impacts the Ready condition
+func (r *DataPlaneReconciler) classifyError(err error) error {
+	var readyConditionError *conditions.ReadyConditionImpactingError
+	if errors.As(err, &readyConditionError) {
+		return err
+	}
+
+	var cloudError *genericarmclient.CloudError
+	if !errors.As(err, &cloudError) {
+		// Not every data plane uses the ARM error format; when it doesn't we can't say anything more useful
+		return conditions.NewReadyConditionImpactingError(
+			err,
+			conditions.ConditionSeverityWarning,
+			conditions.MakeReason(core.UnknownErrorCode))
+	}
+
+	details, classifyErr := armreconciler.ClassifyCloudError(cloudError)
+	if classifyErr != nil {
+		return errors.Wrapf(classifyErr, "Unable to classify cloud error (%s)", cloudError.Error())
+	}
+
+	severity := conditions.ConditionSeverityWarning
+	if details.Classification == core.ErrorFatal {
+		severity = conditions.ConditionSeverityError
+	}
+
+	return conditions.NewReadyConditionImpactingError(
+		errors.Wrapf(cloudError, details.Message),
+		severity,
+		conditions.MakeReason(details.Code))
+}
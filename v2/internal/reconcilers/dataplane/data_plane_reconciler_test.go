@@ -0,0 +1,121 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package dataplane_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/dataplane"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+// fakeDataPlaneResource lets us exercise data plane behaviour without a generated data plane resource
+type fakeDataPlaneResource struct {
+	*resources.ResourceGroup
+	endpoint string
+	path     string
+}
+
+var _ genruntime.DataPlaneResource = &fakeDataPlaneResource{}
+
+func (f *fakeDataPlaneResource) GetDataPlaneAudience() string {
+	return "https://vault.azure.net"
+}
+
+func (f *fakeDataPlaneResource) GetDataPlaneEndpoint() string {
+	return f.endpoint
+}
+
+func (f *fakeDataPlaneResource) GetDataPlanePath() string {
+	return f.path
+}
+
+func newFakeDataPlaneResource(endpoint string, path string, azureName string) *fakeDataPlaneResource {
+	return &fakeDataPlaneResource{
+		ResourceGroup: &resources.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: resources.ResourceGroup_Spec{
+				AzureName: azureName,
+			},
+		},
+		endpoint: endpoint,
+		path:     path,
+	}
+}
+
+func Test_ResourceURL_ReturnsDataPlaneURL(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		endpoint  string
+		path      string
+		ownerName string
+		azureName string
+		expected  string
+	}{
+		"Key Vault secret": {
+			endpoint:  "https://{owner}.vault.azure.net",
+			path:      "/secrets/{name}",
+			ownerName: "myvault",
+			azureName: "mysecret",
+			expected:  "https://myvault.vault.azure.net/secrets/mysecret",
+		},
+		"App Configuration key-value": {
+			endpoint:  "https://{owner}.azconfig.io",
+			path:      "/kv/{name}",
+			ownerName: "mystore",
+			azureName: "app:settings/colour",
+			expected:  "https://mystore.azconfig.io/kv/app:settings%2Fcolour",
+		},
+		"Endpoint with trailing slash": {
+			endpoint:  "https://{owner}.vault.azure.net/",
+			path:      "/secrets/{name}",
+			ownerName: "myvault",
+			azureName: "my secret",
+			expected:  "https://myvault.vault.azure.net/secrets/my%20secret",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			obj := newFakeDataPlaneResource(c.endpoint, c.path, c.azureName)
+
+			url, err := dataplane.ResourceURL(obj, c.ownerName)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(url).To(Equal(c.expected))
+		})
+	}
+}
+
+func Test_ResourceURL_WhenEndpointMissingOwner_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := newFakeDataPlaneResource("https://vault.azure.net", "/secrets/{name}", "mysecret")
+
+	_, err := dataplane.ResourceURL(obj, "myvault")
+	g.Expect(err).To(MatchError(ContainSubstring("{owner}")))
+}
+
+func Test_ResourceURL_WhenPathMissingName_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	obj := newFakeDataPlaneResource("https://{owner}.vault.azure.net", "/secrets", "mysecret")
+
+	_, err := dataplane.ResourceURL(obj, "myvault")
+	g.Expect(err).To(MatchError(ContainSubstring("{name}")))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -24,8 +25,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/events"
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/metrics"
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers"
+	"github.com/Azure/azure-service-operator/v2/internal/sharding"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/internal/util/interval"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
@@ -51,18 +56,45 @@ type GenericReconciler struct {
 	GVK                       schema.GroupVersionKind
 	PositiveConditions        *conditions.PositiveConditionBuilder
 	RequeueIntervalCalculator interval.Calculator
+	Metrics                   *metrics.ResourceMetrics // Optional
+	Shards                    sharding.Filter          // Optional; if set, only resources in our shards are reconciled
 }
 
 var _ reconcile.Reconciler = &GenericReconciler{} // GenericReconciler is a reconcile.Reconciler
 
 // Reconcile will take state in K8s and apply it to Azure
 func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartSpan(
+		ctx,
+		"GenericReconciler.Reconcile",
+		tracing.KindKey.String(gr.GVK.Kind),
+		tracing.NamespaceKey.String(req.Namespace),
+		tracing.NameKey.String(req.Name))
+
+	result, err := gr.reconcile(ctx, req)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (gr *GenericReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if gr.Shards != nil && !gr.Shards.Owns(gr.GVK.GroupKind(), req.Namespace) {
+		// Another replica is responsible for this resource. If we were previously responsible, whichever replica has
+		// now claimed the shard will enqueue it, so there's no need to requeue it here.
+		if gr.Metrics != nil {
+			gr.Metrics.ForgetResource(gr.GVK.GroupKind(), req.NamespacedName)
+		}
+		return ctrl.Result{}, nil
+	}
+
 	metaObj, err := gr.getObjectToReconcile(ctx, req)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if metaObj == nil {
 		// This means that the resource doesn't exist
+		if gr.Metrics != nil {
+			gr.Metrics.ForgetResource(gr.GVK.GroupKind(), req.NamespacedName)
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -70,6 +102,7 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	// Ensure that we're always operating on a copy and not on the value returned from the client directly.
 	// This is important as it avoids us modifying the cached object.
 	metaObj = metaObj.DeepCopyObject().(genruntime.MetaObject)
+	defer gr.recordReadyCondition(originalObj, metaObj)
 
 	log := gr.LoggerFactory(metaObj).WithValues("name", req.Name, "namespace", req.Namespace)
 	reconcilers.LogObj(log, Verbose, "Reconcile invoked", metaObj)
@@ -92,7 +125,7 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	if err != nil {
 		err = gr.writeReadyConditionErrorOrDefault(ctx, log, metaObj, err)
-		result, err = gr.RequeueIntervalCalculator.NextInterval(req, result, err)
+		result, err = gr.nextInterval(req, result, err)
 		log.V(Verbose).Info("Encountered error, re-queuing...", "result", result)
 		return result, err
 	}
@@ -113,7 +146,7 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	// https://github.com/Azure/azure-service-operator/issues/2556).
 	// In order to cater to the above scenarios we calculate some intervals ourselves using this IntervalCalculator and pass others
 	// up to the controller-runtime RateLimiter.
-	result, err = gr.RequeueIntervalCalculator.NextInterval(req, result, nil)
+	result, err = gr.nextInterval(req, result, nil)
 	if err != nil {
 		// This isn't really going to happen but just do it defensively anyway
 		return result, err
@@ -138,6 +171,30 @@ func (gr *GenericReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	return result, nil
 }
 
+// nextInterval calculates when req should next be reconciled, recording why it is being requeued
+func (gr *GenericReconciler) nextInterval(req ctrl.Request, result ctrl.Result, err error) (ctrl.Result, error) {
+	reason := interval.ClassifyRequeue(result, err)
+	result, err = gr.RequeueIntervalCalculator.NextInterval(req, result, err)
+
+	isRequeueing := err != nil || result.Requeue || result.RequeueAfter > time.Duration(0)
+	if gr.Metrics != nil && isRequeueing && reason != interval.RequeueReasonNone {
+		gr.Metrics.RecordRequeue(gr.GVK.GroupKind(), req.Namespace, string(reason))
+	}
+
+	return result, err
+}
+
+// recordReadyCondition records the Ready condition of obj once reconciliation is complete
+func (gr *GenericReconciler) recordReadyCondition(original genruntime.MetaObject, obj genruntime.MetaObject) {
+	if gr.Metrics == nil {
+		return
+	}
+
+	previous, ok := conditions.GetCondition(original, conditions.ConditionTypeReady)
+	wasReady := ok && previous.Status == metav1.ConditionTrue
+	gr.Metrics.RecordReadyCondition(gr.GVK.GroupKind(), obj, wasReady)
+}
+
 func (gr *GenericReconciler) getObjectToReconcile(ctx context.Context, req ctrl.Request) (genruntime.MetaObject, error) {
 	obj, err := gr.KubeClient.GetObjectOrDefault(ctx, req.NamespacedName, gr.GVK)
 	if err != nil {
@@ -306,7 +363,7 @@ func (gr *GenericReconciler) takeOwnership(ctx context.Context, metaObj genrunti
 		// remove the annotation) but those operations are likely to
 		// be rare.
 		message := fmt.Sprintf("Operators in %q and %q are both configured to manage this resource", gr.Config.PodNamespace, reconcilerNamespace)
-		gr.Recorder.Event(metaObj, corev1.EventTypeWarning, "Overlap", message)
+		gr.Recorder.Event(metaObj, corev1.EventTypeWarning, events.ReasonOverlap, message)
 		return &ctrl.Result{}, nil
 	} else if reconcilerNamespace == "" && gr.Config.PodNamespace != "" {
 		genruntime.AddAnnotation(metaObj, NamespaceAnnotation, gr.Config.PodNamespace)
@@ -9,19 +9,30 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/benbjohnson/clock"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	"github.com/Azure/azure-service-operator/v2/internal/capacity"
 	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/events"
 	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/metrics"
+	"github.com/Azure/azure-service-operator/v2/internal/policy"
+	"github.com/Azure/azure-service-operator/v2/internal/sharding"
 	"github.com/Azure/azure-service-operator/v2/internal/util/interval"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
@@ -36,13 +47,20 @@ type Options struct {
 	RequeueIntervalCalculator interval.Calculator
 	Config                    config.Values
 	LoggerFactory             func(obj metav1.Object) logr.Logger
+	ResourceMetrics           *metrics.ResourceMetrics
+	ControllerMetrics         *metrics.ControllerMetrics
+	Sharding                  *sharding.Coordinator // If set, each replica only reconciles the resources in its shards
+	Policy                    *policy.Enforcer      // If set, resources are checked against ASOPolicies before being reconciled
+	Capacity                  *capacity.Checker     // If set, Azure is checked for capacity before resources are created or scaled
 }
 
-func RegisterWebhooks(mgr ctrl.Manager, objs []client.Object) error {
+// RegisterWebhooks registers the webhooks for the given types. If enforcer is not nil, the validating webhooks also
+// check resources against the ASOPolicies in the cluster.
+func RegisterWebhooks(mgr ctrl.Manager, objs []client.Object, enforcer *policy.Enforcer) error {
 	var errs []error
 
 	for _, obj := range objs {
-		if err := registerWebhook(mgr, obj); err != nil {
+		if err := registerWebhook(mgr, obj, enforcer); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -50,13 +68,19 @@ func RegisterWebhooks(mgr ctrl.Manager, objs []client.Object) error {
 	return kerrors.NewAggregate(errs)
 }
 
-func registerWebhook(mgr ctrl.Manager, obj client.Object) error {
+func registerWebhook(mgr ctrl.Manager, obj client.Object, enforcer *policy.Enforcer) error {
 	_, err := conversion.EnforcePtr(obj)
 	if err != nil {
 		return errors.Wrap(err, "obj was expected to be ptr but was not")
 	}
 
-	return ctrl.NewWebhookManagedBy(mgr).For(obj).Complete()
+	builder := ctrl.NewWebhookManagedBy(mgr).For(obj)
+	if _, ok := obj.(genruntime.MetaObject); ok && enforcer != nil {
+		// The policy validator runs the resource's own validations too
+		builder = builder.WithValidator(policy.NewWebhookValidator(enforcer))
+	}
+
+	return builder.Complete()
 }
 
 func RegisterAll(
@@ -69,12 +93,8 @@ func RegisterAll(
 
 	// pre-register any indexes we need
 	for _, obj := range objs {
-		for _, indexer := range obj.Indexes {
-			options.LogConstructor(nil).V(Info).Info("Registering indexer for type", "type", fmt.Sprintf("%T", obj.Obj), "key", indexer.Key)
-			err := fieldIndexer.IndexField(context.Background(), obj.Obj, indexer.Key, indexer.Func)
-			if err != nil {
-				return errors.Wrapf(err, "failed to register indexer for %T, Key: %q", obj.Obj, indexer.Key)
-			}
+		if err := registerIndexes(fieldIndexer, obj, options); err != nil {
+			return err
 		}
 	}
 
@@ -82,7 +102,7 @@ func RegisterAll(
 	for _, obj := range objs {
 		// TODO: Consider pulling some of the construction of things out of register (gvk, etc), so that we can pass in just
 		// TODO: the applicable extensions rather than a map of all of them
-		if err := register(mgr, kubeClient, positiveConditions, obj, options); err != nil {
+		if err := register(mgr, kubeClient, kubeClient, positiveConditions, obj, options); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -90,9 +110,24 @@ func RegisterAll(
 	return kerrors.NewAggregate(errs)
 }
 
+func registerIndexes(fieldIndexer client.FieldIndexer, info *registration.StorageType, options Options) error {
+	for _, indexer := range info.Indexes {
+		options.LogConstructor(nil).V(Info).Info("Registering indexer for type", "type", fmt.Sprintf("%T", info.Obj), "key", indexer.Key)
+		err := fieldIndexer.IndexField(context.Background(), info.Obj, indexer.Key, indexer.Func)
+		if err != nil {
+			return errors.Wrapf(err, "failed to register indexer for %T, Key: %q", info.Obj, indexer.Key)
+		}
+	}
+
+	return nil
+}
+
+// register starts the controller for the given storage type. Its watches use watchClient to find the resources affected
+// by a change to a watched object.
 func register(
 	mgr ctrl.Manager,
 	kubeClient kubeclient.Client,
+	watchClient client.Client,
 	positiveConditions *conditions.PositiveConditionBuilder,
 	info *registration.StorageType,
 	options Options) error {
@@ -113,7 +148,11 @@ func register(
 
 		return result.WithName(info.Name)
 	}
-	eventRecorder := mgr.GetEventRecorderFor(info.Name)
+	// Resources that fail repeatedly would otherwise emit an event on every attempt
+	eventRecorder := events.NewAggregatingRecorder(
+		mgr.GetEventRecorderFor(info.Name),
+		clock.New(),
+		events.DefaultAggregationWindow)
 
 	options.LogConstructor(nil).V(Status).Info("Registering", "GVK", gvk)
 
@@ -126,6 +165,11 @@ func register(
 		GVK:                       gvk,
 		PositiveConditions:        positiveConditions,
 		RequeueIntervalCalculator: options.RequeueIntervalCalculator,
+		Metrics:                   options.ResourceMetrics,
+	}
+
+	if options.Sharding != nil {
+		reconciler.Shards = options.Sharding
 	}
 
 	builder := ctrl.NewControllerManagedBy(mgr).
@@ -133,7 +177,14 @@ func register(
 		WithOptions(options.Options)
 
 	for _, watch := range info.Watches {
-		builder = builder.Watches(watch.Type, watch.MakeEventHandler(kubeClient, options.LogConstructor(nil).WithName(info.Name)))
+		builder = builder.Watches(watch.Type, watch.MakeEventHandler(watchClient, options.LogConstructor(nil).WithName(info.Name)))
+	}
+
+	if options.Sharding != nil {
+		// When we claim additional shards, enqueue the resources they contain
+		builder = builder.WatchesRawSource(
+			&source.Channel{Source: options.Sharding.Subscribe()},
+			handler.EnqueueRequestsFromMapFunc(makeShardedResourceMapper(kubeClient, gvk, options.Sharding, options.LogConstructor(nil).WithName(info.Name))))
 	}
 
 	err = builder.Complete(reconciler)
@@ -143,3 +194,53 @@ func register(
 
 	return nil
 }
+
+// makeShardedResourceMapper returns a handler.MapFunc that lists all resources of the given GVK owned by this replica
+func makeShardedResourceMapper(
+	kubeClient kubeclient.Client,
+	gvk schema.GroupVersionKind,
+	shards sharding.Filter,
+	log logr.Logger,
+) handler.MapFunc {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	return func(ctx context.Context, _ client.Object) []reconcile.Request {
+		obj, err := kubeClient.Scheme().New(listGVK)
+		if err != nil {
+			log.Error(err, "Unable to create list for sharded resources", "gvk", listGVK)
+			return nil
+		}
+
+		list, ok := obj.(client.ObjectList)
+		if !ok {
+			log.Error(errors.Errorf("type %T is not a client.ObjectList", obj), "Unable to list sharded resources", "gvk", listGVK)
+			return nil
+		}
+
+		err = kubeClient.List(ctx, list)
+		if err != nil {
+			log.Error(err, "Unable to list sharded resources", "gvk", gvk)
+			return nil
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			log.Error(err, "Unable to extract sharded resources", "gvk", gvk)
+			return nil
+		}
+
+		var result []reconcile.Request
+		for _, item := range items {
+			itemObj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+
+			if shards.Owns(gvk.GroupKind(), itemObj.GetNamespace()) {
+				result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(itemObj)})
+			}
+		}
+
+		log.V(Verbose).Info("Enqueuing resources in newly claimed shards", "gvk", gvk, "count", len(result))
+		return result
+	}
+}
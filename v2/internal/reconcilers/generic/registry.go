@@ -0,0 +1,144 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+// Registry starts controllers for storage types on demand. Unlike RegisterAll, controllers may be started after the
+// manager has started, allowing the operator to begin reconciling a kind once its CRD has been installed.
+type Registry struct {
+	mgr                ctrl.Manager
+	fieldIndexer       client.FieldIndexer
+	kubeClient         kubeclient.Client
+	positiveConditions *conditions.PositiveConditionBuilder
+	options            Options
+
+	versions map[schema.GroupKind]string // Storage version of each kind
+
+	lock    sync.Mutex
+	pending map[schema.GroupKind]*registration.StorageType // Storage types without a controller
+	active  set.Set[schema.GroupKind]                      // Storage types with a controller
+}
+
+// NewRegistry creates a new Registry for the given storage types; no controllers are started until Register is called
+func NewRegistry(
+	mgr ctrl.Manager,
+	fieldIndexer client.FieldIndexer,
+	kubeClient kubeclient.Client,
+	positiveConditions *conditions.PositiveConditionBuilder,
+	objs []*registration.StorageType,
+	options Options,
+) (*Registry, error) {
+	pending := make(map[schema.GroupKind]*registration.StorageType, len(objs))
+	versions := make(map[schema.GroupKind]string, len(objs))
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj.Obj, mgr.GetScheme())
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating GVK for obj %T", obj.Obj)
+		}
+
+		pending[gvk.GroupKind()] = obj
+		versions[gvk.GroupKind()] = gvk.Version
+	}
+
+	return &Registry{
+		mgr:                mgr,
+		fieldIndexer:       fieldIndexer,
+		kubeClient:         kubeClient,
+		positiveConditions: positiveConditions,
+		options:            options,
+		versions:           versions,
+		pending:            pending,
+		active:             set.Make[schema.GroupKind](),
+	}, nil
+}
+
+// Register starts the controllers for the given kinds, returning the kinds for which controllers were newly started.
+// Kinds that already have a controller, or that don't have a storage type, are ignored.
+// Register must be called before the manager is started; use RegisterAfterStart once it's running.
+func (r *Registry) Register(kinds ...schema.GroupKind) ([]schema.GroupKind, error) {
+	return r.register(kinds, true)
+}
+
+// RegisterAfterStart is like Register, but for use once the manager has started. Indexes can't be added to the cache
+// once it's running, so the watches of these kinds list resources without indexes and filter them instead.
+func (r *Registry) RegisterAfterStart(kinds ...schema.GroupKind) ([]schema.GroupKind, error) {
+	return r.register(kinds, false)
+}
+
+func (r *Registry) register(kinds []schema.GroupKind, indexed bool) ([]schema.GroupKind, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var result []schema.GroupKind
+	for _, gk := range kinds {
+		info, ok := r.pending[gk]
+		if !ok {
+			continue
+		}
+
+		err := r.registerController(info, indexed)
+		if err != nil {
+			if r.options.ControllerMetrics != nil {
+				r.options.ControllerMetrics.RecordControllerFailed(gk)
+			}
+
+			return result, errors.Wrapf(err, "failed to register controller for %s", gk)
+		}
+
+		delete(r.pending, gk)
+		r.active.Add(gk)
+		result = append(result, gk)
+
+		if r.options.ControllerMetrics != nil {
+			r.options.ControllerMetrics.RecordControllerStarted(gk)
+		}
+	}
+
+	return result, nil
+}
+
+// StorageVersion returns the version of the storage type for the given kind, if it is known to the registry.
+// A controller can only be started once the CRD for the kind serves this version.
+func (r *Registry) StorageVersion(gk schema.GroupKind) (string, bool) {
+	version, ok := r.versions[gk]
+	return version, ok
+}
+
+func (r *Registry) registerController(info *registration.StorageType, indexed bool) error {
+	if !indexed {
+		watchClient := newUnindexedClient(r.kubeClient, info.Indexes)
+		return register(r.mgr, r.kubeClient, watchClient, r.positiveConditions, info, r.options)
+	}
+
+	err := registerIndexes(r.fieldIndexer, info, r.options)
+	if err != nil {
+		return err
+	}
+
+	return register(r.mgr, r.kubeClient, r.kubeClient, r.positiveConditions, info, r.options)
+}
+
+// IsActive returns true if a controller has been started for the given kind
+func (r *Registry) IsActive(gk schema.GroupKind) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.active.Contains(gk)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601/storage"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+var resourceGroupKind = schema.GroupKind{Group: "resources.azure.com", Kind: "ResourceGroup"}
+
+// startedFieldIndexer behaves like the cache of a running manager, which can't have indexes added to it
+type startedFieldIndexer struct {
+	started bool
+	keys    []string
+}
+
+var _ client.FieldIndexer = &startedFieldIndexer{}
+
+func (i *startedFieldIndexer) IndexField(_ context.Context, _ client.Object, field string, _ client.IndexerFunc) error {
+	if i.started {
+		return errors.New("informer has already started")
+	}
+
+	i.keys = append(i.keys, field)
+	return nil
+}
+
+func newTestScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(resources.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func newIndexedStorageType() *registration.StorageType {
+	return &registration.StorageType{
+		Obj:  new(resources.ResourceGroup),
+		Name: "ResourceGroupController",
+		Indexes: []registration.Index{
+			{
+				Key:  ".spec.azureName",
+				Func: indexAzureName,
+			},
+		},
+		Watches: []registration.Watch{
+			{
+				Type: &corev1.Secret{},
+				MakeEventHandler: func(_ client.Client, _ logr.Logger) handler.EventHandler {
+					return handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request { return nil })
+				},
+			},
+		},
+	}
+}
+
+func indexAzureName(obj client.Object) []string {
+	rg, ok := obj.(*resources.ResourceGroup)
+	if !ok {
+		return nil
+	}
+
+	return []string{rg.Spec.AzureName}
+}
+
+func newTestRegistry(g *WithT, indexer client.FieldIndexer) *Registry {
+	scheme := newTestScheme(g)
+
+	// The manager is never started, so doesn't need a real API server
+	mgr, err := ctrl.NewManager(
+		&rest.Config{Host: "https://127.0.0.1:6443"},
+		ctrl.Options{
+			Scheme:  scheme,
+			Metrics: metricsserver.Options{BindAddress: "0"},
+		})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kubeClient := kubeclient.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build())
+	registry, err := NewRegistry(
+		mgr,
+		indexer,
+		kubeClient,
+		nil,
+		[]*registration.StorageType{newIndexedStorageType()},
+		Options{
+			Options: controller.Options{
+				LogConstructor: func(*reconcile.Request) logr.Logger { return logr.Discard() },
+			},
+		})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return registry
+}
+
+func TestRegistry_Register_RegistersIndexes(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	indexer := &startedFieldIndexer{}
+	registry := newTestRegistry(g, indexer)
+
+	started, err := registry.Register(resourceGroupKind)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(started).To(ConsistOf(resourceGroupKind))
+	g.Expect(indexer.keys).To(ConsistOf(".spec.azureName"))
+	g.Expect(registry.IsActive(resourceGroupKind)).To(BeTrue())
+}
+
+func TestRegistry_RegisterAfterStart_KindWithIndexes_StartsControllerWithoutIndexes(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	indexer := &startedFieldIndexer{started: true}
+	registry := newTestRegistry(g, indexer)
+
+	started, err := registry.RegisterAfterStart(resourceGroupKind)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(started).To(ConsistOf(resourceGroupKind))
+	g.Expect(indexer.keys).To(BeEmpty())
+	g.Expect(registry.IsActive(resourceGroupKind)).To(BeTrue())
+
+	// Registering again does nothing
+	started, err = registry.RegisterAfterStart(resourceGroupKind)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(started).To(BeEmpty())
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+// unindexedClient is a client for listing resources by fields that haven't been indexed in the cache. Indexes can't
+// be added once the cache has started, so it's used by the watches of kinds whose controllers are started later.
+// Lists selecting on one of the given indexes instead list every resource in the namespace, then filter them using
+// the index functions.
+type unindexedClient struct {
+	client.Client
+	indexes map[string]registration.Index // Indexes by key
+}
+
+var _ client.Client = &unindexedClient{}
+
+func newUnindexedClient(kubeClient client.Client, indexes []registration.Index) *unindexedClient {
+	byKey := make(map[string]registration.Index, len(indexes))
+	for _, index := range indexes {
+		byKey[index.Key] = index
+	}
+
+	return &unindexedClient{
+		Client:  kubeClient,
+		indexes: byKey,
+	}
+}
+
+// List lists resources, filtering them with the index functions if any of our indexes are selected on
+func (c *unindexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOptions := &client.ListOptions{}
+	listOptions.ApplyOptions(opts)
+
+	if listOptions.FieldSelector == nil || listOptions.FieldSelector.Empty() {
+		return c.Client.List(ctx, list, opts...)
+	}
+
+	// We can only handle exact matches on our own indexes; anything else goes to the underlying client unchanged
+	filters := make(map[string]string)
+	for _, requirement := range listOptions.FieldSelector.Requirements() {
+		_, ok := c.indexes[requirement.Field]
+		if !ok || (requirement.Operator != selection.Equals && requirement.Operator != selection.DoubleEquals) {
+			return c.Client.List(ctx, list, opts...)
+		}
+
+		filters[requirement.Field] = requirement.Value
+	}
+
+	listOptions.FieldSelector = nil
+	err := c.Client.List(ctx, list, listOptions)
+	if err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return errors.Wrapf(err, "extracting items from %T", list)
+	}
+
+	matches := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if ok && c.matches(obj, filters) {
+			matches = append(matches, item)
+		}
+	}
+
+	return meta.SetList(list, matches)
+}
+
+// matches returns true if the index functions return the expected value for each of the filters
+func (c *unindexedClient) matches(obj client.Object, filters map[string]string) bool {
+	for key, value := range filters {
+		found := false
+		for _, v := range c.indexes[key].Func(obj) {
+			if v == value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,56 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package generic
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601/storage"
+)
+
+func newResourceGroup(namespace string, name string, azureName string) *resources.ResourceGroup {
+	return &resources.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: resources.ResourceGroup_Spec{
+			AzureName: azureName,
+		},
+	}
+}
+
+func TestUnindexedClient_List_FiltersUsingIndexFunctions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(newTestScheme(g)).
+		WithObjects(
+			newResourceGroup("team-a", "first", "shared"),
+			newResourceGroup("team-a", "second", "other"),
+			newResourceGroup("team-b", "third", "shared")).
+		Build()
+	unindexed := newUnindexedClient(kubeClient, newIndexedStorageType().Indexes)
+
+	var list resources.ResourceGroupList
+	err := unindexed.List(ctx, &list, client.MatchingFields{".spec.azureName": "shared"}, client.InNamespace("team-a"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(list.Items).To(HaveLen(1))
+	g.Expect(list.Items[0].Name).To(Equal("first"))
+
+	// Lists without a field selector are passed through unchanged
+	err = unindexed.List(ctx, &list, client.InNamespace("team-a"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(list.Items).To(HaveLen(2))
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/Azure/azure-service-operator/v2/internal/reflecthelpers"
 	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/core"
@@ -341,26 +342,53 @@ func (r *Resolver) ResolveResourceConfigMapReferences(ctx context.Context, metaO
 // ResolveAll resolves every reference on the provided genruntime.ARMMetaObject.
 // This includes: owner, all resource references, and all secrets.
 func (r *Resolver) ResolveAll(ctx context.Context, metaObject genruntime.ARMMetaObject) (ResourceHierarchy, genruntime.ConvertToARMResolvedDetails, error) {
+	ctx, span := tracing.StartSpan(ctx, "Resolver.ResolveAll", tracing.ObjectAttributes(metaObject)...)
+	resourceHierarchy, resolvedDetails, err := r.resolveAll(ctx, metaObject)
+	tracing.EndSpan(span, err)
+	return resourceHierarchy, resolvedDetails, err
+}
+
+func (r *Resolver) resolveAll(ctx context.Context, metaObject genruntime.ARMMetaObject) (ResourceHierarchy, genruntime.ConvertToARMResolvedDetails, error) {
 	// Resolve the resource hierarchy (owner)
-	resourceHierarchy, err := r.ResolveResourceHierarchy(ctx, metaObject)
+	resourceHierarchy, err := tracing.Trace(
+		ctx,
+		"Resolver.ResolveResourceHierarchy",
+		func(ctx context.Context) (ResourceHierarchy, error) {
+			return r.ResolveResourceHierarchy(ctx, metaObject)
+		})
 	if err != nil {
 		return nil, genruntime.ConvertToARMResolvedDetails{}, err
 	}
 
 	// Resolve all ARM ID references
-	resolvedRefs, err := r.ResolveResourceReferences(ctx, metaObject)
+	resolvedRefs, err := tracing.Trace(
+		ctx,
+		"Resolver.ResolveResourceReferences",
+		func(ctx context.Context) (genruntime.Resolved[genruntime.ResourceReference], error) {
+			return r.ResolveResourceReferences(ctx, metaObject)
+		})
 	if err != nil {
 		return nil, genruntime.ConvertToARMResolvedDetails{}, err
 	}
 
 	// Resolve all secrets
-	resolvedSecrets, err := r.ResolveResourceSecretReferences(ctx, metaObject)
+	resolvedSecrets, err := tracing.Trace(
+		ctx,
+		"Resolver.ResolveResourceSecretReferences",
+		func(ctx context.Context) (genruntime.Resolved[genruntime.SecretReference], error) {
+			return r.ResolveResourceSecretReferences(ctx, metaObject)
+		})
 	if err != nil {
 		return nil, genruntime.ConvertToARMResolvedDetails{}, err
 	}
 
 	// Resolve all configmaps
-	resolvedConfigMaps, err := r.ResolveResourceConfigMapReferences(ctx, metaObject)
+	resolvedConfigMaps, err := tracing.Trace(
+		ctx,
+		"Resolver.ResolveResourceConfigMapReferences",
+		func(ctx context.Context) (genruntime.Resolved[genruntime.ConfigMapReference], error) {
+			return r.ResolveResourceConfigMapReferences(ctx, metaObject)
+		})
 	if err != nil {
 		return nil, genruntime.ConvertToARMResolvedDetails{}, err
 	}
@@ -0,0 +1,458 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	. "github.com/Azure/azure-service-operator/v2/internal/logging"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+)
+
+// MemberLabel is the label placed on the Leases used to track the replicas taking part in sharding.
+// Its value is the LeasePrefix in use, allowing multiple independent operators to share a namespace.
+const MemberLabel = "serviceoperator.azure.com/shard-member"
+
+const (
+	defaultLeasePrefix   = "aso-shard"
+	defaultLeaseDuration = 30 * time.Second
+	defaultRenewInterval = 10 * time.Second
+	releaseTimeout       = 10 * time.Second
+)
+
+// Options configures a Coordinator
+type Options struct {
+	// Namespace is where the Leases are created; usually the namespace of the operator.
+	Namespace string
+	// Identity uniquely identifies this replica; usually the name of the pod.
+	Identity string
+	// ShardCount is the number of shards resources are divided into.
+	ShardCount int
+	// Strategy determines which shard each resource belongs to.
+	Strategy Strategy
+	// LeasePrefix is used to name the Leases. Defaults to "aso-shard".
+	LeasePrefix string
+	// LeaseDuration is how long a Lease is held without being renewed. Defaults to 30s.
+	LeaseDuration time.Duration
+	// RenewInterval is how often Leases are renewed, and shards rebalanced. Defaults to 10s.
+	RenewInterval time.Duration
+	// Clock is used to track time. Defaults to the system clock.
+	Clock clock.Clock
+	// Log is used to report changes in shard ownership.
+	Log logr.Logger
+}
+
+// Coordinator divides resources between replicas of the operator, using a Lease per shard.
+//
+// Each replica maintains a member Lease to announce it is alive. Shards are assigned round-robin across the live
+// members (sorted by identity) and each replica claims the Leases of the shards assigned to it, releasing any it no
+// longer needs. When membership changes, shards are rebalanced: a replica gives up a shard before another claims it,
+// and a Lease held by a replica that has died is claimed once it expires.
+type Coordinator struct {
+	kubeClient client.Client
+	options    Options
+
+	lock        sync.RWMutex
+	owned       map[int]time.Time // Shards we hold, and when each Lease was last renewed
+	subscribers []chan event.GenericEvent
+}
+
+var (
+	_ Filter                         = &Coordinator{}
+	_ manager.Runnable               = &Coordinator{}
+	_ manager.LeaderElectionRunnable = &Coordinator{}
+)
+
+// NewCoordinator creates a new Coordinator. kubeClient should read directly from the API server, not from a cache.
+func NewCoordinator(kubeClient client.Client, options Options) (*Coordinator, error) {
+	if options.Namespace == "" {
+		return nil, errors.New("sharding requires a namespace for Leases")
+	}
+
+	if options.Identity == "" {
+		return nil, errors.New("sharding requires an identity for this replica")
+	}
+
+	if options.ShardCount < 1 {
+		return nil, errors.Errorf("shard count must be at least 1, but was %d", options.ShardCount)
+	}
+
+	if options.Strategy == "" {
+		options.Strategy = StrategyNamespace
+	}
+
+	if options.LeasePrefix == "" {
+		options.LeasePrefix = defaultLeasePrefix
+	}
+
+	if options.LeaseDuration == 0 {
+		options.LeaseDuration = defaultLeaseDuration
+	}
+
+	if options.RenewInterval == 0 {
+		options.RenewInterval = defaultRenewInterval
+	}
+
+	if options.RenewInterval >= options.LeaseDuration {
+		return nil, errors.Errorf(
+			"lease renew interval (%s) must be less than the lease duration (%s)",
+			options.RenewInterval,
+			options.LeaseDuration)
+	}
+
+	if options.Clock == nil {
+		options.Clock = clock.New()
+	}
+
+	return &Coordinator{
+		kubeClient: kubeClient,
+		options:    options,
+		owned:      make(map[int]time.Time),
+	}, nil
+}
+
+// Owns returns true if this replica currently holds the shard for resources of the given kind in the given namespace
+func (c *Coordinator) Owns(gk schema.GroupKind, namespace string) bool {
+	shard := ShardOf(c.options.Strategy, c.options.ShardCount, gk, namespace)
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	// If we've been unable to renew the Lease, another replica may have claimed it
+	renewed, ok := c.owned[shard]
+	return ok && c.options.Clock.Since(renewed) < c.options.LeaseDuration
+}
+
+// OwnedShards returns the shards currently held by this replica, in order
+func (c *Coordinator) OwnedShards() []int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	result := make([]int, 0, len(c.owned))
+	for shard := range c.owned {
+		result = append(result, shard)
+	}
+
+	sort.Ints(result)
+	return result
+}
+
+// Subscribe returns a channel that receives an event whenever this replica claims additional shards, allowing
+// controllers to enqueue the resources they now own.
+func (c *Coordinator) Subscribe() <-chan event.GenericEvent {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Buffer a single event; as each event triggers a full resync, any further events can be dropped
+	result := make(chan event.GenericEvent, 1)
+	c.subscribers = append(c.subscribers, result)
+	return result
+}
+
+// NeedLeaderElection returns false, as every replica takes part in sharding
+func (c *Coordinator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start synchronizes Leases every RenewInterval until ctx is cancelled, then releases all Leases held so that other
+// replicas can promptly take over.
+func (c *Coordinator) Start(ctx context.Context) error {
+	ticker := c.options.Clock.Ticker(c.options.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		err := c.Sync(ctx)
+		if err != nil {
+			c.options.Log.Error(err, "Failed to synchronize shard leases")
+		}
+
+		select {
+		case <-ctx.Done():
+			// ctx is already cancelled, so we need a fresh one to release our leases
+			releaseCtx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+			defer cancel()
+			return c.Release(releaseCtx)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync renews the membership of this replica, then claims or releases shards to match the current membership
+func (c *Coordinator) Sync(ctx context.Context) error {
+	now := c.options.Clock.Now()
+	err := c.renewMembership(ctx, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to renew shard membership")
+	}
+
+	members, err := c.listMembers(ctx, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to list shard members")
+	}
+
+	var errs []error
+	var acquired []int
+	var released []int
+	for shard := 0; shard < c.options.ShardCount; shard++ {
+		wanted := members[shard%len(members)] == c.options.Identity
+		held, err := c.syncShard(ctx, shard, wanted, now)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if c.updateOwnership(shard, held, now) {
+			if held {
+				acquired = append(acquired, shard)
+			} else {
+				released = append(released, shard)
+			}
+		}
+	}
+
+	if len(acquired) > 0 || len(released) > 0 {
+		c.options.Log.V(Status).Info(
+			"Shard ownership changed",
+			"members", members,
+			"acquired", acquired,
+			"released", released,
+			"owned", c.OwnedShards())
+	}
+
+	if len(acquired) > 0 {
+		c.notifySubscribers()
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+// Release gives up all shards held by this replica, and removes it from the membership
+func (c *Coordinator) Release(ctx context.Context) error {
+	var errs []error
+	for _, shard := range c.OwnedShards() {
+		c.updateOwnership(shard, false, time.Time{})
+
+		lease, err := c.getLease(ctx, c.shardLeaseName(shard))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if lease != nil && to.Value(lease.Spec.HolderIdentity) == c.options.Identity {
+			lease.Spec.HolderIdentity = nil
+			errs = append(errs, c.kubeClient.Update(ctx, lease))
+		}
+	}
+
+	member := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.options.Namespace,
+			Name:      c.memberLeaseName(),
+		},
+	}
+	errs = append(errs, client.IgnoreNotFound(c.kubeClient.Delete(ctx, member)))
+
+	return kerrors.NewAggregate(errs)
+}
+
+// syncShard claims, renews, or releases the Lease for a shard, returning true if we hold it
+func (c *Coordinator) syncShard(ctx context.Context, shard int, wanted bool, now time.Time) (bool, error) {
+	name := c.shardLeaseName(shard)
+	lease, err := c.getLease(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	if lease == nil {
+		if !wanted {
+			return false, nil
+		}
+
+		lease = c.newLease(name, nil)
+		c.holdLease(lease, now)
+		err = c.kubeClient.Create(ctx, lease)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to claim shard %d", shard)
+		}
+
+		return true, nil
+	}
+
+	holder := to.Value(lease.Spec.HolderIdentity)
+	if !wanted {
+		if holder != c.options.Identity {
+			return false, nil
+		}
+
+		// Release the lease so the replica that wants it can claim it without waiting for it to expire
+		lease.Spec.HolderIdentity = nil
+		err = c.kubeClient.Update(ctx, lease)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to release shard %d", shard)
+		}
+
+		return false, nil
+	}
+
+	if holder != "" && holder != c.options.Identity && !c.isExpired(lease, now) {
+		// Still held by another replica, which will release it once it sees the new membership
+		return false, nil
+	}
+
+	c.holdLease(lease, now)
+	err = c.kubeClient.Update(ctx, lease)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to claim shard %d", shard)
+	}
+
+	return true, nil
+}
+
+// updateOwnership records whether we hold a shard, returning true if this is a change
+func (c *Coordinator) updateOwnership(shard int, held bool, renewed time.Time) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, wasHeld := c.owned[shard]
+	if held {
+		c.owned[shard] = renewed
+	} else {
+		delete(c.owned, shard)
+	}
+
+	return held != wasHeld
+}
+
+func (c *Coordinator) notifySubscribers() {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	evt := event.GenericEvent{
+		Object: c.newLease(c.memberLeaseName(), nil),
+	}
+
+	for _, subscriber := range c.subscribers {
+		select {
+		case subscriber <- evt:
+		default:
+			// A resync is already pending
+		}
+	}
+}
+
+// renewMembership creates or renews the member Lease for this replica
+func (c *Coordinator) renewMembership(ctx context.Context, now time.Time) error {
+	lease, err := c.getLease(ctx, c.memberLeaseName())
+	if err != nil {
+		return err
+	}
+
+	if lease == nil {
+		lease = c.newLease(c.memberLeaseName(), map[string]string{MemberLabel: c.options.LeasePrefix})
+		c.holdLease(lease, now)
+		return c.kubeClient.Create(ctx, lease)
+	}
+
+	c.holdLease(lease, now)
+	return c.kubeClient.Update(ctx, lease)
+}
+
+// listMembers returns the identities of all live members, sorted. This replica is always included.
+func (c *Coordinator) listMembers(ctx context.Context, now time.Time) ([]string, error) {
+	var leases coordinationv1.LeaseList
+	err := c.kubeClient.List(
+		ctx,
+		&leases,
+		client.InNamespace(c.options.Namespace),
+		client.MatchingLabels{MemberLabel: c.options.LeasePrefix})
+	if err != nil {
+		return nil, err
+	}
+
+	members := []string{c.options.Identity}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		holder := to.Value(lease.Spec.HolderIdentity)
+		if holder == "" || holder == c.options.Identity || c.isExpired(lease, now) {
+			continue
+		}
+
+		members = append(members, holder)
+	}
+
+	sort.Strings(members)
+	return members, nil
+}
+
+// getLease returns the named Lease, or nil if it doesn't exist
+func (c *Coordinator) getLease(ctx context.Context, name string) (*coordinationv1.Lease, error) {
+	var lease coordinationv1.Lease
+	err := c.kubeClient.Get(ctx, types.NamespacedName{Namespace: c.options.Namespace, Name: name}, &lease)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get lease %s", name)
+	}
+
+	return &lease, nil
+}
+
+func (c *Coordinator) newLease(name string, labels map[string]string) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.options.Namespace,
+			Name:      name,
+			Labels:    labels,
+		},
+	}
+}
+
+// holdLease updates lease to show it is held by this replica
+func (c *Coordinator) holdLease(lease *coordinationv1.Lease, now time.Time) {
+	if to.Value(lease.Spec.HolderIdentity) != c.options.Identity {
+		lease.Spec.HolderIdentity = to.Ptr(c.options.Identity)
+		lease.Spec.AcquireTime = to.Ptr(metav1.NewMicroTime(now))
+		lease.Spec.LeaseTransitions = to.Ptr(to.Value(lease.Spec.LeaseTransitions) + 1)
+	}
+
+	lease.Spec.RenewTime = to.Ptr(metav1.NewMicroTime(now))
+	lease.Spec.LeaseDurationSeconds = to.Ptr(int32(c.options.LeaseDuration.Seconds()))
+}
+
+func (c *Coordinator) isExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
+
+func (c *Coordinator) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-%d", c.options.LeasePrefix, shard)
+}
+
+func (c *Coordinator) memberLeaseName() string {
+	return fmt.Sprintf("%s-member-%s", c.options.LeasePrefix, c.options.Identity)
+}
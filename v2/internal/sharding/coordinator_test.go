@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testShardCount = 4
+
+func newFakeClient() client.Client {
+	scheme := runtime.NewScheme()
+	_ = coordinationv1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).Build()
+}
+
+func newTestCoordinator(g *WithT, kubeClient client.Client, clk clock.Clock, identity string) *Coordinator {
+	coordinator, err := NewCoordinator(
+		kubeClient,
+		Options{
+			Namespace:  "azureserviceoperator-system",
+			Identity:   identity,
+			ShardCount: testShardCount,
+			Clock:      clk,
+		})
+	g.Expect(err).ToNot(HaveOccurred())
+	return coordinator
+}
+
+func TestNewCoordinator_WhenOptionsInvalid_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		options     Options
+		expectedErr string
+	}{
+		"No namespace": {
+			options:     Options{Identity: "pod-a", ShardCount: 2},
+			expectedErr: "namespace",
+		},
+		"No identity": {
+			options:     Options{Namespace: "ns", ShardCount: 2},
+			expectedErr: "identity",
+		},
+		"No shards": {
+			options:     Options{Namespace: "ns", Identity: "pod-a"},
+			expectedErr: "shard count must be at least 1",
+		},
+		"Renew too slow": {
+			options:     Options{Namespace: "ns", Identity: "pod-a", ShardCount: 2, RenewInterval: time.Minute},
+			expectedErr: "must be less than the lease duration",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := NewCoordinator(newFakeClient(), c.options)
+			g.Expect(err).To(MatchError(ContainSubstring(c.expectedErr)))
+		})
+	}
+}
+
+func TestCoordinator_SingleReplica_OwnsAllShards(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	clk := clock.NewMock()
+	coordinator := newTestCoordinator(g, newFakeClient(), clk, "pod-a")
+	events := coordinator.Subscribe()
+
+	g.Expect(coordinator.Sync(ctx)).To(Succeed())
+	g.Expect(coordinator.OwnedShards()).To(Equal([]int{0, 1, 2, 3}))
+	g.Expect(coordinator.Owns(resourceGroupKind, "default")).To(BeTrue())
+	g.Expect(events).To(Receive())
+
+	// Renewal doesn't trigger another resync
+	g.Expect(coordinator.Sync(ctx)).To(Succeed())
+	g.Expect(events).ToNot(Receive())
+}
+
+func TestCoordinator_WhenReplicaJoins_RebalancesShards(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	kubeClient := newFakeClient()
+	clk := clock.NewMock()
+	a := newTestCoordinator(g, kubeClient, clk, "pod-a")
+	b := newTestCoordinator(g, kubeClient, clk, "pod-b")
+
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(a.OwnedShards()).To(HaveLen(testShardCount))
+
+	// b joins, but can't claim anything until a releases the shards it no longer wants
+	g.Expect(b.Sync(ctx)).To(Succeed())
+	g.Expect(b.OwnedShards()).To(BeEmpty())
+
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(a.OwnedShards()).To(Equal([]int{0, 2}))
+
+	g.Expect(b.Sync(ctx)).To(Succeed())
+	g.Expect(b.OwnedShards()).To(Equal([]int{1, 3}))
+
+	// Every resource is owned by exactly one replica
+	for _, ns := range []string{"default", "team-a", "team-b", "team-c", "team-d"} {
+		g.Expect(a.Owns(resourceGroupKind, ns)).ToNot(Equal(b.Owns(resourceGroupKind, ns)), ns)
+	}
+}
+
+func TestCoordinator_WhenReplicaDies_SurvivorClaimsShardsAfterExpiry(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	kubeClient := newFakeClient()
+	clk := clock.NewMock()
+	a := newTestCoordinator(g, kubeClient, clk, "pod-a")
+	b := newTestCoordinator(g, kubeClient, clk, "pod-b")
+
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(b.Sync(ctx)).To(Succeed())
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(b.Sync(ctx)).To(Succeed())
+	g.Expect(b.OwnedShards()).To(Equal([]int{1, 3}))
+
+	// b stops renewing; once its leases expire a takes over
+	clk.Add(time.Minute)
+	g.Expect(b.Owns(resourceGroupKind, "default")).To(BeFalse())
+
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(a.OwnedShards()).To(Equal([]int{0, 1, 2, 3}))
+}
+
+func TestCoordinator_Release_AllowsOtherReplicaToClaimImmediately(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	kubeClient := newFakeClient()
+	clk := clock.NewMock()
+	a := newTestCoordinator(g, kubeClient, clk, "pod-a")
+	b := newTestCoordinator(g, kubeClient, clk, "pod-b")
+
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(b.Sync(ctx)).To(Succeed())
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(b.Sync(ctx)).To(Succeed())
+
+	g.Expect(b.Release(ctx)).To(Succeed())
+	g.Expect(b.OwnedShards()).To(BeEmpty())
+
+	g.Expect(a.Sync(ctx)).To(Succeed())
+	g.Expect(a.OwnedShards()).To(Equal([]int{0, 1, 2, 3}))
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package sharding
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Strategy determines how resources are divided between shards
+type Strategy string
+
+const (
+	// StrategyNamespace puts all resources in the same namespace into the same shard
+	StrategyNamespace = Strategy("namespace")
+	// StrategyGroupKind puts all resources of the same group and kind into the same shard
+	StrategyGroupKind = Strategy("groupkind")
+)
+
+// ParseStrategy parses the name of a Strategy, returning an error if it is not recognized
+func ParseStrategy(name string) (Strategy, error) {
+	switch strategy := Strategy(strings.ToLower(name)); strategy {
+	case StrategyNamespace, StrategyGroupKind:
+		return strategy, nil
+	default:
+		return "", errors.Errorf("unknown sharding strategy %q, expected %q or %q", name, StrategyNamespace, StrategyGroupKind)
+	}
+}
+
+// Filter identifies whether this replica of the operator is responsible for a resource
+type Filter interface {
+	// Owns returns true if this replica should reconcile resources of the given kind in the given namespace
+	Owns(gk schema.GroupKind, namespace string) bool
+}
+
+// ShardOf returns the shard (from 0 to shardCount-1) that a resource of the given kind in the given namespace belongs to.
+// Shards are stable: the same inputs always return the same shard.
+func ShardOf(strategy Strategy, shardCount int, gk schema.GroupKind, namespace string) int {
+	if shardCount <= 1 {
+		return 0
+	}
+
+	key := namespace
+	if strategy == StrategyGroupKind {
+		key = gk.String()
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key)) // Writing to a hash never fails
+	return int(hash.Sum32() % uint32(shardCount))
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package sharding
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	resourceGroupKind  = schema.GroupKind{Group: "resources.azure.com", Kind: "ResourceGroup"}
+	storageAccountKind = schema.GroupKind{Group: "storage.azure.com", Kind: "StorageAccount"}
+)
+
+func TestParseStrategy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		name        string
+		expected    Strategy
+		expectedErr string
+	}{
+		"Namespace":        {name: "namespace", expected: StrategyNamespace},
+		"GroupKind":        {name: "groupkind", expected: StrategyGroupKind},
+		"Case insensitive": {name: "GroupKind", expected: StrategyGroupKind},
+		"Unknown":          {name: "random", expectedErr: "unknown sharding strategy \"random\""},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			strategy, err := ParseStrategy(c.name)
+			if c.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(c.expectedErr)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(strategy).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestShardOf_ByNamespace_IgnoresKind(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	for _, ns := range []string{"default", "team-a", "team-b", "production"} {
+		shard := ShardOf(StrategyNamespace, 7, resourceGroupKind, ns)
+		g.Expect(shard).To(BeNumerically(">=", 0))
+		g.Expect(shard).To(BeNumerically("<", 7))
+		g.Expect(ShardOf(StrategyNamespace, 7, storageAccountKind, ns)).To(Equal(shard))
+	}
+}
+
+func TestShardOf_ByGroupKind_IgnoresNamespace(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	for _, gk := range []schema.GroupKind{resourceGroupKind, storageAccountKind} {
+		shard := ShardOf(StrategyGroupKind, 7, gk, "team-a")
+		g.Expect(ShardOf(StrategyGroupKind, 7, gk, "team-b")).To(Equal(shard))
+	}
+}
+
+func TestShardOf_SingleShard_AlwaysReturnsZero(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(ShardOf(StrategyNamespace, 1, resourceGroupKind, "team-a")).To(Equal(0))
+	g.Expect(ShardOf(StrategyGroupKind, 0, resourceGroupKind, "team-a")).To(Equal(0))
+}
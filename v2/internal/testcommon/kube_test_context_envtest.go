@@ -247,7 +247,7 @@ func createSharedEnvTest(cfg testConfig, namespaceResources *namespaceResources)
 	}
 
 	if cfg.OperatorMode.IncludesWebhooks() {
-		err = generic.RegisterWebhooks(mgr, controllers.GetKnownTypes())
+		err = generic.RegisterWebhooks(mgr, controllers.GetKnownTypes(), nil)
 		if err != nil {
 			stopEnvironment()
 			return nil, errors.Wrapf(err, "registering webhooks")
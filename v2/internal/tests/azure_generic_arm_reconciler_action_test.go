@@ -0,0 +1,248 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/benbjohnson/clock"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cache "github.com/Azure/azure-service-operator/v2/api/cache/v1api20230401"
+	resources "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	"github.com/Azure/azure-service-operator/v2/internal/config"
+	"github.com/Azure/azure-service-operator/v2/internal/genericarmclient"
+	"github.com/Azure/azure-service-operator/v2/internal/identity"
+	"github.com/Azure/azure-service-operator/v2/internal/metrics"
+	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm"
+	"github.com/Azure/azure-service-operator/v2/internal/resolver"
+	"github.com/Azure/azure-service-operator/v2/internal/testcommon"
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/registration"
+)
+
+const actionTestSubscriptionID = "00000011-1111-0011-1100-110000000000"
+
+// testConnection is an arm.Connection using the provided client
+type testConnection struct {
+	client *genericarmclient.GenericClient
+}
+
+var _ arm.Connection = &testConnection{}
+
+func (c *testConnection) Client() *genericarmclient.GenericClient {
+	return c.client
+}
+
+func (c *testConnection) CredentialFrom() types.NamespacedName {
+	return types.NamespacedName{}
+}
+
+func (c *testConnection) CredentialSource() identity.CredentialSource {
+	return identity.CredentialSourceGlobal
+}
+
+func (c *testConnection) SubscriptionID() string {
+	return actionTestSubscriptionID
+}
+
+func createActionTestScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{v1.AddToScheme, resources.AddToScheme, cache.AddToScheme} {
+		err := add(scheme)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scheme, nil
+}
+
+func createRedisRegenerateKey() (*resources.ResourceGroup, *cache.Redis, *cache.RedisRegenerateKey) {
+	rg := testcommon.CreateResourceGroup()
+	rg.Spec.AzureName = rg.Name
+
+	redis := &cache.Redis{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rg.Namespace,
+			Name:      "myredis",
+		},
+		Spec: cache.Redis_Spec{
+			AzureName: "myredis",
+			Location:  to.Ptr("westus2"),
+			Owner: &genruntime.KnownResourceReference{
+				Name: rg.Name,
+			},
+		},
+	}
+
+	regenerateKey := &cache.RedisRegenerateKey{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  rg.Namespace,
+			Name:       "regenerate-primary",
+			Generation: 1,
+		},
+		Spec: cache.Redis_RegenerateKey_Spec{
+			KeyType: to.Ptr(cache.Redis_RegenerateKey_KeyType_Spec_Primary),
+			OperatorSpec: &cache.RedisRegenerateKeyOperatorSpec{
+				Secrets: &cache.RedisRegenerateKeyOperatorSecrets{
+					PrimaryKey: &genruntime.SecretDestination{Name: "redis-keys", Key: "primaryKey"},
+				},
+			},
+			Owner: &genruntime.KnownResourceReference{
+				Name: redis.Name,
+			},
+		},
+	}
+
+	return rg, redis, regenerateKey
+}
+
+func createActionTestResolver(scheme *runtime.Scheme, testClient client.Client) (*resolver.Resolver, error) {
+	objs := []*registration.StorageType{
+		registration.NewStorageType(new(resources.ResourceGroup)),
+		registration.NewStorageType(new(cache.Redis)),
+		registration.NewStorageType(new(cache.RedisRegenerateKey)),
+	}
+
+	res := resolver.NewResolver(kubeclient.NewClient(testClient))
+	err := res.IndexStorageTypes(scheme, objs)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func Test_ConvertResourceToARMAction_RedisRegenerateKey(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	scheme, err := createActionTestScheme()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	testClient := testcommon.CreateClient(scheme)
+	resolver, err := createActionTestResolver(scheme, testClient)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	rg, redis, regenerateKey := createRedisRegenerateKey()
+	g.Expect(testClient.Create(ctx, rg)).To(Succeed())
+	g.Expect(testClient.Create(ctx, redis)).To(Succeed())
+	g.Expect(testClient.Create(ctx, regenerateKey)).To(Succeed())
+
+	actionID, body, err := arm.ConvertToARMActionImpl(ctx, regenerateKey, scheme, resolver, actionTestSubscriptionID)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(actionID).To(Equal(
+		"/subscriptions/00000011-1111-0011-1100-110000000000/resourceGroups/myrg/providers/Microsoft.Cache/redis/myredis/regenerateKey"))
+
+	data, err := json.Marshal(body)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(MatchJSON(`{"keyType":"Primary"}`))
+}
+
+func Test_CreateOrUpdate_RedisRegenerateKey_InvokesActionAndWritesSecrets(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	actionPath := "/subscriptions/00000011-1111-0011-1100-110000000000/resourceGroups/myrg/providers/Microsoft.Cache/redis/myredis/regenerateKey"
+	posts := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == actionPath {
+			posts++
+			g.Expect(r.URL.Query().Get("api-version")).To(Equal("2023-04-01"))
+
+			var body map[string]string
+			g.Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			g.Expect(body).To(Equal(map[string]string{"keyType": "Primary"}))
+
+			w.WriteHeader(http.StatusOK)
+			g.Expect(w.Write([]byte(`{"primaryKey":"n3wPr1mary","secondaryKey":"0ldS3condary"}`))).ToNot(BeZero())
+			return
+		}
+
+		g.Fail(fmt.Sprintf("unknown request attempted. Method: %s, URL: %s", r.Method, r.URL))
+	}))
+	defer server.Close()
+
+	cfg := cloud.Configuration{
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Endpoint: server.URL,
+				Audience: cloud.AzurePublic.Services[cloud.ResourceManager].Audience,
+			},
+		},
+	}
+
+	options := &genericarmclient.GenericClientOptions{
+		HttpClient: server.Client(),
+		Metrics:    metrics.NewARMClientMetrics(),
+	}
+	armClient, err := genericarmclient.NewGenericClient(cfg, testcommon.MockTokenCredential{}, options)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	scheme, err := createActionTestScheme()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	testClient := testcommon.CreateClient(scheme)
+	resolver, err := createActionTestResolver(scheme, testClient)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	rg, redis, regenerateKey := createRedisRegenerateKey()
+	g.Expect(testClient.Create(ctx, rg)).To(Succeed())
+	g.Expect(testClient.Create(ctx, redis)).To(Succeed())
+	g.Expect(testClient.Create(ctx, regenerateKey)).To(Succeed())
+
+	connectionFactory := func(_ context.Context, _ genruntime.ARMMetaObject) (arm.Connection, error) {
+		return &testConnection{client: armClient}, nil
+	}
+
+	reconciler := arm.NewAzureDeploymentReconciler(
+		connectionFactory,
+		kubeclient.NewClient(testClient),
+		resolver,
+		conditions.NewPositiveConditionBuilder(clock.New()),
+		config.Values{},
+		nil,
+		nil,
+		nil,
+		nil)
+
+	_, err = reconciler.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), regenerateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(posts).To(Equal(1))
+
+	// The keys are written to the configured secret, and aren't recorded in status
+	var secret v1.Secret
+	g.Expect(testClient.Get(ctx, types.NamespacedName{Namespace: rg.Namespace, Name: "redis-keys"}, &secret)).To(Succeed())
+	g.Expect(secret.StringData).To(HaveKeyWithValue("primaryKey", "n3wPr1mary"))
+	g.Expect(secret.StringData).ToNot(HaveKey("secondaryKey"))
+	g.Expect(regenerateKey.Status.PrimaryKey).To(BeNil())
+	g.Expect(regenerateKey.Status.SecondaryKey).To(BeNil())
+
+	// The action isn't invoked again for the same generation
+	g.Expect(arm.IsActionCompleted(regenerateKey)).To(BeTrue())
+	_, err = reconciler.CreateOrUpdate(ctx, logr.Discard(), record.NewFakeRecorder(10), regenerateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(posts).To(Equal(1))
+}
@@ -10,9 +10,11 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/Azure/azure-service-operator/v2/internal/reconcilers/arm"
 	"github.com/Azure/azure-service-operator/v2/internal/testcommon"
+	"github.com/Azure/azure-service-operator/v2/internal/tracing"
 )
 
 func Test_ConvertResourceToARMResource(t *testing.T) {
@@ -43,3 +45,45 @@ func Test_ConvertResourceToARMResource(t *testing.T) {
 	g.Expect("2021-01-01").To(Equal(resource.Spec().GetAPIVersion()))
 	g.Expect("Microsoft.Batch/batchAccounts").To(Equal(resource.Spec().GetType()))
 }
+
+func Test_ConvertResourceToARMResource_RecordsSpans(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	provider, exporter := tracing.NewInMemoryTracerProvider()
+	ctx, root := provider.Tracer("test").Start(context.Background(), "root")
+
+	scheme, err := testcommon.CreateScheme()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	testClient := testcommon.CreateClient(scheme)
+
+	resolver, err := testcommon.CreateResolver(scheme, testClient)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	rg := testcommon.CreateResourceGroup()
+	g.Expect(testClient.Create(ctx, rg)).To(Succeed())
+
+	account := testcommon.CreateDummyResource()
+	g.Expect(testClient.Create(ctx, account)).To(Succeed())
+
+	_, err = arm.ConvertToARMResourceImpl(ctx, account, scheme, resolver, "1234")
+	g.Expect(err).ToNot(HaveOccurred())
+	root.End()
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+
+	g.Expect(spans).To(HaveKey("ConvertToARMResourceImpl"))
+	g.Expect(spans).To(HaveKey("Resolver.ResolveAll"))
+	g.Expect(spans).To(HaveKey("Resolver.ResolveResourceHierarchy"))
+	g.Expect(spans).To(HaveKey("Resolver.ResolveResourceSecretReferences"))
+
+	// Spans are nested
+	g.Expect(spans["ConvertToARMResourceImpl"].Parent.SpanID()).To(Equal(spans["root"].SpanContext.SpanID()))
+	g.Expect(spans["Resolver.ResolveAll"].Parent.SpanID()).To(Equal(spans["ConvertToARMResourceImpl"].SpanContext.SpanID()))
+	g.Expect(spans["Resolver.ResolveResourceHierarchy"].Parent.SpanID()).To(Equal(spans["Resolver.ResolveAll"].SpanContext.SpanID()))
+	g.Expect(spans["Resolver.ResolveAll"].Attributes).To(ContainElement(tracing.NameKey.String(account.Name)))
+}
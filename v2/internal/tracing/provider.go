@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/Azure/azure-service-operator/v2/internal/version"
+)
+
+// ServiceName is the name the operator reports itself as when exporting spans
+const ServiceName = "azure-service-operator"
+
+// NewOTLPTracerProvider creates a TracerProvider which exports spans in batches via OTLP over HTTP.
+// endpoint is the URL of the collector, such as http://otel-collector:4318; the scheme selects whether TLS is used.
+// Other settings (such as headers) can be supplied using the standard OTEL_EXPORTER_OTLP_* environment variables.
+func NewOTLPTracerProvider(ctx context.Context, endpoint string) (*sdktrace.TracerProvider, error) {
+	options, err := otlpOptionsFromEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating OTLP exporter for %s", endpoint)
+	}
+
+	return NewTracerProvider(sdktrace.WithBatcher(exporter)), nil
+}
+
+// NewInMemoryTracerProvider creates a TracerProvider which synchronously records spans to an in-memory exporter,
+// allowing tests to check the spans created.
+func NewInMemoryTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+// NewTracerProvider creates a TracerProvider describing the operator, with the given options
+func NewTracerProvider(options ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	rsrc := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(version.BuildVersion))
+
+	options = append([]sdktrace.TracerProviderOption{sdktrace.WithResource(rsrc)}, options...)
+	return sdktrace.NewTracerProvider(options...)
+}
+
+func otlpOptionsFromEndpoint(endpoint string) ([]otlptracehttp.Option, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing OTLP endpoint %q", endpoint)
+	}
+
+	if u.Host == "" {
+		return nil, errors.Errorf("OTLP endpoint %q must be a URL such as http://otel-collector:4318", endpoint)
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http":
+		options = append(options, otlptracehttp.WithInsecure())
+	case "https":
+		// TLS is the default
+	default:
+		return nil, errors.Errorf("OTLP endpoint %q must use http or https, not %q", endpoint, u.Scheme)
+	}
+
+	if u.Path != "" && u.Path != "/" {
+		options = append(options, otlptracehttp.WithURLPath(u.Path))
+	}
+
+	return options, nil
+}
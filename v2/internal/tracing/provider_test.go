@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tracing
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestOTLPOptionsFromEndpoint_GivenEndpoint_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		endpoint string
+		options  int
+		err      string
+	}{
+		"Insecure":           {endpoint: "http://otel-collector:4318", options: 2},
+		"Secure":             {endpoint: "https://otel-collector:4318", options: 1},
+		"Secure with path":   {endpoint: "https://collector.example.com/otlp/v1/traces", options: 2},
+		"Missing scheme":     {endpoint: "otel-collector:4318", err: "must be a URL"},
+		"Not a URL":          {endpoint: "otel-collector", err: "must be a URL"},
+		"Unsupported scheme": {endpoint: "grpc://otel-collector:4317", err: "must use http or https"},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			options, err := otlpOptionsFromEndpoint(c.endpoint)
+			if c.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(c.err)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(options).To(HaveLen(c.options))
+		})
+	}
+}
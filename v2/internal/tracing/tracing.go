@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TracerName is the name of the tracer used for all spans created by the operator
+const TracerName = "github.com/Azure/azure-service-operator/v2"
+
+// Attribute keys used on spans created by the operator
+const (
+	// ARMCorrelationIDKey is the correlation ID returned by ARM, used by Azure support to find a request
+	ARMCorrelationIDKey = attribute.Key("azure.correlation_id")
+	// ARMResourceTypeKey is the ARM type of the resource targeted by a request
+	ARMResourceTypeKey = attribute.Key("azure.resource_type")
+	// KindKey is the Kubernetes kind of the resource being reconciled
+	KindKey = attribute.Key("k8s.kind")
+	// NameKey is the Kubernetes name of the resource being reconciled
+	NameKey = attribute.Key("k8s.name")
+	// NamespaceKey is the Kubernetes namespace of the resource being reconciled
+	NamespaceKey = attribute.Key("k8s.namespace")
+)
+
+// StartSpan starts a new span with the given name and attributes.
+// If ctx already contains a span, the new span is a child of it and is created by the same TracerProvider;
+// otherwise the span is a root span created by the global TracerProvider (a no-op unless tracing is configured).
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	provider := otel.GetTracerProvider()
+	if parent := trace.SpanFromContext(ctx); parent.SpanContext().IsValid() {
+		provider = parent.TracerProvider()
+	}
+
+	return provider.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan ends the span, first recording err on it if the operation failed
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Trace runs fn in a child span with the given name, recording any error returned
+func Trace[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := StartSpan(ctx, name)
+	result, err := fn(ctx)
+	EndSpan(span, err)
+	return result, err
+}
+
+// ObjectAttributes returns the attributes identifying a Kubernetes resource
+func ObjectAttributes(obj client.Object) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		KindKey.String(obj.GetObjectKind().GroupVersionKind().Kind),
+		NamespaceKey.String(obj.GetNamespace()),
+		NameKey.String(obj.GetName()),
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestStartSpan_WhenParentInContext_CreatesChildFromSameProvider(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	provider, exporter := NewInMemoryTracerProvider()
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+
+	_, child := StartSpan(ctx, "child", NameKey.String("person"))
+	child.End()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	g.Expect(spans).To(HaveLen(2))
+	g.Expect(spans[0].Name).To(Equal("child"))
+	g.Expect(spans[0].Parent.SpanID()).To(Equal(spans[1].SpanContext.SpanID()))
+	g.Expect(spans[0].Attributes).To(ContainElement(NameKey.String("person")))
+}
+
+func TestTrace_WhenFunctionFails_RecordsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	provider, exporter := NewInMemoryTracerProvider()
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+
+	_, err := Trace(ctx, "failing", func(ctx context.Context) (string, error) {
+		return "", errors.New("boom")
+	})
+	g.Expect(err).To(MatchError("boom"))
+	parent.End()
+
+	spans := exporter.GetSpans()
+	g.Expect(spans).To(HaveLen(2))
+	g.Expect(spans[0].Name).To(Equal("failing"))
+	g.Expect(spans[0].Status.Code).To(Equal(codes.Error))
+	g.Expect(spans[0].Status.Description).To(Equal("boom"))
+	g.Expect(spans[0].Events).To(HaveLen(1)) // The recorded error
+	g.Expect(spans[1].Status.Code).To(Equal(codes.Unset))
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package interval
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/Azure/azure-service-operator/v2/internal/util/kubeclient"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+// RequeueReason describes why a resource is being requeued for reconciliation
+type RequeueReason string
+
+const (
+	// RequeueReasonNone indicates the resource will not be requeued
+	RequeueReasonNone = RequeueReason("")
+	// RequeueReasonError indicates an unclassified error, retried with the controller-runtime backoff
+	RequeueReasonError = RequeueReason("Error")
+	// RequeueReasonRetryFast indicates a ReadyConditionImpactingError classified as RetryFast
+	RequeueReasonRetryFast = RequeueReason("RetryFast")
+	// RequeueReasonRetrySlow indicates a ReadyConditionImpactingError classified as RetrySlow
+	RequeueReasonRetrySlow = RequeueReason("RetrySlow")
+	// RequeueReasonRequested indicates the reconciler asked to be requeued, such as when monitoring a long-running operation
+	RequeueReasonRequested = RequeueReason("Requested")
+	// RequeueReasonSyncPeriod indicates a successful reconcile, requeued to detect drift in Azure
+	RequeueReasonSyncPeriod = RequeueReason("SyncPeriod")
+)
+
+// ClassifyRequeue returns the reason a request with the given result and error will be requeued, using the same
+// rules as Calculator.NextInterval. It should be given the values passed to NextInterval, not those returned from it.
+// RequeueReasonSyncPeriod is returned for any success; callers should check whether the interval calculated
+// actually results in a requeue.
+func ClassifyRequeue(result ctrl.Result, err error) RequeueReason {
+	if err == nil {
+		if (result == ctrl.Result{}) {
+			return RequeueReasonSyncPeriod
+		}
+
+		return RequeueReasonRequested
+	}
+
+	readyErr, ok := conditions.AsReadyConditionImpactingError(err)
+	if !ok {
+		if kubeclient.IgnoreNotFound(err) == nil {
+			// NotFound errors are ignored, see NextInterval
+			return RequeueReasonNone
+		}
+
+		return RequeueReasonError
+	}
+
+	if readyErr.Severity == conditions.ConditionSeverityError {
+		// Fatal, not requeued
+		return RequeueReasonNone
+	}
+
+	switch readyErr.RetryClassification {
+	case conditions.RetryFast:
+		return RequeueReasonRetryFast
+	case conditions.RetrySlow:
+		return RequeueReasonRetrySlow
+	default:
+		return RequeueReasonError
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package interval
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+)
+
+func Test_ClassifyRequeue_ReturnsExpectedReason(t *testing.T) {
+	t.Parallel()
+
+	notFound := &apierrors.StatusError{
+		ErrStatus: metav1.Status{
+			Reason: metav1.StatusReasonNotFound,
+		},
+	}
+
+	makeReadyErr := func(severity conditions.ConditionSeverity, retry conditions.RetryClassification) error {
+		return conditions.NewReadyConditionImpactingError(
+			errors.New("problem"),
+			severity,
+			conditions.Reason{Name: "Abc", RetryClassification: retry})
+	}
+
+	cases := map[string]struct {
+		result   ctrl.Result
+		err      error
+		expected RequeueReason
+	}{
+		"Success":           {result: ctrl.Result{}, expected: RequeueReasonSyncPeriod},
+		"Requeue requested": {result: ctrl.Result{RequeueAfter: 5 * time.Second}, expected: RequeueReasonRequested},
+		"Unclassified":      {err: errors.New("boom"), expected: RequeueReasonError},
+		"NotFound":          {err: notFound, expected: RequeueReasonNone},
+		"Fatal":             {err: makeReadyErr(conditions.ConditionSeverityError, conditions.RetryNone), expected: RequeueReasonNone},
+		"RetryFast":         {err: makeReadyErr(conditions.ConditionSeverityWarning, conditions.RetryFast), expected: RequeueReasonRetryFast},
+		"RetrySlow":         {err: makeReadyErr(conditions.ConditionSeverityWarning, conditions.RetrySlow), expected: RequeueReasonRetrySlow},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(ClassifyRequeue(c.result, c.err)).To(Equal(c.expected))
+		})
+	}
+}
@@ -52,6 +52,37 @@ func (advisor *Advisor) ClearTerms() {
 	advisor.terms.Clear()
 }
 
+// Suggest returns the known term most similar to the specified one (ignoring case), along with true, if its
+// similarity is at least minSimilarity (between 0 and 1, with 1 being identical); otherwise "" and false.
+func (advisor *Advisor) Suggest(term string, minSimilarity float32) (string, bool) {
+	advisor.lock.RLock()
+	defer advisor.lock.RUnlock()
+
+	best := ""
+	var bestSimilarity float32
+	for _, candidate := range set.AsSortedSlice(advisor.terms) {
+		similarity, err := edlib.StringsSimilarity(
+			strings.ToLower(term),
+			strings.ToLower(candidate),
+			edlib.Levenshtein)
+		if err != nil {
+			// Can't compare with this candidate
+			continue
+		}
+
+		if similarity > bestSimilarity {
+			best = candidate
+			bestSimilarity = similarity
+		}
+	}
+
+	if best == "" || bestSimilarity < minSimilarity {
+		return "", false
+	}
+
+	return best, true
+}
+
 // Errorf creates a new error with advice, or a simple error if no advice possible
 func (advisor *Advisor) Errorf(typo string, format string, args ...interface{}) error {
 	advisor.lock.RLock()
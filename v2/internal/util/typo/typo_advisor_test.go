@@ -64,6 +64,34 @@ func TestTypoAdvisor_Wrapf_WhenErrorAndTypo_ReturnsExpectedError(t *testing.T) {
 	g.Expect(actual.Error()).To(ContainSubstring("did you mean beta?"))
 }
 
+func TestTypoAdvisor_Suggest_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		term          string
+		expected      string
+		expectedFound bool
+	}{
+		{"Close match", "TlsVersion", "MinimumTlsVersion", true},
+		{"Differs only by case", "minimumtlsversion", "MinimumTlsVersion", true},
+		{"No close match", "Zone", "", false},
+	}
+
+	advisor := createTestTypoAdvisor("MinimumTlsVersion", "EnableNonSslPort")
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			actual, found := advisor.Suggest(c.term, 0.5)
+			g.Expect(found).To(Equal(c.expectedFound))
+			g.Expect(actual).To(Equal(c.expected))
+		})
+	}
+}
+
 func createTestTypoAdvisor(terms ...string) *Advisor {
 	result := NewAdvisor()
 	for _, term := range terms {
@@ -4,3 +4,7 @@
 package annotations
 
 const PerResourceSecret = "serviceoperator.azure.com/credential-from"
+
+// CRDPattern on a namespace requests installation of the CRDs matching the given patterns (in the same format as the
+// crd-pattern flag), when the operator is using the lazy CRD management mode.
+const CRDPattern = "serviceoperator.azure.com/crd-pattern"
@@ -0,0 +1,26 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package annotations
+
+// The following annotations are set on Kubernetes events emitted by the operator, so that tooling can parse them
+// without relying on the (non-contractual) event message.
+
+// EventOperation is the operation the operator was performing when the event was emitted (CreateOrUpdate or Delete).
+const EventOperation = "serviceoperator.azure.com/operation"
+
+// EventConditionReason is the reason recorded on the Ready condition of the resource for the error.
+const EventConditionReason = "serviceoperator.azure.com/condition-reason"
+
+// EventErrorCode is the error code returned by Azure Resource Manager.
+const EventErrorCode = "serviceoperator.azure.com/error-code"
+
+// EventErrorTarget is the target of the error returned by Azure Resource Manager, if any.
+const EventErrorTarget = "serviceoperator.azure.com/error-target"
+
+// EventCorrelationID is the correlation ID of the failed request to Azure Resource Manager, for use with Azure support.
+const EventCorrelationID = "serviceoperator.azure.com/correlation-id"
+
+// EventSuppressedCount is the number of identical events (same resource, reason, error code and message) suppressed
+// since the last event was emitted.
+const EventSuppressedCount = "serviceoperator.azure.com/suppressed-count"
@@ -6,6 +6,9 @@
 package genruntime
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -59,6 +62,83 @@ func ValidateWriteOnceProperties(oldObj ARMMetaObject, newObj ARMMetaObject) (ad
 	return nil, kerrors.NewAggregate(errs)
 }
 
+// ValidateImmutableProperties validates that create-only properties are unchanged once the resource has been created
+// in Azure. Properties are identified by their JSON path within the spec, e.g. "sku.name".
+func ValidateImmutableProperties(oldObj ARMMetaObject, newObj ARMMetaObject, paths ...string) (admission.Warnings, error) {
+	if !IsResourceCreatedSuccessfully(newObj) {
+		return nil, nil
+	}
+
+	oldSpec := reflect.ValueOf(oldObj.GetSpec())
+	newSpec := reflect.ValueOf(newObj.GetSpec())
+
+	var errs []error
+	for _, path := range paths {
+		oldValue, err := getJSONPath(oldSpec, path)
+		if err != nil {
+			return nil, err
+		}
+
+		newValue, err := getJSONPath(newSpec, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(oldValue, newValue) {
+			errs = append(errs, errors.Errorf("updating 'spec.%s' is not allowed for '%s : %s", path, oldObj.GetObjectKind().GroupVersionKind(), oldObj.GetName()))
+		}
+	}
+
+	return nil, kerrors.NewAggregate(errs)
+}
+
+// getJSONPath returns the value of the property at the JSON path within the struct, or nil if it, or any property
+// containing it, isn't set
+func getJSONPath(value reflect.Value, path string) (any, error) {
+	for _, name := range strings.Split(path, ".") {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil, nil
+			}
+
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return nil, errors.Errorf("unable to find %s in %s, %s is not a struct", name, path, value.Type())
+		}
+
+		field, ok := fieldByJSONName(value.Type(), name)
+		if !ok {
+			return nil, errors.Errorf("unable to find %s in %s, no such property on %s", name, path, value.Type())
+		}
+
+		value = value.FieldByIndex(field.Index)
+	}
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+
+		value = value.Elem()
+	}
+
+	return value.Interface(), nil
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == name {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
 func ValidateCreate(validations []func() (admission.Warnings, error)) (admission.Warnings, error) {
 	var errs []error
 	var warnings admission.Warnings
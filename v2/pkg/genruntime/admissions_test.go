@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package genruntime_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	storage "github.com/Azure/azure-service-operator/v2/api/storage/v1api20220901"
+	"github.com/Azure/azure-service-operator/v2/internal/util/to"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+)
+
+func newStorageAccount(created bool) *storage.StorageAccount {
+	account := &storage.StorageAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "account",
+		},
+		Spec: storage.StorageAccount_Spec{
+			Kind: to.Ptr(storage.StorageAccount_Kind_Spec_StorageV2),
+			Sku: &storage.Sku{
+				Name: to.Ptr(storage.SkuName_Standard_LRS),
+			},
+			Location: to.Ptr("westus"),
+		},
+	}
+
+	if created {
+		genruntime.SetResourceID(account, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/account")
+	}
+
+	return account
+}
+
+func Test_ValidateImmutableProperties_WhenUnchanged_Succeeds(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	oldObj := newStorageAccount(true)
+	newObj := newStorageAccount(true)
+	newObj.Spec.Location = to.Ptr("eastus")
+
+	_, err := genruntime.ValidateImmutableProperties(oldObj, newObj, "kind", "sku.name")
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func Test_ValidateImmutableProperties_WhenChanged_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	oldObj := newStorageAccount(true)
+	newObj := newStorageAccount(true)
+	newObj.Spec.Kind = to.Ptr(storage.StorageAccount_Kind_Spec_BlobStorage)
+	newObj.Spec.Sku = nil
+
+	_, err := genruntime.ValidateImmutableProperties(oldObj, newObj, "kind", "sku.name")
+	g.Expect(err).To(MatchError(ContainSubstring("updating 'spec.kind' is not allowed")))
+	g.Expect(err).To(MatchError(ContainSubstring("updating 'spec.sku.name' is not allowed")))
+}
+
+func Test_ValidateImmutableProperties_WhenNotYetCreated_Succeeds(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	oldObj := newStorageAccount(false)
+	newObj := newStorageAccount(false)
+	newObj.Spec.Kind = to.Ptr(storage.StorageAccount_Kind_Spec_BlobStorage)
+
+	_, err := genruntime.ValidateImmutableProperties(oldObj, newObj, "kind")
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func Test_ValidateImmutableProperties_WhenPathUnknown_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	oldObj := newStorageAccount(true)
+	newObj := newStorageAccount(true)
+
+	_, err := genruntime.ValidateImmutableProperties(oldObj, newObj, "sku.colour")
+	g.Expect(err).To(MatchError(ContainSubstring("no such property")))
+}
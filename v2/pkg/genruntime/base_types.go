@@ -32,6 +32,7 @@ const (
 	ResourceOperationHead   = ResourceOperation("HEAD")
 	ResourceOperationPut    = ResourceOperation("PUT")
 	ResourceOperationDelete = ResourceOperation("DELETE")
+	ResourceOperationPost   = ResourceOperation("POST")
 )
 
 func (o ResourceOperation) IsSupportedBy(obj SupportedResourceOperations) bool {
@@ -44,6 +45,12 @@ func (o ResourceOperation) IsSupportedBy(obj SupportedResourceOperations) bool {
 	return false
 }
 
+// IsActionResource returns true if obj represents an ARM action, invoked once via POST,
+// rather than a resource whose lifecycle is managed via PUT and DELETE.
+func IsActionResource(obj SupportedResourceOperations) bool {
+	return ResourceOperationPost.IsSupportedBy(obj) && !ResourceOperationPut.IsSupportedBy(obj)
+}
+
 // TODO: It's weird that this is isn't with the other annotations
 // TODO: Should we move them all here (so they're exported?) Or shold we move them
 // TODO: to serviceoperator-internal.azure.com to signify they are internal?
@@ -0,0 +1,41 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package genruntime
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeSupportedOperations []ResourceOperation
+
+func (f fakeSupportedOperations) GetSupportedOperations() []ResourceOperation {
+	return f
+}
+
+func TestIsActionResource(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		operations fakeSupportedOperations
+		expected   bool
+	}{
+		"Resource":           {fakeSupportedOperations{ResourceOperationGet, ResourceOperationPut, ResourceOperationDelete}, false},
+		"Action":             {fakeSupportedOperations{ResourceOperationPost}, true},
+		"Resource with POST": {fakeSupportedOperations{ResourceOperationPut, ResourceOperationPost}, false},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(IsActionResource(c.operations)).To(Equal(c.expected))
+		})
+	}
+}
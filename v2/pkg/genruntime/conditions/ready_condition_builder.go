@@ -39,6 +39,7 @@ var ReasonReconciliationFailedPermanently = Reason{Name: "ReconciliationFailedPe
 var ReasonReconcileBlocked = Reason{Name: "ReconciliationBlocked", RetryClassification: RetrySlow}
 var ReasonReconcilePostponed = Reason{Name: "ReconciliationPostponed", RetryClassification: RetrySlow}
 var ReasonPostReconcileFailure = Reason{Name: "PostReconciliationFailure", RetryClassification: RetrySlow}
+var ReasonPolicyViolation = Reason{Name: "PolicyViolation", RetryClassification: RetrySlow}
 
 // ReasonFailed is a catch-all error code for when we don't have a more specific error classification
 var ReasonFailed = Reason{Name: "Failed", RetryClassification: RetrySlow}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package genruntime
+
+// DataPlaneURLAnnotation records the URL of a data plane resource. Data plane resources don't have an ARM ID, so
+// this takes the place of ResourceIDAnnotation.
+const DataPlaneURLAnnotation = "serviceoperator.azure.com/data-plane-url"
+
+// DataPlaneResource represents a resource that is managed through the data plane of an Azure service (such as a
+// secret in a Key Vault) rather than through ARM.
+type DataPlaneResource interface {
+	ARMMetaObject
+
+	// GetDataPlaneAudience returns the audience for which access tokens must be acquired, e.g. https://vault.azure.net
+	GetDataPlaneAudience() string
+
+	// GetDataPlaneEndpoint returns a template for the endpoint of the data plane, e.g. https://{owner}.vault.azure.net.
+	// The {owner} placeholder is replaced with the Azure name of the owner of the resource.
+	GetDataPlaneEndpoint() string
+
+	// GetDataPlanePath returns a template for the path of the resource within the data plane, e.g. /secrets/{name}.
+	// The {name} placeholder is replaced with the Azure name of the resource.
+	GetDataPlanePath() string
+}
+
+// GetDataPlaneURL returns the URL of the data plane resource, if it has been created
+func GetDataPlaneURL(obj DataPlaneResource) (string, bool) {
+	result, ok := obj.GetAnnotations()[DataPlaneURLAnnotation]
+	return result, ok
+}
+
+// SetDataPlaneURL records the URL of the data plane resource
+func SetDataPlaneURL(obj DataPlaneResource, url string) {
+	AddAnnotation(obj, DataPlaneURLAnnotation, url)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/pipeline"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/versiondiff"
+)
+
+// NewDiffVersionsCommand creates a new cobra Command when invoked from the command line
+func NewDiffVersionsCommand() (*cobra.Command, error) {
+	var configFile string
+
+	cmd := &cobra.Command{
+		Use:   "diff-versions <group> <from> <to>",
+		Short: "report differences between two API versions of a group, suggesting configuration for renames",
+		Long: `Report differences between two API versions of a group, suggesting configuration for renames.
+
+Both versions are loaded through the code generation pipeline (up to creation of the conversion graph), so both must
+already be exported by the configuration file. Versions are given as they appear in the configuration (e.g. 2021-05-01).
+The report lists added, removed, retyped, and likely renamed properties, and the properties that will be stored in
+the PropertyBag by the generated conversions between the versions.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			group := strings.ToLower(args[0])
+			ctx := cmd.Context()
+
+			log := CreateLogger()
+
+			cg, err := codegen.NewCodeGeneratorFromConfigFile(configFile, log)
+			if err != nil {
+				log.Error(err, "Error creating code generator")
+				return err
+			}
+
+			state, err := cg.GenerateUntil(ctx, pipeline.CreateConversionGraphStageId, log)
+			if err != nil {
+				return errors.Wrap(err, "error loading versions")
+			}
+
+			configuration := cg.Configuration()
+			from := configuration.MakeLocalPackageReference(group, args[1])
+			to := configuration.MakeLocalPackageReference(group, args[2])
+
+			for _, ref := range []string{from.PackagePath(), to.PackagePath()} {
+				if !hasDefinitionsIn(state, ref) {
+					return errors.Errorf("no types found in %s; is it exported by %s?", ref, configFile)
+				}
+			}
+
+			diff := versiondiff.Compare(
+				state.Definitions(),
+				state.ConversionGraph(),
+				configuration.ObjectModelConfiguration,
+				from,
+				to)
+			return diff.SaveTo(os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "azure-arm.yaml", "Configuration file for the generator")
+
+	return cmd, nil
+}
+
+// hasDefinitionsIn returns true if state has any definitions in the package with the specified path
+func hasDefinitionsIn(state *pipeline.State, packagePath string) bool {
+	for name := range state.Definitions() {
+		if name.InternalPackageReference().PackagePath() == packagePath {
+			return true
+		}
+	}
+
+	return false
+}
@@ -19,6 +19,8 @@ import (
 // NewGenTypesCommand creates a new cobra Command when invoked from the command line
 func NewGenTypesCommand() (*cobra.Command, error) {
 	var debugMode *string
+	var groups *[]string
+	var cacheFolder *string
 
 	cmd := &cobra.Command{
 		// TODO: there's not great support for required
@@ -26,7 +28,12 @@ func NewGenTypesCommand() (*cobra.Command, error) {
 		// TODO: https://github.com/spf13/cobra/issues/395
 		Use:   "gen-types <config>",
 		Short: "generate K8s resources from Azure deployment template schema",
-		Args:  cobra.ExactArgs(1),
+		Long: `Generate K8s resources from Azure deployment template schema.
+
+Use --cache to record the result of generating each group in a cache, keyed by a fingerprint of the generator, the
+Swagger and the configuration used. With a cache, use --groups to regenerate only the specified groups (if they've
+changed) plus any groups depending on them, leaving code for all other groups untouched.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			configFile := args[0]
 			ctx := cmd.Context()
@@ -39,6 +46,18 @@ func NewGenTypesCommand() (*cobra.Command, error) {
 				return err
 			}
 
+			if *cacheFolder != "" {
+				err = cg.UseCache(*cacheFolder)
+				if err != nil {
+					log.Error(err, "Error configuring generation cache")
+					return err
+				}
+			} else if len(*groups) > 0 {
+				err = errors.New("--groups requires a generation cache; use --cache to specify its folder")
+				log.Error(err, "Error configuring generation cache")
+				return err
+			}
+
 			if debugMode != nil && *debugMode != "" {
 				var tmpDir string
 				tmpDir, err = ioutil.TempDir("", createDebugPrefix(*debugMode))
@@ -59,7 +78,11 @@ func NewGenTypesCommand() (*cobra.Command, error) {
 				}()
 			}
 
-			err = cg.Generate(ctx, log)
+			if len(*groups) > 0 {
+				err = cg.GenerateGroups(ctx, *groups, log)
+			} else {
+				err = cg.Generate(ctx, log)
+			}
 
 			if err != nil {
 				err = errors.Wrap(err, "error generating code")
@@ -76,6 +99,16 @@ func NewGenTypesCommand() (*cobra.Command, error) {
 		"",
 		"Write debug logs to a temp folder for a group (e.g. compute), multiple groups (e.g. compute;network), or groups matching a wildcard (e.g. net*)")
 
+	groups = cmd.Flags().StringSlice(
+		"groups",
+		nil,
+		"Regenerate only these groups (e.g. compute,network), along with any groups depending on them; requires --cache")
+
+	cacheFolder = cmd.Flags().String(
+		"cache",
+		"",
+		"Folder for the generation cache; if not specified, no cache is used")
+
 	return cmd, nil
 }
 
@@ -29,6 +29,14 @@ type EnumType struct {
 	// emitValidation determines if the enum includes kubebuilder validation when emitted.
 	// Statuses may use an enum type but without validation.
 	emitValidation bool
+
+	// extensible indicates the Swagger marks the enum as open (x-ms-enum with modelAsString: true), meaning Azure
+	// may add values without a new API version. Can be overridden with $extensibleEnum.
+	extensible bool
+
+	// permitUnknownValues indicates values other than the known options are accepted (opted in with
+	// $extensibleEnum); the known values are retained as constants, but no validation is emitted.
+	permitUnknownValues bool
 }
 
 // EnumType must implement the Type interface correctly
@@ -64,6 +72,40 @@ func (enum *EnumType) WithValidation() *EnumType {
 	return result
 }
 
+// WithExtensible returns a copy of this enum, flagged as open (extensible) or closed.
+func (enum *EnumType) WithExtensible(extensible bool) *EnumType {
+	if enum.extensible == extensible {
+		return enum
+	}
+
+	result := enum.clone()
+	result.extensible = extensible
+	return result
+}
+
+// IsExtensible returns true if the enum is open, with Azure free to add values other than the known options.
+func (enum *EnumType) IsExtensible() bool {
+	return enum.extensible
+}
+
+// WithUnknownValuesPermitted returns a copy of this enum that does (or doesn't) accept values other than the known
+// options.
+func (enum *EnumType) WithUnknownValuesPermitted(permit bool) *EnumType {
+	if enum.permitUnknownValues == permit {
+		return enum
+	}
+
+	result := enum.clone()
+	result.permitUnknownValues = permit
+	return result
+}
+
+// PermitsUnknownValues returns true if the enum accepts values other than the known options, in which case no
+// validation of the value is emitted.
+func (enum *EnumType) PermitsUnknownValues() bool {
+	return enum.permitUnknownValues
+}
+
 // AsDeclarations converts the EnumType to a series of Go AST Decls
 func (enum *EnumType) AsDeclarations(codeGenerationContext *CodeGenerationContext, declContext DeclarationContext) ([]dst.Decl, error) {
 	result := []dst.Decl{enum.createBaseDeclaration(codeGenerationContext, declContext.Name, declContext.Description, declContext.Validations)}
@@ -112,7 +154,11 @@ func (enum *EnumType) createBaseDeclaration(
 	astbuilder.AddWrappedComments(&declaration.Decs.Start, description)
 	AddValidationComments(&declaration.Decs.Start, validations)
 
-	if enum.emitValidation {
+	if enum.permitUnknownValues {
+		astbuilder.AddComment(
+			&declaration.Decs.Start,
+			"// Known values are listed below; as this enum is extensible, other values are also permitted.")
+	} else if enum.emitValidation {
 		validationComment := GenerateKubebuilderComment(enum.CreateValidation())
 		astbuilder.AddComment(&declaration.Decs.Start, validationComment)
 	}
@@ -163,6 +209,14 @@ func (enum *EnumType) Equals(t Type, overrides EqualityOverrides) bool {
 			return false
 		}
 
+		if enum.extensible != e.extensible {
+			return false
+		}
+
+		if enum.permitUnknownValues != e.permitUnknownValues {
+			return false
+		}
+
 		if len(enum.options) != len(e.options) {
 			// Different number of properties, not equal
 			return false
@@ -210,6 +264,8 @@ func (enum *EnumType) BaseType() *PrimitiveType {
 func (enum *EnumType) clone() *EnumType {
 	result := *enum
 	result.emitValidation = enum.emitValidation
+	result.extensible = enum.extensible
+	result.permitUnknownValues = enum.permitUnknownValues
 	result.options = slices.Clone(enum.options)
 	result.baseType = enum.baseType
 
@@ -236,6 +292,12 @@ func (enum *EnumType) WriteDebugDescription(builder *strings.Builder, currentPac
 	}
 
 	builder.WriteString("enum:")
+	if enum.extensible {
+		builder.WriteString("extensible:")
+	}
+	if enum.permitUnknownValues {
+		builder.WriteString("unvalidated:")
+	}
 	enum.baseType.WriteDebugDescription(builder, currentPackage)
 	if len(enum.options) > 0 {
 		builder.WriteString("[")
@@ -58,6 +58,8 @@ func Test_EnumTypeEquals_GivenEnums_ReturnsExpectedResult(t *testing.T) {
 	supersetOfValues := NewEnumType(StringType, underValue, aboveValue, leftValue, rightValue)
 	subsetOfValues := NewEnumType(StringType, underValue)
 	differentValues := NewEnumType(StringType, leftValue, rightValue)
+	extensible := NewEnumType(StringType, aboveValue, underValue).WithExtensible(true)
+	unvalidated := NewEnumType(StringType, aboveValue, underValue).WithUnknownValuesPermitted(true)
 
 	cases := []struct {
 		name      string
@@ -86,6 +88,12 @@ func Test_EnumTypeEquals_GivenEnums_ReturnsExpectedResult(t *testing.T) {
 		// Expect not-equal for different values (but same value count)
 		{"Not-equal when different values", enum, differentValues, false},
 		{"Not-equal when different values", differentValues, enum, false},
+		// Expect not-equal when only one is extensible
+		{"Not-equal when different extensibility", enum, extensible, false},
+		{"Not-equal when different extensibility", extensible, enum, false},
+		// Expect not-equal when only one permits unknown values
+		{"Not-equal when only one permits unknown values", enum, unvalidated, false},
+		{"Not-equal when only one permits unknown values", unvalidated, enum, false},
 	}
 
 	for _, c := range cases {
@@ -100,3 +108,39 @@ func Test_EnumTypeEquals_GivenEnums_ReturnsExpectedResult(t *testing.T) {
 		})
 	}
 }
+
+/*
+ * WithExtensible() tests
+ */
+
+func Test_EnumTypeWithExtensible_GivenFlag_ReturnsExpectedEnum(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	closed := NewEnumType(StringType, aboveValue, underValue)
+	open := closed.WithExtensible(true)
+
+	g.Expect(closed.IsExtensible()).To(BeFalse())
+	g.Expect(open.IsExtensible()).To(BeTrue())
+	g.Expect(open.Options()).To(Equal(closed.Options()))
+	g.Expect(open.WithExtensible(true)).To(BeIdenticalTo(open))
+	g.Expect(TypeEquals(open.WithExtensible(false), closed)).To(BeTrue())
+}
+
+/*
+ * WithUnknownValuesPermitted() tests
+ */
+
+func Test_EnumTypeWithUnknownValuesPermitted_GivenFlag_ReturnsExpectedEnum(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	validated := NewEnumType(StringType, aboveValue, underValue).WithExtensible(true)
+	unvalidated := validated.WithUnknownValuesPermitted(true)
+
+	g.Expect(validated.PermitsUnknownValues()).To(BeFalse())
+	g.Expect(unvalidated.PermitsUnknownValues()).To(BeTrue())
+	g.Expect(unvalidated.IsExtensible()).To(BeTrue())
+	g.Expect(unvalidated.WithUnknownValuesPermitted(true)).To(BeIdenticalTo(unvalidated))
+	g.Expect(TypeEquals(unvalidated.WithUnknownValuesPermitted(false), validated)).To(BeTrue())
+}
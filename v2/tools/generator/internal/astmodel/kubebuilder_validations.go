@@ -30,6 +30,11 @@ type KubeBuilderValidation struct {
 func GenerateKubebuilderComment(validation KubeBuilderValidation) string {
 	const prefix = "// +kubebuilder:validation:"
 
+	if validation.name == XValidationName {
+		// XValidation takes named arguments, e.g. XValidation:rule="self.x > 0",message="x must be positive"
+		return fmt.Sprintf("%s%s:%s", prefix, validation.name, validation.value)
+	}
+
 	if validation.value != nil {
 		value := reflect.ValueOf(validation.value)
 
@@ -112,6 +117,9 @@ const (
 	ExclusiveMaximumValidationName string = "ExclusiveMaximum"
 	ExclusiveMinimumValidationName string = "ExclusiveMinimum"
 	MultipleOfValidationName       string = "MultipleOf"
+
+	// Objects:
+	XValidationName string = "XValidation" // A CEL rule, see https://kubernetes.io/docs/reference/using-api/cel/
 )
 
 /*
@@ -129,6 +137,17 @@ func MakeRequiredValidation() KubeBuilderValidation {
 	return KubeBuilderValidation{RequiredValidationName, nil}
 }
 
+// MakeXValidation returns a Validation that requires the CEL rule to be true.
+// message is optional; if omitted, the API server reports the rule itself when validation fails.
+func MakeXValidation(rule string, message string) KubeBuilderValidation {
+	value := fmt.Sprintf("rule=%q", rule)
+	if message != "" {
+		value += fmt.Sprintf(",message=%q", message)
+	}
+
+	return KubeBuilderValidation{XValidationName, value}
+}
+
 func MakeMinLengthValidation(length int64) KubeBuilderValidation {
 	return KubeBuilderValidation{MinLengthValidationName, length}
 }
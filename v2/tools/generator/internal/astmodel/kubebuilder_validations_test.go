@@ -30,3 +30,23 @@ func Test_ValidateEnum(t *testing.T) {
 
 	g.Expect(comment).To(Equal("// +kubebuilder:validation:Enum={1,true,hello}"))
 }
+
+func Test_ValidateXValidation(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	validation := MakeXValidation(`!has(self.tier) || self.tier == "Premium"`, "tier must be Premium")
+	comment := GenerateKubebuilderComment(validation)
+
+	g.Expect(comment).To(Equal(`// +kubebuilder:validation:XValidation:rule="!has(self.tier) || self.tier == \"Premium\"",message="tier must be Premium"`))
+}
+
+func Test_ValidateXValidation_WithoutMessage(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	validation := MakeXValidation("self.minimum <= self.maximum", "")
+	comment := GenerateKubebuilderComment(validation)
+
+	g.Expect(comment).To(Equal(`// +kubebuilder:validation:XValidation:rule="self.minimum <= self.maximum"`))
+}
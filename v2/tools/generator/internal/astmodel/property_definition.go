@@ -7,7 +7,9 @@ package astmodel
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/dave/dst"
@@ -49,8 +51,14 @@ type PropertyDefinition struct {
 	// originalName is the original name of this property, prior to any renames
 	originalName PropertyName
 
-	isSecret bool
-	readOnly bool
+	isSecret    bool
+	readOnly    bool
+	isImmutable bool // Create-only; may not be changed once the resource exists
+
+	defaultValue          any  // Default value given by the schema, if any; always a string, bool, or number
+	hasKubebuilderDefault bool // True if defaultValue should be emitted as a +kubebuilder:default marker
+
+	preserveUnknownFields bool // True if the CRD schema of this property is replaced by x-kubernetes-preserve-unknown-fields
 
 	tags readonly.Map[string, []string] // Note: have to be careful about not mutating inner []string
 }
@@ -156,6 +164,69 @@ func (property *PropertyDefinition) WithIsSecret(secret bool) *PropertyDefinitio
 	return result
 }
 
+// WithIsImmutable returns a new PropertyDefinition with IsImmutable set to the specified value
+func (property *PropertyDefinition) WithIsImmutable(immutable bool) *PropertyDefinition {
+	if immutable == property.isImmutable {
+		return property
+	}
+
+	result := property.copy()
+	result.isImmutable = immutable
+	return result
+}
+
+// WithDefaultValue returns a new PropertyDefinition with the specified default value
+func (property *PropertyDefinition) WithDefaultValue(value any) *PropertyDefinition {
+	if reflect.DeepEqual(value, property.defaultValue) {
+		return property
+	}
+
+	result := property.copy()
+	result.defaultValue = value
+	return result
+}
+
+// DefaultValue returns the default value of the property and true, or nil and false if it has no default.
+// This is the value Azure uses when the property is omitted.
+func (property *PropertyDefinition) DefaultValue() (any, bool) {
+	return property.defaultValue, property.defaultValue != nil
+}
+
+// WithKubebuilderDefault returns a new PropertyDefinition which emits (or not) its default value as a
+// +kubebuilder:default marker, so that the API server fills in the default when the property is omitted.
+func (property *PropertyDefinition) WithKubebuilderDefault(emit bool) *PropertyDefinition {
+	if emit == property.hasKubebuilderDefault {
+		return property
+	}
+
+	result := property.copy()
+	result.hasKubebuilderDefault = emit
+	return result
+}
+
+// HasKubebuilderDefault returns true iff the property has a default value emitted as a +kubebuilder:default marker.
+func (property *PropertyDefinition) HasKubebuilderDefault() bool {
+	return property.hasKubebuilderDefault && property.defaultValue != nil
+}
+
+// WithPreserveUnknownFields returns a new PropertyDefinition which omits (or not) the schema of its value from the
+// CRD, marking it with x-kubernetes-preserve-unknown-fields instead. This keeps large CRDs within the size limits of
+// the API server, at the cost of the API server no longer validating (or pruning) the value.
+func (property *PropertyDefinition) WithPreserveUnknownFields(preserve bool) *PropertyDefinition {
+	if preserve == property.preserveUnknownFields {
+		return property
+	}
+
+	result := property.copy()
+	result.preserveUnknownFields = preserve
+	return result
+}
+
+// PreservesUnknownFields returns true iff the schema of the property is omitted from the CRD.
+func (property *PropertyDefinition) PreservesUnknownFields() bool {
+	return property.preserveUnknownFields
+}
+
 // WithDescription returns a new PropertyDefinition with the specified description
 func (property *PropertyDefinition) WithDescription(description string) *PropertyDefinition {
 	if description == property.description {
@@ -388,6 +459,11 @@ func (property *PropertyDefinition) IsSecret() bool {
 	return property.isSecret
 }
 
+// IsImmutable returns true iff the property can only be set when the resource is created.
+func (property *PropertyDefinition) IsImmutable() bool {
+	return property.isImmutable
+}
+
 func (property *PropertyDefinition) renderedTags() string {
 	orderedKeys := property.tags.Keys()
 
@@ -423,11 +499,25 @@ func (property *PropertyDefinition) AsField(codeGenerationContext *CodeGeneratio
 		AddValidationComments(&doc, []KubeBuilderValidation{MakeRequiredValidation()})
 	}
 
+	if property.HasKubebuilderDefault() {
+		// not a doc comment, but must go here to be emitted before the property
+		astbuilder.AddComment(&doc, "// +kubebuilder:default="+renderKubebuilderDefault(property.defaultValue))
+	}
+
+	if property.preserveUnknownFields {
+		// not doc comments, but must go here to be emitted before the property
+		astbuilder.AddComment(&doc, "// +kubebuilder:validation:Schemaless")
+		astbuilder.AddComment(&doc, "// +kubebuilder:pruning:PreserveUnknownFields")
+	}
+
 	// if we have validations, unwrap them
 	propType := property.propertyType
 	if validated, ok := propType.(*ValidatedType); ok {
 		propType = validated.ElementType()
-		AddValidationComments(&doc, validated.Validations().ToKubeBuilderValidations())
+		if !property.preserveUnknownFields {
+			// Validations are part of the schema, so are omitted along with it
+			AddValidationComments(&doc, validated.Validations().ToKubeBuilderValidations())
+		}
 	}
 
 	// Some types opt out of codegen by returning nil
@@ -481,11 +571,28 @@ func (property *PropertyDefinition) Equals(o *PropertyDefinition, overrides Equa
 		property.flatten == o.flatten &&
 		propertyNameSlicesEqual(property.flattenedFrom, o.flattenedFrom) &&
 		property.isSecret == o.isSecret &&
+		property.isImmutable == o.isImmutable &&
 		property.tagsEqual(o) &&
 		property.hasKubebuilderRequiredValidation == o.hasKubebuilderRequiredValidation &&
+		property.hasKubebuilderDefault == o.hasKubebuilderDefault &&
+		property.preserveUnknownFields == o.preserveUnknownFields &&
+		reflect.DeepEqual(property.defaultValue, o.defaultValue) &&
 		property.description == o.description)
 }
 
+// renderKubebuilderDefault renders a default value in the form expected by controller-gen
+func renderKubebuilderDefault(value any) string {
+	switch v := value.(type) {
+	case string:
+		// Quoting ensures values such as "true" or "1" remain strings
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 func (property *PropertyDefinition) copy() *PropertyDefinition {
 	result := *property
 	return &result
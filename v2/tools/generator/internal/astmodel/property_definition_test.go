@@ -520,6 +520,63 @@ func Test_PropertyDefinitionAsAst_GivenValidField_ReturnsNonNilResult(t *testing
 	g.Expect(node).NotTo(BeNil())
 }
 
+func Test_PropertyDefinitionAsAst_GivenKubebuilderDefault_EmitsMarker(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"String", "Standard", `// +kubebuilder:default="Standard"`},
+		{"String looking like a bool", "true", `// +kubebuilder:default="true"`},
+		{"Bool", false, "// +kubebuilder:default=false"},
+		{"Integral number", float64(1024), "// +kubebuilder:default=1024"},
+		{"Fractional number", 0.5, "// +kubebuilder:default=0.5"},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			property := NewPropertyDefinition(propertyName, propertyJsonName, propertyType).
+				WithDefaultValue(c.value).
+				WithKubebuilderDefault(true)
+
+			node := property.AsField(nil)
+			g.Expect(node.Decs.Start.All()).To(ContainElement(c.expected))
+		})
+	}
+}
+
+func Test_PropertyDefinitionAsAst_GivenDefaultNotEmitted_OmitsMarker(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	property := NewPropertyDefinition(propertyName, propertyJsonName, propertyType).
+		WithDefaultValue("Standard")
+
+	node := property.AsField(nil)
+	g.Expect(node.Decs.Start.All()).To(BeEmpty())
+}
+
+func Test_PropertyDefinitionAsAst_GivenPreserveUnknownFields_EmitsMarkersInsteadOfValidations(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	maxLength := int64(10)
+	validated := NewValidatedType(StringType, StringValidations{MaxLength: &maxLength})
+	property := NewPropertyDefinition(propertyName, propertyJsonName, validated).
+		WithPreserveUnknownFields(true)
+
+	node := property.AsField(nil)
+	g.Expect(node.Decs.Start.All()).To(ConsistOf(
+		"// +kubebuilder:validation:Schemaless",
+		"// +kubebuilder:pruning:PreserveUnknownFields"))
+}
+
 /*
  * Equals Tests
  */
@@ -536,6 +593,7 @@ func TestPropertyDefinition_Equals_WhenGivenPropertyDefinition_ReturnsExpectedRe
 	differentType := createIntProperty("FullName", "Full Legal Name")
 	differentTags := createStringProperty("FullName", "Full Legal Name").WithTag("a", "b")
 	differentDescription := createStringProperty("FullName", "The whole thing")
+	differentDefault := createStringProperty("FullName", "Full Legal Name").WithDefaultValue("John Doe")
 
 	cases := []struct {
 		name          string
@@ -554,6 +612,7 @@ func TestPropertyDefinition_Equals_WhenGivenPropertyDefinition_ReturnsExpectedRe
 		{"Not-equal if types are different", strProperty, differentType, false},
 		{"Not-equal if descriptions are different", strProperty, differentDescription, false},
 		{"Not-equal if tags are different", strProperty, differentTags, false},
+		{"Not-equal if defaults are different", strProperty, differentDefault, false},
 	}
 
 	for _, c := range cases {
@@ -14,6 +14,7 @@ import (
 	"github.com/dave/dst"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/Azure/azure-service-operator/v2/internal/set"
@@ -41,6 +42,7 @@ const (
 	ResourceOperationHead   = ResourceOperation("HEAD")
 	ResourceOperationPut    = ResourceOperation("PUT")
 	ResourceOperationDelete = ResourceOperation("DELETE")
+	ResourceOperationPost   = ResourceOperation("POST")
 )
 
 // ResourceType represents a Kubernetes CRD resource which has both
@@ -315,6 +317,13 @@ func (resource *ResourceType) WithSupportedOperations(operations set.Set[Resourc
 	return result
 }
 
+// IsAction returns true if this resource represents an ARM action (invoked via POST) rather than a resource with a
+// lifecycle managed via PUT and DELETE
+func (resource *ResourceType) IsAction() bool {
+	return resource.supportedOperations.Contains(ResourceOperationPost) &&
+		!resource.supportedOperations.Contains(ResourceOperationPut)
+}
+
 // SupportedOperations gets the set of supported operations SupportedOperations
 func (resource *ResourceType) SupportedOperations() set.Set[ResourceOperation] {
 	return resource.supportedOperations
@@ -562,6 +571,11 @@ func (resource *ResourceType) WithAnnotation(annotation string) *ResourceType {
 	return result
 }
 
+// HasAnnotation returns true if the resource has the specified annotation
+func (resource *ResourceType) HasAnnotation(annotation string) bool {
+	return slices.Contains(resource.annotations, annotation)
+}
+
 // RequiredPackageReferences returns a list of packages required by this
 func (resource *ResourceType) RequiredPackageReferences() *PackageReferenceSet {
 	references := NewPackageReferenceSet(MetaV1Reference)
@@ -20,6 +20,7 @@ type TypeDefinition struct {
 	name        InternalTypeName
 	description []string
 	theType     Type
+	validations []KubeBuilderValidation // Validations of the whole type, such as CEL rules
 }
 
 func MakeTypeDefinition(name InternalTypeName, theType Type) TypeDefinition {
@@ -59,6 +60,28 @@ func (def TypeDefinition) WithDescription(desc ...string) TypeDefinition {
 	return def
 }
 
+// Validations returns the validations applied to the type as a whole
+// We return a new slice to preserve immutability
+func (def TypeDefinition) Validations() []KubeBuilderValidation {
+	var result []KubeBuilderValidation
+	result = append(result, def.validations...)
+	return result
+}
+
+// WithValidations returns an updated TypeDefinition with the specified validations added
+func (def TypeDefinition) WithValidations(validations ...KubeBuilderValidation) TypeDefinition {
+	result := def
+	result.validations = append(def.Validations(), validations...)
+	return result
+}
+
+// WithoutValidations returns an updated TypeDefinition with all type level validations removed
+func (def TypeDefinition) WithoutValidations() TypeDefinition {
+	result := def
+	result.validations = nil
+	return result
+}
+
 // WithType returns an updated TypeDefinition with the specified type
 func (def TypeDefinition) WithType(t Type) TypeDefinition {
 	result := def
@@ -77,6 +100,7 @@ func (def TypeDefinition) AsDeclarations(codeGenerationContext *CodeGenerationCo
 	declContext := DeclarationContext{
 		Name:        def.name,
 		Description: def.description,
+		Validations: def.Validations(),
 	}
 
 	return def.theType.AsDeclarations(codeGenerationContext, declContext)
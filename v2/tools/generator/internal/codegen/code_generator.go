@@ -13,10 +13,12 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 
 	"github.com/Azure/azure-service-operator/v2/internal/version"
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/incremental"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/pipeline"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
 )
@@ -24,8 +26,10 @@ import (
 // CodeGenerator is a generator of code
 type CodeGenerator struct {
 	configuration *config.Configuration
+	idFactory     astmodel.IdentifierFactory
 	pipeline      []*pipeline.Stage
 	debugReporter *debugReporter
+	cache         *incremental.Cache
 }
 
 // NewCodeGeneratorFromConfigFile produces a new Generator with the given configuration file
@@ -89,6 +93,7 @@ func NewCodeGeneratorFromConfig(
 ) (*CodeGenerator, error) {
 	result := &CodeGenerator{
 		configuration: configuration,
+		idFactory:     idFactory,
 		pipeline:      createAllPipelineStages(idFactory, configuration, log),
 	}
 
@@ -107,6 +112,7 @@ func createAllPipelineStages(
 	return []*pipeline.Stage{
 		// Import Swagger data:
 		pipeline.LoadTypes(idFactory, configuration, log),
+		pipeline.CatalogGroupDependencies(),
 
 		// Assemble actual one-of types from roots and leaves
 		pipeline.AssembleOneOfTypes(idFactory),
@@ -134,6 +140,7 @@ func createAllPipelineStages(
 		pipeline.ApplyTypeRewrites(configuration, log),
 
 		pipeline.ApplyIsResourceOverrides(configuration),
+		pipeline.ApplyExtensibleEnumOverrides(configuration),
 		pipeline.FixIDFields(),
 
 		pipeline.UnrollRecursiveTypes(log),
@@ -148,7 +155,11 @@ func createAllPipelineStages(
 		pipeline.StripUnreferencedTypeDefinitions(),
 		pipeline.AssertTypesCollectionValid(),
 
-		pipeline.RemoveEmbeddedResources(configuration, log).UsedFor(pipeline.ARMTarget),
+		pipeline.RemoveEmbeddedResources(configuration, log).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+
+		// Once cross group references are collapsed, groups loaded only because selected groups use them are no
+		// longer needed
+		pipeline.RemoveUnselectedGroups(configuration),
 
 		// Apply export filters before generating
 		// ARM types for resources etc:
@@ -160,6 +171,7 @@ func createAllPipelineStages(
 		pipeline.MakeStatusPropertiesOptional(),
 		pipeline.TransformValidatedFloats(),
 		pipeline.AddLocatableInterface(idFactory),
+		pipeline.AddDataPlaneInterface(configuration, idFactory).UsedFor(pipeline.DataPlaneTarget),
 
 		// This is currently also run as part of RemoveEmbeddedResources and so is technically not needed here,
 		// but we include it to hedge against future changes
@@ -174,19 +186,21 @@ func createAllPipelineStages(
 
 		pipeline.FixOptionalCollectionAliases(),
 
-		pipeline.ApplyCrossResourceReferencesFromConfig(configuration, log).UsedFor(pipeline.ARMTarget),
-		pipeline.TransformCrossResourceReferences(configuration, idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.ApplyCrossResourceReferencesFromConfig(configuration, log).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.TransformCrossResourceReferences(configuration, idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 		pipeline.TransformCrossResourceReferencesToString().UsedFor(pipeline.CrossplaneTarget),
-		pipeline.AddSecrets(configuration).UsedFor(pipeline.ARMTarget),
-		pipeline.AddConfigMaps(configuration).UsedFor(pipeline.ARMTarget),
+		pipeline.AddSecrets(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.AddConfigMaps(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.ApplyImmutableOverrides(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.EmitKubebuilderDefaults(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
 		pipeline.ReportOnTypesAndVersions(configuration).UsedFor(pipeline.ARMTarget), // TODO: For now only used for ARM
 
-		pipeline.CreateARMTypes(configuration.ObjectModelConfiguration, idFactory, log).UsedFor(pipeline.ARMTarget),
-		pipeline.PruneResourcesWithLifecycleOwnedByParent(configuration).UsedFor(pipeline.ARMTarget),
-		pipeline.MakeOneOfDiscriminantRequired().UsedFor(pipeline.ARMTarget),
-		pipeline.ApplyARMConversionInterface(idFactory, configuration.ObjectModelConfiguration).UsedFor(pipeline.ARMTarget),
-		pipeline.ApplyKubernetesResourceInterface(idFactory, log).UsedFor(pipeline.ARMTarget),
+		pipeline.CreateARMTypes(configuration.ObjectModelConfiguration, idFactory, log).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.PruneResourcesWithLifecycleOwnedByParent(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.MakeOneOfDiscriminantRequired().UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.ApplyARMConversionInterface(idFactory, configuration.ObjectModelConfiguration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.ApplyKubernetesResourceInterface(idFactory, log).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
 		// Effects the "flatten" property of Properties:
 		pipeline.FlattenProperties(log),
@@ -195,14 +209,15 @@ func createAllPipelineStages(
 		pipeline.StripUnreferencedTypeDefinitions(),
 
 		pipeline.RenameProperties(configuration.ObjectModelConfiguration),
-		pipeline.AddStatusConditions(idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.AddStatusConditions(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
-		pipeline.AddOperatorSpec(configuration, idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.AddOperatorSpec(configuration, idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.AddValidationRules(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 		// To be added when needed
 		// pipeline.AddOperatorStatus(idFactory).UsedFor(pipeline.ARMTarget),
 
-		pipeline.AddKubernetesExporter(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.ApplyDefaulterAndValidatorInterfaces(configuration, idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.AddKubernetesExporter(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.ApplyDefaulterAndValidatorInterfaces(configuration, idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
 		pipeline.AddCrossplaneOwnerProperties(idFactory).UsedFor(pipeline.CrossplaneTarget),
 		pipeline.AddCrossplaneForProvider(idFactory).UsedFor(pipeline.CrossplaneTarget),
@@ -212,42 +227,50 @@ func createAllPipelineStages(
 
 		// Create Storage types
 		// TODO: For now only used for ARM
-		pipeline.InjectOriginalVersionFunction(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.CreateStorageTypes().UsedFor(pipeline.ARMTarget),
-		pipeline.CreateConversionGraph(configuration, astmodel.GeneratorVersion).UsedFor(pipeline.ARMTarget),
-		pipeline.InjectOriginalVersionProperty().UsedFor(pipeline.ARMTarget),
-		pipeline.InjectPropertyAssignmentFunctions(configuration, idFactory, log).UsedFor(pipeline.ARMTarget),
-		pipeline.ImplementConvertibleSpecInterface(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.ImplementConvertibleStatusInterface(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.InjectOriginalGVKFunction(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.InjectSpecInitializationFunctions(configuration, idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.InjectOriginalVersionFunction(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.CreateStorageTypes().UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.CreateConversionGraph(configuration, astmodel.GeneratorVersion).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.InjectOriginalVersionProperty().UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.InjectPropertyAssignmentFunctions(configuration, idFactory, log).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.ImplementConvertibleSpecInterface(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.ImplementConvertibleStatusInterface(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.InjectOriginalGVKFunction(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.InjectSpecInitializationFunctions(configuration, idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 		pipeline.ImplementImportableResourceInterface(configuration, idFactory).UsedFor(pipeline.ARMTarget),
 
-		pipeline.MarkLatestStorageVariantAsHubVersion().UsedFor(pipeline.ARMTarget),
+		pipeline.MarkLatestStorageVariantAsHubVersion().UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 		pipeline.MarkLatestAPIVersionAsStorageVersion().UsedFor(pipeline.CrossplaneTarget),
 
-		pipeline.InjectHubFunction(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.ImplementConvertibleInterface(idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.InjectHubFunction(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.ImplementConvertibleInterface(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
 		// Inject test cases
-		pipeline.InjectJsonSerializationTests(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.InjectPropertyAssignmentTests(idFactory).UsedFor(pipeline.ARMTarget),
-		pipeline.InjectResourceConversionTestCases(idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.InjectJsonSerializationTests(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.InjectPropertyAssignmentTests(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.InjectResourceConversionTestCases(idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
 		pipeline.SimplifyDefinitions(),
 
 		// Create Resource Extensions
-		pipeline.CreateResourceExtensions(configuration.LocalPathPrefix(), idFactory).UsedFor(pipeline.ARMTarget),
+		pipeline.CreateResourceExtensions(configuration.LocalPathPrefix(), idFactory).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
 		// Safety checks at the end:
 		pipeline.EnsureDefinitionsDoNotUseAnyTypes(),
-		pipeline.EnsureARMTypeExistsForEveryResource().UsedFor(pipeline.ARMTarget),
-		pipeline.DetectSkippingProperties().UsedFor(pipeline.ARMTarget),
+		pipeline.EnsureARMTypeExistsForEveryResource().UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.DetectSkippingProperties().UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+
+		// Keep CRDs within the size limits of the API server
+		pipeline.PruneCRDSchemas(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
+		pipeline.CheckCRDSchemaSizes(configuration).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
-		pipeline.DeleteGeneratedCode(configuration.FullTypesOutputPath()),
+		pipeline.DeleteGeneratedCode(configuration.FullTypesOutputPath(), configuration),
 		pipeline.ExportPackages(configuration.FullTypesOutputPath(), configuration.EmitDocFiles, log),
+		pipeline.ExportTypesAndVersionsReport(configuration).UsedFor(pipeline.ARMTarget),
+		pipeline.ExportJSONSchemas(configuration, log).UsedFor(pipeline.ARMTarget, pipeline.DataPlaneTarget),
 
-		pipeline.ExportControllerResourceRegistrations(idFactory, configuration.FullTypesRegistrationOutputFilePath()).UsedFor(pipeline.ARMTarget),
+		// Data plane resources are generated by a separate run, so are registered in a separate file
+		pipeline.ExportControllerResourceRegistrations(idFactory, configuration, "").UsedFor(pipeline.ARMTarget),
+		pipeline.ExportControllerResourceRegistrations(idFactory, configuration, "DataPlane").UsedFor(pipeline.DataPlaneTarget),
 
 		pipeline.ReportResourceVersions(configuration),
 		pipeline.ReportResourceStructure(configuration),
@@ -275,16 +298,75 @@ func (generator *CodeGenerator) Generate(
 		}
 	}
 
+	var fingerprints incremental.Fingerprints
+	if generator.cache != nil {
+		var err error
+		fingerprints, err = generator.computeFingerprints()
+		if err != nil {
+			return err
+		}
+	}
+
+	state, err := generator.executeStages(ctx, generator.pipeline, log)
+	if err != nil {
+		return err
+	}
+
+	if err := state.CheckFinalState(); err != nil {
+		return err
+	}
+
+	if generator.cache != nil {
+		err = generator.updateCache(state, fingerprints, maps.Keys(fingerprints), log)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("Finished")
+
+	return nil
+}
+
+// GenerateUntil runs the pipeline up to and including the first stage with the specified id, returning the state
+// at that point. This allows tools to inspect the generated types without exporting any code.
+// ctx is used to cancel the generation process.
+// stageID identifies the last stage to run.
+// log is used to log progress.
+func (generator *CodeGenerator) GenerateUntil(
+	ctx context.Context,
+	stageID string,
+	log logr.Logger,
+) (*pipeline.State, error) {
+	index := generator.IndexOfStage(stageID)
+	if index == -1 {
+		return nil, errors.Errorf("stage %s not found in pipeline", stageID)
+	}
+
+	return generator.executeStages(ctx, generator.pipeline[:index+1], log)
+}
+
+// Configuration returns the configuration used by the generator
+func (generator *CodeGenerator) Configuration() *config.Configuration {
+	return generator.configuration
+}
+
+// executeStages runs the specified stages in order, returning the final state
+func (generator *CodeGenerator) executeStages(
+	ctx context.Context,
+	stages []*pipeline.Stage,
+	log logr.Logger,
+) (*pipeline.State, error) {
 	state := pipeline.NewState()
-	for i, stage := range generator.pipeline {
+	for i, stage := range stages {
 		stageNumber := i + 1
-		stageDescription := fmt.Sprintf("%d/%d: %s", stageNumber, len(generator.pipeline), stage.Description())
+		stageDescription := fmt.Sprintf("%d/%d: %s", stageNumber, len(stages), stage.Description())
 		log.Info(stageDescription)
 		start := time.Now()
 
 		newState, err := generator.executeStage(ctx, stageNumber, stage, state)
 		if err != nil {
-			return errors.Wrapf(err, "failed to execute stage %d: %s", stageNumber, stage.Description())
+			return nil, errors.Wrapf(err, "failed to execute stage %d: %s", stageNumber, stage.Description())
 		}
 
 		duration := time.Since(start).Round(time.Millisecond)
@@ -302,13 +384,7 @@ func (generator *CodeGenerator) Generate(
 		state = newState
 	}
 
-	if err := state.CheckFinalState(); err != nil {
-		return err
-	}
-
-	log.Info("Finished")
-
-	return nil
+	return state, nil
 }
 
 // executeStage runs the given stage against the given state, returning the new state.
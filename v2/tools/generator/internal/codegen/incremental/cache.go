@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package incremental
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Cache is a content-addressed store of the results of generating each group, held in a folder on disk.
+// Entries are keyed by the fingerprint of the inputs used to generate the group; we also track the latest entry for
+// each group, as this tells us how the group was most recently generated.
+type Cache struct {
+	folder string
+}
+
+// Entry records the result of generating a single group
+type Entry struct {
+	Group        string            `json:"group"`
+	Fingerprint  string            `json:"fingerprint"`            // Fingerprint of the inputs used to generate the group
+	Dependencies map[string]string `json:"dependencies,omitempty"` // Fingerprints of the groups used, keyed by group
+	Outputs      map[string]string `json:"outputs,omitempty"`      // Hashes of the files in the group folder, keyed by relative path
+	SharedOutput string            `json:"sharedOutput,omitempty"` // Fingerprint of content contributed to outputs shared by every group
+}
+
+// NewCache returns a cache stored in the specified folder
+func NewCache(folder string) *Cache {
+	return &Cache{
+		folder: folder,
+	}
+}
+
+// Folder returns the folder containing the cache
+func (c *Cache) Folder() string {
+	return c.folder
+}
+
+// Lookup returns the entry for a group generated from inputs with the specified fingerprint, or nil if not found
+func (c *Cache) Lookup(fingerprint string) (*Entry, error) {
+	return c.load(c.entryPath(fingerprint))
+}
+
+// Latest returns the entry for the most recent generation of the specified group, or nil if not found
+func (c *Cache) Latest(group string) (*Entry, error) {
+	return c.load(c.latestPath(group))
+}
+
+// Save stores the entry, also recording it as the latest for its group
+func (c *Cache) Save(entry *Entry) error {
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "serializing cache entry for group %s", entry.Group)
+	}
+
+	for _, path := range []string{c.entryPath(entry.Fingerprint), c.latestPath(entry.Group)} {
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+		if err != nil {
+			return errors.Wrapf(err, "creating cache folder for %q", path)
+		}
+
+		err = os.WriteFile(path, content, 0o600)
+		if err != nil {
+			return errors.Wrapf(err, "writing cache entry %q", path)
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) load(path string) (*Entry, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading cache entry %q", path)
+	}
+
+	var result Entry
+	err = json.Unmarshal(content, &result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing cache entry %q", path)
+	}
+
+	return &result, nil
+}
+
+func (c *Cache) entryPath(fingerprint string) string {
+	return filepath.Join(c.folder, "entries", fingerprint+".json")
+}
+
+func (c *Cache) latestPath(group string) string {
+	return filepath.Join(c.folder, "groups", strings.ToLower(group)+".json")
+}
+
+// HashOutputs returns a hash of each file found in the specified folder, keyed by relative path.
+// Returns an empty map if the folder does not exist.
+func HashOutputs(folder string) (map[string]string, error) {
+	result := make(map[string]string)
+	err := filepath.WalkDir(
+		folder,
+		func(path string, entry fs.DirEntry, err error) error {
+			if os.IsNotExist(err) && path == folder {
+				return filepath.SkipDir
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(folder, path)
+			if err != nil {
+				return err
+			}
+
+			result[filepath.ToSlash(rel)], err = HashFile(path)
+			return err
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "hashing files in %q", folder)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package incremental
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// objectModelConfigurationKey is the key used for per-group configuration in our configuration file
+const objectModelConfigurationKey = "objectModelConfiguration"
+
+// Fingerprints maps each group to a hash of all the inputs used to generate it
+type Fingerprints map[string]string
+
+// ComputeFingerprints calculates a fingerprint for each group, capturing everything used to generate it.
+// generator identifies the build of the generator in use, so that changes to the generator invalidate the cache.
+// configurationFile is the path to our configuration; changes to the configuration of a group only affect that group.
+// groupFiles lists the Swagger files for each group.
+// otherFiles lists Swagger files that might be referenced by any group.
+func ComputeFingerprints(
+	generator string,
+	configurationFile string,
+	groupFiles map[string][]string,
+	otherFiles []string,
+) (Fingerprints, error) {
+	sharedConfig, groupConfig, err := splitConfiguration(configurationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inputs shared by every group are hashed once, then included in the fingerprint of each group
+	shared := sha256.New()
+	writeString(shared, generator)
+	shared.Write(sharedConfig)
+	err = hashFiles(shared, otherFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedHash := hex.EncodeToString(shared.Sum(nil))
+
+	result := make(Fingerprints, len(groupFiles))
+	for group, files := range groupFiles {
+		h := sha256.New()
+		writeString(h, sharedHash)
+		writeString(h, group)
+		h.Write(groupConfig[strings.ToLower(group)])
+		err = hashFiles(h, files)
+		if err != nil {
+			return nil, errors.Wrapf(err, "computing fingerprint for group %s", group)
+		}
+
+		result[group] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	return result, nil
+}
+
+// GeneratorFingerprint returns a hash of the running executable, identifying the build of the generator in use
+func GeneratorFingerprint() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", errors.Wrap(err, "finding generator executable")
+	}
+
+	h := sha256.New()
+	err = hashFiles(h, []string{exe})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFile returns a hash of the content of the specified file
+func HashFile(path string) (string, error) {
+	h := sha256.New()
+	err := hashFile(h, path)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// splitConfiguration reads our configuration file, returning the YAML for each group (keyed by lowercase group name)
+// separately from the YAML for everything else.
+func splitConfiguration(configurationFile string) ([]byte, map[string][]byte, error) {
+	content, err := os.ReadFile(configurationFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading configuration from %q", configurationFile)
+	}
+
+	var document yaml.Node
+	err = yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "configuration file loaded from %q is not valid YAML", configurationFile)
+	}
+
+	groups := make(map[string][]byte)
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		// Nothing to split out
+		return content, groups, nil
+	}
+
+	root := document.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != objectModelConfigurationKey {
+			continue
+		}
+
+		omc := root.Content[i+1]
+		for j := 0; j+1 < len(omc.Content); j += 2 {
+			group := strings.ToLower(omc.Content[j].Value)
+			groups[group], err = yaml.Marshal(omc.Content[j+1])
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "extracting configuration for group %s", group)
+			}
+		}
+
+		// Remove per-group configuration from the shared configuration
+		root.Content = append(root.Content[:i:i], root.Content[i+2:]...)
+		break
+	}
+
+	shared, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "extracting shared configuration")
+	}
+
+	return shared, groups, nil
+}
+
+// hashFiles writes the path and content of each file into the hash, in a stable order
+func hashFiles(h hash.Hash, files []string) error {
+	sorted := make([]string, len(files))
+	copy(sorted, files)
+	sort.Strings(sorted)
+
+	for _, f := range sorted {
+		writeString(h, f)
+		err := hashFile(h, f)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hashFile(h hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "opening %q", path)
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(h, f)
+	return errors.Wrapf(err, "reading %q", path)
+}
+
+// writeString writes a terminated string into the hash, so adjacent values can't run together
+func writeString(h hash.Hash, s string) {
+	_, _ = io.WriteString(h, s)
+	_, _ = h.Write([]byte{0})
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package incremental
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const testConfiguration = `
+schemaRoot: ./specs
+objectModelConfiguration:
+  compute:
+    2021-01-01:
+      VirtualMachine:
+        $export: true
+  network:
+    2021-01-01:
+      VirtualNetwork:
+        $export: true
+`
+
+func TestComputeFingerprints_WhenGroupConfigurationChanges_OnlyThatGroupChanges(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	folder := t.TempDir()
+	configFile := writeTestFile(t, folder, "config.yaml", testConfiguration)
+	groupFiles := map[string][]string{
+		"compute": {writeTestFile(t, folder, "compute.json", "{}")},
+		"network": {writeTestFile(t, folder, "network.json", "{}")},
+	}
+
+	before, err := ComputeFingerprints("generator", configFile, groupFiles, nil)
+	g.Expect(err).To(Succeed())
+
+	writeTestFile(t, folder, "config.yaml", testConfiguration+"        $supportedFrom: v2.0.0\n")
+
+	after, err := ComputeFingerprints("generator", configFile, groupFiles, nil)
+	g.Expect(err).To(Succeed())
+
+	g.Expect(after["compute"]).To(Equal(before["compute"]))
+	g.Expect(after["network"]).NotTo(Equal(before["network"]))
+}
+
+func TestComputeFingerprints_WhenSharedInputsChange_AllGroupsChange(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	folder := t.TempDir()
+	configFile := writeTestFile(t, folder, "config.yaml", testConfiguration)
+	groupFiles := map[string][]string{
+		"compute": {writeTestFile(t, folder, "compute.json", "{}")},
+		"network": {writeTestFile(t, folder, "network.json", "{}")},
+	}
+
+	otherFiles := []string{writeTestFile(t, folder, "common.json", "{}")}
+
+	before, err := ComputeFingerprints("generator", configFile, groupFiles, otherFiles)
+	g.Expect(err).To(Succeed())
+
+	writeTestFile(t, folder, "common.json", `{"definitions": {}}`)
+
+	after, err := ComputeFingerprints("generator", configFile, groupFiles, otherFiles)
+	g.Expect(err).To(Succeed())
+
+	g.Expect(after["compute"]).NotTo(Equal(before["compute"]))
+	g.Expect(after["network"]).NotTo(Equal(before["network"]))
+}
+
+func writeTestFile(t *testing.T, folder string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(folder, name)
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write %s: %s", path, err)
+	}
+
+	return path
+}
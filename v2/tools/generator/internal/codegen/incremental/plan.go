@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package incremental
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/util/typo"
+)
+
+// Plan identifies the groups to regenerate
+type Plan struct {
+	Emitted []string // Groups to regenerate
+	Loaded  []string // Groups to load, including both those to regenerate and any groups they use
+}
+
+// IsEmpty returns true if there's nothing to regenerate
+func (p *Plan) IsEmpty() bool {
+	return len(p.Emitted) == 0
+}
+
+// PlanGeneration works out which groups need regenerating: each requested group that has changed since it was last
+// generated (its inputs differ, or its output has been modified), plus any groups depending on those.
+// requested is the set of groups requested by the user.
+// fingerprints is the current fingerprint of each group.
+// outputFolder is the folder containing the generated code for all groups.
+func (c *Cache) PlanGeneration(
+	requested []string,
+	fingerprints Fingerprints,
+	outputFolder string,
+) (*Plan, error) {
+	advisor := typo.NewAdvisor()
+	for group := range fingerprints {
+		advisor.AddTerm(group)
+	}
+
+	// Work out which requested groups have changed
+	emitted := set.Make[string]()
+	for _, g := range requested {
+		group := strings.ToLower(g)
+		if _, ok := fingerprints[group]; !ok {
+			return nil, advisor.Errorf(group, "no Swagger found for group %s", group)
+		}
+
+		changed, err := c.hasChanged(group, fingerprints, outputFolder)
+		if err != nil {
+			return nil, err
+		}
+
+		if changed {
+			emitted.Add(group)
+		}
+	}
+
+	if len(emitted) == 0 {
+		return &Plan{}, nil
+	}
+
+	// Find the latest dependencies of each group, so we can find those depending on changed groups. Groups never
+	// generated have unknown dependencies, so must be regenerated; as must any group if the dependencies of a
+	// changed group are unknown, as it might need any other group loaded.
+	dependencies := make(map[string]set.Set[string], len(fingerprints))
+	for group := range fingerprints {
+		latest, err := c.Latest(group)
+		if err != nil {
+			return nil, err
+		}
+
+		if latest == nil {
+			emitted.Add(group)
+			continue
+		}
+
+		dependencies[group] = set.Make(maps.Keys(latest.Dependencies)...)
+	}
+
+	// Add dependents of changed groups, until there are no more
+	for {
+		added := false
+		for group, deps := range dependencies {
+			if emitted.Contains(group) {
+				continue
+			}
+
+			for dep := range deps {
+				if emitted.Contains(dep) {
+					emitted.Add(group)
+					added = true
+					break
+				}
+			}
+		}
+
+		if !added {
+			break
+		}
+	}
+
+	// Load the changed groups, plus all the groups they use
+	loaded := set.Make[string]()
+	queue := set.AsSortedSlice(emitted)
+	for len(queue) > 0 {
+		group := queue[0]
+		queue = queue[1:]
+		if loaded.Contains(group) {
+			continue
+		}
+
+		loaded.Add(group)
+		deps, ok := dependencies[group]
+		if !ok {
+			// Dependencies unknown, so we must load everything
+			loaded = set.Make(maps.Keys(fingerprints)...)
+			break
+		}
+
+		queue = append(queue, set.AsSortedSlice(deps)...)
+	}
+
+	return &Plan{
+		Emitted: set.AsSortedSlice(emitted),
+		Loaded:  set.AsSortedSlice(loaded),
+	}, nil
+}
+
+// hasChanged returns true if the group must be regenerated, either because it (or a group it uses) has different
+// inputs, or because its outputs have been modified since it was generated.
+func (c *Cache) hasChanged(group string, fingerprints Fingerprints, outputFolder string) (bool, error) {
+	entry, err := c.Lookup(fingerprints[group])
+	if err != nil {
+		return false, err
+	}
+
+	if entry == nil {
+		return true, nil
+	}
+
+	for dep, fingerprint := range entry.Dependencies {
+		if fingerprints[dep] != fingerprint {
+			return true, nil
+		}
+	}
+
+	outputs, err := HashOutputs(filepath.Join(outputFolder, group))
+	if err != nil {
+		return false, errors.Wrapf(err, "checking outputs of group %s", group)
+	}
+
+	return !maps.Equal(outputs, entry.Outputs), nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package incremental
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// newTestCache returns a cache where network depends on compute, and storage depends on nothing, all with outputs in
+// the returned output folder
+func newTestCache(t *testing.T) (*Cache, Fingerprints, string) {
+	t.Helper()
+	g := NewGomegaWithT(t)
+
+	outputFolder := t.TempDir()
+	cache := NewCache(t.TempDir())
+	fingerprints := Fingerprints{
+		"compute": "c1",
+		"network": "n1",
+		"storage": "s1",
+	}
+
+	dependencies := map[string]map[string]string{
+		"network": {"compute": "c1"},
+	}
+
+	for group, fingerprint := range fingerprints {
+		g.Expect(os.MkdirAll(filepath.Join(outputFolder, group), 0o700)).To(Succeed())
+		writeTestFile(t, filepath.Join(outputFolder, group), "types_gen.go", "package "+group)
+
+		outputs, err := HashOutputs(filepath.Join(outputFolder, group))
+		g.Expect(err).To(Succeed())
+
+		g.Expect(cache.Save(&Entry{
+			Group:        group,
+			Fingerprint:  fingerprint,
+			Dependencies: dependencies[group],
+			Outputs:      outputs,
+		})).To(Succeed())
+	}
+
+	return cache, fingerprints, outputFolder
+}
+
+func TestPlanGeneration_WhenNothingChanged_ReturnsEmptyPlan(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cache, fingerprints, outputFolder := newTestCache(t)
+
+	plan, err := cache.PlanGeneration([]string{"compute", "storage"}, fingerprints, outputFolder)
+	g.Expect(err).To(Succeed())
+	g.Expect(plan.IsEmpty()).To(BeTrue())
+}
+
+func TestPlanGeneration_WhenGroupChanged_IncludesDependents(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cache, fingerprints, outputFolder := newTestCache(t)
+	fingerprints["compute"] = "c2"
+
+	plan, err := cache.PlanGeneration([]string{"compute"}, fingerprints, outputFolder)
+	g.Expect(err).To(Succeed())
+	g.Expect(plan.Emitted).To(Equal([]string{"compute", "network"}))
+	g.Expect(plan.Loaded).To(Equal([]string{"compute", "network"}))
+}
+
+func TestPlanGeneration_WhenDependencyChanged_IncludesDependencyInLoaded(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cache, fingerprints, outputFolder := newTestCache(t)
+	fingerprints["compute"] = "c2"
+
+	plan, err := cache.PlanGeneration([]string{"network"}, fingerprints, outputFolder)
+	g.Expect(err).To(Succeed())
+	g.Expect(plan.Emitted).To(Equal([]string{"network"}))
+	g.Expect(plan.Loaded).To(Equal([]string{"compute", "network"}))
+}
+
+func TestPlanGeneration_WhenOutputModified_RegeneratesGroup(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cache, fingerprints, outputFolder := newTestCache(t)
+	writeTestFile(t, filepath.Join(outputFolder, "storage"), "types_gen.go", "package modified")
+
+	plan, err := cache.PlanGeneration([]string{"storage"}, fingerprints, outputFolder)
+	g.Expect(err).To(Succeed())
+	g.Expect(plan.Emitted).To(Equal([]string{"storage"}))
+	g.Expect(plan.Loaded).To(Equal([]string{"storage"}))
+}
+
+func TestPlanGeneration_WhenGroupNeverGenerated_LoadsEverything(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cache, fingerprints, outputFolder := newTestCache(t)
+	fingerprints["batch"] = "b1"
+	fingerprints["storage"] = "s2"
+
+	plan, err := cache.PlanGeneration([]string{"storage"}, fingerprints, outputFolder)
+	g.Expect(err).To(Succeed())
+	g.Expect(plan.Emitted).To(Equal([]string{"batch", "storage"}))
+	g.Expect(plan.Loaded).To(Equal([]string{"batch", "compute", "network", "storage"}))
+}
+
+func TestPlanGeneration_WhenGroupUnknown_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cache, fingerprints, outputFolder := newTestCache(t)
+
+	_, err := cache.PlanGeneration([]string{"stroage"}, fingerprints, outputFolder)
+	g.Expect(err).To(MatchError(ContainSubstring("did you mean storage")))
+}
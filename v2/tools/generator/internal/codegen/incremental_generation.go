@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package codegen
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/version"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/incremental"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/pipeline"
+)
+
+// UseCache configures the generator to record the result of generating each group in a cache held in the specified
+// folder, allowing later runs to regenerate only the groups that have changed. Entries are keyed by a fingerprint of
+// the generator build, the configuration and the Swagger used, so a cache may be shared between output folders.
+func (generator *CodeGenerator) UseCache(folder string) error {
+	if folder == "" {
+		return errors.New("a folder is required for the generation cache")
+	}
+
+	generator.cache = incremental.NewCache(folder)
+	return nil
+}
+
+// GenerateGroups regenerates the specified groups if they have changed since they were last generated, along with
+// any groups that depend on them. Groups they use are loaded too, but only until references between groups have been
+// collapsed, after which they're discarded. Code for all other groups is left untouched, as are the outputs shared by all
+// groups; if the changes require those shared outputs to be updated, an error is returned, and a full run is required.
+// Requires a cache; see UseCache().
+// ctx is used to cancel the generation process.
+// groups is the set of groups requested.
+// log is used to log progress.
+func (generator *CodeGenerator) GenerateGroups(
+	ctx context.Context,
+	groups []string,
+	log logr.Logger,
+) error {
+	if generator.cache == nil {
+		return errors.New("a cache is required to generate selected groups")
+	}
+
+	log.V(1).Info(
+		"ASO Code Generator",
+		"version", version.BuildVersion,
+		"cache", generator.cache.Folder())
+
+	fingerprints, err := generator.computeFingerprints()
+	if err != nil {
+		return err
+	}
+
+	plan, err := generator.cache.PlanGeneration(groups, fingerprints, generator.configuration.FullTypesOutputPath())
+	if err != nil {
+		return err
+	}
+
+	if plan.IsEmpty() {
+		log.Info("Requested groups are up to date", "groups", groups)
+		return nil
+	}
+
+	log.Info(
+		"Regenerating groups",
+		"groups", plan.Emitted,
+		"loading", plan.Loaded)
+
+	generator.configuration.SelectGroups(plan.Emitted, plan.Loaded)
+
+	// Check the shared outputs are unaffected before we start writing any files, so a failed run leaves the
+	// existing code untouched
+	stages, err := generator.withStage(
+		pipeline.DeleteGeneratedCodeStageID,
+		generator.checkSharedOutputsUnchanged(plan.Emitted))
+	if err != nil {
+		return err
+	}
+
+	state, err := generator.executeStages(ctx, stages, log)
+	if err != nil {
+		return err
+	}
+
+	if err := state.CheckFinalState(); err != nil {
+		return err
+	}
+
+	err = generator.updateCache(state, fingerprints, plan.Emitted, log)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished")
+
+	return nil
+}
+
+// computeFingerprints finds the current fingerprint for every group
+func (generator *CodeGenerator) computeFingerprints() (incremental.Fingerprints, error) {
+	groupFiles, otherFiles, err := pipeline.FindSchemaFiles(generator.configuration, generator.idFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	generatorFingerprint, err := incremental.GeneratorFingerprint()
+	if err != nil {
+		return nil, err
+	}
+
+	return incremental.ComputeFingerprints(
+		generatorFingerprint,
+		generator.configuration.ConfigurationFilePath(),
+		groupFiles,
+		otherFiles)
+}
+
+// checkSharedOutputsUnchangedStageID is the unique identifier for this pipeline stage
+const checkSharedOutputsUnchangedStageID = "checkSharedOutputsUnchanged"
+
+// checkSharedOutputsUnchanged creates a pipeline stage verifying that the content the specified groups contribute
+// to the outputs shared by all groups is unchanged since the groups were last generated. Those outputs aren't
+// written when only some groups are generated, so any change requires a full run.
+func (generator *CodeGenerator) checkSharedOutputsUnchanged(groups []string) *pipeline.Stage {
+	return pipeline.NewStage(
+		checkSharedOutputsUnchangedStageID,
+		"Check outputs shared by all groups don't need updating",
+		func(ctx context.Context, state *pipeline.State) (*pipeline.State, error) {
+			shared, err := pipeline.SharedOutputFingerprints(state.Definitions(), generator.idFactory, generator.configuration)
+			if err != nil {
+				return nil, errors.Wrap(err, "computing fingerprints of shared outputs")
+			}
+
+			var stale []string
+			for _, group := range groups {
+				latest, err := generator.cache.Latest(group)
+				if err != nil {
+					return nil, err
+				}
+
+				if latest == nil || latest.SharedOutput != shared[group] {
+					stale = append(stale, group)
+				}
+			}
+
+			if len(stale) > 0 {
+				sort.Strings(stale)
+				return nil, errors.Errorf(
+					"changes to groups %v affect the resource registrations and supported resources report shared by all groups; run gen-types without --groups to update them",
+					stale)
+			}
+
+			return state, nil
+		})
+}
+
+// withStage returns a copy of our pipeline with the stage inserted immediately before the stage with the specified id
+func (generator *CodeGenerator) withStage(beforeStage string, stage *pipeline.Stage) ([]*pipeline.Stage, error) {
+	index := generator.IndexOfStage(beforeStage)
+	if index == -1 {
+		return nil, errors.Errorf("stage %s not found in pipeline", beforeStage)
+	}
+
+	result := make([]*pipeline.Stage, 0, len(generator.pipeline)+1)
+	result = append(result, generator.pipeline[:index]...)
+	result = append(result, stage)
+	result = append(result, generator.pipeline[index:]...)
+	return result, nil
+}
+
+// updateCache records the result of generating the specified groups.
+func (generator *CodeGenerator) updateCache(
+	state *pipeline.State,
+	fingerprints incremental.Fingerprints,
+	groups []string,
+	log logr.Logger,
+) error {
+	shared, err := pipeline.SharedOutputFingerprints(state.Definitions(), generator.idFactory, generator.configuration)
+	if err != nil {
+		return errors.Wrap(err, "computing fingerprints of shared outputs")
+	}
+
+	dependencies := state.GroupDependencies()
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		outputs, err := incremental.HashOutputs(filepath.Join(generator.configuration.FullTypesOutputPath(), group))
+		if err != nil {
+			return err
+		}
+
+		entry := &incremental.Entry{
+			Group:        group,
+			Fingerprint:  fingerprints[group],
+			Dependencies: make(map[string]string),
+			Outputs:      outputs,
+			SharedOutput: shared[group],
+		}
+
+		for dep := range dependencies[group] {
+			entry.Dependencies[dep] = fingerprints[dep]
+		}
+
+		err = generator.cache.Save(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.V(1).Info("Updated generation cache", "groups", len(groups))
+	return nil
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package codegen
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+)
+
+func TestGenerateGroups_WhenGroupChanged_MatchesFullGeneration(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ctx := context.Background()
+	log := logr.Discard()
+
+	// Generate everything, then regenerate only the group that changed
+	incrementalRoot := t.TempDir()
+	cacheFolder := t.TempDir()
+	writeIncrementalTestInputs(g, incrementalRoot, "Widget", "The colour of the widget.")
+
+	generator := createIncrementalTestGenerator(g, incrementalRoot, cacheFolder)
+	g.Expect(generator.Generate(ctx, log)).To(Succeed())
+
+	writeIncrementalTestInputs(g, incrementalRoot, "Widget", "The colour of the widget, as a CSS colour name.")
+
+	generator = createIncrementalTestGenerator(g, incrementalRoot, cacheFolder)
+	g.Expect(generator.GenerateGroups(ctx, []string{"widgets"}, log)).To(Succeed())
+
+	// Generate everything from the changed inputs, without a cache
+	fullRoot := t.TempDir()
+	writeIncrementalTestInputs(g, fullRoot, "Widget", "The colour of the widget, as a CSS colour name.")
+
+	generator = createIncrementalTestGenerator(g, fullRoot, "")
+	g.Expect(generator.Generate(ctx, log)).To(Succeed())
+
+	incremental := readIncrementalTestOutputs(g, incrementalRoot)
+	full := readIncrementalTestOutputs(g, fullRoot)
+	g.Expect(incremental).NotTo(BeEmpty())
+	g.Expect(incremental).To(HaveLen(len(full)))
+	for path, content := range full {
+		g.Expect(incremental).To(HaveKey(path))
+		g.Expect(incremental[path]).To(Equal(content), "content of %s", path)
+	}
+}
+
+func TestGenerateGroups_WhenSharedOutputsChange_ReturnsErrorWithoutWritingFiles(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ctx := context.Background()
+	log := logr.Discard()
+
+	root := t.TempDir()
+	cacheFolder := t.TempDir()
+	writeIncrementalTestInputs(g, root, "Widget", "The colour of the widget.")
+
+	generator := createIncrementalTestGenerator(g, root, cacheFolder)
+	g.Expect(generator.Generate(ctx, log)).To(Succeed())
+
+	before := readIncrementalTestOutputs(g, root)
+
+	// Renaming the resource changes the resource registrations shared by all groups
+	writeIncrementalTestInputs(g, root, "Gadget", "The colour of the widget.")
+
+	generator = createIncrementalTestGenerator(g, root, cacheFolder)
+	err := generator.GenerateGroups(ctx, []string{"widgets"}, log)
+	g.Expect(err).To(MatchError(ContainSubstring("run gen-types without --groups")))
+
+	g.Expect(readIncrementalTestOutputs(g, root)).To(Equal(before))
+}
+
+func TestGenerateGroups_WhenGroupUsesAnotherGroup_MatchesFullGeneration(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ctx := context.Background()
+	log := logr.Discard()
+
+	// Widgets use the properties of sprockets, so sprockets are loaded (but not written) when widgets change
+	sprocketProperties := `"sprocket": { "$ref": "../../../../../sprockets/resource-manager/Microsoft.Sprockets/stable/2020-01-01/sprockets.json#/definitions/SprocketProperties" },`
+
+	incrementalRoot := t.TempDir()
+	cacheFolder := t.TempDir()
+	writeIncrementalTestInputsWithProperties(g, incrementalRoot, "Widget", "The colour of the widget.", sprocketProperties)
+
+	generator := createIncrementalTestGenerator(g, incrementalRoot, cacheFolder)
+	g.Expect(generator.Generate(ctx, log)).To(Succeed())
+
+	writeIncrementalTestInputsWithProperties(g, incrementalRoot, "Widget", "The colour of the widget, as a CSS colour name.", sprocketProperties)
+
+	generator = createIncrementalTestGenerator(g, incrementalRoot, cacheFolder)
+	g.Expect(generator.GenerateGroups(ctx, []string{"widgets"}, log)).To(Succeed())
+
+	// Generate everything from the changed inputs, without a cache
+	fullRoot := t.TempDir()
+	writeIncrementalTestInputsWithProperties(g, fullRoot, "Widget", "The colour of the widget, as a CSS colour name.", sprocketProperties)
+
+	generator = createIncrementalTestGenerator(g, fullRoot, "")
+	g.Expect(generator.Generate(ctx, log)).To(Succeed())
+
+	incremental := readIncrementalTestOutputs(g, incrementalRoot)
+	full := readIncrementalTestOutputs(g, fullRoot)
+	g.Expect(incremental).To(HaveLen(len(full)))
+	for path, content := range full {
+		g.Expect(incremental).To(HaveKey(path))
+		g.Expect(incremental[path]).To(Equal(content), "content of %s", path)
+	}
+}
+
+// createIncrementalTestGenerator creates a code generator for the inputs in root, using the cache in cacheFolder
+// (if any)
+func createIncrementalTestGenerator(g *WithT, root string, cacheFolder string) *CodeGenerator {
+	generator, err := NewCodeGeneratorFromConfigFile(filepath.Join(root, "azure-arm.yaml"), logr.Discard())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	if cacheFolder != "" {
+		g.Expect(generator.UseCache(cacheFolder)).To(Succeed())
+	}
+
+	return generator
+}
+
+// writeIncrementalTestInputs writes the configuration and Swagger for two groups into root; the first group contains
+// a resource with the specified name, and a property with the specified description
+func writeIncrementalTestInputs(g *WithT, root string, resource string, description string) {
+	writeIncrementalTestInputsWithProperties(g, root, resource, description, "")
+}
+
+// writeIncrementalTestInputsWithProperties writes the same inputs as writeIncrementalTestInputs, adding the specified
+// JSON properties to the properties of the resource in the first group
+func writeIncrementalTestInputsWithProperties(g *WithT, root string, resource string, description string, properties string) {
+	files := map[string]string{
+		"go.mod":         "module github.com/Azure/azure-service-operator/v2\n",
+		"azure-arm.yaml": fmt.Sprintf(incrementalTestConfig, resource),
+		"specs/specification/widgets/resource-manager/Microsoft.Widgets/stable/2020-01-01/widgets.json": fmt.Sprintf(
+			incrementalTestSwagger, "Microsoft.Widgets", strings.ToLower(resource)+"s", resource, description, properties),
+		"specs/specification/sprockets/resource-manager/Microsoft.Sprockets/stable/2020-01-01/sprockets.json": fmt.Sprintf(
+			incrementalTestSwagger, "Microsoft.Sprockets", "sprockets", "Sprocket", "The colour of the sprocket.", ""),
+	}
+
+	for path, content := range files {
+		path = filepath.Join(root, path)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	// The generator expects the folder for resource registrations to exist
+	g.Expect(os.MkdirAll(filepath.Join(root, "internal", "controllers"), 0o755)).To(Succeed())
+}
+
+// readIncrementalTestOutputs returns the content of every generated file under root, keyed by relative path
+func readIncrementalTestOutputs(g *WithT, root string) map[string]string {
+	result := make(map[string]string)
+	for _, folder := range []string{"api", "internal"} {
+		err := filepath.WalkDir(filepath.Join(root, folder), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			result[filepath.ToSlash(rel)] = string(content)
+			return nil
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	return result
+}
+
+const incrementalTestConfig = `
+schemaRoot: ./specs/specification/
+destinationGoModuleFile: go.mod
+typesOutputPath: api
+typeRegistrationOutputFile: internal/controllers/controller_resources_gen.go
+pipeline: azure
+objectModelConfiguration:
+  widgets:
+    2020-01-01:
+      %s:
+        $export: true
+        $supportedFrom: v2.0.0
+  sprockets:
+    2020-01-01:
+      Sprocket:
+        $export: true
+        $supportedFrom: v2.0.0
+`
+
+// incrementalTestSwagger is a minimal Swagger document for a single resource; the placeholders are the provider,
+// the resource type, the name of the resource, the description of its colour property, and any further properties
+// (each followed by a comma)
+const incrementalTestSwagger = `{
+  "swagger": "2.0",
+  "info": { "title": "%[1]s", "version": "2020-01-01" },
+  "host": "management.azure.com",
+  "schemes": [ "https" ],
+  "consumes": [ "application/json" ],
+  "produces": [ "application/json" ],
+  "paths": {
+    "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/%[1]s/%[2]s/{name}": {
+      "get": {
+        "operationId": "%[3]s_Get",
+        "parameters": [
+          { "name": "subscriptionId", "in": "path", "required": true, "type": "string" },
+          { "name": "resourceGroupName", "in": "path", "required": true, "type": "string" },
+          { "name": "name", "in": "path", "required": true, "type": "string" },
+          { "name": "api-version", "in": "query", "required": true, "type": "string" }
+        ],
+        "responses": {
+          "200": { "description": "OK", "schema": { "$ref": "#/definitions/%[3]s" } }
+        }
+      },
+      "put": {
+        "operationId": "%[3]s_CreateOrUpdate",
+        "parameters": [
+          { "name": "subscriptionId", "in": "path", "required": true, "type": "string" },
+          { "name": "resourceGroupName", "in": "path", "required": true, "type": "string" },
+          { "name": "name", "in": "path", "required": true, "type": "string" },
+          { "name": "api-version", "in": "query", "required": true, "type": "string" },
+          { "name": "parameters", "in": "body", "required": true, "schema": { "$ref": "#/definitions/%[3]s" } }
+        ],
+        "responses": {
+          "200": { "description": "OK", "schema": { "$ref": "#/definitions/%[3]s" } }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "%[3]s": {
+      "type": "object",
+      "x-ms-azure-resource": true,
+      "properties": {
+        "id": { "type": "string", "readOnly": true },
+        "name": { "type": "string", "readOnly": true },
+        "type": { "type": "string", "readOnly": true },
+        "location": { "type": "string", "x-ms-mutability": [ "read", "create" ] },
+        "properties": { "$ref": "#/definitions/%[3]sProperties", "x-ms-client-flatten": true }
+      }
+    },
+    "%[3]sProperties": {
+      "type": "object",
+      "properties": {
+        %[5]s
+        "colour": { "type": "string", "description": "%[4]s" }
+      }
+    }
+  }
+}
+`
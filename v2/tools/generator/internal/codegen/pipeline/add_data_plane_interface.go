@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/functions"
+)
+
+const AddDataPlaneInterfaceStageID = "addDataPlaneInterface"
+
+// AddDataPlaneInterface adds the DataPlaneResource interface to resources in groups configured with $dataPlane,
+// and makes each one owned by the ARM resource hosting the data plane (e.g. a Key Vault secret is owned by its vault).
+func AddDataPlaneInterface(
+	configuration *config.Configuration,
+	idFactory astmodel.IdentifierFactory,
+) *Stage {
+	stage := NewStage(
+		AddDataPlaneInterfaceStageID,
+		"Add the DataPlaneResource interface for resources managed through a data plane",
+		func(ctx context.Context, state *State) (*State, error) {
+			updatedDefs := make(astmodel.TypeDefinitionSet)
+
+			var errs []error
+			for _, def := range astmodel.FindResourceDefinitions(state.Definitions()) {
+				dataPlane, err := configuration.ObjectModelConfiguration.DataPlane.Lookup(def.Name().InternalPackageReference())
+				if err != nil {
+					if config.IsNotConfiguredError(err) {
+						// Not a data plane resource
+						continue
+					}
+
+					errs = append(errs, err)
+					continue
+				}
+
+				owner, err := dataPlaneOwner(configuration, dataPlane)
+				if err != nil {
+					errs = append(errs, errors.Wrapf(err, "resource %s", def.Name()))
+					continue
+				}
+
+				// Data plane resources are scoped to their owner, which lives in a resource group
+				rt := def.Type().(*astmodel.ResourceType)
+				rt = rt.WithOwner(owner).
+					WithScope(astmodel.ResourceScopeResourceGroup).
+					WithInterface(functions.NewDataPlaneResource(idFactory, dataPlane.Audience, dataPlane.Endpoint, dataPlane.Path))
+				updatedDefs.Add(def.WithType(rt))
+			}
+
+			if len(errs) > 0 {
+				return nil, kerrors.NewAggregate(errs)
+			}
+
+			if err := configuration.ObjectModelConfiguration.DataPlane.VerifyConsumed(); err != nil {
+				return nil, err
+			}
+
+			return state.WithDefinitions(state.Definitions().OverlayWith(updatedDefs)), nil
+		},
+	)
+
+	stage.RequiresPrerequisiteStages(DetermineResourceOwnershipStageId)
+
+	// The owner property is created from the owner we set here
+	stage.RequiresPostrequisiteStages(ApplyARMConversionInterfaceStageID)
+
+	return stage
+}
+
+// dataPlaneOwner returns the type name of the ARM resource owning a data plane, given in configuration as group/kind.
+func dataPlaneOwner(configuration *config.Configuration, dataPlane config.DataPlane) (astmodel.InternalTypeName, error) {
+	group, kind, ok := strings.Cut(dataPlane.Owner, "/")
+	if !ok || group == "" || kind == "" {
+		return astmodel.InternalTypeName{}, errors.Errorf("expected data plane owner %q to be of the form group/kind", dataPlane.Owner)
+	}
+
+	return astmodel.MakeInternalTypeName(
+		configuration.MakeLocalPackageReference(strings.ToLower(group), dataPlane.OwnerVersion),
+		kind), nil
+}
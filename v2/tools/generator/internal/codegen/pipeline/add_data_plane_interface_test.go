@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func makeDataPlaneConfiguration(g *WithT, ref astmodel.InternalPackageReference, dataPlane config.DataPlane) *config.Configuration {
+	configuration := config.NewConfiguration()
+	g.Expect(
+		configuration.ObjectModelConfiguration.ModifyGroup(
+			ref,
+			func(gc *config.GroupConfiguration) error {
+				gc.DataPlane.Set(dataPlane)
+				return nil
+			})).
+		To(Succeed())
+
+	return configuration
+}
+
+func TestGolden_AddDataPlaneInterface_AddsInterfaceAndOwner(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec)
+
+	configuration := makeDataPlaneConfiguration(
+		g,
+		test.Pkg2020,
+		config.DataPlane{
+			Audience:     "https://vault.azure.net",
+			Endpoint:     "https://{owner}.vault.azure.net",
+			Path:         "/secrets/{name}",
+			Owner:        "keyvault/Vault",
+			OwnerVersion: "2021-04-01-preview",
+		})
+
+	stage := AddDataPlaneInterface(configuration, astmodel.NewIdentifierFactory())
+
+	// Don't need a context when testing
+	state := NewState().WithDefinitions(defs).WithSeenStage(DetermineResourceOwnershipStageId)
+	finalState, err := stage.Run(context.TODO(), state)
+	g.Expect(err).To(Succeed())
+
+	rt, ok := astmodel.AsResourceType(finalState.Definitions()[resource.Name()].Type())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(rt.Scope()).To(Equal(astmodel.ResourceScopeResourceGroup))
+	g.Expect(rt.Owner().Name()).To(Equal("Vault"))
+	g.Expect(rt.Owner().InternalPackageReference().Group()).To(Equal("keyvault"))
+	g.Expect(rt.Owner().InternalPackageReference().PackageName()).To(Equal("v1api20210401preview"))
+
+	test.AssertPackagesGenerateExpectedCode(t, finalState.Definitions())
+}
+
+func TestAddDataPlaneInterface_WhenOwnerMalformed_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec)
+
+	configuration := makeDataPlaneConfiguration(
+		g,
+		test.Pkg2020,
+		config.DataPlane{
+			Audience:     "https://vault.azure.net",
+			Endpoint:     "https://{owner}.vault.azure.net",
+			Path:         "/secrets/{name}",
+			Owner:        "Vault",
+			OwnerVersion: "2021-04-01-preview",
+		})
+
+	stage := AddDataPlaneInterface(configuration, astmodel.NewIdentifierFactory())
+	state := NewState().WithDefinitions(defs).WithSeenStage(DetermineResourceOwnershipStageId)
+	_, err := stage.Run(context.TODO(), state)
+	g.Expect(err).To(MatchError(ContainSubstring("group/kind")))
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"github.com/pkg/errors"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// AddValidationRulesStageID is the unique identifier for this pipeline stage
+const AddValidationRulesStageID = "addValidationRules"
+
+// AddValidationRules applies any $validations configured for a type, emitting them as CEL validation rules
+// (+kubebuilder:validation:XValidation) on the generated type. Each rule is compiled as CEL, type checked against the
+// properties of the type, so that mistakes are caught here rather than when the CRD is applied to a cluster.
+func AddValidationRules(configuration *config.Configuration) *Stage {
+	stage := NewStage(
+		AddValidationRulesStageID,
+		"Add CEL validation rules configured via $validations",
+		func(ctx context.Context, state *State) (*State, error) {
+			defs := state.Definitions()
+			updatedDefs := make(astmodel.TypeDefinitionSet)
+			var errs []error
+			for name, def := range defs {
+				rules, err := configuration.ObjectModelConfiguration.Validations.Lookup(name)
+				if err != nil {
+					if config.IsNotConfiguredError(err) {
+						// No $validations configured for this type
+						continue
+					}
+
+					// If something else went wrong, keep details
+					errs = append(errs, err)
+					continue
+				}
+
+				updated, err := addValidationRules(def, rules, defs)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+
+				updatedDefs.Add(updated)
+			}
+
+			if len(errs) > 0 {
+				return nil, kerrors.NewAggregate(errs)
+			}
+
+			// Ensure that all the $validations were used
+			err := configuration.ObjectModelConfiguration.Validations.VerifyConsumed()
+			if err != nil {
+				return nil, errors.Wrap(
+					err,
+					"Found unused $validations configurations; these need to be fixed or removed.")
+			}
+
+			state = state.WithDefinitions(defs.OverlayWith(updatedDefs))
+			return state, nil
+		})
+
+	return stage
+}
+
+// addValidationRules returns an updated definition with the specified rules added, after checking each rule is valid
+func addValidationRules(
+	def astmodel.TypeDefinition,
+	rules []config.ValidationRule,
+	defs astmodel.TypeDefinitionSet,
+) (astmodel.TypeDefinition, error) {
+	if _, ok := astmodel.AsObjectType(def.Type()); !ok {
+		return astmodel.TypeDefinition{}, errors.Errorf(
+			"$validations can only be applied to object types, but %s is not an object",
+			def.Name())
+	}
+
+	validations := make([]astmodel.KubeBuilderValidation, 0, len(rules))
+	var errs []error
+	for _, rule := range rules {
+		err := checkValidationRule(rule.Rule, def.Name(), defs)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "checking $validations rule %q for %s", rule.Rule, def.Name()))
+			continue
+		}
+
+		validations = append(validations, astmodel.MakeXValidation(rule.Rule, rule.Message))
+	}
+
+	if len(errs) > 0 {
+		return astmodel.TypeDefinition{}, kerrors.NewAggregate(errs)
+	}
+
+	return def.WithValidations(validations...), nil
+}
+
+// checkValidationRule compiles a CEL rule, returning an error if it's malformed, references properties that don't
+// exist on the object, uses properties with the wrong types, or doesn't evaluate to a bool.
+// self and oldSelf are declared with the type of the object, with properties typed as they will be in the CRD schema.
+func checkValidationRule(
+	rule string,
+	name astmodel.InternalTypeName,
+	defs astmodel.TypeDefinitionSet,
+) error {
+	if strings.TrimSpace(rule) == "" {
+		return errors.New("rule is empty")
+	}
+
+	provider := newCELTypeProvider(defs)
+	selfType := provider.celTypeOf(name)
+	options := append(
+		kubernetesCELOptions(),
+		cel.CustomTypeProvider(provider),
+		cel.Declarations(
+			decls.NewVar("self", selfType),
+			decls.NewVar("oldSelf", selfType)))
+
+	env, err := cel.NewEnv(options...)
+	if err != nil {
+		return errors.Wrap(err, "creating CEL environment")
+	}
+
+	ast, issues := env.Parse(rule)
+	if issues.Err() != nil {
+		return errors.Wrap(issues.Err(), "parsing rule")
+	}
+
+	checked, issues := env.Check(ast)
+	if issues.Err() != nil {
+		return errors.Wrap(issues.Err(), "type checking rule")
+	}
+
+	// Rules using properties we don't model may be dynamic; these will be checked by Kubernetes
+	result := checked.ResultType()
+	if result.GetPrimitive() != exprpb.Type_BOOL && result.GetDyn() == nil {
+		return errors.Errorf("rule must evaluate to a bool, not %s", checked.OutputType())
+	}
+
+	return nil
+}
+
+// kubernetesCELOptions returns the options needed for our CEL environment to match the one Kubernetes uses for CRD
+// validation rules, so that rules using the functions it provides can be type checked.
+// The Kubernetes specific libraries are declared here, as they're only available from k8s.io/apiserver.
+func kubernetesCELOptions() []cel.EnvOption {
+	listType := cel.ListType(cel.TypeParamType("T"))
+	urlType := cel.OpaqueType("kubernetes.URL")
+	return []cel.EnvOption{
+		cel.HomogeneousAggregateLiterals(),
+		cel.CrossTypeNumericComparisons(true),
+		cel.DefaultUTCTimeZone(true),
+		cel.OptionalTypes(),
+		ext.Strings(ext.StringsVersion(2)),
+		ext.Sets(),
+		// Lists
+		cel.Function("isSorted",
+			cel.MemberOverload("list_is_sorted", []*cel.Type{listType}, cel.BoolType)),
+		cel.Function("sum",
+			cel.MemberOverload("list_sum", []*cel.Type{listType}, cel.TypeParamType("T"))),
+		cel.Function("min",
+			cel.MemberOverload("list_min", []*cel.Type{listType}, cel.TypeParamType("T"))),
+		cel.Function("max",
+			cel.MemberOverload("list_max", []*cel.Type{listType}, cel.TypeParamType("T"))),
+		cel.Function("indexOf",
+			cel.MemberOverload("list_index_of", []*cel.Type{listType, cel.TypeParamType("T")}, cel.IntType)),
+		cel.Function("lastIndexOf",
+			cel.MemberOverload("list_last_index_of", []*cel.Type{listType, cel.TypeParamType("T")}, cel.IntType)),
+		// Regex
+		cel.Function("find",
+			cel.MemberOverload("string_find", []*cel.Type{cel.StringType, cel.StringType}, cel.StringType)),
+		cel.Function("findAll",
+			cel.MemberOverload("string_find_all", []*cel.Type{cel.StringType, cel.StringType}, cel.ListType(cel.StringType)),
+			cel.MemberOverload("string_find_all_n", []*cel.Type{cel.StringType, cel.StringType, cel.IntType}, cel.ListType(cel.StringType))),
+		// URLs
+		cel.Function("url",
+			cel.Overload("string_to_url", []*cel.Type{cel.StringType}, urlType)),
+		cel.Function("isURL",
+			cel.Overload("is_url_string", []*cel.Type{cel.StringType}, cel.BoolType)),
+		cel.Function("getScheme",
+			cel.MemberOverload("url_get_scheme", []*cel.Type{urlType}, cel.StringType)),
+		cel.Function("getHost",
+			cel.MemberOverload("url_get_host", []*cel.Type{urlType}, cel.StringType)),
+		cel.Function("getHostname",
+			cel.MemberOverload("url_get_hostname", []*cel.Type{urlType}, cel.StringType)),
+		cel.Function("getPort",
+			cel.MemberOverload("url_get_port", []*cel.Type{urlType}, cel.StringType)),
+		cel.Function("getEscapedPath",
+			cel.MemberOverload("url_get_escaped_path", []*cel.Type{urlType}, cel.StringType)),
+		cel.Function("getQuery",
+			cel.MemberOverload("url_get_query", []*cel.Type{urlType}, cel.MapType(cel.StringType, cel.ListType(cel.StringType)))),
+	}
+}
+
+// celTypeProvider makes the object types in a set of definitions available to CEL for type checking.
+// Each object type is identified by its full name, and has fields for each of its properties, named and typed as
+// they'll appear in the CRD schema.
+type celTypeProvider struct {
+	ref.TypeProvider
+	defs    astmodel.TypeDefinitionSet
+	objects map[string]*astmodel.ObjectType // Object types, keyed by full name
+}
+
+var _ ref.TypeProvider = &celTypeProvider{}
+
+// newCELTypeProvider returns a celTypeProvider for the object types in defs, falling back to the CEL types
+// registered by default
+func newCELTypeProvider(defs astmodel.TypeDefinitionSet) *celTypeProvider {
+	objects := make(map[string]*astmodel.ObjectType)
+	for name, def := range defs {
+		if obj, ok := astmodel.AsObjectType(def.Type()); ok {
+			objects[name.String()] = obj
+		}
+	}
+
+	return &celTypeProvider{
+		TypeProvider: types.NewEmptyRegistry(),
+		defs:         defs,
+		objects:      objects,
+	}
+}
+
+// FindType returns the type for typeName, if it's the name of one of our object types
+func (p *celTypeProvider) FindType(typeName string) (*exprpb.Type, bool) {
+	if _, ok := p.objects[typeName]; ok {
+		return decls.NewTypeType(decls.NewObjectType(typeName)), true
+	}
+
+	return p.TypeProvider.FindType(typeName)
+}
+
+// FindFieldType returns the type of the property with the JSON name fieldName, if messageType is the name of one of
+// our object types
+func (p *celTypeProvider) FindFieldType(messageType string, fieldName string) (*ref.FieldType, bool) {
+	obj, ok := p.objects[messageType]
+	if !ok {
+		return p.TypeProvider.FindFieldType(messageType, fieldName)
+	}
+
+	prop, ok := obj.Properties().Find(func(prop *astmodel.PropertyDefinition) bool {
+		jsonName, ok := prop.JSONName()
+		return ok && jsonName == fieldName
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return &ref.FieldType{Type: p.celTypeOf(prop.PropertyType())}, true
+}
+
+// celTypeOf returns the CEL type used for values of t in the CRD schema. Types we don't model (such as those defined
+// outside the generated code) are dynamic, so CEL won't check how they're used.
+func (p *celTypeProvider) celTypeOf(t astmodel.Type) *exprpb.Type {
+	t = astmodel.Unwrap(t)
+
+	if name, ok := astmodel.AsInternalTypeName(t); ok {
+		def, ok := p.defs[name]
+		if !ok {
+			return decls.Dyn
+		}
+
+		switch actual := astmodel.Unwrap(def.Type()).(type) {
+		case *astmodel.ObjectType:
+			return decls.NewObjectType(name.String())
+		case *astmodel.EnumType:
+			return p.celTypeOf(actual.BaseType())
+		default:
+			return p.celTypeOf(actual)
+		}
+	}
+
+	switch actual := t.(type) {
+	case *astmodel.PrimitiveType:
+		switch actual {
+		case astmodel.StringType, astmodel.ARMIDType:
+			return decls.String
+		case astmodel.IntType, astmodel.UInt32Type, astmodel.UInt64Type:
+			// Kubernetes represents all integers as int64
+			return decls.Int
+		case astmodel.FloatType:
+			return decls.Double
+		case astmodel.BoolType:
+			return decls.Bool
+		}
+	case *astmodel.ArrayType:
+		return decls.NewListType(p.celTypeOf(actual.Element()))
+	case *astmodel.MapType:
+		return decls.NewMapType(decls.String, p.celTypeOf(actual.ValueType()))
+	}
+
+	return decls.Dyn
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestGolden_AddValidationRules_AddsConfiguredRules(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty, test.FamilyNameProperty, test.KnownAsProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec)
+
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyType(
+			spec.Name(),
+			func(tc *config.TypeConfiguration) error {
+				tc.Validations.Set([]config.ValidationRule{
+					{
+						Rule:    `self.fullName.startsWith(self.knownAs)`,
+						Message: "fullName must start with knownAs",
+					},
+					{
+						Rule: `!has(oldSelf.familyName) || self.familyName == oldSelf.familyName`,
+					},
+				})
+				return nil
+			})).
+		To(Succeed())
+
+	configuration := config.NewConfiguration()
+	configuration.ObjectModelConfiguration = omc
+
+	addValidationRules := AddValidationRules(configuration)
+
+	// Don't need a context when testing
+	state := NewState().WithDefinitions(defs)
+	finalState, err := addValidationRules.Run(context.TODO(), state)
+	g.Expect(err).To(Succeed())
+
+	test.AssertPackagesGenerateExpectedCode(t, finalState.Definitions())
+}
+
+func TestAddValidationRules_WhenRuleInvalid_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		rule          string
+		expectedError string
+	}{
+		"Unknown property": {
+			rule:          "self.nickName != ''",
+			expectedError: `undefined field 'nickName'`,
+		},
+		"Unknown nested property": {
+			rule:          "self.residence.postcode != ''",
+			expectedError: `undefined field 'postcode'`,
+		},
+		"Unknown property in macro": {
+			rule:          "self.tags.all(t, t != '') && self.nickName.size() > 0",
+			expectedError: `undefined field 'nickName'`,
+		},
+		"Unknown property of oldSelf": {
+			rule:          "self.fullName == oldSelf.nickName",
+			expectedError: `undefined field 'nickName'`,
+		},
+		"Mismatched types": {
+			rule:          "self.fullName > 0",
+			expectedError: "found no matching overload for '_>_' applied to '(string, int)'",
+		},
+		"Mismatched element type": {
+			rule:          "self.tags.all(t, t > 0)",
+			expectedError: "found no matching overload for '_>_' applied to '(string, int)'",
+		},
+		"Method on wrong type": {
+			rule:          "self.residence.startsWith('a')",
+			expectedError: "found no matching overload for 'startsWith'",
+		},
+		"Not a bool": {
+			rule:          "self.fullName",
+			expectedError: "rule must evaluate to a bool",
+		},
+		"Unbalanced brackets": {
+			rule:          "size(self.fullName > 0",
+			expectedError: "parsing rule",
+		},
+		"Mismatched brackets": {
+			rule:          "self.fullName in ['a', 'b')",
+			expectedError: "parsing rule",
+		},
+		"Unterminated string": {
+			rule:          `self.fullName != "`,
+			expectedError: "parsing rule",
+		},
+		"Missing operand": {
+			rule:          "self.fullName == ",
+			expectedError: "parsing rule",
+		},
+		"Unknown operator": {
+			rule:          "self.fullName === 'a'",
+			expectedError: "parsing rule",
+		},
+		"Empty rule": {
+			rule:          " ",
+			expectedError: "rule is empty",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			address := test.CreateObjectDefinition(test.Pkg2020, "Address", test.FullAddressProperty, test.CityProperty)
+			residenceProperty := astmodel.NewPropertyDefinition("Residence", "residence", address.Name()).MakeTypeOptional()
+			tagsProperty := astmodel.NewPropertyDefinition("Tags", "tags", astmodel.NewArrayType(astmodel.StringType))
+			spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty, residenceProperty, tagsProperty)
+
+			defs := make(astmodel.TypeDefinitionSet)
+			defs.AddAll(spec, address)
+
+			_, err := addValidationRules(spec, []config.ValidationRule{{Rule: c.rule}}, defs)
+			g.Expect(err).To(MatchError(ContainSubstring(c.expectedError)))
+		})
+	}
+}
+
+func TestAddValidationRules_IgnoresContentOfStrings(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	address := test.CreateObjectDefinition(test.Pkg2020, "Address", test.FullAddressProperty, test.CityProperty)
+	residenceProperty := astmodel.NewPropertyDefinition("Residence", "residence", address.Name()).MakeTypeOptional()
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty, residenceProperty)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec, address)
+
+	rule := `self.residence.city.lowerAscii() != "self.unknown (" && self.fullName.size() > 0`
+	updated, err := addValidationRules(spec, []config.ValidationRule{{Rule: rule}}, defs)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(updated.Validations()).To(HaveLen(1))
+}
+
+func TestAddValidationRules_AllowsKubernetesLibraryFunctions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tagsProperty := astmodel.NewPropertyDefinition("Tags", "tags", astmodel.NewArrayType(astmodel.StringType))
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty, tagsProperty)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec)
+
+	rule := `self.tags.isSorted() && url(self.fullName).getHost() != "" && self.fullName.find("[a-z]+") == self.fullName.lowerAscii()`
+	updated, err := addValidationRules(spec, []config.ValidationRule{{Rule: rule}}, defs)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(updated.Validations()).To(HaveLen(1))
+}
+
+func TestAddValidationRules_WhenConfigurationUnused_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty)
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec)
+
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyType(
+			astmodel.MakeInternalTypeName(test.Pkg2020, "Address"),
+			func(tc *config.TypeConfiguration) error {
+				tc.Validations.Set([]config.ValidationRule{{Rule: "has(self.city)"}})
+				return nil
+			})).
+		To(Succeed())
+
+	configuration := config.NewConfiguration()
+	configuration.ObjectModelConfiguration = omc
+
+	stage := AddValidationRules(configuration)
+	_, err := stage.Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(MatchError(ContainSubstring("$validations")))
+}
@@ -165,9 +165,61 @@ func getValidations(
 			functions.NewValidateOptionalConfigMapReferenceFunction(resource, idFactory))
 	}
 
+	immutablePaths, err := findImmutablePropertyPaths(resource, defs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(immutablePaths) > 0 {
+		validations[functions.ValidationKindUpdate] = append(
+			validations[functions.ValidationKindUpdate],
+			functions.NewValidateImmutablePropertiesFunction(resource, idFactory, immutablePaths))
+	}
+
 	return validations, nil
 }
 
+// findImmutablePropertyPaths returns the JSON paths of all the create-only properties in the spec of the resource.
+// We don't look inside arrays or maps as we can't reliably pair up their elements between the old and new objects;
+// an array or map that is itself create-only is still included.
+func findImmutablePropertyPaths(resource *astmodel.ResourceType, defs astmodel.TypeDefinitionSet) ([]string, error) {
+	spec, ok := defs.ResolveObjectType(resource.SpecType())
+	if !ok {
+		return nil, errors.Errorf("unable to resolve spec type %s", resource.SpecType())
+	}
+
+	var result []string
+	var collect func(obj *astmodel.ObjectType, prefix string, visited astmodel.TypeNameSet)
+	collect = func(obj *astmodel.ObjectType, prefix string, visited astmodel.TypeNameSet) {
+		for _, prop := range obj.Properties().AsSlice() {
+			jsonName, ok := prop.JSONName()
+			if !ok {
+				continue
+			}
+
+			path := prefix + jsonName
+			if prop.IsImmutable() {
+				result = append(result, path)
+				continue
+			}
+
+			name, ok := astmodel.AsInternalTypeName(prop.PropertyType())
+			if !ok || visited.Contains(name) {
+				continue
+			}
+
+			if nested, ok := defs.ResolveObjectType(name); ok {
+				visited.Add(name)
+				collect(nested, path+".", visited)
+				visited.Remove(name)
+			}
+		}
+	}
+
+	collect(spec, "", astmodel.NewTypeNameSet())
+	return result, nil
+}
+
 // Note: This isn't defined in the functions package because it has a dependency on getResourceSecretsType which
 // doesn't make a lot of sense to put into astmodel. Functions can't import code from pipelines though, so we just
 // define this function here.
@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// ApplyExtensibleEnumOverridesStageID is the unique identifier for this pipeline stage
+const ApplyExtensibleEnumOverridesStageID = "applyExtensibleEnumOverrides"
+
+// ApplyExtensibleEnumOverrides applies $extensibleEnum configuration, opting enums in to accepting values beyond
+// those listed in the Swagger (such as new SKUs or regions). Whether the Swagger marks an enum as extensible is
+// recorded when the enum is loaded, but enums remain validated unless configured otherwise; the configuration also
+// overrides the extensibility given by the Swagger.
+// Configuration on an enum type applies wherever the enum is used; configuration on a property applies only to that
+// property, which is given its own copy of the enum if the enum is shared with other properties.
+func ApplyExtensibleEnumOverrides(configuration *config.Configuration) *Stage {
+	stage := NewStage(
+		ApplyExtensibleEnumOverridesStageID,
+		"Apply $extensibleEnum configuration to enum types and properties",
+		func(ctx context.Context, state *State) (*State, error) {
+			omc := configuration.ObjectModelConfiguration
+
+			defs, err := applyTypeExtensibleEnums(omc, state.Definitions())
+			if err != nil {
+				return nil, err
+			}
+
+			defs, err = applyPropertyExtensibleEnums(omc, defs)
+			if err != nil {
+				return nil, err
+			}
+
+			// Ensure that all the $extensibleEnum configurations were used
+			err = kerrors.NewAggregate([]error{
+				omc.TypeExtensibleEnum.VerifyConsumed(),
+				omc.PropertyExtensibleEnum.VerifyConsumed(),
+			})
+			if err != nil {
+				return nil, errors.Wrap(
+					err,
+					"Found unused $extensibleEnum configurations; these need to be fixed or removed.")
+			}
+
+			return state.WithDefinitions(defs), nil
+		})
+
+	// Must be applied before the enums are copied into ARM and storage variants
+	stage.RequiresPostrequisiteStages(CreateARMTypesStageID, CreateStorageTypesStageID)
+
+	return stage
+}
+
+// applyTypeExtensibleEnums opens (or closes) each enum type configured with $extensibleEnum
+func applyTypeExtensibleEnums(
+	omc *config.ObjectModelConfiguration,
+	defs astmodel.TypeDefinitionSet,
+) (astmodel.TypeDefinitionSet, error) {
+	updatedDefs := make(astmodel.TypeDefinitionSet)
+	var errs []error
+	for name, def := range defs {
+		enumType, ok := astmodel.AsEnumType(def.Type())
+		if !ok {
+			continue
+		}
+
+		extensible, err := omc.TypeExtensibleEnum.Lookup(name)
+		if err != nil {
+			if !config.IsNotConfiguredError(err) {
+				// If something went wrong, keep details
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		updatedDefs.Add(def.WithType(configureExtensibleEnum(enumType, extensible)))
+	}
+
+	if len(errs) > 0 {
+		return nil, kerrors.NewAggregate(errs)
+	}
+
+	return defs.OverlayWith(updatedDefs), nil
+}
+
+// applyPropertyExtensibleEnums opens (or closes) the enum used by each property configured with $extensibleEnum
+func applyPropertyExtensibleEnums(
+	omc *config.ObjectModelConfiguration,
+	defs astmodel.TypeDefinitionSet,
+) (astmodel.TypeDefinitionSet, error) {
+	usages := countEnumUsages(defs)
+
+	updatedDefs := make(astmodel.TypeDefinitionSet)
+	var errs []error
+	for name, def := range defs {
+		objectType, ok := astmodel.AsObjectType(def.Type())
+		if !ok {
+			continue
+		}
+
+		modified := false
+		for _, prop := range objectType.Properties().Copy() {
+			extensible, err := omc.PropertyExtensibleEnum.Lookup(name, prop.PropertyName())
+			if err != nil {
+				if !config.IsNotConfiguredError(err) {
+					// If something went wrong, keep details
+					errs = append(errs, err)
+				}
+
+				continue
+			}
+
+			enumName, enumDef, err := findPropertyEnum(defs, prop)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "applying $extensibleEnum to %s.%s", name, prop.PropertyName()))
+				continue
+			}
+
+			enumType, _ := astmodel.AsEnumType(enumDef.Type())
+			if enumType.IsExtensible() == extensible && enumType.PermitsUnknownValues() == extensible {
+				// Nothing to change
+				continue
+			}
+
+			if usages[enumName] <= 1 {
+				// Only this property uses the enum, so we can modify it in place
+				updatedDefs.Add(enumDef.WithType(configureExtensibleEnum(enumType, extensible)))
+				continue
+			}
+
+			// The enum is shared, so give this property a copy of its own
+			copyName := newNameHint(name).WithBasePart(string(prop.PropertyName())).AsTypeName(name.InternalPackageReference())
+			if _, exists := defs[copyName]; exists {
+				errs = append(errs, errors.Errorf(
+					"applying $extensibleEnum to %s.%s: unable to create enum %s as it already exists",
+					name,
+					prop.PropertyName(),
+					copyName))
+				continue
+			}
+
+			updatedDefs.Add(enumDef.WithName(copyName).WithType(configureExtensibleEnum(enumType, extensible)))
+
+			renamer := astmodel.NewRenamingVisitor(
+				map[astmodel.InternalTypeName]astmodel.InternalTypeName{enumName: copyName})
+			propType, err := renamer.Rename(prop.PropertyType())
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			objectType = objectType.WithProperty(prop.WithType(propType))
+			modified = true
+		}
+
+		if modified {
+			updatedDefs.Add(def.WithType(objectType))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, kerrors.NewAggregate(errs)
+	}
+
+	return defs.OverlayWith(updatedDefs), nil
+}
+
+// configureExtensibleEnum returns a copy of the enum that is open (accepting values other than the known options) or
+// closed, as configured, regardless of what the Swagger says.
+func configureExtensibleEnum(enumType *astmodel.EnumType, extensible bool) *astmodel.EnumType {
+	return enumType.WithExtensible(extensible).WithUnknownValuesPermitted(extensible)
+}
+
+// findPropertyEnum returns the name and definition of the enum used by the property, looking through optional,
+// array and map types. An error is returned if the property doesn't use an enum.
+func findPropertyEnum(
+	defs astmodel.TypeDefinitionSet,
+	prop *astmodel.PropertyDefinition,
+) (astmodel.InternalTypeName, astmodel.TypeDefinition, error) {
+	name, ok := astmodel.ExtractTypeName(prop.PropertyType())
+	if !ok {
+		return astmodel.InternalTypeName{}, astmodel.TypeDefinition{}, errors.Errorf(
+			"property type %s is not an enum", astmodel.DebugDescription(prop.PropertyType()))
+	}
+
+	def, ok := defs[name]
+	if !ok {
+		return astmodel.InternalTypeName{}, astmodel.TypeDefinition{}, errors.Errorf(
+			"definition for %s not found", name)
+	}
+
+	if !isEnumDefinition(def) {
+		return astmodel.InternalTypeName{}, astmodel.TypeDefinition{}, errors.Errorf(
+			"property type %s is not an enum", name)
+	}
+
+	return name, def, nil
+}
+
+// countEnumUsages returns the number of properties referring to each enum definition
+func countEnumUsages(defs astmodel.TypeDefinitionSet) map[astmodel.InternalTypeName]int {
+	result := make(map[astmodel.InternalTypeName]int)
+	for _, def := range defs {
+		objectType, ok := astmodel.AsObjectType(def.Type())
+		if !ok {
+			continue
+		}
+
+		objectType.Properties().ForEach(func(prop *astmodel.PropertyDefinition) {
+			if name, ok := astmodel.ExtractTypeName(prop.PropertyType()); ok {
+				if enumDef, ok := defs[name]; ok && isEnumDefinition(enumDef) {
+					result[name]++
+				}
+			}
+		})
+	}
+
+	return result
+}
+
+// isEnumDefinition returns true if the definition is for an enum
+func isEnumDefinition(def astmodel.TypeDefinition) bool {
+	_, ok := astmodel.AsEnumType(def.Type())
+	return ok
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestApplyExtensibleEnumOverrides_WhenEnumNotShared_ModifiesEnumInPlace(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tier := createTierEnum(false)
+	spec := test.CreateSpec(
+		test.Pkg2020,
+		"Person",
+		test.FullNameProperty,
+		astmodel.NewPropertyDefinition("Tier", "tier", astmodel.NewOptionalType(tier.Name())))
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec, tier)
+
+	configuration := createExtensibleEnumConfiguration(g, spec.Name(), "Tier", true)
+
+	state, err := ApplyExtensibleEnumOverrides(configuration).Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	enum, ok := astmodel.AsEnumType(state.Definitions()[tier.Name()].Type())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(enum.IsExtensible()).To(BeTrue())
+	g.Expect(enum.PermitsUnknownValues()).To(BeTrue())
+}
+
+func TestApplyExtensibleEnumOverrides_WhenEnumShared_CreatesCopyForProperty(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tier := createTierEnum(true)
+	spec := test.CreateSpec(
+		test.Pkg2020,
+		"Person",
+		astmodel.NewPropertyDefinition("Tier", "tier", astmodel.NewOptionalType(tier.Name())),
+		astmodel.NewPropertyDefinition("PreviousTier", "previousTier", astmodel.NewOptionalType(tier.Name())))
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec, tier)
+
+	configuration := createExtensibleEnumConfiguration(g, spec.Name(), "Tier", false)
+
+	state, err := ApplyExtensibleEnumOverrides(configuration).Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The shared enum is unchanged
+	shared, ok := astmodel.AsEnumType(state.Definitions()[tier.Name()].Type())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(shared.IsExtensible()).To(BeTrue())
+	g.Expect(shared.PermitsUnknownValues()).To(BeTrue())
+
+	// The configured property has its own closed enum
+	copyName := astmodel.MakeInternalTypeName(test.Pkg2020, "Person_Tier_Spec")
+	closed, ok := astmodel.AsEnumType(state.Definitions()[copyName].Type())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(closed.IsExtensible()).To(BeFalse())
+	g.Expect(closed.PermitsUnknownValues()).To(BeFalse())
+
+	updatedSpec, ok := astmodel.AsObjectType(state.Definitions()[spec.Name()].Type())
+	g.Expect(ok).To(BeTrue())
+
+	prop, ok := updatedSpec.Property("Tier")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(prop.PropertyType()).To(Equal(astmodel.NewOptionalType(copyName)))
+
+	prop, ok = updatedSpec.Property("PreviousTier")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(prop.PropertyType()).To(Equal(astmodel.NewOptionalType(tier.Name())))
+}
+
+func TestApplyExtensibleEnumOverrides_WhenPropertyNotEnum_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec)
+
+	configuration := createExtensibleEnumConfiguration(g, spec.Name(), test.FullNameProperty.PropertyName(), true)
+
+	_, err := ApplyExtensibleEnumOverrides(configuration).Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(MatchError(ContainSubstring("not an enum")))
+}
+
+func TestApplyExtensibleEnumOverrides_WhenConfigurationUnused_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec)
+
+	configuration := createExtensibleEnumConfiguration(g, spec.Name(), "Tier", true)
+
+	_, err := ApplyExtensibleEnumOverrides(configuration).Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(MatchError(ContainSubstring("$extensibleEnum")))
+}
+
+func TestApplyExtensibleEnumOverrides_WhenEnumTypeConfigured_OpensEnum(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tier := createTierEnum(false)
+	spec := test.CreateSpec(
+		test.Pkg2020,
+		"Person",
+		astmodel.NewPropertyDefinition("Tier", "tier", astmodel.NewOptionalType(tier.Name())),
+		astmodel.NewPropertyDefinition("PreviousTier", "previousTier", astmodel.NewOptionalType(tier.Name())))
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec, tier)
+
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyType(
+			tier.Name(),
+			func(tc *config.TypeConfiguration) error {
+				tc.ExtensibleEnum.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	configuration := config.NewConfiguration()
+	configuration.ObjectModelConfiguration = omc
+
+	state, err := ApplyExtensibleEnumOverrides(configuration).Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The enum is opened in place, for every property using it
+	enum, ok := astmodel.AsEnumType(state.Definitions()[tier.Name()].Type())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(enum.IsExtensible()).To(BeTrue())
+	g.Expect(enum.PermitsUnknownValues()).To(BeTrue())
+	g.Expect(state.Definitions()[spec.Name()]).To(Equal(spec))
+}
+
+func TestApplyExtensibleEnumOverrides_WhenNotConfigured_LeavesSwaggerExtensibleEnumValidated(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// Extensible according to the Swagger, but not opted in to accepting unknown values
+	tier := createTierEnum(false)
+	swaggerEnum, ok := astmodel.AsEnumType(tier.Type())
+	g.Expect(ok).To(BeTrue())
+	tier = tier.WithType(swaggerEnum.WithExtensible(true))
+	spec := test.CreateSpec(
+		test.Pkg2020,
+		"Person",
+		astmodel.NewPropertyDefinition("Tier", "tier", astmodel.NewOptionalType(tier.Name())))
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(spec, tier)
+
+	configuration := config.NewConfiguration()
+
+	state, err := ApplyExtensibleEnumOverrides(configuration).Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	enum, ok := astmodel.AsEnumType(state.Definitions()[tier.Name()].Type())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(enum.IsExtensible()).To(BeTrue())
+	g.Expect(enum.PermitsUnknownValues()).To(BeFalse())
+}
+
+func createTierEnum(extensible bool) astmodel.TypeDefinition {
+	return astmodel.MakeTypeDefinition(
+		astmodel.MakeInternalTypeName(test.Pkg2020, "Tier"),
+		astmodel.NewEnumType(
+			astmodel.StringType,
+			astmodel.MakeEnumValue("Basic", "\"Basic\""),
+			astmodel.MakeEnumValue("Premium", "\"Premium\"")).
+			WithExtensible(extensible).
+			WithUnknownValuesPermitted(extensible))
+}
+
+func createExtensibleEnumConfiguration(
+	g *WithT,
+	name astmodel.InternalTypeName,
+	property astmodel.PropertyName,
+	extensible bool,
+) *config.Configuration {
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyProperty(
+			name,
+			property,
+			func(prop *config.PropertyConfiguration) error {
+				prop.ExtensibleEnum.Set(extensible)
+				return nil
+			})).
+		To(Succeed())
+
+	configuration := config.NewConfiguration()
+	configuration.ObjectModelConfiguration = omc
+	return configuration
+}
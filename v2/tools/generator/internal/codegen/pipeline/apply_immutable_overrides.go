@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// ApplyImmutableOverridesStageID is the unique identifier for this pipeline stage
+const ApplyImmutableOverridesStageID = "applyImmutableOverrides"
+
+// ApplyImmutableOverrides applies $immutable overrides to properties, allowing us to fix up Swagger specs where
+// x-ms-mutability is missing or wrong
+func ApplyImmutableOverrides(configuration *config.Configuration) *Stage {
+	stage := NewStage(
+		ApplyImmutableOverridesStageID,
+		"Apply $immutable overrides to properties",
+		func(ctx context.Context, state *State) (*State, error) {
+			updatedDefs := make(astmodel.TypeDefinitionSet)
+			var errs []error
+			for name, def := range state.Definitions() {
+				objectType, ok := def.Type().(*astmodel.ObjectType)
+				if !ok {
+					continue
+				}
+
+				modified := false
+				for _, prop := range objectType.Properties().Copy() {
+					immutable, err := configuration.ObjectModelConfiguration.Immutable.Lookup(name, prop.PropertyName())
+					if err != nil {
+						if config.IsNotConfiguredError(err) {
+							// $immutable is not configured, keep what we found in the Swagger
+							continue
+						}
+
+						// If something else went wrong, keep details
+						errs = append(errs, err)
+						continue
+					}
+
+					objectType = objectType.WithProperty(prop.WithIsImmutable(immutable))
+					modified = true
+				}
+
+				if modified {
+					updatedDefs.Add(def.WithType(objectType))
+				}
+			}
+
+			if len(errs) > 0 {
+				return nil, kerrors.NewAggregate(errs)
+			}
+
+			// Ensure that all the $immutable properties were used
+			err := configuration.ObjectModelConfiguration.Immutable.VerifyConsumed()
+			if err != nil {
+				return nil, errors.Wrap(
+					err,
+					"Found unused $immutable configurations; these need to be fixed or removed.")
+			}
+
+			state = state.WithDefinitions(state.Definitions().OverlayWith(updatedDefs))
+			return state, nil
+		})
+
+	stage.RequiresPostrequisiteStages(ApplyDefaulterAndValidatorInterfaceStageID)
+
+	return stage
+}
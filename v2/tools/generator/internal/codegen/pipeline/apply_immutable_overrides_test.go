@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestApplyImmutableOverrides_FindsImmutablePropertiesFromSwaggerAndConfiguration(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// Address.City is create-only according to the Swagger
+	address := test.CreateObjectDefinition(
+		test.Pkg2020,
+		"Address",
+		test.FullAddressProperty,
+		test.CityProperty.WithIsImmutable(true))
+
+	residenceProperty := astmodel.NewPropertyDefinition("Residence", "residence", address.Name()).MakeTypeOptional()
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty, test.KnownAsProperty, residenceProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec, address)
+
+	// FullName is create-only according to our configuration
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyProperty(
+			spec.Name(),
+			test.FullNameProperty.PropertyName(),
+			func(prop *config.PropertyConfiguration) error {
+				prop.Immutable.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	configuration := config.NewConfiguration()
+	configuration.ObjectModelConfiguration = omc
+
+	applyImmutableOverrides := ApplyImmutableOverrides(configuration)
+
+	// Don't need a context when testing
+	state := NewState().WithDefinitions(defs)
+	intermediateState, err := applyImmutableOverrides.Run(context.TODO(), state)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	paths, err := findImmutablePropertyPaths(resource.Type().(*astmodel.ResourceType), intermediateState.Definitions())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(paths).To(Equal([]string{"fullName", "residence.city"}))
+}
+
+func TestApplyImmutableOverrides_WhenConfigurationUnused_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec)
+
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyProperty(
+			spec.Name(),
+			"Nickname",
+			func(prop *config.PropertyConfiguration) error {
+				prop.Immutable.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	configuration := config.NewConfiguration()
+	configuration.ObjectModelConfiguration = omc
+
+	stage := ApplyImmutableOverrides(configuration)
+	_, err := stage.Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(MatchError(ContainSubstring("$immutable")))
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+)
+
+// CatalogGroupDependenciesStageID is the unique identifier for this pipeline stage
+const CatalogGroupDependenciesStageID = "catalogGroupDependencies"
+
+// CatalogGroupDependencies creates a pipeline stage to record which groups use types from other groups. This must run
+// before cross group references are collapsed, as afterwards each group is self-contained. Incremental generation uses
+// these dependencies to find the groups needing regeneration when another group changes.
+func CatalogGroupDependencies() *Stage {
+	stage := NewStage(
+		CatalogGroupDependenciesStageID,
+		"Catalog dependencies between groups",
+		func(ctx context.Context, state *State) (*State, error) {
+			dependencies := FindGroupDependencies(state.Definitions())
+			return state.WithGroupDependencies(dependencies), nil
+		})
+
+	stage.RequiresPostrequisiteStages(CollapseCrossGroupReferencesStageID)
+
+	return stage
+}
+
+// FindGroupDependencies returns, for each group, the set of other groups referenced by its types.
+// Every group is included, even if it has no dependencies.
+func FindGroupDependencies(definitions astmodel.TypeDefinitionSet) map[string]set.Set[string] {
+	result := make(map[string]set.Set[string])
+	for name, def := range definitions {
+		group := name.InternalPackageReference().Group()
+		deps, ok := result[group]
+		if !ok {
+			deps = set.Make[string]()
+			result[group] = deps
+		}
+
+		for ref := range def.References() {
+			if tn, ok := ref.(astmodel.InternalTypeName); ok {
+				if g := tn.InternalPackageReference().Group(); g != group {
+					deps.Add(g)
+				}
+			}
+		}
+	}
+
+	return result
+}
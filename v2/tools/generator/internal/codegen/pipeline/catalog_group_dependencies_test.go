@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestCatalogGroupDependencies_FindsReferencesBetweenGroups(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	batchAddress := test.CreateObjectDefinition(test.BatchPkg2020, "Address", test.FullAddressProperty)
+	addressProperty := astmodel.NewPropertyDefinition("Address", "address", batchAddress.Name())
+	person := test.CreateObjectDefinition(test.Pkg2020, "Person", test.FullNameProperty, addressProperty)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(person, batchAddress)
+
+	state, err := CatalogGroupDependencies().Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(Succeed())
+
+	dependencies := state.GroupDependencies()
+	g.Expect(dependencies).To(HaveLen(2))
+	g.Expect(dependencies[test.Group]).To(Equal(set.Make(test.BatchGroup)))
+	g.Expect(dependencies[test.BatchGroup]).To(BeEmpty())
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// CheckCRDSchemaSizesStageID is the unique identifier for this pipeline stage
+const CheckCRDSchemaSizesStageID = "checkCRDSchemaSizes"
+
+// CheckCRDSchemaSizes creates a pipeline stage that fails generation if the estimated schema size of any CRD (summed
+// across all the versions included in the CRD) exceeds the configured crdSchemaSizeBudget. The API server rejects
+// CRDs that are too large, and we'd rather find out when generating code than when installing the operator.
+func CheckCRDSchemaSizes(configuration *config.Configuration) *Stage {
+	stage := NewStage(
+		CheckCRDSchemaSizesStageID,
+		"Check the estimated schema size of each CRD is within budget",
+		func(ctx context.Context, state *State) (*State, error) {
+			if configuration.CRDSchemaSizeBudget <= 0 {
+				// No budget configured
+				return state, nil
+			}
+
+			crds := estimateCRDSchemaSizes(state.Definitions())
+			var errs []error
+			for _, crd := range crds {
+				if crd.size() > configuration.CRDSchemaSizeBudget {
+					errs = append(errs, errors.Errorf(
+						"CRD %s has an estimated schema size of %d bytes (%s)",
+						crd.name,
+						crd.size(),
+						crd.describeVersions()))
+				}
+			}
+
+			if len(errs) > 0 {
+				return nil, errors.Wrapf(
+					kerrors.NewAggregate(errs),
+					"CRD schemas exceed the budget of %d bytes; consider $crdCollapseDescriptions, $crdStatusSchemaDepth, or $crdExcludeStorageVersion",
+					configuration.CRDSchemaSizeBudget)
+			}
+
+			return state, nil
+		})
+
+	// Pruning reduces the size of CRDs, so must come first
+	stage.RequiresPrerequisiteStages(PruneCRDSchemasStageID)
+
+	return stage
+}
+
+// crdSchemaSize captures the estimated schema size of each version of a CRD
+type crdSchemaSize struct {
+	name     string         // Name of the CRD, in the form plural.group
+	versions map[string]int // Estimated schema size of each version included in the CRD
+}
+
+// estimateCRDSchemaSizes returns the estimated schema sizes of all the CRDs for resources in the specified
+// definitions, ordered by name
+func estimateCRDSchemaSizes(defs astmodel.TypeDefinitionSet) []*crdSchemaSize {
+	estimator := newCRDSchemaSizeEstimator(defs)
+	crds := make(map[string]*crdSchemaSize)
+	for name, def := range astmodel.FindResourceDefinitions(defs) {
+		rsrc := astmodel.MustBeResourceType(def.Type())
+		if rsrc.HasAnnotation(skipVersionAnnotation) {
+			// Not included in the CRD
+			continue
+		}
+
+		crdName := strings.ToLower(name.Plural().Name() + "." + name.InternalPackageReference().Group() + astmodel.GroupSuffix)
+		crd, ok := crds[crdName]
+		if !ok {
+			crd = &crdSchemaSize{
+				name:     crdName,
+				versions: make(map[string]int),
+			}
+
+			crds[crdName] = crd
+		}
+
+		// Use the same Kubernetes version as the generated code
+		version := astmodel.NewPackageDefinition(name.InternalPackageReference()).Version
+		crd.versions[version] = estimator.estimateResource(rsrc)
+	}
+
+	result := make([]*crdSchemaSize, 0, len(crds))
+	for _, crd := range crds {
+		result = append(result, crd)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+
+	return result
+}
+
+// size returns the estimated size of the schema of the CRD, summed across all versions
+func (crd *crdSchemaSize) size() int {
+	result := 0
+	for _, size := range crd.versions {
+		result += size
+	}
+
+	return result
+}
+
+// describeVersions returns a description of the estimated size of each version, largest first, so it's easy to see
+// where pruning would help the most
+func (crd *crdSchemaSize) describeVersions() string {
+	versions := make([]string, 0, len(crd.versions))
+	for v := range crd.versions {
+		versions = append(versions, v)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		left := crd.versions[versions[i]]
+		right := crd.versions[versions[j]]
+		if left != right {
+			return left > right
+		}
+
+		return versions[i] < versions[j]
+	})
+
+	parts := make([]string, 0, len(versions))
+	for _, v := range versions {
+		parts = append(parts, fmt.Sprintf("%s: %d", v, crd.versions[v]))
+	}
+
+	return strings.Join(parts, ", ")
+}
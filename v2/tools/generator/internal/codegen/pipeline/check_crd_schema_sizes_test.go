@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestCheckCRDSchemaSizes_WhenWithinBudget_Succeeds(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	defs, _, _ := createPersonStorageVersions()
+
+	configuration := config.NewConfiguration()
+	configuration.CRDSchemaSizeBudget = 1024 * 1024
+
+	_, err := CheckCRDSchemaSizes(configuration).Run(context.TODO(), NewState().WithDefinitions(defs).WithSeenStage(PruneCRDSchemasStageID))
+	g.Expect(err).To(Succeed())
+}
+
+func TestCheckCRDSchemaSizes_WhenOverBudget_ReturnsErrorListingVersions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	defs, _, _ := createPersonStorageVersions()
+
+	configuration := config.NewConfiguration()
+	configuration.CRDSchemaSizeBudget = 100
+
+	_, err := CheckCRDSchemaSizes(configuration).Run(context.TODO(), NewState().WithDefinitions(defs).WithSeenStage(PruneCRDSchemasStageID))
+	g.Expect(err).To(MatchError(ContainSubstring("people.person.azure.com")))
+	g.Expect(err).To(MatchError(ContainSubstring("v20200101storage")))
+	g.Expect(err).To(MatchError(ContainSubstring("v20211231storage")))
+}
+
+func TestCRDSchemaSizeEstimator_WhenPropertyPreservesUnknownFields_EstimatesSmallerSchema(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	defs, person, _ := createPersonStorageVersions()
+	before := estimateCRDSchemaSizes(defs)[0].versions["v20200101storage"]
+
+	spec := defs[test.MakeSpecName(person.InternalPackageReference(), "Person")]
+	obj, ok := astmodel.AsObjectType(spec.Type())
+	g.Expect(ok).To(BeTrue())
+
+	prop, ok := obj.Property("FullName")
+	g.Expect(ok).To(BeTrue())
+	defs[spec.Name()] = spec.WithType(obj.WithProperty(prop.WithDescription("").WithPreserveUnknownFields(true)))
+
+	after := estimateCRDSchemaSizes(defs)[0].versions["v20200101storage"]
+	g.Expect(after).To(BeNumerically("<", before))
+}
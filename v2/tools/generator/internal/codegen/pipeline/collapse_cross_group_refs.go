@@ -19,7 +19,7 @@ const CollapseCrossGroupReferencesStageID = "collapseCrossGroupReferences"
 // CollapseCrossGroupReferences finds and removes references between API groups. This isn't particularly common
 // but does occur in a few instances, for example from Microsoft.Compute -> Microsoft.Compute.Extensions.
 func CollapseCrossGroupReferences() *Stage {
-	return NewLegacyStage(
+	stage := NewLegacyStage(
 		CollapseCrossGroupReferencesStageID,
 		"Find and remove cross group references",
 		func(ctx context.Context, definitions astmodel.TypeDefinitionSet) (astmodel.TypeDefinitionSet, error) {
@@ -43,6 +43,11 @@ func CollapseCrossGroupReferences() *Stage {
 
 			return result, nil
 		})
+
+	// Groups that aren't selected can only be removed once nothing refers to them
+	stage.RequiresPostrequisiteStages(RemoveUnselectedGroupsStageID)
+
+	return stage
 }
 
 func newTypeWalker(
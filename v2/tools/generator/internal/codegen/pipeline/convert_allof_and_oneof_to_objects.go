@@ -674,7 +674,12 @@ func (s synthesizer) handleEnumEnum(leftEnum *astmodel.EnumType, rightEnum *astm
 		}
 	}
 
-	return astmodel.NewEnumType(leftEnum.BaseType(), inBoth...), nil
+	// The intersection is only open if both sides are open
+	extensible := leftEnum.IsExtensible() && rightEnum.IsExtensible()
+	permitUnknown := leftEnum.PermitsUnknownValues() && rightEnum.PermitsUnknownValues()
+	return astmodel.NewEnumType(leftEnum.BaseType(), inBoth...).
+		WithExtensible(extensible).
+		WithUnknownValuesPermitted(permitUnknown), nil
 }
 
 func (s synthesizer) handleEnum(leftEnum *astmodel.EnumType, right astmodel.Type) (astmodel.Type, error) {
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+)
+
+// Approximate sizes (in bytes) of fixed parts of the JSON schemas generated by controller-gen
+const (
+	// apiVersion, kind, and metadata of every resource
+	resourceSchemaOverhead = 400
+	// "name":{...}, plus separators
+	propertySchemaOverhead = 6
+	// "description":"..."
+	descriptionSchemaOverhead = 16
+	// {"type":"object","properties":{}}
+	objectSchemaOverhead = 34
+	// "required":[...]
+	requiredSchemaOverhead = 13
+	// {"type":"array","items":...}
+	arraySchemaOverhead = 26
+	// {"type":"object","additionalProperties":...}
+	mapSchemaOverhead = 42
+	// "enum":[...]
+	enumSchemaOverhead = 9
+	// "default":...
+	defaultSchemaOverhead = 10
+	// "x-kubernetes-preserve-unknown-fields":true
+	preserveUnknownFieldsSchemaSize = 42
+	// Types from other modules (such as genruntime.ResourceReference) are typically small objects
+	externalTypeSchemaSize = 600
+)
+
+// skipVersionAnnotation excludes a version of a resource from the CRD generated by controller-gen
+const skipVersionAnnotation = "// +kubebuilder:skipversion"
+
+// crdSchemaSizeEstimator estimates the size of the OpenAPI schema generated by controller-gen for each resource.
+// Sizes are estimated for the JSON form of the schema, as stored by the API server, from the type definitions alone;
+// this allows the size of each CRD to be checked during code generation without running controller-gen.
+type crdSchemaSizeEstimator struct {
+	defs       astmodel.TypeDefinitionSet
+	sizes      map[astmodel.InternalTypeName]int  // Cached sizes of the schemas of named types
+	inProgress set.Set[astmodel.InternalTypeName] // Named types being estimated, to guard against cycles
+}
+
+// newCRDSchemaSizeEstimator returns a new estimator for resources within the specified set of definitions
+func newCRDSchemaSizeEstimator(defs astmodel.TypeDefinitionSet) *crdSchemaSizeEstimator {
+	return &crdSchemaSizeEstimator{
+		defs:       defs,
+		sizes:      make(map[astmodel.InternalTypeName]int),
+		inProgress: set.Make[astmodel.InternalTypeName](),
+	}
+}
+
+// estimateResource returns the estimated size of the schema of the specified resource within its CRD
+func (e *crdSchemaSizeEstimator) estimateResource(rsrc *astmodel.ResourceType) int {
+	size := resourceSchemaOverhead
+	for _, prop := range rsrc.Properties().AsSlice() {
+		size += e.estimateProperty(prop)
+	}
+
+	return size
+}
+
+// estimateProperty returns the estimated size of the schema of the specified property, including its name
+func (e *crdSchemaSizeEstimator) estimateProperty(prop *astmodel.PropertyDefinition) int {
+	name, _ := prop.JSONName()
+	size := len(name) + propertySchemaOverhead
+	if desc := prop.Description(); desc != "" {
+		// controller-gen uses the doc comment of the field, which is prefixed with the name of the property
+		size += descriptionSchemaOverhead + len(prop.PropertyName()) + len(": ") + len(desc)
+	}
+
+	if prop.PreservesUnknownFields() {
+		return size + preserveUnknownFieldsSchemaSize
+	}
+
+	if value, ok := prop.DefaultValue(); ok && prop.HasKubebuilderDefault() {
+		size += defaultSchemaOverhead + len(fmt.Sprintf("%v", value))
+	}
+
+	return size + e.estimateType(prop.PropertyType())
+}
+
+// estimateType returns the estimated size of the schema of the specified type
+func (e *crdSchemaSizeEstimator) estimateType(t astmodel.Type) int {
+	switch t := t.(type) {
+	case astmodel.InternalTypeName:
+		return e.estimateTypeName(t)
+	case astmodel.TypeName:
+		return externalTypeSchemaSize
+	case *astmodel.ValidatedType:
+		size := e.estimateType(t.ElementType())
+		for _, v := range t.Validations().ToKubeBuilderValidations() {
+			// The rendered marker is a reasonable proxy for the size of the schema keyword
+			size += len(astmodel.GenerateKubebuilderComment(v)) - len("// +kubebuilder:validation:")
+		}
+
+		return size
+	case astmodel.MetaType:
+		return e.estimateType(t.Unwrap())
+	case *astmodel.PrimitiveType:
+		// {"type":"string"}
+		return len(`{"type":""}`) + len(t.Name())
+	case *astmodel.EnumType:
+		if t.PermitsUnknownValues() {
+			// No enum validation is emitted for an extensible enum
+			return e.estimateType(t.BaseType())
+		}
+
+		size := e.estimateType(t.BaseType()) + enumSchemaOverhead
+		for _, option := range t.Options() {
+			size += len(option.Value) + 1
+		}
+
+		return size
+	case *astmodel.ArrayType:
+		return arraySchemaOverhead + e.estimateType(t.Element())
+	case *astmodel.MapType:
+		return mapSchemaOverhead + e.estimateType(t.ValueType())
+	case *astmodel.ObjectType:
+		return e.estimateObject(t)
+	case *astmodel.ResourceType:
+		return e.estimateResource(t)
+	}
+
+	// Other types don't make it into the final object model
+	return 0
+}
+
+// estimateTypeName returns the estimated size of the schema of a named type, which controller-gen inlines at each use
+func (e *crdSchemaSizeEstimator) estimateTypeName(name astmodel.InternalTypeName) int {
+	if size, ok := e.sizes[name]; ok {
+		return size
+	}
+
+	def, ok := e.defs[name]
+	if !ok || e.inProgress.Contains(name) {
+		// Recursive types are unrolled before we get here, so this is unexpected; we can't do better than ignoring it
+		return 0
+	}
+
+	e.inProgress.Add(name)
+	size := e.estimateType(def.Type())
+	e.inProgress.Remove(name)
+
+	e.sizes[name] = size
+	return size
+}
+
+// estimateObject returns the estimated size of the schema of an object, including any embedded properties
+func (e *crdSchemaSizeEstimator) estimateObject(obj *astmodel.ObjectType) int {
+	size := objectSchemaOverhead
+	required := 0
+	for _, prop := range obj.Properties().AsSlice() {
+		size += e.estimateProperty(prop)
+		if prop.IsRequired() {
+			name, _ := prop.JSONName()
+			required += len(name) + 3
+		}
+	}
+
+	if required > 0 {
+		size += requiredSchemaOverhead + required
+	}
+
+	for _, prop := range obj.EmbeddedProperties() {
+		// Embedded properties are inlined, so we only count the properties of the embedded type
+		size += e.estimateType(prop.PropertyType()) - objectSchemaOverhead
+	}
+
+	return size
+}
@@ -404,8 +404,8 @@ func (c *armTypeCreator) createARMProperty(
 		return nil, err
 	}
 
-	// Return a property with (potentially) a new type
-	result := prop.WithType(newType)
+	// Return a property with (potentially) a new type; defaults are applied by the API server, so aren't needed here
+	result := prop.WithType(newType).WithKubebuilderDefault(false)
 
 	switch convContext.payloadType {
 	case config.OmitEmptyProperties:
@@ -20,18 +20,26 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
 )
 
 // DeleteGeneratedCodeStageID is the unique identifier of this stage
 const DeleteGeneratedCodeStageID = "deleteGenerated"
 
-// DeleteGeneratedCode creates a pipeline stage for cleanup of our output folder prior to generating files
-func DeleteGeneratedCode(outputFolder string) *Stage {
+// DeleteGeneratedCode creates a pipeline stage for cleanup of our output folder prior to generating files.
+// If only some groups have been selected for generation, only code for those groups is deleted.
+func DeleteGeneratedCode(outputFolder string, configuration *config.Configuration) *Stage {
 	return NewLegacyStage(
 		DeleteGeneratedCodeStageID,
 		"Delete generated code from "+outputFolder,
 		func(ctx context.Context, definitions astmodel.TypeDefinitionSet) (astmodel.TypeDefinitionSet, error) {
-			err := deleteGeneratedCodeFromFolder(ctx, outputFolder)
+			var err error
+			if configuration.HasGroupSelection() {
+				err = deleteGeneratedCodeForSelectedGroups(ctx, outputFolder, configuration)
+			} else {
+				err = deleteGeneratedCodeFromFolder(ctx, outputFolder)
+			}
+
 			if err != nil {
 				return nil, err
 			}
@@ -40,6 +48,35 @@ func DeleteGeneratedCode(outputFolder string) *Stage {
 		})
 }
 
+// deleteGeneratedCodeForSelectedGroups deletes generated code from the folder of each group selected for output
+func deleteGeneratedCodeForSelectedGroups(
+	ctx context.Context,
+	outputFolder string,
+	configuration *config.Configuration,
+) error {
+	entries, err := os.ReadDir(outputFolder)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error reading directory %q", outputFolder)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !configuration.IsGroupEmitted(entry.Name()) {
+			continue
+		}
+
+		err = deleteGeneratedCodeFromFolder(ctx, filepath.Join(outputFolder, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func deleteGeneratedCodeFromFolder(ctx context.Context, outputFolder string) error {
 	genPattern := filepath.Join(outputFolder, "**", "*", "*"+astmodel.CodeGeneratedFileSuffix+"*.go")
 	err := deleteGeneratedCodeByPattern(ctx, genPattern)
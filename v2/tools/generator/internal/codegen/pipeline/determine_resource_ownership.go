@@ -107,8 +107,15 @@ func findChildren(
 			//     {prefix}/resourceType/resourceName
 			// and not a grandchild resource:
 			//     {prefix}/resourceType/resourceName/anotherResourceType/anotherResourceName
+			// Actions are the exception, as they're of the form:
+			//     {prefix}/actionName
 			withoutPrefix := otherURI[len(myPrefix)-1:]
-			if strings.Count(withoutPrefix, "/") == 2 {
+			expectedSlashes := 2
+			if other.IsAction() {
+				expectedSlashes = 1
+			}
+
+			if strings.Count(withoutPrefix, "/") == expectedSlashes {
 				result = append(result, otherName)
 			}
 
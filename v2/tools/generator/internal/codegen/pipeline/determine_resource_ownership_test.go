@@ -10,6 +10,7 @@ import (
 
 	"github.com/onsi/gomega"
 
+	"github.com/Azure/azure-service-operator/v2/internal/set"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
 )
 
@@ -124,3 +125,24 @@ func Test_FindChildren_ResourceDoesNotOwnExtendedVersionOfName(t *testing.T) {
 	children := findChildren(ownerType, ownerName, resources)
 	g.Expect(children).To(gomega.BeEmpty())
 }
+
+func Test_FindChildren_ResourceOwnsAction(t *testing.T) {
+	t.Parallel()
+
+	g := gomega.NewWithT(t)
+
+	resources := make(astmodel.TypeDefinitionSet)
+
+	ownerType := astmodel.NewResourceType(nil, nil).WithARMURI("/resources/{name}")
+	ownerName := astmodel.MakeInternalTypeName(pr, "Owner")
+	resources.Add(astmodel.MakeTypeDefinition(ownerName, ownerType))
+
+	actionType := astmodel.NewResourceType(nil, nil).
+		WithARMURI("/resources/{name}/regenerateKey").
+		WithSupportedOperations(set.Make(astmodel.ResourceOperationPost))
+	actionName := astmodel.MakeInternalTypeName(pr, "Owner_RegenerateKey")
+	resources.Add(astmodel.MakeTypeDefinition(actionName, actionType))
+
+	children := findChildren(ownerType, ownerName, resources)
+	g.Expect(children).To(gomega.ConsistOf(actionName))
+}
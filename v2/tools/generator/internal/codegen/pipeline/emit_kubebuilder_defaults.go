@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// EmitKubebuilderDefaultsStageID is the unique identifier for this pipeline stage
+const EmitKubebuilderDefaultsStageID = "emitKubebuilderDefaults"
+
+// EmitKubebuilderDefaults marks spec properties with $emitDefaults configured so that their Swagger default values are
+// emitted as +kubebuilder:default markers. The API server then fills in the same value Azure would use, so the spec
+// of a resource matches what's in Azure and tools comparing the two don't report server-side defaults as drift.
+// Only the CRD is affected; the operator doesn't compare the spec with the resource returned by Azure.
+// $emitDefaults may be configured on a group, a type, or a property; the most specific configuration wins.
+func EmitKubebuilderDefaults(configuration *config.Configuration) *Stage {
+	stage := NewStage(
+		EmitKubebuilderDefaultsStageID,
+		"Emit Swagger defaults of properties as kubebuilder defaults where configured",
+		func(ctx context.Context, state *State) (*State, error) {
+			defs := state.Definitions()
+
+			// Defaults only make sense in the spec; Azure populates the status
+			specDefs, err := astmodel.FindSpecConnectedDefinitions(defs)
+			if err != nil {
+				return nil, errors.Wrap(err, "finding spec definitions")
+			}
+
+			omc := configuration.ObjectModelConfiguration
+			updatedDefs := make(astmodel.TypeDefinitionSet)
+			var errs []error
+			for name, def := range specDefs {
+				objectType, ok := def.Type().(*astmodel.ObjectType)
+				if !ok {
+					continue
+				}
+
+				// Look up group and type configuration even for types without defaults, so it's always consumed
+				groupEmit, err := lookupEmitDefaults(omc.GroupEmitDefaults.Lookup(name.InternalPackageReference()))
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+
+				typeEmit, err := lookupEmitDefaults(omc.TypeEmitDefaults.Lookup(name))
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+
+				modified := false
+				for _, prop := range objectType.Properties().Copy() {
+					propertyEmit, err := lookupEmitDefaults(omc.PropertyEmitDefaults.Lookup(name, prop.PropertyName()))
+					if err != nil {
+						errs = append(errs, err)
+						continue
+					}
+
+					emit := mostSpecificEmitDefaults(groupEmit, typeEmit, propertyEmit)
+					if emit == nil || !*emit {
+						continue
+					}
+
+					value, hasDefault := prop.DefaultValue()
+					if !hasDefault {
+						if propertyEmit != nil {
+							// Explicitly configured for this property, so the lack of a default is worth flagging
+							errs = append(errs, errors.Errorf(
+								"property %s of %s has no default value to emit", prop.PropertyName(), name))
+						}
+
+						continue
+					}
+
+					if err := checkDefaultValueSuitsType(value, prop.PropertyType(), defs); err != nil {
+						errs = append(errs, errors.Wrapf(err, "property %s of %s", prop.PropertyName(), name))
+						continue
+					}
+
+					objectType = objectType.WithProperty(prop.WithKubebuilderDefault(true))
+					modified = true
+				}
+
+				if modified {
+					updatedDefs.Add(def.WithType(objectType))
+				}
+			}
+
+			if len(errs) > 0 {
+				return nil, kerrors.NewAggregate(errs)
+			}
+
+			// Ensure that all the $emitDefaults configurations were used
+			err = kerrors.NewAggregate([]error{
+				omc.GroupEmitDefaults.VerifyConsumed(),
+				omc.TypeEmitDefaults.VerifyConsumed(),
+				omc.PropertyEmitDefaults.VerifyConsumed(),
+			})
+			if err != nil {
+				return nil, errors.Wrap(
+					err,
+					"Found unused $emitDefaults configurations; these need to be fixed or removed.")
+			}
+
+			return state.WithDefinitions(defs.OverlayWith(updatedDefs)), nil
+		})
+
+	// ARM and storage variants are created from the spec, and mustn't carry the markers
+	stage.RequiresPostrequisiteStages(CreateARMTypesStageID, CreateStorageTypesStageID)
+
+	return stage
+}
+
+// lookupEmitDefaults converts the result of an $emitDefaults lookup into a pointer which is nil when not configured
+func lookupEmitDefaults(emit bool, err error) (*bool, error) {
+	if err != nil {
+		if config.IsNotConfiguredError(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &emit, nil
+}
+
+// mostSpecificEmitDefaults returns the most specific of the configured values, or nil if none are configured
+func mostSpecificEmitDefaults(values ...*bool) *bool {
+	var result *bool
+	for _, v := range values {
+		if v != nil {
+			result = v
+		}
+	}
+
+	return result
+}
+
+// checkDefaultValueSuitsType returns an error if value can't be used as a default for the specified type, guarding
+// against Swagger specs with (say) a string default for an integer property, which would give us an invalid CRD.
+func checkDefaultValueSuitsType(value any, t astmodel.Type, defs astmodel.TypeDefinitionSet) error {
+	t = astmodel.Unwrap(t)
+	if tn, ok := astmodel.AsInternalTypeName(t); ok {
+		resolved, err := defs.FullyResolve(tn)
+		if err != nil {
+			return err
+		}
+
+		t = astmodel.Unwrap(resolved)
+	}
+
+	if enum, ok := astmodel.AsEnumType(t); ok {
+		t = enum.BaseType()
+	}
+
+	primitive, ok := astmodel.AsPrimitiveType(t)
+	if !ok {
+		return errors.Errorf("default value %v can only be emitted for primitive or enum types, not %s", value, t)
+	}
+
+	suits := false
+	switch v := value.(type) {
+	case string:
+		suits = primitive == astmodel.StringType
+	case bool:
+		suits = primitive == astmodel.BoolType
+	case int, int64:
+		suits = primitive == astmodel.IntType || primitive == astmodel.FloatType
+	case float64:
+		suits = primitive == astmodel.FloatType ||
+			(primitive == astmodel.IntType && v == math.Trunc(v))
+	}
+
+	if !suits {
+		return errors.Errorf("default value %v isn't valid for type %s", value, primitive)
+	}
+
+	return nil
+}
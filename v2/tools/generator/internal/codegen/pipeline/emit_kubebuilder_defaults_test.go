@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestGolden_EmitKubebuilderDefaults_EmitsConfiguredDefaults(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ageProperty := astmodel.NewPropertyDefinition("Age", "age", astmodel.OptionalIntType).
+		WithDefaultValue(float64(21))
+	fullName := test.FullNameProperty.WithDefaultValue("Anonymous")
+	knownAs := test.KnownAsProperty.WithDefaultValue("Anon")
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", fullName, knownAs, ageProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec)
+
+	// Opt in the whole type, then opt out KnownAs
+	configuration := config.NewConfiguration()
+	omc := configuration.ObjectModelConfiguration
+	g.Expect(
+		omc.ModifyType(
+			spec.Name(),
+			func(tc *config.TypeConfiguration) error {
+				tc.EmitDefaults.Set(true)
+				return nil
+			})).
+		To(Succeed())
+	g.Expect(
+		omc.ModifyProperty(
+			spec.Name(),
+			"KnownAs",
+			func(pc *config.PropertyConfiguration) error {
+				pc.EmitDefaults.Set(false)
+				return nil
+			})).
+		To(Succeed())
+
+	stage := EmitKubebuilderDefaults(configuration)
+
+	// Don't need a context when testing
+	finalState, err := stage.Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(Succeed())
+
+	test.AssertPackagesGenerateExpectedCode(t, finalState.Definitions())
+}
+
+func TestEmitKubebuilderDefaults_WhenDefaultDoesNotSuitType_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ageProperty := astmodel.NewPropertyDefinition("Age", "age", astmodel.OptionalIntType).
+		WithDefaultValue("twenty-one")
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", ageProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec)
+
+	configuration := config.NewConfiguration()
+	g.Expect(
+		configuration.ObjectModelConfiguration.ModifyGroup(
+			test.Pkg2020,
+			func(gc *config.GroupConfiguration) error {
+				gc.EmitDefaults.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	stage := EmitKubebuilderDefaults(configuration)
+	_, err := stage.Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(MatchError(ContainSubstring("isn't valid for type int")))
+}
+
+func TestEmitKubebuilderDefaults_WhenPropertyHasNoDefault_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty)
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec)
+
+	configuration := config.NewConfiguration()
+	g.Expect(
+		configuration.ObjectModelConfiguration.ModifyProperty(
+			spec.Name(),
+			"FullName",
+			func(pc *config.PropertyConfiguration) error {
+				pc.EmitDefaults.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	stage := EmitKubebuilderDefaults(configuration)
+	_, err := stage.Run(context.TODO(), NewState().WithDefinitions(defs))
+	g.Expect(err).To(MatchError(ContainSubstring("has no default value")))
+}
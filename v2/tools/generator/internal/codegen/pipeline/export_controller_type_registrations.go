@@ -15,14 +15,27 @@ import (
 	"github.com/Azure/azure-service-operator/v2/internal/set"
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/functions"
 )
 
+// ExportControllerResourceRegistrationsStageID is the unique identifier for this pipeline stage
+const ExportControllerResourceRegistrationsStageID = "exportControllerResourceRegistrations"
+
 // ExportControllerResourceRegistrations creates a Stage to generate type registrations
 // for resources.
-func ExportControllerResourceRegistrations(idFactory astmodel.IdentifierFactory, outputPath string) *Stage {
+// The registration file is shared by every group, so it isn't written when only some groups have been selected for
+// generation.
+// variant distinguishes the functions in the file from those written by other pipelines, so that each pipeline can
+// write its own registration file into the same package.
+func ExportControllerResourceRegistrations(
+	idFactory astmodel.IdentifierFactory,
+	configuration *config.Configuration,
+	variant string,
+) *Stage {
+	outputPath := configuration.FullTypesRegistrationOutputFilePath()
 	return NewLegacyStage(
-		"exportControllerResourceRegistrations",
+		ExportControllerResourceRegistrationsStageID,
 		fmt.Sprintf("Export resource registrations to %q", outputPath),
 		func(ctx context.Context, definitions astmodel.TypeDefinitionSet) (astmodel.TypeDefinitionSet, error) {
 			// If the configuration doesn't specify an output destination for us, just do nothing
@@ -30,55 +43,17 @@ func ExportControllerResourceRegistrations(idFactory astmodel.IdentifierFactory,
 				return definitions, nil
 			}
 
-			var resources []astmodel.InternalTypeName
-			var storageVersionResources []astmodel.InternalTypeName
-			var resourceExtensions []astmodel.InternalTypeName
-			indexFunctions := make(map[astmodel.InternalTypeName][]*functions.IndexRegistrationFunction)
-			secretPropertyKeys := make(map[astmodel.InternalTypeName][]string)
-			configMapPropertyKeys := make(map[astmodel.InternalTypeName][]string)
-
-			// We need to register each version
-			for _, def := range definitions {
-				if resource, ok := astmodel.AsResourceType(def.Type()); ok {
-
-					if resource.IsStorageVersion() {
-						storageVersionResources = append(storageVersionResources, def.Name())
-
-						secretChains, err := catalogSecretPropertyChains(def, definitions)
-						if err != nil {
-							return nil, errors.Wrapf(err, "failed to catalog %s secret property chains", def.Name())
-						}
-
-						configMapChains, err := catalogConfigMapPropertyChains(def, definitions)
-						if err != nil {
-							return nil, errors.Wrapf(err, "failed to catalog %s configmap property chains", def.Name())
-						}
-
-						resourceSecretIndexFunctions, resourceSecretPropertyKeys := transformChainsToIndexFunctionsAndKeys(secretChains, idFactory, def)
-						resourceConfigMapIndexFunctions, resourceConfigMapPropertyKeys := transformChainsToIndexFunctionsAndKeys(configMapChains, idFactory, def)
-
-						indexFunctions[def.Name()] = append(resourceSecretIndexFunctions, resourceConfigMapIndexFunctions...)
-						secretPropertyKeys[def.Name()] = resourceSecretPropertyKeys
-						configMapPropertyKeys[def.Name()] = resourceConfigMapPropertyKeys
-					}
-
-					resources = append(resources, def.Name())
-				} else if object, ok := astmodel.AsObjectType(def.Type()); ok {
-					if object.HasFunctionWithName(functions.ExtendedResourcesFunctionName) {
-						resourceExtensions = append(resourceExtensions, def.Name())
-					}
-				}
+			if configuration.HasGroupSelection() {
+				return definitions, nil
+			}
+
+			file, err := createResourceRegistrationFile(definitions, idFactory, variant)
+			if err != nil {
+				return nil, err
 			}
-			file := NewResourceRegistrationFile(
-				resources,
-				storageVersionResources,
-				indexFunctions,
-				secretPropertyKeys,
-				configMapPropertyKeys,
-				resourceExtensions)
-			fileWriter := astmodel.NewGoSourceFileWriter(file)
 
-			err := fileWriter.SaveToFile(outputPath)
+			fileWriter := astmodel.NewGoSourceFileWriter(file)
+			err = fileWriter.SaveToFile(outputPath)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to write controller type registration file to %q", outputPath)
 			}
@@ -87,6 +62,62 @@ func ExportControllerResourceRegistrations(idFactory astmodel.IdentifierFactory,
 		})
 }
 
+// createResourceRegistrationFile creates a file registering all the resources found in definitions
+func createResourceRegistrationFile(
+	definitions astmodel.TypeDefinitionSet,
+	idFactory astmodel.IdentifierFactory,
+	variant string,
+) (*ResourceRegistrationFile, error) {
+	var resources []astmodel.InternalTypeName
+	var storageVersionResources []astmodel.InternalTypeName
+	var resourceExtensions []astmodel.InternalTypeName
+	indexFunctions := make(map[astmodel.InternalTypeName][]*functions.IndexRegistrationFunction)
+	secretPropertyKeys := make(map[astmodel.InternalTypeName][]string)
+	configMapPropertyKeys := make(map[astmodel.InternalTypeName][]string)
+
+	// We need to register each version
+	for _, def := range definitions {
+		if resource, ok := astmodel.AsResourceType(def.Type()); ok {
+
+			if resource.IsStorageVersion() {
+				storageVersionResources = append(storageVersionResources, def.Name())
+
+				secretChains, err := catalogSecretPropertyChains(def, definitions)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to catalog %s secret property chains", def.Name())
+				}
+
+				configMapChains, err := catalogConfigMapPropertyChains(def, definitions)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to catalog %s configmap property chains", def.Name())
+				}
+
+				resourceSecretIndexFunctions, resourceSecretPropertyKeys := transformChainsToIndexFunctionsAndKeys(secretChains, idFactory, def)
+				resourceConfigMapIndexFunctions, resourceConfigMapPropertyKeys := transformChainsToIndexFunctionsAndKeys(configMapChains, idFactory, def)
+
+				indexFunctions[def.Name()] = append(resourceSecretIndexFunctions, resourceConfigMapIndexFunctions...)
+				secretPropertyKeys[def.Name()] = resourceSecretPropertyKeys
+				configMapPropertyKeys[def.Name()] = resourceConfigMapPropertyKeys
+			}
+
+			resources = append(resources, def.Name())
+		} else if object, ok := astmodel.AsObjectType(def.Type()); ok {
+			if object.HasFunctionWithName(functions.ExtendedResourcesFunctionName) {
+				resourceExtensions = append(resourceExtensions, def.Name())
+			}
+		}
+	}
+
+	return NewResourceRegistrationFile(
+		variant,
+		resources,
+		storageVersionResources,
+		indexFunctions,
+		secretPropertyKeys,
+		configMapPropertyKeys,
+		resourceExtensions), nil
+}
+
 func transformChainsToIndexFunctionsAndKeys(
 	chains []*propertyChain,
 	idFactory astmodel.IdentifierFactory,
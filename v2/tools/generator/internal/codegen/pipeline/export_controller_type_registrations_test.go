@@ -126,3 +126,23 @@ func (factory testChainFactory) createTestChain(chain string) *propertyChain {
 	factory[chain] = result
 	return result
 }
+
+func TestCreateResourceRegistrationFile_WithVariant_NamesFunctionsForVariant(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	idFactory := astmodel.NewIdentifierFactory()
+	file, err := createResourceRegistrationFile(make(astmodel.TypeDefinitionSet), idFactory, "DataPlane")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var buffer strings.Builder
+	g.Expect(astmodel.NewGoSourceFileWriter(file).SaveToWriter(&buffer)).To(Succeed())
+
+	content := buffer.String()
+	g.Expect(content).To(ContainSubstring("func getKnownDataPlaneStorageTypes() []*registration.StorageType"))
+	g.Expect(content).To(ContainSubstring("func getKnownDataPlaneTypes() []client.Object"))
+	g.Expect(content).To(ContainSubstring("func addDataPlaneTypesToScheme(scheme *runtime.Scheme)"))
+	g.Expect(content).To(ContainSubstring("func getDataPlaneResourceExtensions() []genruntime.ResourceExtension"))
+	g.Expect(content).ToNot(ContainSubstring("clientgoscheme"))
+	g.Expect(content).ToNot(ContainSubstring("func createScheme"))
+}
@@ -0,0 +1,568 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// ExportJSONSchemasStageID is the unique identifier for this pipeline stage
+const ExportJSONSchemasStageID = "exportJSONSchemas"
+
+// ExportJSONSchemas creates a pipeline stage that writes a standalone JSON Schema for each version of each resource,
+// allowing editors and policy engines to validate resources without access to a cluster. Schemas are written using
+// the catalog layout understood by kubeconform: <group>/<kind>_<version>.json
+func ExportJSONSchemas(configuration *config.Configuration, log logr.Logger) *Stage {
+	outputPath := configuration.FullJSONSchemaOutputPath()
+	stage := NewStage(
+		ExportJSONSchemasStageID,
+		fmt.Sprintf("Export JSON Schemas for resources to %q", outputPath),
+		func(ctx context.Context, state *State) (*State, error) {
+			// If the configuration doesn't specify an output destination for us, just do nothing
+			if outputPath == "" {
+				return state, nil
+			}
+
+			schemas, err := createResourceJSONSchemas(state.Definitions())
+			if err != nil {
+				return nil, err
+			}
+
+			// Remove stale schemas for the groups we're exporting, leaving other groups alone
+			groups := set.Make[string]()
+			for _, schema := range schemas {
+				groups.Add(schema.group)
+			}
+
+			for group := range groups {
+				err = os.RemoveAll(filepath.Join(outputPath, group))
+				if err != nil {
+					return nil, errors.Wrapf(err, "removing existing JSON Schemas for %s", group)
+				}
+			}
+
+			for _, schema := range schemas {
+				err = schema.saveTo(outputPath)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			log.Info(
+				"Exported JSON Schemas",
+				"resources", len(schemas),
+				"outputPath", outputPath)
+
+			return state, nil
+		})
+
+	// We need to know the storage version of each resource
+	stage.RequiresPrerequisiteStages(MarkLatestStorageVariantAsHubVersionID)
+
+	return stage
+}
+
+// jsonSchema is a JSON Schema (or a fragment of one); using a map gives us keys in a stable order when serialized
+type jsonSchema map[string]any
+
+// resourceJSONSchema is the JSON Schema for a single version of a resource
+type resourceJSONSchema struct {
+	group   string // Group of the resource, e.g. person.azure.com
+	kind    string // Kind of the resource
+	version string // Kubernetes version of the resource
+	schema  jsonSchema
+}
+
+// fileName returns the name of the file for this schema, relative to the root of the catalog
+func (s *resourceJSONSchema) fileName() string {
+	return filepath.Join(s.group, strings.ToLower(s.kind)+"_"+s.version+".json")
+}
+
+// saveTo writes this schema into the catalog found in the specified folder
+func (s *resourceJSONSchema) saveTo(outputPath string) error {
+	fileName := filepath.Join(outputPath, s.fileName())
+	content, err := json.MarshalIndent(s.schema, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "serializing JSON Schema for %s", s.fileName())
+	}
+
+	err = os.MkdirAll(filepath.Dir(fileName), 0o700)
+	if err != nil {
+		return errors.Wrapf(err, "creating folder for JSON Schema %s", fileName)
+	}
+
+	err = os.WriteFile(fileName, append(content, '\n'), 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "writing JSON Schema %s", fileName)
+	}
+
+	return nil
+}
+
+// createResourceJSONSchemas creates a JSON Schema for every version of every resource included in a CRD, ordered by
+// file name
+func createResourceJSONSchemas(defs astmodel.TypeDefinitionSet) ([]*resourceJSONSchema, error) {
+	resources := astmodel.FindResourceDefinitions(defs)
+
+	// Find the storage version of each resource, keyed by group and kind
+	storageVersions := make(map[string]string)
+	for name, def := range resources {
+		if astmodel.MustBeResourceType(def.Type()).IsStorageVersion() {
+			storageVersions[groupKind(name)] = astmodel.NewPackageDefinition(name.InternalPackageReference()).Version
+		}
+	}
+
+	result := make([]*resourceJSONSchema, 0, len(resources))
+	for name, def := range resources {
+		rsrc := astmodel.MustBeResourceType(def.Type())
+		if rsrc.HasAnnotation(skipVersionAnnotation) {
+			// Not included in the CRD
+			continue
+		}
+
+		storageVersion, ok := storageVersions[groupKind(name)]
+		if !ok {
+			return nil, errors.Errorf("no storage version found for resource %s", name)
+		}
+
+		builder := newJSONSchemaBuilder(defs)
+		schema, err := builder.resourceSchema(def, storageVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating JSON Schema for %s", name)
+		}
+
+		result = append(result, &resourceJSONSchema{
+			group:   name.InternalPackageReference().Group() + astmodel.GroupSuffix,
+			kind:    name.Name(),
+			version: astmodel.NewPackageDefinition(name.InternalPackageReference()).Version,
+			schema:  schema,
+		})
+	}
+
+	slices.SortFunc(
+		result,
+		func(left *resourceJSONSchema, right *resourceJSONSchema) int {
+			return strings.Compare(left.fileName(), right.fileName())
+		})
+
+	return result, nil
+}
+
+// groupKind returns a key identifying all versions of a resource
+func groupKind(name astmodel.InternalTypeName) string {
+	return name.InternalPackageReference().Group() + "/" + name.Name()
+}
+
+// jsonSchemaBuilder creates the JSON Schema for a resource, capturing each named type as a definition within the schema
+type jsonSchemaBuilder struct {
+	defs        astmodel.TypeDefinitionSet
+	definitions map[string]jsonSchema // Schemas of named types, keyed by name
+}
+
+// newJSONSchemaBuilder returns a new builder for resources within the specified set of definitions
+func newJSONSchemaBuilder(defs astmodel.TypeDefinitionSet) *jsonSchemaBuilder {
+	return &jsonSchemaBuilder{
+		defs:        defs,
+		definitions: make(map[string]jsonSchema),
+	}
+}
+
+// resourceSchema returns the JSON Schema of the specified resource, recording the storage version of the resource and
+// the version of the Azure API it was generated from
+func (b *jsonSchemaBuilder) resourceSchema(def astmodel.TypeDefinition, storageVersion string) (jsonSchema, error) {
+	name := def.Name()
+	rsrc := astmodel.MustBeResourceType(def.Type())
+	group := name.InternalPackageReference().Group() + astmodel.GroupSuffix
+	version := astmodel.NewPackageDefinition(name.InternalPackageReference()).Version
+
+	properties := jsonSchema{
+		"apiVersion": jsonSchema{
+			"type": "string",
+			"enum": []string{group + "/" + version},
+		},
+		"kind": jsonSchema{
+			"type": "string",
+			"enum": []string{name.Name()},
+		},
+		"metadata": jsonSchema{
+			"type": "object",
+		},
+	}
+
+	for _, prop := range rsrc.Properties().AsSlice() {
+		jsonName, ok := prop.JSONName()
+		if !ok {
+			continue
+		}
+
+		schema, err := b.propertySchema(prop)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating schema for property %s", prop.PropertyName())
+		}
+
+		properties[jsonName] = schema
+	}
+
+	result := jsonSchema{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"title":      name.Name(),
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"apiVersion", "kind"},
+		"x-kubernetes-group-version-kind": []jsonSchema{
+			{
+				"group":   group,
+				"kind":    name.Name(),
+				"version": version,
+			},
+		},
+		"x-aso-storage-version": storageVersion,
+	}
+
+	if desc := def.Description(); len(desc) > 0 {
+		result["description"] = strings.Join(desc, "\n")
+	}
+
+	if rsrc.HasAPIVersion() {
+		result["x-aso-azure-api-version"] = strings.Trim(rsrc.APIVersionEnumValue().Value, "\"")
+	}
+
+	if len(b.definitions) > 0 {
+		result["definitions"] = b.definitions
+	}
+
+	return result, nil
+}
+
+// propertySchema returns the JSON Schema of the specified property
+func (b *jsonSchemaBuilder) propertySchema(prop *astmodel.PropertyDefinition) (jsonSchema, error) {
+	if prop.PreservesUnknownFields() {
+		return jsonSchema{
+			"x-kubernetes-preserve-unknown-fields": true,
+		}, nil
+	}
+
+	result, err := b.typeSchema(prop.PropertyType())
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := jsonSchema{}
+	if desc := prop.Description(); desc != "" {
+		annotations["description"] = desc
+	}
+
+	if value, ok := prop.DefaultValue(); ok && prop.HasKubebuilderDefault() {
+		annotations["default"] = value
+	}
+
+	if len(annotations) == 0 {
+		return result, nil
+	}
+
+	if _, isRef := result["$ref"]; isRef {
+		// Keywords alongside $ref are ignored, so we wrap the reference
+		annotations["allOf"] = []jsonSchema{result}
+		return annotations, nil
+	}
+
+	maps.Copy(result, annotations)
+	return result, nil
+}
+
+// typeSchema returns the JSON Schema of the specified type
+func (b *jsonSchemaBuilder) typeSchema(t astmodel.Type) (jsonSchema, error) {
+	switch t := t.(type) {
+	case astmodel.InternalTypeName:
+		return b.typeNameSchema(t)
+	case astmodel.TypeName:
+		// Types from other modules (such as genruntime.ResourceReference) aren't described in detail
+		return jsonSchema{
+			"type":                                 "object",
+			"x-kubernetes-preserve-unknown-fields": true,
+		}, nil
+	case *astmodel.ValidatedType:
+		return b.validatedSchema(t)
+	case astmodel.MetaType:
+		return b.typeSchema(t.Unwrap())
+	case *astmodel.PrimitiveType:
+		return primitiveSchema(t), nil
+	case *astmodel.EnumType:
+		return enumSchema(t), nil
+	case *astmodel.ArrayType:
+		items, err := b.typeSchema(t.Element())
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonSchema{
+			"type":  "array",
+			"items": items,
+		}, nil
+	case *astmodel.MapType:
+		values, err := b.typeSchema(t.ValueType())
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonSchema{
+			"type":                 "object",
+			"additionalProperties": values,
+		}, nil
+	case *astmodel.ObjectType:
+		return b.objectSchema(t)
+	}
+
+	return nil, errors.Errorf("unable to create a JSON Schema for %s", astmodel.DebugDescription(t))
+}
+
+// typeNameSchema returns a reference to the definition of the named type, creating the definition if required
+func (b *jsonSchemaBuilder) typeNameSchema(name astmodel.InternalTypeName) (jsonSchema, error) {
+	ref := jsonSchema{
+		"$ref": "#/definitions/" + name.Name(),
+	}
+
+	if _, ok := b.definitions[name.Name()]; ok {
+		// Already defined (or in progress, if the type is recursive)
+		return ref, nil
+	}
+
+	def, err := b.defs.GetDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Placeholder to terminate recursion
+	b.definitions[name.Name()] = jsonSchema{}
+
+	schema, err := b.typeSchema(def.Type())
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating schema for %s", name)
+	}
+
+	if desc := def.Description(); len(desc) > 0 {
+		schema["description"] = strings.Join(desc, "\n")
+	}
+
+	b.definitions[name.Name()] = schema
+	return ref, nil
+}
+
+// objectSchema returns the JSON Schema of an object, including any embedded properties
+func (b *jsonSchemaBuilder) objectSchema(obj *astmodel.ObjectType) (jsonSchema, error) {
+	properties := jsonSchema{}
+	var required []string
+	for _, prop := range obj.Properties().AsSlice() {
+		jsonName, ok := prop.JSONName()
+		if !ok || jsonName == "-" {
+			continue
+		}
+
+		schema, err := b.propertySchema(prop)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating schema for property %s", prop.PropertyName())
+		}
+
+		properties[jsonName] = schema
+		if prop.IsRequired() {
+			required = append(required, jsonName)
+		}
+	}
+
+	for _, prop := range obj.EmbeddedProperties() {
+		// Embedded properties are inlined, so we merge the properties of the embedded type
+		t, err := b.defs.FullyResolve(prop.PropertyType())
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving embedded property %s", astmodel.DebugDescription(prop.PropertyType()))
+		}
+
+		embedded, ok := astmodel.AsObjectType(t)
+		if !ok {
+			continue
+		}
+
+		schema, err := b.objectSchema(embedded)
+		if err != nil {
+			return nil, err
+		}
+
+		if props, ok := schema["properties"].(jsonSchema); ok {
+			maps.Copy(properties, props)
+		}
+
+		if req, ok := schema["required"].([]string); ok {
+			required = append(required, req...)
+		}
+	}
+
+	result := jsonSchema{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		slices.Sort(required)
+		result["required"] = required
+	}
+
+	return result, nil
+}
+
+// validatedSchema returns the JSON Schema of a validated type, including its validations
+func (b *jsonSchemaBuilder) validatedSchema(t *astmodel.ValidatedType) (jsonSchema, error) {
+	result, err := b.typeSchema(t.ElementType())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, isRef := result["$ref"]; isRef {
+		// Keywords alongside $ref are ignored, so we wrap the reference
+		result = jsonSchema{
+			"allOf": []jsonSchema{result},
+		}
+	}
+
+	switch v := t.Validations().(type) {
+	case astmodel.StringValidations:
+		addStringValidations(result, v)
+	case astmodel.NumberValidations:
+		addNumberValidations(result, v)
+	case astmodel.ArrayValidations:
+		addArrayValidations(result, v)
+	}
+
+	return result, nil
+}
+
+// addStringValidations adds the specified string validations to a schema
+func addStringValidations(schema jsonSchema, v astmodel.StringValidations) {
+	if v.MinLength != nil {
+		schema["minLength"] = *v.MinLength
+	}
+
+	if v.MaxLength != nil {
+		schema["maxLength"] = *v.MaxLength
+	}
+
+	switch len(v.Patterns) {
+	case 0:
+		// Nothing to do
+	case 1:
+		schema["pattern"] = v.Patterns[0].String()
+	default:
+		// Only one pattern is permitted per schema, so all patterns must be matched via allOf
+		patterns := make([]jsonSchema, 0, len(v.Patterns))
+		for _, p := range v.Patterns {
+			patterns = append(patterns, jsonSchema{"pattern": p.String()})
+		}
+
+		schema["allOf"] = patterns
+	}
+}
+
+// addNumberValidations adds the specified number validations to a schema
+func addNumberValidations(schema jsonSchema, v astmodel.NumberValidations) {
+	if v.Minimum != nil {
+		if v.ExclusiveMinimum {
+			schema["exclusiveMinimum"] = ratAsJSONNumber(v.Minimum)
+		} else {
+			schema["minimum"] = ratAsJSONNumber(v.Minimum)
+		}
+	}
+
+	if v.Maximum != nil {
+		if v.ExclusiveMaximum {
+			schema["exclusiveMaximum"] = ratAsJSONNumber(v.Maximum)
+		} else {
+			schema["maximum"] = ratAsJSONNumber(v.Maximum)
+		}
+	}
+
+	if v.MultipleOf != nil {
+		schema["multipleOf"] = ratAsJSONNumber(v.MultipleOf)
+	}
+}
+
+// addArrayValidations adds the specified array validations to a schema
+func addArrayValidations(schema jsonSchema, v astmodel.ArrayValidations) {
+	if v.MinItems != nil {
+		schema["minItems"] = *v.MinItems
+	}
+
+	if v.MaxItems != nil {
+		schema["maxItems"] = *v.MaxItems
+	}
+
+	if v.UniqueItems {
+		schema["uniqueItems"] = true
+	}
+}
+
+// ratAsJSONNumber returns the JSON representation of a rational number
+func ratAsJSONNumber(r *big.Rat) json.Number {
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+
+	f, _ := r.Float64()
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// primitiveSchema returns the JSON Schema of a primitive type
+func primitiveSchema(t *astmodel.PrimitiveType) jsonSchema {
+	switch t.Name() {
+	case "string":
+		return jsonSchema{"type": "string"}
+	case "bool":
+		return jsonSchema{"type": "boolean"}
+	case "float32", "float64":
+		return jsonSchema{"type": "number"}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return jsonSchema{"type": "integer"}
+	}
+
+	// Any other primitive (such as interface{}) can hold any value
+	return jsonSchema{}
+}
+
+// enumSchema returns the JSON Schema of an enumeration.
+// Extensible enums permit other values, so their known values are listed as examples instead.
+func enumSchema(t *astmodel.EnumType) jsonSchema {
+	result := primitiveSchema(t.BaseType())
+
+	values := make([]any, 0, len(t.Options()))
+	for _, option := range t.Options() {
+		if result["type"] == "string" {
+			values = append(values, strings.Trim(option.Value, "\""))
+		} else {
+			values = append(values, json.Number(option.Value))
+		}
+	}
+
+	if t.PermitsUnknownValues() {
+		result["examples"] = values
+	} else {
+		result["enum"] = values
+	}
+
+	return result
+}
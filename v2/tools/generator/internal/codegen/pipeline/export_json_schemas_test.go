@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"github.com/sebdah/goldie/v2"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestGolden_ExportJSONSchemas_CreatesSchemaForResource(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gold := goldie.New(t)
+
+	defs := createPersonWithSchemaFeatures()
+
+	schemas, err := createResourceJSONSchemas(defs)
+	g.Expect(err).To(Succeed())
+	g.Expect(schemas).To(HaveLen(2))
+
+	// The API version is first, by file name
+	g.Expect(schemas[0].fileName()).To(Equal(filepath.Join("person.azure.com", "person_v20200101.json")))
+	g.Expect(schemas[1].fileName()).To(Equal(filepath.Join("person.azure.com", "person_v20200101storage.json")))
+
+	content, err := json.MarshalIndent(schemas[0].schema, "", "  ")
+	g.Expect(err).To(Succeed())
+
+	gold.Assert(t, t.Name(), content)
+}
+
+func TestExportJSONSchemas_WhenOutputPathConfigured_WritesSchemaCatalog(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	root := t.TempDir()
+	configuration := config.NewConfiguration()
+	configuration.DestinationGoModuleFile = filepath.Join(root, "go.mod")
+	configuration.JSONSchemaOutputPath = "schemas"
+
+	// A stale schema that should be removed
+	stale := filepath.Join(root, "schemas", "person.azure.com", "person_v19990101.json")
+	g.Expect(os.MkdirAll(filepath.Dir(stale), 0o700)).To(Succeed())
+	g.Expect(os.WriteFile(stale, []byte("{}"), 0o600)).To(Succeed())
+
+	state := NewState().
+		WithDefinitions(createPersonWithSchemaFeatures()).
+		WithSeenStage(MarkLatestStorageVariantAsHubVersionID)
+	_, err := ExportJSONSchemas(configuration, logr.Discard()).Run(context.TODO(), state)
+	g.Expect(err).To(Succeed())
+
+	g.Expect(stale).NotTo(BeAnExistingFile())
+
+	content, err := os.ReadFile(filepath.Join(root, "schemas", "person.azure.com", "person_v20200101.json"))
+	g.Expect(err).To(Succeed())
+
+	var schema map[string]any
+	g.Expect(json.Unmarshal(content, &schema)).To(Succeed())
+	g.Expect(schema).To(HaveKeyWithValue("x-aso-storage-version", "v20200101storage"))
+	g.Expect(schema).To(HaveKeyWithValue("x-aso-azure-api-version", "2020-01-01"))
+}
+
+// createPersonWithSchemaFeatures creates API and storage versions of a Person resource, using descriptions, enums,
+// validations and nested objects
+func createPersonWithSchemaFeatures() astmodel.TypeDefinitionSet {
+	maxLength := int64(50)
+	nickname := astmodel.NewPropertyDefinition(
+		"Nickname",
+		"nickname",
+		astmodel.NewValidatedType(
+			astmodel.StringType,
+			astmodel.StringValidations{
+				MaxLength: &maxLength,
+				Patterns:  []*regexp.Regexp{regexp.MustCompile("^[a-z]+$")},
+			})).
+		WithDescription("Informal name")
+
+	age := astmodel.NewPropertyDefinition(
+		"Age",
+		"age",
+		astmodel.NewValidatedType(
+			astmodel.IntType,
+			astmodel.NumberValidations{
+				Minimum: big.NewRat(0, 1),
+				Maximum: big.NewRat(150, 1),
+			}))
+
+	kind := astmodel.MakeTypeDefinition(
+		astmodel.MakeInternalTypeName(test.Pkg2020, "Kind"),
+		astmodel.NewEnumType(
+			astmodel.StringType,
+			astmodel.MakeEnumValue("Contractor", "\"Contractor\""),
+			astmodel.MakeEnumValue("Permanent", "\"Permanent\"")))
+
+	address := test.CreateObjectDefinition(test.Pkg2020, "Address", test.FullAddressProperty, test.CityProperty).
+		WithDescription("A postal address")
+
+	apiVersion := astmodel.MakeTypeDefinition(
+		astmodel.MakeInternalTypeName(test.Pkg2020, "APIVersion"),
+		astmodel.NewEnumType(astmodel.StringType, astmodel.MakeEnumValue("Value", "\"2020-01-01\"")))
+
+	spec := test.CreateSpec(
+		test.Pkg2020,
+		"Person",
+		test.OptionalStringProperty.MakeRequired(),
+		nickname,
+		age,
+		astmodel.NewPropertyDefinition("Kind", "kind", astmodel.NewOptionalType(kind.Name())),
+		astmodel.NewPropertyDefinition("Addresses", "addresses", astmodel.NewArrayType(address.Name())).
+			WithDescription("Places the person lives"))
+	status := test.CreateStatus(test.Pkg2020, "Person")
+	resource := test.CreateARMResource(test.Pkg2020, "Person", spec, status, apiVersion).
+		WithDescription("A person")
+
+	storageSpec := test.CreateSpec(test.Pkg2020s, "Person", test.FullNameProperty, test.PropertyBagProperty)
+	storageStatus := test.CreateStatus(test.Pkg2020s, "Person")
+	storage := test.CreateResource(test.Pkg2020s, "Person", storageSpec, storageStatus)
+	storage = storage.WithType(astmodel.MustBeResourceType(storage.Type()).MarkAsStorageVersion())
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, spec, status, kind, address, apiVersion, storage, storageSpec, storageStatus)
+	return defs
+}
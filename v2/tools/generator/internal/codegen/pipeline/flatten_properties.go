@@ -185,7 +185,13 @@ func flattenProperty(
 	}
 
 	for i, p := range props {
-		props[i] = p.AddFlattenedFrom(prop.PropertyName())
+		p = p.AddFlattenedFrom(prop.PropertyName())
+		if prop.IsImmutable() {
+			// Properties flattened out of a create-only property are themselves create-only
+			p = p.WithIsImmutable(true)
+		}
+
+		props[i] = p
 	}
 
 	return props, nil
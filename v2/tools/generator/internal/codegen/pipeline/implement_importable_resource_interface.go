@@ -87,6 +87,11 @@ func createImportableResourceImplementation(
 		return nil, errors.Errorf("expected %q to be a resource", def.Name())
 	}
 
+	if rsrc.IsAction() {
+		// Actions have no state in Azure, so there's nothing to import
+		return nil, nil
+	}
+
 	// Find the InterfaceInitialization function on the Spec, so we can call it later
 	specDef, err := defs.ResolveResourceSpecDefinition(rsrc)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/dave/dst"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astbuilder"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
@@ -102,6 +103,19 @@ func InjectPropertyAssignmentFunctions(
 				result[modified.Name()] = modified
 			}
 
+			// Ensure that all the configured property conversions were used
+			omc := configuration.ObjectModelConfiguration
+			err := kerrors.NewAggregate([]error{
+				omc.ConvertEnumValues.VerifyConsumed(),
+				omc.ConvertVia.VerifyConsumed(),
+				omc.SplitInto.VerifyConsumed(),
+			})
+			if err != nil {
+				return nil, errors.Wrap(
+					err,
+					"Found unused $convertVia, $convertEnumValues or $splitInto configurations; these need to be fixed or removed.")
+			}
+
 			return state.WithDefinitions(result), nil
 		})
 
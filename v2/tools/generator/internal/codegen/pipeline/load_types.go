@@ -313,10 +313,12 @@ func loadSwaggerData(
 ) (jsonast.SwaggerTypes, error) {
 	schemas, err := loadAllSchemas(
 		ctx,
+		config.Pipeline,
 		config.SchemaRoot,
 		config.LocalPathPrefix(),
 		idFactory,
 		config.Status.Overrides,
+		config.IsGroupLoaded,
 		log)
 	if err != nil {
 		return jsonast.SwaggerTypes{}, err
@@ -664,10 +666,17 @@ var skipDirectories = []string{
 	"/examples/",
 	"/quickstart-templates/",
 	"/control-plane/",
-	"/data-plane/",
 }
 
-func shouldSkipDir(filePath string) bool {
+// skipDirectoriesByPipeline lists additional directories to skip, depending on whether we're generating resources
+// managed through ARM or through the data plane of each service
+var skipDirectoriesByPipeline = map[config.GenerationPipeline][]string{
+	config.GenerationPipelineAzure:      {"/data-plane/"},
+	config.GenerationPipelineCrossplane: {"/data-plane/"},
+	config.GenerationPipelineDataPlane:  {"/resource-manager/"},
+}
+
+func shouldSkipDir(filePath string, pipeline config.GenerationPipeline) bool {
 	p := filepath.ToSlash(filePath)
 
 	for _, skipDir := range skipDirectories {
@@ -676,18 +685,69 @@ func shouldSkipDir(filePath string) bool {
 		}
 	}
 
+	for _, skipDir := range skipDirectoriesByPipeline[pipeline] {
+		if strings.Contains(p, skipDir) {
+			return true
+		}
+	}
+
 	return false
 }
 
+// FindSchemaFiles walks all .json files in the schema root (excluding those matching shouldSkipDir), returning the
+// files that will be loaded for each group, keyed by the name of the group in generated code. All other files are
+// returned separately, as any group may reference them (e.g. common-types).
+func FindSchemaFiles(
+	configuration *config.Configuration,
+	idFactory astmodel.IdentifierFactory,
+) (map[string][]string, []string, error) {
+	rootPath := configuration.SchemaRoot
+	groupFiles := make(map[string][]string)
+	var otherFiles []string
+
+	err := filepath.Walk(rootPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if shouldSkipDir(filePath, configuration.Pipeline) {
+			return filepath.SkipDir // this is a magic error
+		}
+
+		if fileInfo.IsDir() || filepath.Ext(filePath) != ".json" {
+			return nil
+		}
+
+		group := groupFromPath(filePath, rootPath, configuration.Status.Overrides)
+		version := versionFromPath(filePath, rootPath)
+		if group == "" || version == "" {
+			otherFiles = append(otherFiles, filePath)
+			return nil
+		}
+
+		name := idFactory.CreateGroupName(group)
+		groupFiles[name] = append(groupFiles[name], filePath)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "scanning for schemas in %q", rootPath)
+	}
+
+	return groupFiles, otherFiles, nil
+}
+
 // loadAllSchemas walks all .json files in the given rootPath in directories
 // of the form "Microsoft.GroupName/…/2000-01-01/…" (excluding those matching
-// shouldSkipDir), and returns those files in a map of path→swagger spec.
+// shouldSkipDir, or belonging to a group not selected by isGroupLoaded), and
+// returns those files in a map of path→swagger spec.
 func loadAllSchemas(
 	ctx context.Context,
+	pipeline config.GenerationPipeline,
 	rootPath string,
 	localPathPrefix string,
 	idFactory astmodel.IdentifierFactory,
 	overrides []config.SchemaOverride,
+	isGroupLoaded func(group string) bool,
 	log logr.Logger,
 ) (map[string]jsonast.PackageAndSwagger, error) {
 	var mutex sync.Mutex
@@ -707,7 +767,7 @@ func loadAllSchemas(
 			return ctx.Err()
 		}
 
-		if shouldSkipDir(filePath) {
+		if shouldSkipDir(filePath, pipeline) {
 			return filepath.SkipDir // this is a magic error
 		}
 
@@ -720,9 +780,14 @@ func loadAllSchemas(
 				return nil
 			}
 
+			groupName := idFactory.CreateGroupName(group)
+			if !isGroupLoaded(groupName) {
+				return nil
+			}
+
 			pkg := astmodel.MakeLocalPackageReference(
 				localPathPrefix,
-				idFactory.CreateGroupName(group),
+				groupName,
 				astmodel.GeneratorVersion,
 				version)
 
@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
 )
 
@@ -31,6 +32,8 @@ func Test_ShouldSkipDir_GivenPath_HasExpectedResult(t *testing.T) {
 		{"Skip top level", "/examples/", true},
 		{"Skip nested", "/foo/examples/", true},
 		{"Skip nested, trailing directory", "/foo/examples/bar/", true},
+		{"Skip data plane", "/foo/data-plane/bar/", true},
+		{"Include resource manager", "/foo/resource-manager/bar/", false},
 	}
 
 	windowsCases := []struct {
@@ -62,13 +65,22 @@ func Test_ShouldSkipDir_GivenPath_HasExpectedResult(t *testing.T) {
 			t.Parallel()
 			g := NewGomegaWithT(t)
 
-			skipped := shouldSkipDir(c.path)
+			skipped := shouldSkipDir(c.path, config.GenerationPipelineAzure)
 
 			g.Expect(skipped).To(Equal(c.shouldSkip))
 		})
 	}
 }
 
+func Test_ShouldSkipDir_ForDataPlanePipeline_SkipsResourceManager(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(shouldSkipDir("/keyvault/resource-manager/Microsoft.KeyVault/", config.GenerationPipelineDataPlane)).To(BeTrue())
+	g.Expect(shouldSkipDir("/keyvault/data-plane/Microsoft.KeyVault/", config.GenerationPipelineDataPlane)).To(BeFalse())
+	g.Expect(shouldSkipDir("/keyvault/data-plane/examples/", config.GenerationPipelineDataPlane)).To(BeTrue())
+}
+
 func Test_StructurallyIdentical_RecursesIntoTypeNames(t *testing.T) {
 	t.Parallel()
 	g := NewGomegaWithT(t)
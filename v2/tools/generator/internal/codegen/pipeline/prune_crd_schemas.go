@@ -0,0 +1,260 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// PruneCRDSchemasStageID is the unique identifier for this pipeline stage
+const PruneCRDSchemasStageID = "pruneCRDSchemas"
+
+// PruneCRDSchemas creates a pipeline stage that reduces the size of the CRD schemas of resources where configured, so
+// that large resources stay within the limits of the API server. Strategies are opt-in for each resource:
+//
+// $crdCollapseDescriptions cuts the description of each spec and status property down to its first sentence.
+//
+// $crdStatusSchemaDepth replaces the schema of object valued status properties at the given depth with
+// x-kubernetes-preserve-unknown-fields; the API server no longer validates or prunes those subtrees, which is safe for
+// status as it's only written by the operator.
+//
+// $crdExcludeStorageVersion leaves the (non-hub) storage variant of the resource out of the CRD. This is only safe
+// once no cluster has resources persisted in that version.
+//
+// Configuration applies to both the API and storage variants of a resource. As types may be shared between resources
+// in the same package, pruning one resource can affect another.
+func PruneCRDSchemas(configuration *config.Configuration) *Stage {
+	stage := NewStage(
+		PruneCRDSchemasStageID,
+		"Prune the CRD schemas of resources where configured",
+		func(ctx context.Context, state *State) (*State, error) {
+			omc := configuration.ObjectModelConfiguration
+			pruner := newCRDSchemaPruner(state.Definitions())
+
+			var errs []error
+			for name, def := range astmodel.FindResourceDefinitions(state.Definitions()) {
+				rsrc := astmodel.MustBeResourceType(def.Type())
+				err := pruner.prune(name, rsrc, omc)
+				if err != nil {
+					errs = append(errs, errors.Wrapf(err, "pruning CRD schema of %s", name))
+				}
+			}
+
+			if len(errs) > 0 {
+				return nil, kerrors.NewAggregate(errs)
+			}
+
+			// Ensure that all the CRD pruning configurations were used
+			err := kerrors.NewAggregate([]error{
+				omc.CRDCollapseDescriptions.VerifyConsumed(),
+				omc.CRDExcludeStorageVersion.VerifyConsumed(),
+				omc.CRDStatusSchemaDepth.VerifyConsumed(),
+			})
+			if err != nil {
+				return nil, errors.Wrap(
+					err,
+					"Found unused CRD pruning configurations; these need to be fixed or removed.")
+			}
+
+			return state.WithDefinitions(state.Definitions().OverlayWith(pruner.updated)), nil
+		})
+
+	// We need to know which storage variant is the hub before we can safely exclude any others
+	stage.RequiresPrerequisiteStages(MarkLatestStorageVariantAsHubVersionID)
+
+	return stage
+}
+
+// crdSchemaPruner accumulates the modifications made to definitions while pruning the schemas of resources
+type crdSchemaPruner struct {
+	defs    astmodel.TypeDefinitionSet
+	updated astmodel.TypeDefinitionSet
+}
+
+// newCRDSchemaPruner returns a new pruner for resources within the specified set of definitions
+func newCRDSchemaPruner(defs astmodel.TypeDefinitionSet) *crdSchemaPruner {
+	return &crdSchemaPruner{
+		defs:    defs,
+		updated: make(astmodel.TypeDefinitionSet),
+	}
+}
+
+// prune applies the configured pruning strategies to the specified resource
+func (p *crdSchemaPruner) prune(
+	name astmodel.InternalTypeName,
+	rsrc *astmodel.ResourceType,
+	omc *config.ObjectModelConfiguration,
+) error {
+	collapse, err := lookupOptional(omc.CRDCollapseDescriptions.Lookup(name))
+	if err != nil {
+		return err
+	}
+
+	depth, err := lookupOptional(omc.CRDStatusSchemaDepth.Lookup(name))
+	if err != nil {
+		return err
+	}
+
+	exclude, err := lookupOptional(omc.CRDExcludeStorageVersion.Lookup(name))
+	if err != nil {
+		return err
+	}
+
+	if collapse {
+		err = p.collapseDescriptions(name.InternalPackageReference(), rsrc)
+		if err != nil {
+			return err
+		}
+	}
+
+	if depth > 0 {
+		if status, ok := astmodel.AsInternalTypeName(rsrc.StatusType()); ok {
+			p.preserveUnknownFieldsBelow(status, depth)
+		}
+	}
+
+	// API versions always remain part of the CRD, we only exclude storage variants
+	if exclude && astmodel.IsStoragePackageReference(name.PackageReference()) {
+		if rsrc.IsStorageVersion() {
+			return errors.Errorf(
+				"the storage version of a CRD can't be excluded; remove $crdExcludeStorageVersion from %s",
+				name.InternalPackageReference().PackageName())
+		}
+
+		def := p.definition(name)
+		p.update(def.WithType(rsrc.WithAnnotation(skipVersionAnnotation)))
+	}
+
+	return nil
+}
+
+// collapseDescriptions cuts down the descriptions of all properties of the spec and status of the resource
+func (p *crdSchemaPruner) collapseDescriptions(pkg astmodel.InternalPackageReference, rsrc *astmodel.ResourceType) error {
+	roots := make(astmodel.TypeDefinitionSet)
+	for _, t := range []astmodel.Type{rsrc.SpecType(), rsrc.StatusType()} {
+		if tn, ok := astmodel.AsInternalTypeName(t); ok {
+			roots.Add(p.definition(tn))
+		}
+	}
+
+	connected, err := astmodel.FindConnectedDefinitions(p.defs, roots)
+	if err != nil {
+		return errors.Wrap(err, "finding definitions used by spec and status")
+	}
+
+	for name := range connected {
+		// Types in other packages have their own configuration
+		if !name.InternalPackageReference().Equals(pkg) {
+			continue
+		}
+
+		def := p.definition(name)
+		obj, ok := astmodel.AsObjectType(def.Type())
+		if !ok {
+			continue
+		}
+
+		for _, prop := range obj.Properties().Copy() {
+			obj = obj.WithProperty(prop.WithDescription(firstSentence(prop.Description())))
+		}
+
+		p.update(def.WithType(obj))
+	}
+
+	return nil
+}
+
+// preserveUnknownFieldsBelow walks the status tree breadth first from the specified type, marking object valued
+// properties found at the maximum depth to preserve unknown fields instead of having a schema. Walking breadth first
+// ensures types reachable by multiple paths are pruned at their shallowest depth.
+func (p *crdSchemaPruner) preserveUnknownFieldsBelow(status astmodel.InternalTypeName, maxDepth int) {
+	visited := set.Make(status)
+	pending := []astmodel.InternalTypeName{status}
+	for depth := 1; len(pending) > 0; depth++ {
+		var next []astmodel.InternalTypeName
+		for _, name := range pending {
+			def := p.definition(name)
+			obj, ok := astmodel.AsObjectType(def.Type())
+			if !ok {
+				continue
+			}
+
+			modified := false
+			for _, prop := range obj.Properties().Copy() {
+				tn, ok := astmodel.ExtractTypeName(prop.PropertyType())
+				if !ok || !p.isObject(tn) {
+					// Only object valued properties have a schema worth pruning
+					continue
+				}
+
+				if depth == maxDepth {
+					obj = obj.WithProperty(prop.WithPreserveUnknownFields(true))
+					modified = true
+				} else if !visited.Contains(tn) {
+					visited.Add(tn)
+					next = append(next, tn)
+				}
+			}
+
+			if modified {
+				p.update(def.WithType(obj))
+			}
+		}
+
+		pending = next
+	}
+}
+
+// definition returns the current definition of the named type, including any modifications already made
+func (p *crdSchemaPruner) definition(name astmodel.InternalTypeName) astmodel.TypeDefinition {
+	if def, ok := p.updated[name]; ok {
+		return def
+	}
+
+	return p.defs.MustGetDefinition(name)
+}
+
+// update records a modified definition, replacing any earlier modification
+func (p *crdSchemaPruner) update(def astmodel.TypeDefinition) {
+	p.updated[def.Name()] = def
+}
+
+// isObject returns true if the named type is an object
+func (p *crdSchemaPruner) isObject(name astmodel.InternalTypeName) bool {
+	def, ok := p.defs[name]
+	if !ok {
+		return false
+	}
+
+	_, ok = astmodel.AsObjectType(def.Type())
+	return ok
+}
+
+// firstSentence returns the first sentence of the specified description
+func firstSentence(description string) string {
+	if i := strings.Index(description, ". "); i >= 0 {
+		return description[:i+1]
+	}
+
+	return description
+}
+
+// lookupOptional converts the result of a configuration lookup, returning the zero value if not configured
+func lookupOptional[T any](value T, err error) (T, error) {
+	if config.IsNotConfiguredError(err) {
+		return *new(T), nil
+	}
+
+	return value, err
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestGolden_PruneCRDSchemas_PrunesConfiguredResources(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	knownAs := test.KnownAsProperty.WithDescription("How the person is generally known. Often a nickname or diminutive.")
+	spec := test.CreateSpec(test.Pkg2020, "Person", test.FullNameProperty, knownAs)
+
+	address := test.CreateObjectDefinition(
+		test.Pkg2020, "Address_STATUS", test.FullAddressProperty, test.CityProperty)
+	residence := test.CreateObjectDefinition(
+		test.Pkg2020,
+		"Residence_STATUS",
+		astmodel.NewPropertyDefinition("Address", "address", astmodel.NewOptionalType(address.Name())),
+		test.CityProperty)
+	status := test.CreateStatus(
+		test.Pkg2020,
+		"Person",
+		astmodel.NewPropertyDefinition("Residence", "residence", astmodel.NewOptionalType(residence.Name())))
+	resource := test.CreateResource(test.Pkg2020, "Person", spec, status)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(resource, status, spec, residence, address)
+
+	configuration := config.NewConfiguration()
+	g.Expect(
+		configuration.ObjectModelConfiguration.ModifyType(
+			resource.Name(),
+			func(tc *config.TypeConfiguration) error {
+				tc.CRDCollapseDescriptions.Set(true)
+				tc.CRDStatusSchemaDepth.Set(2)
+				return nil
+			})).
+		To(Succeed())
+
+	stage := PruneCRDSchemas(configuration)
+
+	// Don't need a context when testing
+	state := NewState().WithDefinitions(defs).WithSeenStage(MarkLatestStorageVariantAsHubVersionID)
+	finalState, err := stage.Run(context.TODO(), state)
+	g.Expect(err).To(Succeed())
+
+	test.AssertPackagesGenerateExpectedCode(t, finalState.Definitions())
+}
+
+func TestPruneCRDSchemas_WhenExcludingStorageVersion_ExcludesItFromCRD(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	defs, person2020s, _ := createPersonStorageVersions()
+
+	configuration := config.NewConfiguration()
+	g.Expect(
+		configuration.ObjectModelConfiguration.ModifyType(
+			person2020s,
+			func(tc *config.TypeConfiguration) error {
+				tc.CRDExcludeStorageVersion.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	state := NewState().WithDefinitions(defs).WithSeenStage(MarkLatestStorageVariantAsHubVersionID)
+	finalState, err := PruneCRDSchemas(configuration).Run(context.TODO(), state)
+	g.Expect(err).To(Succeed())
+
+	rsrc, ok := astmodel.AsResourceType(finalState.Definitions()[person2020s].Type())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(rsrc.HasAnnotation(skipVersionAnnotation)).To(BeTrue())
+
+	crds := estimateCRDSchemaSizes(finalState.Definitions())
+	g.Expect(crds).To(HaveLen(1))
+	g.Expect(crds[0].versions).To(HaveKey("v20211231storage"))
+	g.Expect(crds[0].versions).NotTo(HaveKey("v20200101storage"))
+}
+
+func TestPruneCRDSchemas_WhenExcludingHubVersion_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	defs, _, person2021s := createPersonStorageVersions()
+
+	configuration := config.NewConfiguration()
+	g.Expect(
+		configuration.ObjectModelConfiguration.ModifyType(
+			person2021s,
+			func(tc *config.TypeConfiguration) error {
+				tc.CRDExcludeStorageVersion.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	state := NewState().WithDefinitions(defs).WithSeenStage(MarkLatestStorageVariantAsHubVersionID)
+	_, err := PruneCRDSchemas(configuration).Run(context.TODO(), state)
+	g.Expect(err).To(MatchError(ContainSubstring("storage version of a CRD can't be excluded")))
+}
+
+// createPersonStorageVersions creates two storage variants of a Person resource, marking the later one as the hub.
+// Returns the definitions and the names of both resources.
+func createPersonStorageVersions() (astmodel.TypeDefinitionSet, astmodel.InternalTypeName, astmodel.InternalTypeName) {
+	defs := make(astmodel.TypeDefinitionSet)
+	var names []astmodel.InternalTypeName
+	for _, pkg := range []astmodel.InternalPackageReference{test.Pkg2020s, test.Pkg2021s} {
+		spec := test.CreateSpec(pkg, "Person", test.FullNameProperty)
+		status := test.CreateStatus(pkg, "Person")
+		resource := test.CreateResource(pkg, "Person", spec, status)
+		defs.AddAll(resource, status, spec)
+		names = append(names, resource.Name())
+	}
+
+	hub := defs[names[1]]
+	defs[names[1]] = hub.WithType(astmodel.MustBeResourceType(hub.Type()).MarkAsStorageVersion())
+
+	return defs, names[0], names[1]
+}
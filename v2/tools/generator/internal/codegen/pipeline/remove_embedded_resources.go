@@ -22,7 +22,7 @@ func RemoveEmbeddedResources(
 	configuration *config.Configuration,
 	log logr.Logger,
 ) *Stage {
-	return NewLegacyStage(
+	stage := NewLegacyStage(
 		RemoveEmbeddedResourcesStageID,
 		// Only removes structural aspects of embedded resources, Id/ARMId references are retained.
 		"Remove properties that point to embedded resources.",
@@ -34,4 +34,9 @@ func RemoveEmbeddedResources(
 
 			return remover.RemoveEmbeddedResources(log)
 		})
+
+	// Sub resources in other groups are needed to find the embedded resources to remove
+	stage.RequiresPostrequisiteStages(RemoveUnselectedGroupsStageID)
+
+	return stage
 }
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"context"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// RemoveUnselectedGroupsStageID is the unique identifier for this pipeline stage
+const RemoveUnselectedGroupsStageID = "removeUnselectedGroups"
+
+// RemoveUnselectedGroups creates a pipeline stage that removes definitions from any group not selected for output.
+// When generating a subset of groups, we also load the groups they use; once cross group references have been
+// collapsed, the selected groups are self-contained, so this stage discards the other groups (along with their
+// configuration) to avoid running them through the rest of the pipeline. Has no effect if no group selection has
+// been made.
+func RemoveUnselectedGroups(configuration *config.Configuration) *Stage {
+	stage := NewStage(
+		RemoveUnselectedGroupsStageID,
+		"Remove definitions from groups not selected for output",
+		func(ctx context.Context, state *State) (*State, error) {
+			if !configuration.HasGroupSelection() {
+				return state, nil
+			}
+
+			defs := state.Definitions().Where(
+				func(def astmodel.TypeDefinition) bool {
+					return configuration.IsGroupEmitted(def.Name().InternalPackageReference().Group())
+				})
+
+			configuration.DiscardUnemittedGroups()
+
+			return state.WithDefinitions(defs), nil
+		})
+
+	stage.RequiresPostrequisiteStages(DeleteGeneratedCodeStageID)
+
+	return stage
+}
@@ -7,7 +7,9 @@ package pipeline
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"sort"
 
 	"github.com/pkg/errors"
 
@@ -19,7 +21,8 @@ import (
 // ReportResourceStructureStageId is the unique identifier for this stage
 const ReportResourceStructureStageId = "reportResourceStructure"
 
-// ReportResourceStructure creates a pipeline stage that reports the structure of resources in each package
+// ReportResourceStructure creates a pipeline stage that reports the structure of resources in each package, along
+// with the estimated size of the CRD schema of each resource
 func ReportResourceStructure(configuration *config.Configuration) *Stage {
 	return NewStage(
 		ReportResourceStructureStageId,
@@ -32,12 +35,14 @@ func ReportResourceStructure(configuration *config.Configuration) *Stage {
 }
 
 type ResourceStructureReport struct {
-	lists map[astmodel.InternalPackageReference]astmodel.TypeDefinitionSet // A separate list of resources for each package
+	lists       map[astmodel.InternalPackageReference]astmodel.TypeDefinitionSet // A separate list of resources for each package
+	schemaSizes map[astmodel.InternalTypeName]int                                // Estimated CRD schema size of each resource
 }
 
 func NewResourceStructureReport(defs astmodel.TypeDefinitionSet) *ResourceStructureReport {
 	result := &ResourceStructureReport{
-		lists: make(map[astmodel.InternalPackageReference]astmodel.TypeDefinitionSet),
+		lists:       make(map[astmodel.InternalPackageReference]astmodel.TypeDefinitionSet),
+		schemaSizes: make(map[astmodel.InternalTypeName]int),
 	}
 
 	result.summarize(defs)
@@ -59,6 +64,7 @@ func (report *ResourceStructureReport) SaveReports(baseFolder string) error {
 
 // summarize collates a list of all definitions, grouped by package
 func (report *ResourceStructureReport) summarize(definitions astmodel.TypeDefinitionSet) {
+	estimator := newCRDSchemaSizeEstimator(definitions)
 	for name, def := range definitions {
 		pkg := name.InternalPackageReference()
 
@@ -67,12 +73,39 @@ func (report *ResourceStructureReport) summarize(definitions astmodel.TypeDefini
 		}
 
 		report.lists[pkg].Add(def)
+
+		if rsrc, ok := astmodel.AsResourceType(def.Type()); ok && !rsrc.HasAnnotation(skipVersionAnnotation) {
+			report.schemaSizes[name] = estimator.estimateResource(rsrc)
+		}
 	}
 }
 
 func (report *ResourceStructureReport) saveReport(filePath string, defs astmodel.TypeDefinitionSet) error {
 	rpt := reporting.NewTypeCatalogReport(defs, reporting.InlineTypes)
 	rpt.AddHeader(astmodel.CodeGenerationComments...)
+	rpt.AddHeader(report.schemaSizeComments(defs)...)
 	err := rpt.SaveTo(filePath)
 	return errors.Wrapf(err, "unable to save type catalog report to %q", filePath)
 }
+
+// schemaSizeComments returns header lines giving the estimated CRD schema size of each resource in defs
+func (report *ResourceStructureReport) schemaSizeComments(defs astmodel.TypeDefinitionSet) []string {
+	names := make([]astmodel.InternalTypeName, 0, len(defs))
+	for name := range defs {
+		if _, ok := report.schemaSizes[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].Name() < names[j].Name()
+	})
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, fmt.Sprintf(
+			"// Estimated CRD schema size of %s: %d bytes", name.Name(), report.schemaSizes[name]))
+	}
+
+	return result
+}
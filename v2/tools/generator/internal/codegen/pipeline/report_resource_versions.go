@@ -46,13 +46,20 @@ func ReportResourceVersions(configuration *config.Configuration) *Stage {
 				return nil, err
 			}
 
-			err = report.SaveAllResourcesReportTo(configuration.SupportedResourcesReport.FullOutputPath())
-			if err != nil {
-				return nil, err
+			// The report of all resources is shared by every group, so we only write it when generating every group
+			if !configuration.HasGroupSelection() {
+				err = report.SaveAllResourcesReportTo(configuration.SupportedResourcesReport.FullOutputPath())
+				if err != nil {
+					return nil, err
+				}
 			}
 
 			var errs []error
 			for grp := range report.groups {
+				if !configuration.IsGroupEmitted(grp) {
+					continue
+				}
+
 				outputFile := configuration.SupportedResourcesReport.GroupFullOutputPath(grp)
 				err = report.SaveGroupResourcesReportTo(grp, outputFile)
 				if err != nil {
@@ -64,6 +71,11 @@ func ReportResourceVersions(configuration *config.Configuration) *Stage {
 				return nil, kerrors.NewAggregate(errs)
 			}
 
+			if configuration.HasGroupSelection() {
+				// Groups loaded but not selected for output have no resources, so their configuration isn't used
+				return state, nil
+			}
+
 			err = configuration.ObjectModelConfiguration.SupportedFrom.VerifyConsumed()
 			return state, err
 		})
@@ -24,16 +24,45 @@ import (
 const ReportOnTypesAndVersionsStageID = "reportTypesAndVersions"
 
 // ReportOnTypesAndVersions creates a pipeline stage that generates a report for each group showing a matrix of all
-// types and versions
+// types and versions. The report is written by ExportTypesAndVersionsReport, so no files are written until the
+// definitions are ready for export.
 func ReportOnTypesAndVersions(configuration *config.Configuration) *Stage {
-	return NewLegacyStage(
+	stage := NewStage(
 		ReportOnTypesAndVersionsStageID,
 		"Generate reports on types and versions in each package",
-		func(ctx context.Context, definitions astmodel.TypeDefinitionSet) (astmodel.TypeDefinitionSet, error) {
+		func(ctx context.Context, state *State) (*State, error) {
 			report := NewPackagesMatrixReport()
-			report.Summarize(definitions)
+			report.Summarize(
+				state.Definitions().Where(
+					func(def astmodel.TypeDefinition) bool {
+						return configuration.IsGroupEmitted(def.Name().InternalPackageReference().Group())
+					}))
+
+			return state.WithTypesAndVersionsReport(report), nil
+		})
+
+	stage.RequiresPostrequisiteStages(ExportTypesAndVersionsReportStageID)
+
+	return stage
+}
+
+// ExportTypesAndVersionsReportStageID is the unique identifier of this stage
+const ExportTypesAndVersionsReportStageID = "exportTypesAndVersionsReport"
+
+// ExportTypesAndVersionsReport creates a pipeline stage that writes the report generated by ReportOnTypesAndVersions
+func ExportTypesAndVersionsReport(configuration *config.Configuration) *Stage {
+	return NewStage(
+		ExportTypesAndVersionsReportStageID,
+		"Export reports on types and versions in each package",
+		func(ctx context.Context, state *State) (*State, error) {
+			report := state.TypesAndVersionsReport()
+			if report == nil {
+				// No report to write
+				return state, nil
+			}
+
 			err := report.WriteTo(configuration.FullTypesOutputPath())
-			return definitions, err
+			return state, err
 		})
 }
 
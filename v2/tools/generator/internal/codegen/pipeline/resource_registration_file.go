@@ -6,6 +6,7 @@
 package pipeline
 
 import (
+	"fmt"
 	"github.com/pkg/errors"
 	"go/token"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -20,7 +21,10 @@ import (
 
 // ResourceRegistrationFile is a file containing functions that assist in registering resources
 // with a Kubernetes scheme.
+// Each pipeline writes its own file; the functions in each are distinguished by the variant, which is empty for the
+// ARM pipeline (getKnownTypes) and DataPlane for the data plane pipeline (getKnownDataPlaneTypes).
 type ResourceRegistrationFile struct {
+	variant                 string
 	resources               []astmodel.InternalTypeName
 	storageVersionResources []astmodel.InternalTypeName
 	resourceExtensions      []astmodel.InternalTypeName
@@ -34,6 +38,7 @@ var _ astmodel.GoSourceFile = &ResourceRegistrationFile{}
 // NewResourceRegistrationFile returns a ResourceRegistrationFile for registering the specified resources
 // with a controller
 func NewResourceRegistrationFile(
+	variant string,
 	resources []astmodel.InternalTypeName,
 	storageVersionResources []astmodel.InternalTypeName,
 	indexFunctions map[astmodel.InternalTypeName][]*functions.IndexRegistrationFunction,
@@ -42,6 +47,7 @@ func NewResourceRegistrationFile(
 	resourceExtensions []astmodel.InternalTypeName,
 ) *ResourceRegistrationFile {
 	return &ResourceRegistrationFile{
+		variant:                 variant,
 		resources:               resources,
 		storageVersionResources: storageVersionResources,
 		indexFunctions:          indexFunctions,
@@ -84,14 +90,20 @@ func (r *ResourceRegistrationFile) AsAst() (*dst.File, error) {
 	decls = append(decls, knownStorageTypes)
 
 	// getKnownTypes() function
-	knownTypes, err := createGetKnownTypesFunc(codeGenContext, r.resources)
+	knownTypes, err := createGetKnownTypesFunc(codeGenContext, "getKnown"+r.variant+"Types", r.resources)
 	if err != nil {
 		return nil, err
 	}
 	decls = append(decls, knownTypes)
 
-	// createScheme() function
-	createSchemeFunc, err := r.createCreateSchemeFunc(codeGenContext)
+	// createScheme() function, or add<Variant>TypesToScheme() for other variants
+	var createSchemeFunc dst.Decl
+	if r.variant == "" {
+		createSchemeFunc, err = r.createCreateSchemeFunc(codeGenContext)
+	} else {
+		createSchemeFunc, err = r.createAddToSchemeFunc(codeGenContext)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -145,15 +157,40 @@ func (r *ResourceRegistrationFile) generateImports() *astmodel.PackageImportSet
 
 	// We require these imports
 	requiredImports.AddImport(astmodel.NewPackageImport(astmodel.GenRuntimeReference))
-	requiredImports.AddImport(astmodel.NewPackageImport(astmodel.ClientGoSchemeReference).WithName("clientgoscheme"))
 	requiredImports.AddImport(astmodel.NewPackageImport(astmodel.APIMachineryRuntimeReference))
 	requiredImports.AddImport(astmodel.NewPackageImport(astmodel.ControllerRuntimeClient))
 	requiredImports.AddImport(astmodel.NewPackageImport(astmodel.GenRuntimeRegistrationReference))
-	requiredImports.AddImport(astmodel.NewPackageImport(astmodel.CoreV1Reference))
+
+	// Only createScheme() adds the clientgo types
+	if r.variant == "" {
+		requiredImports.AddImport(astmodel.NewPackageImport(astmodel.ClientGoSchemeReference).WithName("clientgoscheme"))
+	}
+
+	// Watches are only needed for resources referring to secrets or config maps
+	if r.hasWatches() {
+		requiredImports.AddImport(astmodel.NewPackageImport(astmodel.CoreV1Reference))
+	}
 
 	return requiredImports
 }
 
+// hasWatches returns true if any of the resources need to watch secrets or config maps
+func (r *ResourceRegistrationFile) hasWatches() bool {
+	for _, keys := range r.secretPropertyKeys {
+		if len(keys) > 0 {
+			return true
+		}
+	}
+
+	for _, keys := range r.configMapPropertyKeys {
+		if len(keys) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 func orderByImportedTypeName(
 	codeGenerationContext *astmodel.CodeGenerationContext,
 	resources []astmodel.InternalTypeName,
@@ -196,9 +233,9 @@ func orderByFunctionName(functions []*functions.IndexRegistrationFunction) func(
 //	}
 func createGetKnownTypesFunc(
 	codeGenerationContext *astmodel.CodeGenerationContext,
+	funcName string,
 	resources []astmodel.InternalTypeName,
 ) (dst.Decl, error) {
-	funcName := "getKnownTypes"
 	funcComment := "returns the list of all types."
 
 	client, err := codeGenerationContext.GetImportedPackageName(astmodel.ControllerRuntimeClient)
@@ -276,7 +313,7 @@ func (r *ResourceRegistrationFile) createGetKnownStorageTypesFunc(
 	codeGenerationContext *astmodel.CodeGenerationContext,
 ) dst.Decl {
 
-	funcName := "getKnownStorageTypes"
+	funcName := "getKnown" + r.variant + "StorageTypes"
 	funcComment := "returns the list of storage types which can be reconciled."
 
 	resultIdent := dst.NewIdent("result")
@@ -355,7 +392,7 @@ func (r *ResourceRegistrationFile) createGetKnownStorageTypesFunc(
 }
 
 func (r *ResourceRegistrationFile) createGetResourceExtensions(context *astmodel.CodeGenerationContext) dst.Decl {
-	funcName := "getResourceExtensions"
+	funcName := "get" + r.variant + "ResourceExtensions"
 	funcComment := "returns a list of resource extensions"
 
 	sort.Slice(r.resourceExtensions, orderByImportedTypeName(context, r.resourceExtensions))
@@ -463,6 +500,54 @@ func (r *ResourceRegistrationFile) createCreateSchemeFunc(codeGenerationContext
 	return f.DefineFunc(), nil
 }
 
+// createAddToSchemeFunc creates an add<Variant>TypesToScheme() function, for adding the types of a variant to the
+// scheme created by createScheme(), like:
+//
+//	func addDataPlaneTypesToScheme(scheme *runtime.Scheme) {
+//		_ = keyvaultdatav20230701.AddToScheme(scheme)
+//		_ = keyvaultdatav20230701storage.AddToScheme(scheme)
+//	}
+func (r *ResourceRegistrationFile) createAddToSchemeFunc(codeGenerationContext *astmodel.CodeGenerationContext) (dst.Decl, error) {
+	runtime, err := codeGenerationContext.GetImportedPackageName(astmodel.APIMachineryRuntimeReference)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "scheme"
+
+	importedPackages := r.getImportedPackages()
+	importedPackageNames := make([]string, 0, len(importedPackages))
+	for pkg := range importedPackages {
+		packageName, err := codeGenerationContext.GetImportedPackageName(pkg)
+		if err != nil {
+			return nil, err
+		}
+		importedPackageNames = append(importedPackageNames, packageName)
+	}
+
+	// Sort the slice for reproducibility
+	sort.Strings(importedPackageNames)
+
+	groupVersionAssignments := make([]dst.Stmt, 0, len(importedPackageNames))
+	for _, group := range importedPackageNames {
+		groupSchemeAssign := astbuilder.SimpleAssignment(
+			dst.NewIdent("_"),
+			astbuilder.CallQualifiedFunc(group, "AddToScheme", dst.NewIdent(scheme)))
+
+		groupVersionAssignments = append(groupVersionAssignments, groupSchemeAssign)
+	}
+
+	f := &astbuilder.FuncDetails{
+		Name: "add" + r.variant + "TypesToScheme",
+		Body: astbuilder.Statements(groupVersionAssignments),
+	}
+
+	f.AddParameter(scheme, astbuilder.Dereference(astbuilder.Selector(dst.NewIdent(runtime), "Scheme")))
+	f.AddComments(fmt.Sprintf("adds the types returned by getKnown%sTypes to the given Scheme", r.variant))
+
+	return f.DefineFunc(), nil
+}
+
 func (r *ResourceRegistrationFile) defineIndexFunctions(
 	codeGenerationContext *astmodel.CodeGenerationContext,
 ) ([]dst.Decl, error) {
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+)
+
+// SharedOutputFingerprints returns, for each group, a fingerprint of the content it contributes to the outputs shared
+// by every group: the controller resource registration file and the report of all supported resources. Neither is
+// written when only some groups are generated, so incremental generation compares these fingerprints with those from
+// an earlier run to detect when a full run is needed to update them.
+func SharedOutputFingerprints(
+	definitions astmodel.TypeDefinitionSet,
+	idFactory astmodel.IdentifierFactory,
+	configuration *config.Configuration,
+) (map[string]string, error) {
+	groups := set.Make[string]()
+	definitionsByGroup := make(map[string]astmodel.TypeDefinitionSet)
+	for name, def := range definitions {
+		group := name.InternalPackageReference().Group()
+		groups.Add(group)
+		if _, ok := definitionsByGroup[group]; !ok {
+			definitionsByGroup[group] = make(astmodel.TypeDefinitionSet)
+		}
+
+		definitionsByGroup[group].Add(def)
+	}
+
+	report, err := NewResourceVersionsReport(definitions, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	for group := range report.groups {
+		groups.Add(group)
+	}
+
+	result := make(map[string]string, len(groups))
+	for group := range groups {
+		hash := sha256.New()
+
+		if configuration.FullTypesRegistrationOutputFilePath() != "" {
+			err = writeResourceRegistrations(definitionsByGroup[group], idFactory, hash)
+			if err != nil {
+				return nil, errors.Wrapf(err, "creating resource registrations for group %s", group)
+			}
+		}
+
+		if report.groups.Contains(group) {
+			var buffer strings.Builder
+			err = report.WriteGroupResourcesReportToBuffer(group, "", &buffer)
+			if err != nil {
+				return nil, errors.Wrapf(err, "creating resource versions report for group %s", group)
+			}
+
+			_, err = io.WriteString(hash, buffer.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result[group] = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return result, nil
+}
+
+func writeResourceRegistrations(
+	definitions astmodel.TypeDefinitionSet,
+	idFactory astmodel.IdentifierFactory,
+	writer io.Writer,
+) error {
+	file, err := createResourceRegistrationFile(definitions, idFactory, "")
+	if err != nil {
+		return err
+	}
+
+	return astmodel.NewGoSourceFileWriter(file).SaveToWriter(writer)
+}
@@ -108,7 +108,6 @@ var knownLegacyStages = set.Make(
 	"removeAliases",
 	"removeEmbeddedResources",
 	"replaceAnyTypeWithJSON",
-	"reportTypesAndVersions",
 	"rogueCheck",
 	"simplifyDefinitions",
 	"stripUnreferenced")
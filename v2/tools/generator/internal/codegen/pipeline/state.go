@@ -19,6 +19,8 @@ type State struct {
 	definitions        astmodel.TypeDefinitionSet // set of type definitions generated so far
 	conversionGraph    *storage.ConversionGraph   // graph of transitions between packages in our conversion graph
 	exportedConfigMaps *ExportedTypeNameProperties
+	groupDependencies  map[string]set.Set[string] // for each group, the other groups it uses
+	typesAndVersions   *PackagesMatrixReport      // report of the types and versions in each package, written on export
 	stagesSeen         set.Set[string]            // set of ids of the stages already run
 	stagesExpected     map[string]set.Set[string] // set of ids of expected stages, each with a set of ids for the stages expecting them
 }
@@ -75,6 +77,28 @@ func (s *State) WithGeneratedConfigMaps(exportedConfigMaps *ExportedTypeNameProp
 	return result
 }
 
+// WithGroupDependencies returns a new independent State with the given group dependencies
+func (s *State) WithGroupDependencies(dependencies map[string]set.Set[string]) *State {
+	if s.groupDependencies != nil {
+		panic("may only set the group dependencies once")
+	}
+
+	result := s.copy()
+	result.groupDependencies = dependencies
+	return result
+}
+
+// WithTypesAndVersionsReport returns a new independent State with the given report of types and versions
+func (s *State) WithTypesAndVersionsReport(report *PackagesMatrixReport) *State {
+	if s.typesAndVersions != nil {
+		panic("may only set the types and versions report once")
+	}
+
+	result := s.copy()
+	result.typesAndVersions = report
+	return result
+}
+
 // WithSeenStage records that the passed stage has been seen
 func (s *State) WithSeenStage(id string) *State {
 	result := s.copy()
@@ -112,6 +136,16 @@ func (s *State) GeneratedConfigMaps() *ExportedTypeNameProperties {
 	return s.exportedConfigMaps
 }
 
+// GroupDependencies returns, for each group, the set of other groups it uses (may be nil)
+func (s *State) GroupDependencies() map[string]set.Set[string] {
+	return s.groupDependencies
+}
+
+// TypesAndVersionsReport returns the report of types and versions in each package (may be nil)
+func (s *State) TypesAndVersionsReport() *PackagesMatrixReport {
+	return s.typesAndVersions
+}
+
 // CheckFinalState checks that our final state is valid, returning an error if not
 func (s *State) CheckFinalState() error {
 	var errs []error
@@ -130,6 +164,8 @@ func (s *State) copy() *State {
 		definitions:        s.definitions.Copy(),
 		conversionGraph:    s.conversionGraph,
 		exportedConfigMaps: s.exportedConfigMaps.Copy(),
+		groupDependencies:  s.groupDependencies,
+		typesAndVersions:   s.typesAndVersions,
 		stagesSeen:         s.stagesSeen,
 		stagesExpected:     s.stagesExpected,
 	}
@@ -27,6 +27,10 @@ var (
 
 	// CrossplaneTarget is used to tag stages that are required when generating types for working with Crossplane
 	CrossplaneTarget Target = MakePipelineTarget("crossplane")
+
+	// DataPlaneTarget is used to tag stages that are required when generating types for working with the data plane
+	// of Azure services (such as Key Vault secrets), rather than with ARM
+	DataPlaneTarget Target = MakePipelineTarget("dataplane")
 )
 
 func MakePipelineTarget(tag string) Target {
@@ -45,6 +49,8 @@ func TranslatePipelineToTarget(pipeline config.GenerationPipeline) (Target, erro
 		return ARMTarget, nil
 	case config.GenerationPipelineCrossplane:
 		return CrossplaneTarget, nil
+	case config.GenerationPipelineDataPlane:
+		return DataPlaneTarget, nil
 	default:
 		return Target{}, errors.Errorf("unknown pipeline target kind %s", pipeline)
 	}
@@ -57,6 +57,24 @@ func TestGolden_NewCrossplaneCodeGeneratorFromConfigCreatesRightPipeline(t *test
 	gold.Assert(t, t.Name(), result)
 }
 
+func TestGolden_NewDataPlaneCodeGeneratorFromConfigCreatesRightPipeline(t *testing.T) {
+	t.Parallel()
+	gold := goldie.New(t)
+	g := NewGomegaWithT(t)
+
+	idFactory := astmodel.NewIdentifierFactory()
+	configuration := config.NewConfiguration()
+
+	codegen, err := NewTargetedCodeGeneratorFromConfig(configuration, idFactory, pipeline.DataPlaneTarget, logr.Discard())
+	g.Expect(err).To(Succeed())
+
+	result := writePipeline("Expected Pipeline Stages for Data Plane Code Generation", codegen)
+
+	// When reviewing changes to the golden file, ensure they make sense in the context of an operator built to work
+	// against the data plane of Azure services - we don't want to see any Crossplane specific stages showing up there.
+	gold.Assert(t, t.Name(), result)
+}
+
 func TestGolden_NewTestCodeGeneratorCreatesRightPipeline(t *testing.T) {
 	t.Parallel()
 	gold := goldie.New(t)
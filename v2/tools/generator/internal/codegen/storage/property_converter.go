@@ -178,6 +178,7 @@ func (p *PropertyConverter) defaultPropertyConversion(
 	newProperty := property.WithType(propertyType).
 		MakeOptional().
 		MakeTypeOptional().
+		WithKubebuilderDefault(false).
 		WithDescription("")
 
 	return newProperty, nil
@@ -53,6 +53,9 @@ func (t *TypeConverter) ConvertDefinition(def astmodel.TypeDefinition) (astmodel
 	description := t.descriptionForStorageVariant(def)
 	result = result.WithDescription(description...)
 
+	// Storage variants are permissive by design; validation rules are only enforced on API versions
+	result = result.WithoutValidations()
+
 	return result, nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"gopkg.in/yaml.v3"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
+	"github.com/Azure/azure-service-operator/v2/internal/set"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
 )
 
@@ -26,6 +27,7 @@ type GenerationPipeline string
 const (
 	GenerationPipelineAzure      = GenerationPipeline("azure")
 	GenerationPipelineCrossplane = GenerationPipeline("crossplane")
+	GenerationPipelineDataPlane  = GenerationPipeline("dataplane")
 )
 
 // Configuration is used to control which types get generated
@@ -53,14 +55,23 @@ type Configuration struct {
 	RootURL string `yaml:"rootUrl"`
 	// SamplesPath is the Path the samples are accessible at. This is used to walk through the samples directory and generate sample links.
 	SamplesPath string `yaml:"samplesPath"`
+	// The folder relative to the go.mod file path where standalone JSON Schemas for each resource version should be
+	// written, in the catalog layout used by kubeconform. If omitted, JSON Schemas are not exported.
+	JSONSchemaOutputPath string `yaml:"jsonSchemaOutputPath"`
 	// EmitDocFiles is used as a signal to create doc.go files for packages. If omitted, default is false.
 	EmitDocFiles bool `yaml:"emitDocFiles"`
 	// Destination file and additional information for our supported resources report
 	SupportedResourcesReport *SupportedResourcesReport `yaml:"supportedResourcesReport"`
+	// The maximum estimated size (in bytes) of the schema of each generated CRD, summed across all its versions.
+	// Generation fails if any CRD is larger. If omitted (or zero), CRD sizes are not checked.
+	CRDSchemaSizeBudget int `yaml:"crdSchemaSizeBudget"`
 	// Additional information about our object model
 	ObjectModelConfiguration *ObjectModelConfiguration `yaml:"objectModelConfiguration"`
 
-	goModulePath string
+	goModulePath  string
+	configPath    string          // absolute path of the file this configuration was loaded from
+	loadedGroups  set.Set[string] // if set, only these groups are loaded from Swagger
+	emittedGroups set.Set[string] // if set, only these groups have code written
 }
 
 type RewriteRule struct {
@@ -88,6 +99,18 @@ func (config *Configuration) FullTypesRegistrationOutputFilePath() string {
 		config.TypeRegistrationOutputFile)
 }
 
+// FullJSONSchemaOutputPath returns the folder where JSON Schemas for each resource version should be written, or
+// empty string if they shouldn't be exported
+func (config *Configuration) FullJSONSchemaOutputPath() string {
+	if config.JSONSchemaOutputPath == "" {
+		return ""
+	}
+
+	return filepath.Join(
+		filepath.Dir(config.DestinationGoModuleFile),
+		config.JSONSchemaOutputPath)
+}
+
 func (config *Configuration) FullSamplesPath() string {
 	if filepath.IsAbs(config.SamplesPath) {
 		return config.SamplesPath
@@ -108,6 +131,11 @@ func (config *Configuration) FullSamplesPath() string {
 }
 
 func (config *Configuration) GetTypeFiltersError() error {
+	if config.HasGroupSelection() {
+		// Filters for other groups won't match; only a full run can tell if a filter is unused
+		return nil
+	}
+
 	for _, filter := range config.TypeFilters {
 		if err := filter.RequiredTypesWereMatched(); err != nil {
 			return errors.Wrapf(err, "type filter action: %q", filter.Action)
@@ -118,6 +146,11 @@ func (config *Configuration) GetTypeFiltersError() error {
 }
 
 func (config *Configuration) GetTransformersError() error {
+	if config.HasGroupSelection() {
+		// Transformers for other groups won't match; only a full run can tell if a transformer is unused
+		return nil
+	}
+
 	for _, filter := range config.Transformers {
 		if err := filter.RequiredTypesWereMatched(); err != nil {
 			return errors.Wrap(err, "type transformer")
@@ -133,6 +166,59 @@ func (config *Configuration) GetTransformersError() error {
 	return nil
 }
 
+// ConfigurationFilePath returns the absolute path of the file this configuration was loaded from, or an empty string
+// if it wasn't loaded from a file.
+func (config *Configuration) ConfigurationFilePath() string {
+	return config.configPath
+}
+
+// SelectGroups restricts code generation to a subset of groups.
+// emitted is the set of groups for which code will be written.
+// loaded is the set of groups loaded from Swagger; must include all emitted groups, along with any groups they use.
+// Configuration for other groups is discarded, and we no longer require every type filter and transformer to be used,
+// as that can only be verified by a run including every group.
+func (config *Configuration) SelectGroups(emitted []string, loaded []string) {
+	config.emittedGroups = set.Make[string]()
+	config.loadedGroups = set.Make[string]()
+	for _, g := range emitted {
+		config.emittedGroups.Add(strings.ToLower(g))
+		config.loadedGroups.Add(strings.ToLower(g))
+	}
+
+	for _, g := range loaded {
+		config.loadedGroups.Add(strings.ToLower(g))
+	}
+
+	config.ObjectModelConfiguration.retainGroups(config.loadedGroups)
+}
+
+// DiscardUnemittedGroups discards configuration for groups loaded only because emitted groups use them, so that
+// configuration for those groups isn't reported as unused once their definitions have been removed.
+// Has no effect if no group selection has been made.
+func (config *Configuration) DiscardUnemittedGroups() {
+	if !config.HasGroupSelection() {
+		return
+	}
+
+	config.loadedGroups = config.emittedGroups.Copy()
+	config.ObjectModelConfiguration.retainGroups(config.loadedGroups)
+}
+
+// HasGroupSelection returns true if code generation has been restricted to a subset of groups
+func (config *Configuration) HasGroupSelection() bool {
+	return config.loadedGroups != nil
+}
+
+// IsGroupLoaded returns true if Swagger for the specified group should be loaded
+func (config *Configuration) IsGroupLoaded(group string) bool {
+	return config.loadedGroups == nil || config.loadedGroups.Contains(strings.ToLower(group))
+}
+
+// IsGroupEmitted returns true if code for the specified group should be written
+func (config *Configuration) IsGroupEmitted(group string) bool {
+	return config.emittedGroups == nil || config.emittedGroups.Contains(strings.ToLower(group))
+}
+
 func (config *Configuration) SetGoModulePath(path string) {
 	config.goModulePath = path
 }
@@ -253,6 +339,7 @@ func (config *Configuration) initialize(configPath string) error {
 		return errors.Wrapf(err, "unable to find absolute config file location")
 	}
 
+	config.configPath = absConfigLocation
 	configDirectory := filepath.Dir(absConfigLocation)
 
 	// resolve SchemaRoot relative to config file directory
@@ -277,6 +364,8 @@ func (config *Configuration) initialize(configPath string) error {
 			config.Pipeline = GenerationPipelineAzure
 		case string(GenerationPipelineCrossplane):
 			config.Pipeline = GenerationPipelineCrossplane
+		case string(GenerationPipelineDataPlane):
+			config.Pipeline = GenerationPipelineDataPlane
 		default:
 			errs = append(errs, errors.Errorf("unknown pipeline kind %s", config.Pipeline))
 		}
@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
 )
 
 func Test_CanMakeUnixStylePathIntoURL(t *testing.T) {
@@ -26,3 +29,69 @@ func Test_CanMakeWindowsPathIntoURL(t *testing.T) {
 	url := absDirectoryPathToURL("D:\\yellow\\brick\\road")
 	g.Expect(url.String()).To(Equal("file:///D:/yellow/brick/road/"))
 }
+
+func TestConfiguration_SelectGroups_DiscardsConfigurationForOtherGroups(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	person := astmodel.MakeInternalTypeName(test.Pkg2020, "Person")
+	address := astmodel.MakeInternalTypeName(test.MakeLocalPackageReference(test.BatchGroup, "v20200101"), "Address")
+
+	config := NewConfiguration()
+	omc := config.ObjectModelConfiguration
+	for _, name := range []astmodel.InternalTypeName{person, address} {
+		g.Expect(
+			omc.ModifyType(
+				name,
+				func(tc *TypeConfiguration) error {
+					tc.NameInNextVersion.Set("Party")
+					return nil
+				})).
+			To(Succeed())
+	}
+
+	config.SelectGroups([]string{test.Pkg2020.Group()}, nil)
+
+	g.Expect(config.HasGroupSelection()).To(BeTrue())
+	g.Expect(config.IsGroupEmitted(test.Pkg2020.Group())).To(BeTrue())
+	g.Expect(config.IsGroupLoaded(test.BatchGroup)).To(BeFalse())
+
+	// Only configuration for the selected group needs to be consumed
+	_, err := omc.TypeNameInNextVersion.Lookup(person)
+	g.Expect(err).To(Succeed())
+	g.Expect(omc.TypeNameInNextVersion.VerifyConsumed()).To(Succeed())
+}
+
+func TestConfiguration_DiscardUnemittedGroups_DiscardsConfigurationForLoadedGroups(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	person := astmodel.MakeInternalTypeName(test.Pkg2020, "Person")
+	address := astmodel.MakeInternalTypeName(test.MakeLocalPackageReference(test.BatchGroup, "v20200101"), "Address")
+
+	config := NewConfiguration()
+	omc := config.ObjectModelConfiguration
+	for _, name := range []astmodel.InternalTypeName{person, address} {
+		g.Expect(
+			omc.ModifyType(
+				name,
+				func(tc *TypeConfiguration) error {
+					tc.NameInNextVersion.Set("Party")
+					return nil
+				})).
+			To(Succeed())
+	}
+
+	config.SelectGroups([]string{test.Pkg2020.Group()}, []string{test.BatchGroup})
+	g.Expect(config.IsGroupLoaded(test.BatchGroup)).To(BeTrue())
+
+	config.DiscardUnemittedGroups()
+
+	g.Expect(config.IsGroupEmitted(test.Pkg2020.Group())).To(BeTrue())
+	g.Expect(config.IsGroupLoaded(test.BatchGroup)).To(BeFalse())
+
+	// Configuration for the loaded group no longer needs to be consumed
+	_, err := omc.TypeNameInNextVersion.Lookup(person)
+	g.Expect(err).To(Succeed())
+	g.Expect(omc.TypeNameInNextVersion.VerifyConsumed()).To(Succeed())
+}
@@ -32,7 +32,19 @@ type GroupConfiguration struct {
 	versions map[string]*VersionConfiguration
 	advisor  *typo.Advisor
 	// Configurable properties here (alphabetical, please)
-	PayloadType configurable[PayloadType]
+	DataPlane    configurable[DataPlane]
+	EmitDefaults configurable[bool]
+	PayloadType  configurable[PayloadType]
+}
+
+// DataPlane describes how to reach the data plane of an Azure service, for groups of resources (such as Key Vault
+// secrets) that are managed through the data plane rather than through ARM.
+type DataPlane struct {
+	Audience     string `yaml:"audience"`     // Audience for which access tokens are acquired, e.g. https://vault.azure.net
+	Endpoint     string `yaml:"endpoint"`     // Endpoint of the data plane, with {owner} standing in for the name of the owner
+	Path         string `yaml:"path"`         // Path of each resource in the data plane, with {name} standing in for its name
+	Owner        string `yaml:"owner"`        // Group and kind of the ARM resource owning the data plane, e.g. keyvault/Vault
+	OwnerVersion string `yaml:"ownerVersion"` // API version of the owner to refer to, e.g. 2021-04-01-preview
 }
 
 type PayloadType string
@@ -45,7 +57,9 @@ const (
 )
 
 const (
-	payloadTypeTag = "$payloadType" // Enumeration specifying what kind of payload to send to ARM.
+	dataPlaneTag    = "$dataPlane"    // Details of the data plane through which resources in the group are managed
+	emitDefaultsTag = "$emitDefaults" // Boolean specifying whether Swagger defaults are emitted as CRD defaults
+	payloadTypeTag  = "$payloadType"  // Enumeration specifying what kind of payload to send to ARM.
 )
 
 // NewGroupConfiguration returns a new (empty) GroupConfiguration
@@ -56,7 +70,9 @@ func NewGroupConfiguration(name string) *GroupConfiguration {
 		versions: make(map[string]*VersionConfiguration),
 		advisor:  typo.NewAdvisor(),
 		// Initialize configurable properties here (alphabetical, please)
-		PayloadType: makeConfigurable[PayloadType](payloadTypeTag, scope),
+		DataPlane:    makeConfigurable[DataPlane](dataPlaneTag, scope),
+		EmitDefaults: makeConfigurable[bool](emitDefaultsTag, scope),
+		PayloadType:  makeConfigurable[PayloadType](payloadTypeTag, scope),
 	}
 }
 
@@ -177,6 +193,31 @@ func (gc *GroupConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $dataPlane: <mapping>
+		// Must be handled before versions, as it's also a mapping
+		if strings.EqualFold(lastId, dataPlaneTag) && c.Kind == yaml.MappingNode {
+			var dataPlane DataPlane
+			err := c.Decode(&dataPlane)
+			if err != nil {
+				return errors.Wrapf(err, "decoding yaml for %q", lastId)
+			}
+
+			if dataPlane.Audience == "" ||
+				dataPlane.Endpoint == "" ||
+				dataPlane.Path == "" ||
+				dataPlane.Owner == "" ||
+				dataPlane.OwnerVersion == "" {
+				return errors.Errorf(
+					"%s requires audience, endpoint, path, owner and ownerVersion (line %d col %d)",
+					dataPlaneTag,
+					c.Line,
+					c.Column)
+			}
+
+			gc.DataPlane.Set(dataPlane)
+			continue
+		}
+
 		// Handle nested version metadata
 		if c.Kind == yaml.MappingNode {
 			v := NewVersionConfiguration(lastId)
@@ -189,6 +230,18 @@ func (gc *GroupConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $emitDefaults: <bool>
+		if strings.EqualFold(lastId, emitDefaultsTag) && c.Kind == yaml.ScalarNode {
+			var emitDefaults bool
+			err := c.Decode(&emitDefaults)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", emitDefaultsTag)
+			}
+
+			gc.EmitDefaults.Set(emitDefaults)
+			continue
+		}
+
 		// $payloadType: <string>
 		if strings.EqualFold(lastId, payloadTypeTag) && c.Kind == yaml.ScalarNode {
 			switch strings.ToLower(c.Value) {
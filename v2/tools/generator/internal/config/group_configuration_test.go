@@ -186,3 +186,52 @@ func TestGroupConfiguration_VerifyPayloadTypeConsumed_WhenNotConsumed_ReturnsExp
 	g.Expect(err).NotTo(BeNil())
 	g.Expect(err.Error()).To(ContainSubstring(groupConfig.name))
 }
+
+/*
+ * DataPlane tests
+ */
+
+func TestGroupConfiguration_WhenYAMLHasDataPlane_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	yamlText := `
+$dataPlane:
+  audience: https://vault.azure.net
+  endpoint: https://{owner}.vault.azure.net
+  path: /secrets/{name}
+  owner: keyvault/Vault
+  ownerVersion: 2021-04-01-preview
+7.4:
+  Secret:
+    $export: true
+`
+
+	var group GroupConfiguration
+	g.Expect(yaml.Unmarshal([]byte(yamlText), &group)).To(Succeed())
+	g.Expect(group.versions).To(HaveKey("7.4"))
+
+	dataPlane, err := group.DataPlane.Lookup()
+	g.Expect(err).To(Succeed())
+	g.Expect(dataPlane).To(Equal(DataPlane{
+		Audience:     "https://vault.azure.net",
+		Endpoint:     "https://{owner}.vault.azure.net",
+		Path:         "/secrets/{name}",
+		Owner:        "keyvault/Vault",
+		OwnerVersion: "2021-04-01-preview",
+	}))
+}
+
+func TestGroupConfiguration_WhenDataPlaneIncomplete_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	yamlText := `
+$dataPlane:
+  audience: https://vault.azure.net
+`
+
+	var group GroupConfiguration
+	err := yaml.Unmarshal([]byte(yamlText), &group)
+	g.Expect(err).To(MatchError(ContainSubstring(dataPlaneTag)))
+}
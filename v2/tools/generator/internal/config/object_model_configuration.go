@@ -14,6 +14,7 @@ import (
 	"gopkg.in/yaml.v3"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
+	"github.com/Azure/azure-service-operator/v2/internal/set"
 	"github.com/Azure/azure-service-operator/v2/internal/util/typo"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
 )
@@ -31,10 +32,15 @@ type ObjectModelConfiguration struct {
 	typoAdvisor *typo.Advisor
 
 	// Group access fields here (alphabetical, please)
-	PayloadType groupAccess[PayloadType]
+	DataPlane         groupAccess[DataPlane]
+	GroupEmitDefaults groupAccess[bool]
+	PayloadType       groupAccess[PayloadType]
 
 	// Type access fields here (alphabetical, please)
 	AzureGeneratedSecrets    typeAccess[[]string]
+	CRDCollapseDescriptions  typeAccess[bool]
+	CRDExcludeStorageVersion typeAccess[bool]
+	CRDStatusSchemaDepth     typeAccess[int]
 	DefaultAzureName         typeAccess[bool]
 	Export                   typeAccess[bool]
 	ExportAs                 typeAccess[string]
@@ -45,14 +51,24 @@ type ObjectModelConfiguration struct {
 	RenameTo                 typeAccess[string]
 	ResourceEmbeddedInParent typeAccess[string]
 	SupportedFrom            typeAccess[string]
+	TypeEmitDefaults         typeAccess[bool]
+	TypeExtensibleEnum       typeAccess[bool]
 	TypeNameInNextVersion    typeAccess[string]
+	Validations              typeAccess[[]ValidationRule]
 
 	// Property access fields here (alphabetical, please)
 	ARMReference                   propertyAccess[bool]
+	ConvertEnumValues              propertyAccess[map[string]string]
+	ConvertVia                     propertyAccess[ConvertVia]
+	Immutable                      propertyAccess[bool]
 	ImportConfigMapMode            propertyAccess[ImportConfigMapMode]
 	IsSecret                       propertyAccess[bool]
+	PropertyEmitDefaults           propertyAccess[bool]
+	PropertyExtensibleEnum         propertyAccess[bool]
+	PropertyNameInNextVersion      propertyAccess[string]
 	RenamePropertyTo               propertyAccess[string]
 	ResourceLifecycleOwnedByParent propertyAccess[string]
+	SplitInto                      propertyAccess[[]string]
 }
 
 type groupAccess[T any] struct {
@@ -78,12 +94,22 @@ func NewObjectModelConfiguration() *ObjectModelConfiguration {
 	}
 
 	// Initialize group access fields here (alphabetical, please)
+	result.DataPlane = makeGroupAccess[DataPlane](
+		result, func(c *GroupConfiguration) *configurable[DataPlane] { return &c.DataPlane })
+	result.GroupEmitDefaults = makeGroupAccess[bool](
+		result, func(c *GroupConfiguration) *configurable[bool] { return &c.EmitDefaults })
 	result.PayloadType = makeGroupAccess[PayloadType](
 		result, func(c *GroupConfiguration) *configurable[PayloadType] { return &c.PayloadType })
 
 	// Initialize type access fields here (alphabetical, please)
 	result.AzureGeneratedSecrets = makeTypeAccess[[]string](
 		result, func(c *TypeConfiguration) *configurable[[]string] { return &c.AzureGeneratedSecrets })
+	result.CRDCollapseDescriptions = makeTypeAccess[bool](
+		result, func(c *TypeConfiguration) *configurable[bool] { return &c.CRDCollapseDescriptions })
+	result.CRDExcludeStorageVersion = makeTypeAccess[bool](
+		result, func(c *TypeConfiguration) *configurable[bool] { return &c.CRDExcludeStorageVersion })
+	result.CRDStatusSchemaDepth = makeTypeAccess[int](
+		result, func(c *TypeConfiguration) *configurable[int] { return &c.CRDStatusSchemaDepth })
 	result.DefaultAzureName = makeTypeAccess[bool](
 		result, func(c *TypeConfiguration) *configurable[bool] { return &c.DefaultAzureName })
 	result.Export = makeTypeAccess[bool](
@@ -104,20 +130,40 @@ func NewObjectModelConfiguration() *ObjectModelConfiguration {
 		result, func(c *TypeConfiguration) *configurable[string] { return &c.ResourceEmbeddedInParent })
 	result.SupportedFrom = makeTypeAccess[string](
 		result, func(c *TypeConfiguration) *configurable[string] { return &c.SupportedFrom })
+	result.TypeEmitDefaults = makeTypeAccess[bool](
+		result, func(c *TypeConfiguration) *configurable[bool] { return &c.EmitDefaults })
+	result.TypeExtensibleEnum = makeTypeAccess[bool](
+		result, func(c *TypeConfiguration) *configurable[bool] { return &c.ExtensibleEnum })
 	result.TypeNameInNextVersion = makeTypeAccess[string](
 		result, func(c *TypeConfiguration) *configurable[string] { return &c.NameInNextVersion })
+	result.Validations = makeTypeAccess[[]ValidationRule](
+		result, func(c *TypeConfiguration) *configurable[[]ValidationRule] { return &c.Validations })
 
 	// Initialize property access fields here (alphabetical, please)
 	result.ARMReference = makePropertyAccess[bool](
 		result, func(c *PropertyConfiguration) *configurable[bool] { return &c.ARMReference })
+	result.ConvertEnumValues = makePropertyAccess[map[string]string](
+		result, func(c *PropertyConfiguration) *configurable[map[string]string] { return &c.ConvertEnumValues })
+	result.ConvertVia = makePropertyAccess[ConvertVia](
+		result, func(c *PropertyConfiguration) *configurable[ConvertVia] { return &c.ConvertVia })
+	result.Immutable = makePropertyAccess[bool](
+		result, func(c *PropertyConfiguration) *configurable[bool] { return &c.Immutable })
 	result.ImportConfigMapMode = makePropertyAccess[ImportConfigMapMode](
 		result, func(c *PropertyConfiguration) *configurable[ImportConfigMapMode] { return &c.ImportConfigMapMode })
 	result.IsSecret = makePropertyAccess[bool](
 		result, func(c *PropertyConfiguration) *configurable[bool] { return &c.IsSecret })
+	result.PropertyEmitDefaults = makePropertyAccess[bool](
+		result, func(c *PropertyConfiguration) *configurable[bool] { return &c.EmitDefaults })
+	result.PropertyExtensibleEnum = makePropertyAccess[bool](
+		result, func(c *PropertyConfiguration) *configurable[bool] { return &c.ExtensibleEnum })
+	result.PropertyNameInNextVersion = makePropertyAccess[string](
+		result, func(c *PropertyConfiguration) *configurable[string] { return &c.NameInNextVersion })
 	result.RenamePropertyTo = makePropertyAccess[string](
 		result, func(c *PropertyConfiguration) *configurable[string] { return &c.RenameTo })
 	result.ResourceLifecycleOwnedByParent = makePropertyAccess[string](
 		result, func(c *PropertyConfiguration) *configurable[string] { return &c.ResourceLifecycleOwnedByParent })
+	result.SplitInto = makePropertyAccess[[]string](
+		result, func(c *PropertyConfiguration) *configurable[[]string] { return &c.SplitInto })
 
 	return result
 }
@@ -247,6 +293,15 @@ func (omc *ObjectModelConfiguration) addGroup(name string, group *GroupConfigura
 	omc.groups[strings.ToLower(name)] = group
 }
 
+// retainGroups discards configuration for any group not in the provided set
+func (omc *ObjectModelConfiguration) retainGroups(groups set.Set[string]) {
+	for name := range omc.groups {
+		if !groups.Contains(name) {
+			delete(omc.groups, name)
+		}
+	}
+}
+
 // visitGroup invokes the provided visitor on the specified group if present.
 // Returns a NotConfiguredError if the group is not found; otherwise whatever error is returned by the visitor.
 func (omc *ObjectModelConfiguration) visitGroup(
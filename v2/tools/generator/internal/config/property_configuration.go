@@ -24,11 +24,24 @@ type PropertyConfiguration struct {
 	name string
 	// Configurable properties here (alphabetical, please)
 	ARMReference                   configurable[bool]                // Specify whether this property is an ARM reference
+	ConvertEnumValues              configurable[map[string]string]   // Map of enum values in this version to enum values in the next version
+	ConvertVia                     configurable[ConvertVia]          // Hand-written functions used to convert this property to and from the next version
+	EmitDefaults                   configurable[bool]                // Specify whether the Swagger default of this property is emitted as a CRD default
+	ExtensibleEnum                 configurable[bool]                // Specify whether the enum used by this property permits values beyond those known
+	Immutable                      configurable[bool]                // Specify whether this property can only be set on creation
 	ImportConfigMapMode            configurable[ImportConfigMapMode] // The config map mode
 	IsSecret                       configurable[bool]                // Specify whether this property is a secret
 	NameInNextVersion              configurable[string]              // Name this property has in the next version
 	RenameTo                       configurable[string]              // Name this property should be renamed to
 	ResourceLifecycleOwnedByParent configurable[string]              // Name of the parent resource which owns the lifecycle of the sub-resource.
+	SplitInto                      configurable[[]string]            // Names of the properties this property is split into in the next version
+}
+
+// ConvertVia names the hand-written functions used to convert the value of a property between versions, when the
+// type of the property changes in a way the generated conversions can't handle.
+type ConvertVia struct {
+	From string `yaml:"from"` // Function converting the value of the property in the next version to this version
+	To   string `yaml:"to"`   // Function converting the value of the property in this version to the next version
 }
 
 type ImportConfigMapMode string
@@ -41,11 +54,16 @@ const (
 // Tags used in yaml files to specify configurable properties. Alphabetical please.
 const (
 	armReferenceTag                   = "$armReference"                   // Bool specifying whether a property is an ARM reference
+	convertEnumValuesTag              = "$convertEnumValues"              // Map of enum values in this version to enum values in the next version
+	convertViaTag                     = "$convertVia"                     // Functions (to and from) used to convert a property to and from the next version
 	exportAsConfigMapPropertyNameTag  = "$exportAsConfigMapPropertyName"  // String specifying the name of the property set to export this property as a config map.
+	extensibleEnumTag                 = "$extensibleEnum"                 // Bool specifying whether the enum of a property is open (extensible) or closed
+	immutableTag                      = "$immutable"                      // Bool specifying whether a property can only be set when the resource is created
 	importConfigMapModeTag            = "$importConfigMapMode"            // string specifying the ImportConfigMapMode mode
 	isSecretTag                       = "$isSecret"                       // Bool specifying whether a property contains a secret
 	renamePropertyToTag               = "$renameTo"                       // String specifying the name this property should be renamed to
 	resourceLifecycleOwnedByParentTag = "$resourceLifecycleOwnedByParent" // String specifying whether a property represents a subresource whose lifecycle is owned by the parent resource (and what that parent resource is)
+	splitIntoTag                      = "$splitInto"                      // List of the properties this property is split into in the next version
 )
 
 // NewPropertyConfiguration returns a new (empty) property configuration
@@ -55,11 +73,17 @@ func NewPropertyConfiguration(name string) *PropertyConfiguration {
 		name: name,
 		// Initialize configurable properties here (alphabetical, please)
 		ARMReference:                   makeConfigurable[bool](armReferenceTag, scope),
+		ConvertEnumValues:              makeConfigurable[map[string]string](convertEnumValuesTag, scope),
+		ConvertVia:                     makeConfigurable[ConvertVia](convertViaTag, scope),
+		EmitDefaults:                   makeConfigurable[bool](emitDefaultsTag, scope),
+		ExtensibleEnum:                 makeConfigurable[bool](extensibleEnumTag, scope),
+		Immutable:                      makeConfigurable[bool](immutableTag, scope),
 		ImportConfigMapMode:            makeConfigurable[ImportConfigMapMode](importConfigMapModeTag, scope),
 		IsSecret:                       makeConfigurable[bool](isSecretTag, scope),
 		NameInNextVersion:              makeConfigurable[string](nameInNextVersionTag, scope),
 		RenameTo:                       makeConfigurable[string](renamePropertyToTag, scope),
 		ResourceLifecycleOwnedByParent: makeConfigurable[string](resourceLifecycleOwnedByParentTag, scope),
+		SplitInto:                      makeConfigurable[[]string](splitIntoTag, scope),
 	}
 }
 
@@ -96,6 +120,42 @@ func (pc *PropertyConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $emitDefaults: <bool>
+		if strings.EqualFold(lastId, emitDefaultsTag) && c.Kind == yaml.ScalarNode {
+			var emitDefaults bool
+			err := c.Decode(&emitDefaults)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", emitDefaultsTag)
+			}
+
+			pc.EmitDefaults.Set(emitDefaults)
+			continue
+		}
+
+		// $extensibleEnum: <bool>
+		if strings.EqualFold(lastId, extensibleEnumTag) && c.Kind == yaml.ScalarNode {
+			var extensible bool
+			err := c.Decode(&extensible)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", extensibleEnumTag)
+			}
+
+			pc.ExtensibleEnum.Set(extensible)
+			continue
+		}
+
+		// $immutable: <bool>
+		if strings.EqualFold(lastId, immutableTag) && c.Kind == yaml.ScalarNode {
+			var immutable bool
+			err := c.Decode(&immutable)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", immutableTag)
+			}
+
+			pc.Immutable.Set(immutable)
+			continue
+		}
+
 		// $resourceLifecycleOwnedByParent: string
 		if strings.EqualFold(lastId, resourceLifecycleOwnedByParentTag) && c.Kind == yaml.ScalarNode {
 			var resourceLifecycleOwnedByParent string
@@ -134,6 +194,48 @@ func (pc *PropertyConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $convertEnumValues:
+		//   <value>: <value in next version>
+		if strings.EqualFold(lastId, convertEnumValuesTag) && c.Kind == yaml.MappingNode {
+			var values map[string]string
+			err := c.Decode(&values)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", convertEnumValuesTag)
+			}
+
+			// Values must map one-to-one so we can convert back from the next version
+			seen := make(map[string]string, len(values))
+			for value, next := range values {
+				if other, ok := seen[next]; ok {
+					return errors.Errorf(
+						"%s maps both %s and %s to %s; values must map one-to-one", convertEnumValuesTag, other, value, next)
+				}
+
+				seen[next] = value
+			}
+
+			pc.ConvertEnumValues.Set(values)
+			continue
+		}
+
+		// $convertVia:
+		//   to: <function>
+		//   from: <function>
+		if strings.EqualFold(lastId, convertViaTag) && c.Kind == yaml.MappingNode {
+			var via ConvertVia
+			err := c.Decode(&via)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", convertViaTag)
+			}
+
+			if via.To == "" || via.From == "" {
+				return errors.Errorf("%s requires functions for both directions (to and from)", convertViaTag)
+			}
+
+			pc.ConvertVia.Set(via)
+			continue
+		}
+
 		// renameTo: string
 		if strings.EqualFold(lastId, renamePropertyToTag) && c.Kind == yaml.ScalarNode {
 			var renameTo string
@@ -146,6 +248,23 @@ func (pc *PropertyConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $splitInto:
+		//   - <property in next version>
+		if strings.EqualFold(lastId, splitIntoTag) && c.Kind == yaml.SequenceNode {
+			var properties []string
+			err := c.Decode(&properties)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", splitIntoTag)
+			}
+
+			if len(properties) < 2 {
+				return errors.Errorf("%s requires at least two properties", splitIntoTag)
+			}
+
+			pc.SplitInto.Set(properties)
+			continue
+		}
+
 		// No handler for this value, return an error
 		return errors.Errorf(
 			"property configuration, unexpected yaml value %s: %s (line %d col %d)", lastId, c.Value, c.Line, c.Column)
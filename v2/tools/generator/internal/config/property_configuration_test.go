@@ -23,6 +23,9 @@ func TestPropertyConfiguration_WhenYAMLWellFormed_ReturnsExpectedResult(t *testi
 	g.Expect(err).To(Succeed())
 	g.Expect(*property.NameInNextVersion.value).To(Equal("DemoProperty"))
 	g.Expect(*property.ARMReference.value).To(BeTrue())
+	g.Expect(*property.ConvertVia.value).To(Equal(ConvertVia{To: "joinAliases", From: "splitAliases"}))
+	g.Expect(*property.ConvertEnumValues.value).To(Equal(map[string]string{"Contractor": "Freelancer"}))
+	g.Expect(*property.SplitInto.value).To(Equal([]string{"Host", "Port"}))
 }
 
 func TestPropertyConfiguration_WhenYAMLBadlyFormed_ReturnsError(t *testing.T) {
@@ -36,6 +39,49 @@ func TestPropertyConfiguration_WhenYAMLBadlyFormed_ReturnsError(t *testing.T) {
 	g.Expect(err).NotTo(Succeed())
 }
 
+func TestPropertyConfiguration_WhenConvertEnumValuesNotOneToOne_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	yamlBytes := []byte(`
+$convertEnumValues:
+  Contractor: Employee
+  Permanent: Employee
+`)
+
+	var property PropertyConfiguration
+	err := yaml.Unmarshal(yamlBytes, &property)
+	g.Expect(err).To(MatchError(ContainSubstring("one-to-one")))
+}
+
+func TestPropertyConfiguration_WhenConvertViaMissingDirection_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	yamlBytes := []byte(`
+$convertVia:
+  to: joinAliases
+`)
+
+	var property PropertyConfiguration
+	err := yaml.Unmarshal(yamlBytes, &property)
+	g.Expect(err).To(MatchError(ContainSubstring("both directions")))
+}
+
+func TestPropertyConfiguration_WhenSplitIntoSingleProperty_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	yamlBytes := []byte(`
+$splitInto:
+  - Host
+`)
+
+	var property PropertyConfiguration
+	err := yaml.Unmarshal(yamlBytes, &property)
+	g.Expect(err).To(MatchError(ContainSubstring("at least two")))
+}
+
 func TestPropertyConfiguration_ARMReference_WhenSpecified_ReturnsExpectedResult(t *testing.T) {
 	t.Parallel()
 	g := NewGomegaWithT(t)
@@ -114,3 +160,42 @@ func TestPropertyConfiguration_IsSecret_WhenNotSpecified_ReturnsExpectedResult(t
 	g.Expect(err.Error()).To(ContainSubstring(property.name))
 	g.Expect(isSecret).To(BeFalse())
 }
+
+func TestPropertyConfiguration_Immutable_WhenSpecified_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var property PropertyConfiguration
+	err := yaml.Unmarshal([]byte("$immutable: false"), &property)
+	g.Expect(err).To(Succeed())
+
+	immutable, err := property.Immutable.Lookup()
+	g.Expect(err).To(Succeed())
+	g.Expect(immutable).To(BeFalse())
+}
+
+func TestPropertyConfiguration_ExtensibleEnum_WhenSpecified_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var property PropertyConfiguration
+	err := yaml.Unmarshal([]byte("$extensibleEnum: true"), &property)
+	g.Expect(err).To(Succeed())
+
+	extensible, err := property.ExtensibleEnum.Lookup()
+	g.Expect(err).To(Succeed())
+	g.Expect(extensible).To(BeTrue())
+}
+
+func TestPropertyConfiguration_EmitDefaults_WhenSpecified_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var property PropertyConfiguration
+	err := yaml.Unmarshal([]byte("$emitDefaults: true"), &property)
+	g.Expect(err).To(Succeed())
+
+	emitDefaults, err := property.EmitDefaults.Lookup()
+	g.Expect(err).To(Succeed())
+	g.Expect(emitDefaults).To(BeTrue())
+}
@@ -31,9 +31,14 @@ type TypeConfiguration struct {
 	advisor    *typo.Advisor
 	// Configurable properties here (alphabetical, please)
 	AzureGeneratedSecrets    configurable[[]string]
+	CRDCollapseDescriptions  configurable[bool]
+	CRDExcludeStorageVersion configurable[bool]
+	CRDStatusSchemaDepth     configurable[int]
 	DefaultAzureName         configurable[bool]
+	EmitDefaults             configurable[bool]
 	Export                   configurable[bool]
 	ExportAs                 configurable[string]
+	ExtensibleEnum           configurable[bool]
 	GeneratedConfigs         configurable[map[string]string]
 	Importable               configurable[bool]
 	IsResource               configurable[bool]
@@ -42,10 +47,21 @@ type TypeConfiguration struct {
 	RenameTo                 configurable[string]
 	ResourceEmbeddedInParent configurable[string]
 	SupportedFrom            configurable[string]
+	Validations              configurable[[]ValidationRule]
+}
+
+// ValidationRule is a CEL rule validating a type, enforced by the Kubernetes API server.
+// See https://kubernetes.io/docs/reference/using-api/cel/
+type ValidationRule struct {
+	Rule    string `yaml:"rule"`              // CEL expression that must evaluate to true, with self referring to the object
+	Message string `yaml:"message,omitempty"` // Message shown to users when the rule fails
 }
 
 const (
 	azureGeneratedSecretsTag    = "$azureGeneratedSecrets"    // A set of strings specifying which secrets are generated by Azure
+	crdCollapseDescriptionsTag  = "$crdCollapseDescriptions"  // Boolean specifying whether property descriptions are cut down to their first sentence
+	crdExcludeStorageVersionTag = "$crdExcludeStorageVersion" // Boolean specifying whether the storage variant of the resource is left out of the CRD
+	crdStatusSchemaDepthTag     = "$crdStatusSchemaDepth"     // Integer depth below which the status schema is replaced by x-kubernetes-preserve-unknown-fields
 	generatedConfigsTag         = "$generatedConfigs"         // A map of strings specifying which spec or status properties should be exported to configmap
 	manualConfigsTag            = "$manualConfigs"            // A set of strings specifying which config map fields should be generated (to be filled out by resource extension)
 	exportTag                   = "$export"                   // Boolean specifying whether a resource type is exported
@@ -57,6 +73,7 @@ const (
 	renameTo                    = "$renameTo"                 // String specifying the new name of a type
 	resourceEmbeddedInParentTag = "$resourceEmbeddedInParent" // String specifying resource name of parent
 	defaultAzureNameTag         = "$defaultAzureName"         // Boolean indicating if the resource should automatically default AzureName
+	validationsTag              = "$validations"              // A list of CEL rules used to validate the type
 )
 
 func NewTypeConfiguration(name string) *TypeConfiguration {
@@ -67,9 +84,14 @@ func NewTypeConfiguration(name string) *TypeConfiguration {
 		advisor:    typo.NewAdvisor(),
 		// Initialize configurable properties here (alphabetical, please)
 		AzureGeneratedSecrets:    makeConfigurable[[]string](azureGeneratedSecretsTag, scope),
+		CRDCollapseDescriptions:  makeConfigurable[bool](crdCollapseDescriptionsTag, scope),
+		CRDExcludeStorageVersion: makeConfigurable[bool](crdExcludeStorageVersionTag, scope),
+		CRDStatusSchemaDepth:     makeConfigurable[int](crdStatusSchemaDepthTag, scope),
 		DefaultAzureName:         makeConfigurable[bool](defaultAzureNameTag, scope),
+		EmitDefaults:             makeConfigurable[bool](emitDefaultsTag, scope),
 		Export:                   makeConfigurable[bool](exportTag, scope),
 		ExportAs:                 makeConfigurable[string](exportAsTag, scope),
+		ExtensibleEnum:           makeConfigurable[bool](extensibleEnumTag, scope),
 		Importable:               makeConfigurable[bool](importableTag, scope),
 		IsResource:               makeConfigurable[bool](isResourceTag, scope),
 		GeneratedConfigs:         makeConfigurable[map[string]string](generatedConfigsTag, scope),
@@ -78,6 +100,7 @@ func NewTypeConfiguration(name string) *TypeConfiguration {
 		RenameTo:                 makeConfigurable[string](renameTo, scope),
 		ResourceEmbeddedInParent: makeConfigurable[string](resourceEmbeddedInParentTag, scope),
 		SupportedFrom:            makeConfigurable[string](supportedFromTag, scope),
+		Validations:              makeConfigurable[[]ValidationRule](validationsTag, scope),
 	}
 }
 
@@ -272,6 +295,26 @@ func (tc *TypeConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $validations
+		// - rule: <CEL expression>
+		//   message: <string>
+		if strings.EqualFold(lastId, validationsTag) && c.Kind == yaml.SequenceNode {
+			var rules []ValidationRule
+			err := c.Decode(&rules)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", validationsTag)
+			}
+
+			for _, rule := range rules {
+				if strings.TrimSpace(rule.Rule) == "" {
+					return errors.Errorf("%s entries must have a rule (line %d col %d)", validationsTag, c.Line, c.Column)
+				}
+			}
+
+			tc.Validations.Set(rules)
+			continue
+		}
+
 		// $SupportedFrom
 		if strings.EqualFold(lastId, supportedFromTag) && c.Kind == yaml.ScalarNode {
 			tc.SupportedFrom.Set(c.Value)
@@ -326,6 +369,46 @@ func (tc *TypeConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $crdCollapseDescriptions: <bool>
+		if strings.EqualFold(lastId, crdCollapseDescriptionsTag) && c.Kind == yaml.ScalarNode {
+			var collapse bool
+			err := c.Decode(&collapse)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", crdCollapseDescriptionsTag)
+			}
+
+			tc.CRDCollapseDescriptions.Set(collapse)
+			continue
+		}
+
+		// $crdExcludeStorageVersion: <bool>
+		if strings.EqualFold(lastId, crdExcludeStorageVersionTag) && c.Kind == yaml.ScalarNode {
+			var exclude bool
+			err := c.Decode(&exclude)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", crdExcludeStorageVersionTag)
+			}
+
+			tc.CRDExcludeStorageVersion.Set(exclude)
+			continue
+		}
+
+		// $crdStatusSchemaDepth: <int>
+		if strings.EqualFold(lastId, crdStatusSchemaDepthTag) && c.Kind == yaml.ScalarNode {
+			var depth int
+			err := c.Decode(&depth)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", crdStatusSchemaDepthTag)
+			}
+
+			if depth < 1 {
+				return errors.Errorf("%s must be at least 1, but was %d", crdStatusSchemaDepthTag, depth)
+			}
+
+			tc.CRDStatusSchemaDepth.Set(depth)
+			continue
+		}
+
 		// $defaultAzureName: <bool>
 		if strings.EqualFold(lastId, defaultAzureNameTag) && c.Kind == yaml.ScalarNode {
 			var defaultAzureName bool
@@ -338,6 +421,30 @@ func (tc *TypeConfiguration) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
+		// $emitDefaults: <bool>
+		if strings.EqualFold(lastId, emitDefaultsTag) && c.Kind == yaml.ScalarNode {
+			var emitDefaults bool
+			err := c.Decode(&emitDefaults)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", emitDefaultsTag)
+			}
+
+			tc.EmitDefaults.Set(emitDefaults)
+			continue
+		}
+
+		// $extensibleEnum: <bool>
+		if strings.EqualFold(lastId, extensibleEnumTag) && c.Kind == yaml.ScalarNode {
+			var extensible bool
+			err := c.Decode(&extensible)
+			if err != nil {
+				return errors.Wrapf(err, "decoding %s", extensibleEnumTag)
+			}
+
+			tc.ExtensibleEnum.Set(extensible)
+			continue
+		}
+
 		// No handler for this value, return an error
 		return errors.Errorf(
 			"type configuration, unexpected yaml value %s: %s (line %d col %d)", lastId, c.Value, c.Line, c.Column)
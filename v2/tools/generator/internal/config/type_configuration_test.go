@@ -42,6 +42,69 @@ func TestTypeConfiguration_WhenYAMLWellFormed_ReturnsExpectedResult(t *testing.T
 	supportedFrom, ok := typeConfig.SupportedFrom.read()
 	g.Expect(supportedFrom).To(Equal("beta.3"))
 	g.Expect(ok).To(BeTrue())
+
+	validations, ok := typeConfig.Validations.read()
+	g.Expect(validations).To(Equal([]ValidationRule{
+		{
+			Rule:    "!has(self.nickname) || self.nickname != self.name",
+			Message: "nickname must differ from name",
+		},
+	}))
+	g.Expect(ok).To(BeTrue())
+}
+
+func TestTypeConfiguration_WhenValidationHasNoRule_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var typeConfig TypeConfiguration
+	err := yaml.Unmarshal([]byte("$validations:\n  - message: no rule\n"), &typeConfig)
+	g.Expect(err).To(MatchError(ContainSubstring(validationsTag)))
+}
+
+func TestTypeConfiguration_WhenCRDPruningConfigured_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	yamlText := "$crdCollapseDescriptions: true\n$crdExcludeStorageVersion: true\n$crdStatusSchemaDepth: 3\n"
+
+	var typeConfig TypeConfiguration
+	err := yaml.Unmarshal([]byte(yamlText), &typeConfig)
+	g.Expect(err).To(Succeed())
+
+	collapse, ok := typeConfig.CRDCollapseDescriptions.read()
+	g.Expect(collapse).To(BeTrue())
+	g.Expect(ok).To(BeTrue())
+
+	exclude, ok := typeConfig.CRDExcludeStorageVersion.read()
+	g.Expect(exclude).To(BeTrue())
+	g.Expect(ok).To(BeTrue())
+
+	depth, ok := typeConfig.CRDStatusSchemaDepth.read()
+	g.Expect(depth).To(Equal(3))
+	g.Expect(ok).To(BeTrue())
+}
+
+func TestTypeConfiguration_WhenCRDStatusSchemaDepthNotPositive_ReturnsError(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var typeConfig TypeConfiguration
+	err := yaml.Unmarshal([]byte("$crdStatusSchemaDepth: 0\n"), &typeConfig)
+	g.Expect(err).To(MatchError(ContainSubstring(crdStatusSchemaDepthTag)))
+}
+
+func TestTypeConfiguration_ExtensibleEnum_WhenSpecified_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var typeConfig TypeConfiguration
+	err := yaml.Unmarshal([]byte("$extensibleEnum: true\n"), &typeConfig)
+	g.Expect(err).To(Succeed())
+
+	extensible, err := typeConfig.ExtensibleEnum.Lookup()
+	g.Expect(err).To(Succeed())
+	g.Expect(extensible).To(BeTrue())
 }
 
 func TestTypeConfiguration_WhenYAMLBadlyFormed_ReturnsError(t *testing.T) {
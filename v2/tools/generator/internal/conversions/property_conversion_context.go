@@ -6,8 +6,12 @@
 package conversions
 
 import (
+	"strings"
+
+	"github.com/dave/dst"
 	"github.com/pkg/errors"
 
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astbuilder"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/storage"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
@@ -37,6 +41,10 @@ type PropertyConversionContext struct {
 	conversionGraph *storage.ConversionGraph
 	// additionalReferences is a reference to a shared set of additional package references needed by conversions
 	additionalReferences *astmodel.PackageReferenceSet
+	// declaringType is the type declaring the property being converted, used to look up configured conversions
+	declaringType astmodel.InternalTypeName
+	// property is the name of the property being converted, used to look up configured conversions
+	property astmodel.PropertyName
 }
 
 // NewPropertyConversionContext creates a new instance of a PropertyConversionContext
@@ -103,6 +111,18 @@ func (c *PropertyConversionContext) WithPackageReferenceSet(set *astmodel.Packag
 	return result
 }
 
+// WithDeclaringProperty returns a new context for conversion of the specified property, allowing conversions configured
+// for that property to be used. The declaring type is the earlier of the two versions being converted.
+func (c *PropertyConversionContext) WithDeclaringProperty(
+	declaringType astmodel.InternalTypeName,
+	property astmodel.PropertyName,
+) *PropertyConversionContext {
+	result := c.clone()
+	result.declaringType = declaringType
+	result.property = property
+	return result
+}
+
 // ResolveType resolves a type that might be a type name into both the name and the actual
 // type it references, returning true iff it was a TypeName that could be resolved
 func (c *PropertyConversionContext) ResolveType(t astmodel.Type) (astmodel.InternalTypeName, astmodel.Type, bool) {
@@ -135,6 +155,87 @@ func (c *PropertyConversionContext) TypeRename(name astmodel.InternalTypeName) (
 	return c.configuration.TypeNameInNextVersion.Lookup(name)
 }
 
+// ConvertVia looks up the hand-written function configured for conversion of the current property in our current
+// direction, returning the name of the function and nil if found, or empty string and an error if not. If no
+// configuration or property is available, acts as though there is no configured function, returning "" and a
+// NotConfiguredError
+func (c *PropertyConversionContext) ConvertVia() (string, error) {
+	if c.configuration == nil || c.declaringType.IsEmpty() {
+		return "", config.NewNotConfiguredError("No configuration available")
+	}
+
+	via, err := c.configuration.ConvertVia.Lookup(c.declaringType, c.property)
+	if err != nil {
+		return "", err
+	}
+
+	return c.direction.SelectString(via.From, via.To), nil
+}
+
+// SplitInto looks up the properties of the next version that the current property is configured to be split into,
+// returning their names and nil if found, or nil and an error if not. If no configuration or property is available,
+// acts as though the property isn't split, returning nil and a NotConfiguredError
+func (c *PropertyConversionContext) SplitInto() ([]string, error) {
+	if c.configuration == nil || c.declaringType.IsEmpty() {
+		return nil, config.NewNotConfiguredError("No configuration available")
+	}
+
+	return c.configuration.SplitInto.Lookup(c.declaringType, c.property)
+}
+
+// CallConfiguredFunction returns a factory for calls to the hand-written function fn, configured with $convertVia.
+// Functions may be local to the package being generated, or qualified with the import path of another package
+// (e.g. strings.ToLower), in which case a reference to that package is added.
+func (c *PropertyConversionContext) CallConfiguredFunction(
+	fn string,
+) func(generationContext *astmodel.CodeGenerationContext, arguments ...dst.Expr) dst.Expr {
+	i := strings.LastIndex(fn, ".")
+	if i < 0 {
+		return func(_ *astmodel.CodeGenerationContext, arguments ...dst.Expr) dst.Expr {
+			return astbuilder.CallFunc(fn, arguments...)
+		}
+	}
+
+	pkg := astmodel.MakeExternalPackageReference(fn[:i])
+	funcName := fn[i+1:]
+	c.AddPackageReference(pkg)
+
+	return func(generationContext *astmodel.CodeGenerationContext, arguments ...dst.Expr) dst.Expr {
+		pkgName := generationContext.MustGetImportedPackageName(pkg)
+		return astbuilder.CallQualifiedFunc(pkgName, funcName, arguments...)
+	}
+}
+
+// ConvertEnumValues looks up the map of enum values configured for conversion of the current property, returning a
+// map from source values to destination values (inverting the configured map when converting from the next version)
+// and nil if found, or nil and an error if not. If no configuration or property is available, acts as though there is
+// no configured map, returning nil and a NotConfiguredError
+func (c *PropertyConversionContext) ConvertEnumValues() (map[string]string, error) {
+	if c.configuration == nil || c.declaringType.IsEmpty() {
+		return nil, config.NewNotConfiguredError("No configuration available")
+	}
+
+	values, err := c.configuration.ConvertEnumValues.Lookup(c.declaringType, c.property)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.direction == ConvertTo {
+		return values, nil
+	}
+
+	result := make(map[string]string, len(values))
+	for value, next := range values {
+		if _, ok := result[next]; ok {
+			return nil, errors.Errorf("unable to convert %s back from the next version as multiple values map to it", next)
+		}
+
+		result[next] = value
+	}
+
+	return result, nil
+}
+
 // FindNextType returns the next type in the storage conversion graph, if any.
 // If no conversion graph is available, returns an empty type name and no error.
 func (c *PropertyConversionContext) FindNextType(name astmodel.InternalTypeName) (astmodel.InternalTypeName, error) {
@@ -161,6 +262,8 @@ func (c *PropertyConversionContext) clone() *PropertyConversionContext {
 		configuration:        c.configuration,
 		conversionGraph:      c.conversionGraph,
 		additionalReferences: c.additionalReferences,
+		declaringType:        c.declaringType,
+		property:             c.property,
 	}
 }
 
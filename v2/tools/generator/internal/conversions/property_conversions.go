@@ -11,9 +11,12 @@ import (
 
 	"github.com/dave/dst"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astbuilder"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
 )
 
 // PropertyConversion generates the AST for a given property conversion.
@@ -60,6 +63,9 @@ func init() {
 		// Property bag items
 		pullFromBagItem,
 		writeToBagItem,
+		// Conversions configured for specific properties
+		assignViaConfiguredFunction,
+		assignPrimitiveViaConfiguredEnumValues,
 		// Primitive definitions and aliases
 		assignPrimitiveFromPrimitive,
 		assignAliasedPrimitiveFromAliasedPrimitive,
@@ -621,6 +627,118 @@ func assignToEnumeration(
 	}, nil
 }
 
+// assignViaConfiguredFunction will generate a call to a hand-written function if one has been configured (with
+// $convertVia) for the property being converted. Functions may be local to the package being generated, or qualified
+// with the import path of another package (e.g. strings.ToLower)
+//
+// <destination> = <function>(<source>)
+func assignViaConfiguredFunction(
+	sourceEndpoint *TypedConversionEndpoint,
+	destinationEndpoint *TypedConversionEndpoint,
+	conversionContext *PropertyConversionContext) (PropertyConversion, error) {
+
+	// Require both source and destination to not be bag items
+	if sourceEndpoint.IsBagItem() || destinationEndpoint.IsBagItem() {
+		return nil, nil
+	}
+
+	// Require a function to be configured
+	fn, err := conversionContext.ConvertVia()
+	if config.IsNotConfiguredError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up configured conversion function")
+	}
+
+	call := conversionContext.CallConfiguredFunction(fn)
+
+	return func(
+		reader dst.Expr,
+		writer func(dst.Expr) []dst.Stmt,
+		knownLocals *astmodel.KnownLocalsSet,
+		generationContext *astmodel.CodeGenerationContext,
+	) ([]dst.Stmt, error) {
+		return writer(call(generationContext, reader)), nil
+	}, nil
+}
+
+// assignPrimitiveViaConfiguredEnumValues will generate a translation of values if a map of enum values has been
+// configured (with $convertEnumValues) for the property being converted. Enums are stored as strings in storage
+// versions, so both definitions must be strings; optional values and collections are handled by other conversions.
+//
+//	<local> := <source>
+//	switch <local> {
+//	case "<value>":
+//		<local> = "<value in destination>"
+//	}
+//	<destination> = <local>
+func assignPrimitiveViaConfiguredEnumValues(
+	sourceEndpoint *TypedConversionEndpoint,
+	destinationEndpoint *TypedConversionEndpoint,
+	conversionContext *PropertyConversionContext) (PropertyConversion, error) {
+
+	// Require both source and destination to not be bag items
+	if sourceEndpoint.IsBagItem() || destinationEndpoint.IsBagItem() {
+		return nil, nil
+	}
+
+	// Require both source and destination to be non-optional
+	if sourceEndpoint.IsOptional() || destinationEndpoint.IsOptional() {
+		return nil, nil
+	}
+
+	// Require both source and destination to be strings
+	if !astmodel.TypeEquals(sourceEndpoint.Type(), astmodel.StringType) ||
+		!astmodel.TypeEquals(destinationEndpoint.Type(), astmodel.StringType) {
+		return nil, nil
+	}
+
+	// Require a map of values to be configured
+	values, err := conversionContext.ConvertEnumValues()
+	if config.IsNotConfiguredError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up configured enum values")
+	}
+
+	// Sort the values so that we generate consistent code
+	keys := maps.Keys(values)
+	slices.Sort(keys)
+
+	return func(
+		reader dst.Expr,
+		writer func(dst.Expr) []dst.Stmt,
+		knownLocals *astmodel.KnownLocalsSet,
+		generationContext *astmodel.CodeGenerationContext,
+	) ([]dst.Stmt, error) {
+		local := knownLocals.CreateSingularLocal(sourceEndpoint.Name(), "", "Value")
+
+		cases := make([]dst.Stmt, 0, len(keys))
+		for _, key := range keys {
+			cases = append(cases, &dst.CaseClause{
+				List: []dst.Expr{astbuilder.StringLiteral(key)},
+				Body: []dst.Stmt{
+					astbuilder.SimpleAssignment(dst.NewIdent(local), astbuilder.StringLiteral(values[key])),
+				},
+			})
+		}
+
+		translate := &dst.SwitchStmt{
+			Tag:  dst.NewIdent(local),
+			Body: &dst.BlockStmt{List: cases},
+		}
+
+		return astbuilder.Statements(
+			astbuilder.ShortDeclaration(local, reader),
+			translate,
+			writer(dst.NewIdent(local))), nil
+	}, nil
+}
+
 // assignPrimitiveFromPrimitive will generate a direct assignment if both definitions have the
 // same primitive type and are not optional
 //
@@ -910,13 +1028,18 @@ var handCraftedConversions = []handCraftedConversion{
 func assignHandcraftedImplementations(
 	sourceEndpoint *TypedConversionEndpoint,
 	destinationEndpoint *TypedConversionEndpoint,
-	_ *PropertyConversionContext) (PropertyConversion, error) {
+	conversionContext *PropertyConversionContext) (PropertyConversion, error) {
 
 	// Require both source and destination to not be bag items
 	if sourceEndpoint.IsBagItem() || destinationEndpoint.IsBagItem() {
 		return nil, nil
 	}
 
+	// Require no enum values to be configured, as each value needs to be translated
+	if _, err := conversionContext.ConvertEnumValues(); err == nil {
+		return nil, nil
+	}
+
 	for _, impl := range handCraftedConversions {
 		if astmodel.TypeEquals(sourceEndpoint.Type(), impl.fromType) &&
 			astmodel.TypeEquals(destinationEndpoint.Type(), impl.toType) {
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package functions
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+)
+
+// NewDataPlaneResource creates an implementation of genruntime.DataPlaneResource, returning the details needed to
+// reach the data plane through which the resource is managed.
+// audience is the audience for which access tokens must be acquired.
+// endpoint is a template for the endpoint of the data plane, containing an {owner} placeholder.
+// path is a template for the path of the resource within the data plane, containing a {name} placeholder.
+func NewDataPlaneResource(
+	idFactory astmodel.IdentifierFactory,
+	audience string,
+	endpoint string,
+	path string,
+) *astmodel.InterfaceImplementation {
+	audienceComment := fmt.Sprintf("returns the audience of access tokens for the data plane. This is always %q", audience)
+	audienceFn := NewObjectFunction(
+		"GetDataPlaneAudience",
+		idFactory,
+		createBodyReturningLiteralString(audience, audienceComment, ReceiverTypePtr))
+
+	endpointComment := fmt.Sprintf("returns the endpoint template for the data plane. This is always %q", endpoint)
+	endpointFn := NewObjectFunction(
+		"GetDataPlaneEndpoint",
+		idFactory,
+		createBodyReturningLiteralString(endpoint, endpointComment, ReceiverTypePtr))
+
+	pathComment := fmt.Sprintf("returns the path template for the resource within the data plane. This is always %q", path)
+	pathFn := NewObjectFunction(
+		"GetDataPlanePath",
+		idFactory,
+		createBodyReturningLiteralString(path, pathComment, ReceiverTypePtr))
+
+	return astmodel.NewInterfaceImplementation(astmodel.DataPlaneResourceInterfaceName, audienceFn, endpointFn, pathFn)
+}
@@ -41,6 +41,26 @@ func NewValidateWriteOncePropertiesFunction(resource *astmodel.ResourceType, idF
 		astmodel.NewPackageReferenceSet())
 }
 
+// NewValidateImmutablePropertiesFunction creates a function to reject changes to create-only properties.
+// paths are the JSON paths of the immutable properties within the spec, e.g. "sku.name".
+func NewValidateImmutablePropertiesFunction(
+	resource *astmodel.ResourceType,
+	idFactory astmodel.IdentifierFactory,
+	paths []string,
+) *ResourceFunction {
+	return NewResourceFunction(
+		"validateImmutableProperties",
+		resource,
+		idFactory,
+		func(f *ResourceFunction, codeGenerationContext *astmodel.CodeGenerationContext, receiver astmodel.TypeName, methodName string) *dst.FuncDecl {
+			return validateImmutablePropertiesFunction(f, codeGenerationContext, receiver, methodName, paths)
+		},
+		astmodel.NewPackageReferenceSet(
+			astmodel.GenRuntimeReference,
+			astmodel.APIMachineryRuntimeReference,
+			astmodel.ControllerRuntimeAdmission))
+}
+
 func NewValidateOptionalConfigMapReferenceFunction(resource *astmodel.ResourceType, idFactory astmodel.IdentifierFactory) *ResourceFunction {
 	return NewResourceFunction(
 		"validateOptionalConfigMapReferences",
@@ -192,6 +212,73 @@ func validateWriteOncePropertiesFunctionBody(receiver astmodel.TypeName, codeGen
 		returnStmt)
 }
 
+func validateImmutablePropertiesFunction(
+	resourceFn *ResourceFunction,
+	codeGenerationContext *astmodel.CodeGenerationContext,
+	receiver astmodel.TypeName,
+	methodName string,
+	paths []string,
+) *dst.FuncDecl {
+	receiverIdent := resourceFn.IdFactory().CreateReceiver(receiver.Name())
+	receiverType := receiver.AsType(codeGenerationContext)
+
+	runtimePackage := codeGenerationContext.MustGetImportedPackageName(astmodel.APIMachineryRuntimeReference)
+
+	fn := &astbuilder.FuncDetails{
+		Name:          methodName,
+		ReceiverIdent: receiverIdent,
+		ReceiverType:  astbuilder.PointerTo(receiverType),
+		Body:          validateImmutablePropertiesFunctionBody(receiver, codeGenerationContext, receiverIdent, paths),
+	}
+
+	fn.AddReturn(astbuilder.QualifiedTypeName(codeGenerationContext.MustGetImportedPackageName(astmodel.ControllerRuntimeAdmission), "Warnings"))
+	fn.AddReturn(dst.NewIdent("error"))
+	fn.AddParameter("old", astbuilder.QualifiedTypeName(runtimePackage, "Object"))
+	fn.AddComments("validates that create-only properties are not changed once the resource has been created")
+
+	return fn.DefineFunc()
+}
+
+// validateImmutablePropertiesFunctionBody helps generate the body of the validateImmutableProperties function:
+//
+//	oldObj, ok := old.(*Receiver)
+//	if !ok {
+//	    return nil, nil
+//	}
+//
+//	return genruntime.ValidateImmutableProperties(oldObj, <receiverIdent>, "<path>", ...)
+func validateImmutablePropertiesFunctionBody(
+	receiver astmodel.TypeName,
+	codeGenerationContext *astmodel.CodeGenerationContext,
+	receiverIdent string,
+	paths []string,
+) []dst.Stmt {
+	genRuntime := codeGenerationContext.MustGetImportedPackageName(astmodel.GenRuntimeReference)
+
+	obj := dst.NewIdent("oldObj")
+
+	cast := astbuilder.TypeAssert(obj, dst.NewIdent("old"), astbuilder.PointerTo(receiver.AsType(codeGenerationContext)))
+	checkAssert := astbuilder.ReturnIfNotOk(astbuilder.Nil(), astbuilder.Nil())
+
+	args := make([]dst.Expr, 0, len(paths)+2)
+	args = append(args, obj, dst.NewIdent(receiverIdent))
+	for _, path := range paths {
+		args = append(args, astbuilder.StringLiteral(path))
+	}
+
+	returnStmt := astbuilder.Returns(
+		astbuilder.CallQualifiedFunc(
+			genRuntime,
+			"ValidateImmutableProperties",
+			args...))
+	returnStmt.Decorations().Before = dst.EmptyLine
+
+	return astbuilder.Statements(
+		cast,
+		checkAssert,
+		returnStmt)
+}
+
 func validateOptionalConfigMapReferences(k *ResourceFunction, codeGenerationContext *astmodel.CodeGenerationContext, receiver astmodel.TypeName, methodName string) *dst.FuncDecl {
 	receiverIdent := k.IdFactory().CreateReceiver(receiver.Name())
 	receiverType := receiver.AsType(codeGenerationContext)
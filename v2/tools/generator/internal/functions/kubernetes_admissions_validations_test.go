@@ -0,0 +1,35 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestGolden_ValidateImmutablePropertiesFunction_GeneratesExpectedCode(t *testing.T) {
+	t.Parallel()
+	idFactory := astmodel.NewIdentifierFactory()
+
+	testGroup := "microsoft.person"
+	testPackage := test.MakeLocalPackageReference(testGroup, "v20200101")
+
+	fullNameProperty := astmodel.NewPropertyDefinition("FullName", "fullName", astmodel.StringType).
+		WithIsImmutable(true)
+
+	// Define a test resource
+	spec := test.CreateSpec(testPackage, "Person", fullNameProperty)
+	status := test.CreateStatus(testPackage, "Person")
+	resource := test.CreateResource(testPackage, "Person", spec, status)
+
+	resourceType := resource.Type().(*astmodel.ResourceType)
+	validateFunction := NewValidateImmutablePropertiesFunction(resourceType, idFactory, []string{"fullName", "residence.city"})
+	resource = resource.WithType(resourceType.WithFunction(validateFunction))
+
+	test.AssertSingleTypeDefinitionGeneratesExpectedCode(t, "ValidateImmutablePropertiesFunction", resource)
+}
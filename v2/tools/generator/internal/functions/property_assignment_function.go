@@ -14,6 +14,8 @@ import (
 	"github.com/dave/dst"
 	"golang.org/x/exp/maps"
 
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astbuilder"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/conversions"
@@ -47,6 +49,8 @@ type PropertyAssignmentFunction struct {
 	readsFromPropertyBag bool
 	// writesToPropertyBag keeps track of whether we will be writing property values into a property bag
 	writesToPropertyBag bool
+	// propertyBagWrites is the set of source endpoints whose values we will be writing into a property bag
+	propertyBagWrites set.Set[string]
 	// augmentationInterface is the conversion augmentation interface associated with this conversion.
 	// If this is nil, there is no augmented conversion associated with this conversion
 	augmentationInterface astmodel.TypeName
@@ -163,6 +167,12 @@ func (fn *PropertyAssignmentFunction) ParameterType() astmodel.TypeName {
 	return fn.otherDefinition.Name()
 }
 
+// PropertyBagWrites returns the names of the source properties whose values are written into the property bag of the
+// destination, in alphabetical order
+func (fn *PropertyAssignmentFunction) PropertyBagWrites() []string {
+	return set.AsSortedSlice(fn.propertyBagWrites)
+}
+
 // generateBody returns the statements required for the conversion function
 // receiver is an expression for access our receiver type, used to qualify field access
 // parameter is an expression for access to our parameter passed to the function, also used for field access
@@ -6,14 +6,18 @@
 package functions
 
 import (
+	"go/token"
 	"strings"
 
 	"github.com/Azure/azure-service-operator/v2/internal/set"
 	"github.com/dave/dst"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astbuilder"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/conversions"
 )
 
@@ -39,6 +43,8 @@ type PropertyAssignmentFunctionBuilder struct {
 	readsFromPropertyBag bool
 	// writesToPropertyBag keeps track of whether we will be writing property values into a property bag
 	writesToPropertyBag bool
+	// propertyBagWrites is the set of source endpoints whose values we will be writing into a property bag
+	propertyBagWrites set.Set[string]
 	// augmentationInterface is the conversion augmentation interface associated with this conversion.
 	// If this is nil, there is no augmented conversion associated with this conversion
 	augmentationInterface astmodel.TypeName
@@ -77,6 +83,7 @@ func NewPropertyAssignmentFunctionBuilder(
 		otherDefinition:    otherDefinition,
 		direction:          direction,
 		conversions:        make(map[string]StoragePropertyConversion),
+		propertyBagWrites:  set.Make[string](),
 	}
 
 	result.assignmentSelectors = []assignmentSelector{
@@ -192,6 +199,7 @@ func (builder *PropertyAssignmentFunctionBuilder) Build(
 		destinationPropertyBag: builder.findPropertyBagProperty(builder.destinationType()),
 		readsFromPropertyBag:   builder.readsFromPropertyBag,
 		writesToPropertyBag:    builder.writesToPropertyBag,
+		propertyBagWrites:      builder.propertyBagWrites,
 	}
 
 	return result, nil
@@ -261,6 +269,12 @@ func (builder *PropertyAssignmentFunctionBuilder) createConversions(
 		return nil
 	}
 
+	// Properties split into several in the next version are handled first, as they don't pair up one-to-one
+	err := builder.createSplitConversions(sourceEndpoints, destinationEndpoints, conversionContext, propertyConversions)
+	if err != nil {
+		return err
+	}
+
 	for _, s := range builder.assignmentSelectors {
 		err := s.selector(sourceEndpoints, destinationEndpoints, assign)
 		if err != nil {
@@ -282,6 +296,163 @@ func (builder *PropertyAssignmentFunctionBuilder) createConversions(
 	return nil
 }
 
+// createSplitConversions creates conversions for properties of our receiver configured (with $splitInto) to be split
+// into several properties in the next version, using the hand-written functions configured with $convertVia. The
+// endpoints used are removed from sourceEndpoints and destinationEndpoints.
+// When converting TO the next version, the split is generated as:
+//
+//	<local1>, <local2> := <to>(<source>)
+//	<destination1> = <local1>
+//	<destination2> = <local2>
+//
+// When converting FROM the next version, the properties are joined back together:
+//
+//	<destination> = <from>(<source1>, <source2>)
+func (builder *PropertyAssignmentFunctionBuilder) createSplitConversions(
+	sourceEndpoints conversions.ReadableConversionEndpointSet,
+	destinationEndpoints conversions.WritableConversionEndpointSet,
+	conversionContext *conversions.PropertyConversionContext,
+	propertyConversions map[string]StoragePropertyConversion,
+) error {
+	if !builder.usesConfiguredConversions() {
+		return nil
+	}
+
+	// The properties of our receiver are our source when converting TO, our destination when converting FROM
+	var properties []string
+	if builder.direction == conversions.ConvertTo {
+		properties = maps.Keys(sourceEndpoints)
+	} else {
+		properties = maps.Keys(destinationEndpoints)
+	}
+
+	// Sort the properties so that errors are reported consistently
+	slices.Sort(properties)
+
+	for _, property := range properties {
+		cc := conversionContext.WithDeclaringProperty(builder.receiverDefinition.Name(), astmodel.PropertyName(property))
+		parts, err := cc.SplitInto()
+		if config.IsNotConfiguredError(err) {
+			continue
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "looking up properties %s is split into", property)
+		}
+
+		fn, err := cc.ConvertVia()
+		if err != nil {
+			return errors.Wrapf(err, "splitting %s requires $convertVia functions", property)
+		}
+
+		call := cc.CallConfiguredFunction(fn)
+		if builder.direction == conversions.ConvertTo {
+			err = builder.createSplitConversion(property, parts, call, sourceEndpoints, destinationEndpoints, propertyConversions)
+		} else {
+			err = builder.createJoinConversion(property, parts, call, sourceEndpoints, destinationEndpoints, propertyConversions)
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "splitting %s into %s", property, strings.Join(parts, ", "))
+		}
+	}
+
+	return nil
+}
+
+// createSplitConversion creates a conversion that splits the source property into several destination properties
+func (builder *PropertyAssignmentFunctionBuilder) createSplitConversion(
+	property string,
+	parts []string,
+	call func(*astmodel.CodeGenerationContext, ...dst.Expr) dst.Expr,
+	sourceEndpoints conversions.ReadableConversionEndpointSet,
+	destinationEndpoints conversions.WritableConversionEndpointSet,
+	propertyConversions map[string]StoragePropertyConversion,
+) error {
+	sourceEndpoint := sourceEndpoints[property]
+	writers := make([]*conversions.WritableConversionEndpoint, 0, len(parts))
+	for _, part := range parts {
+		writer, ok := destinationEndpoints[part]
+		if !ok {
+			return errors.Errorf("property %s not found on %s", part, builder.otherDefinition.Name())
+		}
+
+		writers = append(writers, writer)
+	}
+
+	propertyConversions[property] = func(
+		source dst.Expr,
+		destination dst.Expr,
+		knownLocals *astmodel.KnownLocalsSet,
+		generationContext *astmodel.CodeGenerationContext,
+	) ([]dst.Stmt, error) {
+		locals := make([]dst.Expr, 0, len(writers))
+		var assignments []dst.Stmt
+		for _, writer := range writers {
+			local := knownLocals.CreateSingularLocal(writer.Name(), "", "Value")
+			locals = append(locals, dst.NewIdent(local))
+			assignments = append(assignments, writer.Write(destination, dst.NewIdent(local))...)
+		}
+
+		split := &dst.AssignStmt{
+			Lhs: locals,
+			Tok: token.DEFINE,
+			Rhs: []dst.Expr{call(generationContext, sourceEndpoint.Read(source))},
+		}
+
+		return astbuilder.Statements(split, assignments), nil
+	}
+
+	sourceEndpoints.Delete(property)
+	for _, part := range parts {
+		destinationEndpoints.Delete(part)
+	}
+
+	return nil
+}
+
+// createJoinConversion creates a conversion that joins several source properties back into the destination property
+func (builder *PropertyAssignmentFunctionBuilder) createJoinConversion(
+	property string,
+	parts []string,
+	call func(*astmodel.CodeGenerationContext, ...dst.Expr) dst.Expr,
+	sourceEndpoints conversions.ReadableConversionEndpointSet,
+	destinationEndpoints conversions.WritableConversionEndpointSet,
+	propertyConversions map[string]StoragePropertyConversion,
+) error {
+	destinationEndpoint := destinationEndpoints[property]
+	readers := make([]*conversions.ReadableConversionEndpoint, 0, len(parts))
+	for _, part := range parts {
+		reader, ok := sourceEndpoints[part]
+		if !ok {
+			return errors.Errorf("property %s not found on %s", part, builder.otherDefinition.Name())
+		}
+
+		readers = append(readers, reader)
+	}
+
+	propertyConversions[property] = func(
+		source dst.Expr,
+		destination dst.Expr,
+		_ *astmodel.KnownLocalsSet,
+		generationContext *astmodel.CodeGenerationContext,
+	) ([]dst.Stmt, error) {
+		arguments := make([]dst.Expr, 0, len(readers))
+		for _, reader := range readers {
+			arguments = append(arguments, reader.Read(source))
+		}
+
+		return destinationEndpoint.Write(destination, call(generationContext, arguments...)), nil
+	}
+
+	destinationEndpoints.Delete(property)
+	for _, part := range parts {
+		sourceEndpoints.Delete(part)
+	}
+
+	return nil
+}
+
 // findPropertyBagProperty looks for a property bag on the specified type and returns it if found, or nil otherwise
 // We recognize the property bag by type, so that the name can vary to avoid collisions with other properties if needed.
 func (builder *PropertyAssignmentFunctionBuilder) findPropertyBagProperty(instance astmodel.Type) *astmodel.PropertyDefinition {
@@ -303,6 +474,14 @@ func (builder *PropertyAssignmentFunctionBuilder) createConversion(
 	destinationEndpoint *conversions.WritableConversionEndpoint,
 	conversionContext *conversions.PropertyConversionContext,
 ) (StoragePropertyConversion, error) {
+	if builder.usesConfiguredConversions() {
+		// Allow conversions configured on the property of our receiver to be used
+		property := builder.direction.SelectString(destinationEndpoint.Name(), sourceEndpoint.Name())
+		conversionContext = conversionContext.WithDeclaringProperty(
+			builder.receiverDefinition.Name(),
+			astmodel.PropertyName(property))
+	}
+
 	conversion, err := conversions.CreateTypeConversion(
 		sourceEndpoint.Endpoint(),
 		destinationEndpoint.Endpoint(),
@@ -337,6 +516,14 @@ func (builder *PropertyAssignmentFunctionBuilder) createConversion(
 	}, nil
 }
 
+// usesConfiguredConversions returns true if conversions configured for properties (such as $convertVia) apply to this
+// function. They don't apply when converting between an API version and its matching storage version, as the storage
+// version is always synthesized to be compatible.
+func (builder *PropertyAssignmentFunctionBuilder) usesConfiguredConversions() bool {
+	return astmodel.IsStoragePackageReference(builder.receiverDefinition.Name().PackageReference()) ||
+		!astmodel.IsStoragePackageReference(builder.otherDefinition.Name().PackageReference())
+}
+
 // sourceType returns the type we are reading information from
 // When converting FROM, otherDefinition.Type() is our source
 // When converting TO, receiverDefinition.Type() is our source
@@ -447,6 +634,7 @@ func (builder *PropertyAssignmentFunctionBuilder) writePropertiesToPropertyBag(
 		}
 
 		builder.writesToPropertyBag = true
+		builder.propertyBagWrites.Add(sourceName)
 	}
 
 	return nil
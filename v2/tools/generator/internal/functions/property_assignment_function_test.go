@@ -18,10 +18,11 @@ import (
 )
 
 type StorageConversionPropertyTestCase struct {
-	name        string
-	current     astmodel.TypeDefinition
-	other       astmodel.TypeDefinition
-	definitions astmodel.TypeDefinitionSet
+	name          string
+	current       astmodel.TypeDefinition
+	other         astmodel.TypeDefinition
+	definitions   astmodel.TypeDefinitionSet
+	configuration *config.ObjectModelConfiguration
 }
 
 func CreatePropertyAssignmentFunctionTestCases() []*StorageConversionPropertyTestCase {
@@ -127,6 +128,13 @@ func CreatePropertyAssignmentFunctionTestCases() []*StorageConversionPropertyTes
 
 	bagProperty := astmodel.NewPropertyDefinition("propertyBag", "$propertyBag", astmodel.PropertyBagType)
 
+	// Properties with configured conversions
+	aliasesStringProperty := astmodel.NewPropertyDefinition("Aliases", "aliases", astmodel.StringType)
+	aliasesSliceProperty := astmodel.NewPropertyDefinition("Aliases", "aliases", astmodel.NewArrayType(astmodel.StringType))
+	requiredKindProperty := astmodel.NewPropertyDefinition("Kind", "kind", astmodel.StringType)
+	optionalKindProperty := astmodel.NewPropertyDefinition("Kind", "kind", astmodel.OptionalStringType)
+	sliceOfKindProperty := astmodel.NewPropertyDefinition("Kind", "kind", astmodel.NewArrayType(astmodel.StringType))
+
 	idFactory := astmodel.NewIdentifierFactory()
 	ageFunction := test.NewFakeFunction("Age", idFactory)
 	ageFunction.TypeReturned = astmodel.IntType
@@ -168,6 +176,61 @@ func CreatePropertyAssignmentFunctionTestCases() []*StorageConversionPropertyTes
 		}
 	}
 
+	createConfiguredPropertyAssignmentTest := func(
+		name string,
+		currentProperty *astmodel.PropertyDefinition,
+		hubProperty *astmodel.PropertyDefinition,
+		configure func(pc *config.PropertyConfiguration)) *StorageConversionPropertyTestCase {
+
+		result := createPropertyAssignmentTest(name, currentProperty, hubProperty)
+		result.configuration = config.NewObjectModelConfiguration()
+		err := result.configuration.ModifyProperty(
+			result.current.Name(),
+			currentProperty.PropertyName(),
+			func(pc *config.PropertyConfiguration) error {
+				configure(pc)
+				return nil
+			})
+		if err != nil {
+			panic(err)
+		}
+
+		return result
+	}
+
+	convertAliasesVia := func(pc *config.PropertyConfiguration) {
+		pc.ConvertVia.Set(config.ConvertVia{To: "splitAliases", From: "joinAliases"})
+	}
+
+	convertNameVia := func(pc *config.PropertyConfiguration) {
+		pc.ConvertVia.Set(config.ConvertVia{To: "strings.ToLower", From: "strings.ToUpper"})
+	}
+
+	convertKindValues := func(pc *config.PropertyConfiguration) {
+		pc.ConvertEnumValues.Set(map[string]string{
+			"Contractor": "Freelancer",
+			"Permanent":  "Employee",
+		})
+	}
+
+	// A property split into two in the next version
+	endpointProperty := astmodel.NewPropertyDefinition("Endpoint", "endpoint", astmodel.StringType)
+	hostProperty := astmodel.NewPropertyDefinition("Host", "host", astmodel.StringType)
+	portProperty := astmodel.NewPropertyDefinition("Port", "port", astmodel.OptionalIntType)
+
+	splitEndpoint := createConfiguredPropertyAssignmentTest(
+		"SplitStringIntoTwoPropertiesViaConfiguredFunctions",
+		endpointProperty,
+		hostProperty,
+		func(pc *config.PropertyConfiguration) {
+			pc.SplitInto.Set([]string{"Host", "Port"})
+			pc.ConvertVia.Set(config.ConvertVia{To: "splitEndpoint", From: "joinEndpoint"})
+		})
+	splitEndpoint.other = splitEndpoint.other.WithType(
+		astmodel.NewObjectType().WithProperties(hostProperty, portProperty))
+	splitEndpoint.definitions = splitEndpoint.definitions.OverlayWith(
+		astmodel.MakeTypeDefinitionSetFromDefinitions(splitEndpoint.other))
+
 	createFunctionAssignmentTest := func(
 		name string,
 		property *astmodel.PropertyDefinition,
@@ -274,6 +337,13 @@ func CreatePropertyAssignmentFunctionTestCases() []*StorageConversionPropertyTes
 
 		createPropertyAssignmentTest("SetSliceOfString", sliceOfStringProperty, sliceOfStringProperty),
 		createPropertyAssignmentTest("SetMapOfStringToString", mapOfStringToStringProperty, mapOfStringToStringProperty),
+
+		createConfiguredPropertyAssignmentTest("ConvertStringToSliceViaConfiguredFunctions", aliasesStringProperty, aliasesSliceProperty, convertAliasesVia),
+		createConfiguredPropertyAssignmentTest("ConvertStringViaConfiguredQualifiedFunctions", requiredStringProperty, requiredStringProperty, convertNameVia),
+		createConfiguredPropertyAssignmentTest("ConvertStringViaConfiguredEnumValues", requiredKindProperty, requiredKindProperty, convertKindValues),
+		createConfiguredPropertyAssignmentTest("ConvertOptionalStringViaConfiguredEnumValues", optionalKindProperty, optionalKindProperty, convertKindValues),
+		createConfiguredPropertyAssignmentTest("ConvertSliceOfStringViaConfiguredEnumValues", sliceOfKindProperty, sliceOfKindProperty, convertKindValues),
+		splitEndpoint,
 	}
 }
 
@@ -297,6 +367,10 @@ func runTestPropertyAssignmentFunction_AsFunc(c *StorageConversionPropertyTestCa
 	g.Expect(ok).To(BeTrue())
 
 	conversionContext := conversions.NewPropertyConversionContext(conversions.AssignPropertiesMethodPrefix, c.definitions, idFactory)
+	if c.configuration != nil {
+		conversionContext = conversionContext.WithConfiguration(c.configuration)
+	}
+
 	assignFromBuilder := NewPropertyAssignmentFunctionBuilder(c.current, c.other, conversions.ConvertFrom)
 	assignFrom, err := assignFromBuilder.Build(conversionContext)
 	g.Expect(err).To(BeNil())
@@ -585,3 +659,57 @@ func TestGolden_PropertyAssignmentFunction_WhenOverrideInterfacePresent(t *testi
 
 	test.AssertSingleTypeDefinitionGeneratesExpectedCode(t, "OverrideInterface", receiverDefinition)
 }
+
+func TestPropertyAssignmentFunction_WhenSplitIntoMisconfigured_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		via           *config.ConvertVia
+		expectedError string
+	}{
+		"Missing $convertVia": {
+			expectedError: "requires $convertVia",
+		},
+		"Missing property": {
+			via:           &config.ConvertVia{To: "splitName", From: "joinName"},
+			expectedError: "property GivenName not found",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			idFactory := astmodel.NewIdentifierFactory()
+			person2020 := test.CreateObjectDefinition(test.Pkg2020, "Person", test.FullNameProperty)
+			person2021 := test.CreateObjectDefinition(test.Pkg2021, "Person", test.FamilyNameProperty)
+
+			omc := config.NewObjectModelConfiguration()
+			g.Expect(
+				omc.ModifyProperty(
+					person2020.Name(),
+					test.FullNameProperty.PropertyName(),
+					func(pc *config.PropertyConfiguration) error {
+						pc.SplitInto.Set([]string{"FamilyName", "GivenName"})
+						if c.via != nil {
+							pc.ConvertVia.Set(*c.via)
+						}
+
+						return nil
+					})).
+				To(Succeed())
+
+			conversionContext := conversions.NewPropertyConversionContext(
+				conversions.AssignPropertiesMethodPrefix,
+				astmodel.MakeTypeDefinitionSetFromDefinitions(person2020, person2021),
+				idFactory).
+				WithConfiguration(omc)
+
+			builder := NewPropertyAssignmentFunctionBuilder(person2020, person2021, conversions.ConvertTo)
+			_, err := builder.Build(conversionContext)
+			g.Expect(err).To(MatchError(ContainSubstring(c.expectedError)))
+		})
+	}
+}
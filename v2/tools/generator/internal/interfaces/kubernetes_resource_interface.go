@@ -425,6 +425,8 @@ func getSupportedOperationsFunction(r *functions.ResourceFunction, codeGeneratio
 			genruntimeOpName = "ResourceOperationHead"
 		case astmodel.ResourceOperationDelete:
 			genruntimeOpName = "ResourceOperationDelete"
+		case astmodel.ResourceOperationPost:
+			genruntimeOpName = "ResourceOperationPost"
 		default:
 			panic(fmt.Sprintf("unknown resource operation %s", op))
 		}
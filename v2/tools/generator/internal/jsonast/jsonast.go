@@ -408,7 +408,8 @@ func enumHandler(ctx context.Context, scanner *SchemaScanner, schema Schema, _ l
 		values = append(values, astmodel.MakeEnumValue(id, v))
 	}
 
-	enumType := astmodel.NewEnumType(baseType, values...)
+	enumType := astmodel.NewEnumType(baseType, values...).
+		WithExtensible(schema.enumIsExtensible())
 
 	return enumType, nil
 }
@@ -481,6 +482,28 @@ func generatePropertyDefinition(ctx context.Context, scanner *SchemaScanner, raw
 	return property, nil
 }
 
+// isCreateOnly returns true if the property can be set when the resource is created, but not changed afterwards.
+// See https://github.com/Azure/autorest/blob/main/docs/extensions/readme.md#x-ms-mutability
+func isCreateOnly(schema Schema) bool {
+	mutability, ok := schema.extensionAsStringSlice("x-ms-mutability")
+	if !ok {
+		// Absent means the property may be created, read and updated
+		return false
+	}
+
+	canCreate := false
+	for _, m := range mutability {
+		switch strings.ToLower(m) {
+		case "update":
+			return false
+		case "create":
+			canCreate = true
+		}
+	}
+
+	return canCreate
+}
+
 func getProperties(
 	ctx context.Context,
 	scanner *SchemaScanner,
@@ -517,9 +540,17 @@ func getProperties(
 			property = property.WithDescription(*propSchema.description())
 		}
 
+		// add default, if any
+		if defaultValue := propSchema.defaultValue(); defaultValue != nil {
+			property = property.WithDefaultValue(defaultValue)
+		}
+
 		// add flattening
 		property = property.SetFlatten(propSchema.extensionAsBool("x-ms-client-flatten") == true)
 
+		// add immutability flag
+		property = property.WithIsImmutable(isCreateOnly(propSchema))
+
 		// add secret flag
 		hasSecretExtension := propSchema.extensionAsBool("x-ms-secret")
 
@@ -25,12 +25,17 @@ type Schema interface {
 	Id() string // Unique ID of this Schema within the document (if any)
 	title() *string
 	description() *string
+	defaultValue() any // Default value of the schema, if any (only scalar defaults are returned)
 
 	// for extensions like x-ms-...
 	extensionAsString(key string) (string, bool)
 	extensionAsBool(key string) bool
+	extensionAsStringSlice(key string) ([]string, bool)
 	hasExtension(key string) bool
 
+	// enumIsExtensible returns true if this is an open enum (x-ms-enum with modelAsString: true)
+	enumIsExtensible() bool
+
 	hasType(schemaType SchemaType) bool
 
 	// number things
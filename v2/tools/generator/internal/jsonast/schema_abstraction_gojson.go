@@ -74,10 +74,18 @@ func (schema GoJSONSchema) extensionAsBool(_ string) bool {
 	return false
 }
 
+func (schema GoJSONSchema) extensionAsStringSlice(_ string) ([]string, bool) {
+	return nil, false
+}
+
 func (schema GoJSONSchema) hasExtension(_ string) bool {
 	return false
 }
 
+func (schema GoJSONSchema) enumIsExtensible() bool {
+	return false
+}
+
 func (schema GoJSONSchema) hasType(schemaType SchemaType) bool {
 	return schema.inner.Types.Contains(string(schemaType))
 }
@@ -201,6 +209,11 @@ func (schema GoJSONSchema) description() *string {
 	return schema.inner.Description
 }
 
+func (schema GoJSONSchema) defaultValue() any {
+	// Our JSON Schema loader doesn't retain defaults
+	return nil
+}
+
 func (schema GoJSONSchema) items() []Schema {
 	return schema.transformGoJSONSlice(schema.inner.ItemsChildren)
 }
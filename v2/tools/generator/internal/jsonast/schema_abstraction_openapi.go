@@ -264,6 +264,16 @@ func (schema *OpenAPISchema) description() *string {
 	return &schema.inner.Description
 }
 
+func (schema *OpenAPISchema) defaultValue() any {
+	// Complex defaults (objects and arrays) can't be surfaced as simple CRD defaults, so we ignore them
+	switch v := schema.inner.Default.(type) {
+	case string, bool, float64, int, int64:
+		return v
+	default:
+		return nil
+	}
+}
+
 func (schema *OpenAPISchema) items() []Schema {
 	if schema.inner.Items.Schema != nil {
 		return []Schema{schema.withNewSchema(*schema.inner.Items.Schema)}
@@ -322,11 +332,26 @@ func (schema *OpenAPISchema) extensionAsBool(key string) bool {
 	return ok && value
 }
 
+func (schema *OpenAPISchema) extensionAsStringSlice(key string) ([]string, bool) {
+	return schema.inner.Extensions.GetStringSlice(key)
+}
+
 func (schema *OpenAPISchema) hasExtension(key string) bool {
 	_, found := schema.inner.Extensions[strings.ToLower(key)]
 	return found
 }
 
+func (schema *OpenAPISchema) enumIsExtensible() bool {
+	// x-ms-enum is an object; modelAsString indicates the service may return values not listed in the enum
+	xmsEnum, ok := schema.inner.Extensions["x-ms-enum"].(map[string]any)
+	if !ok {
+		return false
+	}
+
+	modelAsString, ok := xmsEnum["modelAsString"].(bool)
+	return ok && modelAsString
+}
+
 func (schema *OpenAPISchema) isRef() bool {
 	return schema.inner.Ref.GetURL() != nil
 }
@@ -6,6 +6,8 @@
 package jsonast
 
 import (
+	"context"
+	"encoding/json"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -15,6 +17,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
 )
 
@@ -292,6 +295,64 @@ func Test_GeneratingCollidingTypeNamesWithSiblingFilesReturnsError(t *testing.T)
 	g.Expect(msg).To(ContainSubstring("could generate collision"))
 }
 
+func Test_EnumHandler_GivenXMSEnum_ReturnsEnumWithExpectedExtensibility(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		json       string
+		extensible bool
+	}{
+		"No x-ms-enum": {
+			json:       `{"type": "string", "enum": ["Basic", "Premium"]}`,
+			extensible: false,
+		},
+		"Closed x-ms-enum": {
+			json:       `{"type": "string", "enum": ["Basic", "Premium"], "x-ms-enum": {"name": "Tier", "modelAsString": false}}`,
+			extensible: false,
+		},
+		"x-ms-enum without modelAsString": {
+			json:       `{"type": "string", "enum": ["Basic", "Premium"], "x-ms-enum": {"name": "Tier"}}`,
+			extensible: false,
+		},
+		"Extensible x-ms-enum": {
+			json:       `{"type": "string", "enum": ["Basic", "Premium"], "x-ms-enum": {"name": "Tier", "modelAsString": true}}`,
+			extensible: true,
+		},
+	}
+
+	for n, c := range cases {
+		c := c
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			var schema spec.Schema
+			g.Expect(json.Unmarshal([]byte(c.json), &schema)).To(Succeed())
+
+			idFactory := astmodel.NewIdentifierFactory()
+			wrappedSchema := MakeOpenAPISchema(
+				"name",
+				schema,
+				schemaPath,
+				test.MakeLocalPackageReference("Microsoft.Test", "v1"),
+				idFactory,
+				NewCachingFileLoader(nil),
+				logr.Discard())
+
+			g.Expect(wrappedSchema.enumIsExtensible()).To(Equal(c.extensible))
+
+			scanner := NewSchemaScanner(idFactory, config.NewConfiguration(), logr.Discard())
+			enumType, err := enumHandler(context.Background(), scanner, wrappedSchema, logr.Discard())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			enum, ok := astmodel.AsEnumType(enumType)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(enum.IsExtensible()).To(Equal(c.extensible))
+			g.Expect(enum.Options()).To(HaveLen(2))
+		})
+	}
+}
+
 func init() {
 	if runtime.GOOS == "windows" {
 		schemaPath = "C:" + filepath.FromSlash(schemaPath)
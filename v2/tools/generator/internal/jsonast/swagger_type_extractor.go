@@ -55,6 +55,11 @@ func NewSwaggerTypeExtractor(
 	}
 }
 
+// isDataPlane returns true if we're extracting resources from the Swagger for the data plane of a service
+func (extractor *SwaggerTypeExtractor) isDataPlane() bool {
+	return extractor.config != nil && extractor.config.Pipeline == config.GenerationPipelineDataPlane
+}
+
 type SwaggerTypes struct {
 	ResourceDefinitions ResourceDefinitionSet
 	OtherDefinitions    astmodel.TypeDefinitionSet
@@ -89,6 +94,11 @@ func (extractor *SwaggerTypeExtractor) ExtractTypes(ctx context.Context) (Swagge
 		return SwaggerTypes{}, errors.Wrap(err, "error extracting resource types")
 	}
 
+	err = extractor.ExtractActionTypes(ctx, scanner, result)
+	if err != nil {
+		return SwaggerTypes{}, errors.Wrap(err, "error extracting action types")
+	}
+
 	err = extractor.ExtractOneOfTypes(ctx, scanner, result)
 	if err != nil {
 		return SwaggerTypes{}, errors.Wrap(err, "error extracting one-of option types")
@@ -138,12 +148,25 @@ func (extractor *SwaggerTypeExtractor) ExtractResourceTypes(ctx context.Context,
 	}
 
 	for rawOperationPath, op := range extractor.swagger.Paths.Paths {
-		// a resource must have a PUT and one of GET or HEAD
-		if op.Put == nil || (op.Get == nil && op.Head == nil) {
-			continue
+		var specSchema *Schema
+		var statusSchema *Schema
+		var ok bool
+		if extractor.isDataPlane() {
+			// a data plane resource need only have a PUT
+			if op.Put == nil {
+				continue
+			}
+
+			specSchema, statusSchema, ok = extractor.findDataPlaneResourceSchema(op, rawOperationPath)
+		} else {
+			// a resource must have a PUT and one of GET or HEAD
+			if op.Put == nil || (op.Get == nil && op.Head == nil) {
+				continue
+			}
+
+			specSchema, statusSchema, ok = extractor.findARMResourceSchema(op, rawOperationPath)
 		}
 
-		specSchema, statusSchema, ok := extractor.findARMResourceSchema(op, rawOperationPath)
 		if !ok {
 			continue
 		}
@@ -283,6 +306,173 @@ func (extractor *SwaggerTypeExtractor) extractOneResourceType(
 	return nil
 }
 
+// ExtractActionTypes finds all operations in the Swagger spec that have a POST verb on a path like
+// "Microsoft.GroupName/…/resourceName/{resourceId}/actionName", where the parent path is itself a resource, and
+// extracts them as action resources. ARM has a great many actions, so only those explicitly named in our configuration
+// (as <Resource>_<ActionName>) are extracted.
+func (extractor *SwaggerTypeExtractor) ExtractActionTypes(ctx context.Context, scanner *SchemaScanner, result SwaggerTypes) error {
+	if extractor.swagger.Paths == nil {
+		// No paths, nothing to extract
+		return nil
+	}
+
+	for rawOperationPath, op := range extractor.swagger.Paths.Paths {
+		if op.Post == nil {
+			continue
+		}
+
+		index := strings.LastIndex(rawOperationPath, "/")
+		if index <= 0 {
+			continue
+		}
+
+		rawParentPath := rawOperationPath[:index]
+		action := rawOperationPath[index+1:]
+		if action == "" || action[0] == '{' {
+			// Not an action
+			continue
+		}
+
+		// the parent must be a resource, which must have a PUT and one of GET or HEAD
+		parent, ok := extractor.swagger.Paths.Paths[rawParentPath]
+		if !ok || parent.Put == nil || (parent.Get == nil && parent.Head == nil) {
+			continue
+		}
+
+		fullPutParameters := append(parent.Parameters, parent.Put.Parameters...)
+		parentPaths := extractor.expandAndCanonicalizePath(ctx, rawParentPath, scanner, fullPutParameters)
+
+		// Parameters may be defined at the URL level or the individual verb level; the union is the final parameter set
+		fullPostParameters := append(op.Parameters, op.Post.Parameters...)
+
+		for _, parentPath := range parentPaths {
+			err := extractor.extractOneActionType(
+				ctx,
+				scanner,
+				result,
+				op.Post,
+				fullPostParameters,
+				parentPath,
+				action)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (extractor *SwaggerTypeExtractor) extractOneActionType(
+	ctx context.Context,
+	scanner *SchemaScanner,
+	result SwaggerTypes,
+	post *spec.Operation,
+	parameters []spec.Parameter,
+	parentPath string,
+	action string,
+) error {
+	armType, parentName, err := extractor.resourceNameFromOperationPath(parentPath)
+	if err != nil {
+		extractor.log.V(1).Info(
+			"Error extracting action name",
+			"swaggerPath", extractor.swaggerPath,
+			"error", err)
+		return nil
+	}
+
+	// Singularize for consistency with other resources (and because ownership assumes singular names)
+	actionName := names.Singularize(strings.ToUpper(action[0:1]) + action[1:])
+	resourceName := parentName.WithName(parentName.Name() + "_" + actionName)
+	if !extractor.config.ObjectModelConfiguration.IsTypeConfigured(resourceName) {
+		// Only actions explicitly requested are extracted
+		return nil
+	}
+
+	shouldPrune, because := scanner.configuration.ShouldPrune(resourceName)
+	if shouldPrune == config.Prune {
+		extractor.log.V(1).Info(
+			"Skipping action",
+			"name", resourceName,
+			"because", because)
+		return nil
+	}
+
+	// The parameters of the action come from the body of the POST, if any
+	var specSchema *Schema
+	for _, param := range parameters {
+		_, innerParam := extractor.fullyResolveParameter(param)
+		if param.In == "body" || innerParam.In == "body" {
+			specSchema = extractor.schemaFromParameter(param)
+			break
+		}
+	}
+
+	var actionSpec astmodel.Type
+	if specSchema == nil {
+		// No body, so no parameters
+		name := resourceName.WithName(fmt.Sprintf("%sParameters", resourceName.Name()))
+		result.OtherDefinitions[name] = astmodel.MakeTypeDefinition(name, astmodel.NewObjectType())
+		actionSpec = name
+	} else {
+		actionSpec, err = scanner.RunHandlerForSchema(ctx, *specSchema)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+
+			return errors.Wrapf(err, "unable to produce spec type for action %s", resourceName)
+		}
+	}
+
+	// The name of an action is fixed, so we use a single valued enum for it
+	nameType := astmodel.NewEnumType(astmodel.StringType, astmodel.MakeEnumValue(action, fmt.Sprintf("%q", action)))
+	nameProperty := astmodel.NewPropertyDefinition(astmodel.NameProperty, "name", nameType)
+	actionSpec = astmodel.NewAllOfType(actionSpec, astmodel.NewObjectType().WithProperty(nameProperty))
+
+	// The result of the action (if any) is captured as the status
+	var statusSchema *Schema
+	if post.Responses != nil {
+		if response, ok := post.Responses.StatusCodeResponses[200]; ok {
+			// We don't care whether the response looks like an ARM resource
+			statusSchema, _ = extractor.doesResponseRepresentARMResource(response, parentPath+"/"+action)
+		}
+	}
+
+	var actionStatus astmodel.Type
+	if statusSchema == nil {
+		// No response body
+		name := resourceName.WithName(fmt.Sprintf("%sStatus", resourceName.Name()))
+		result.OtherDefinitions[name] = astmodel.MakeTypeDefinition(name, astmodel.NewObjectType())
+		actionStatus = name
+	} else {
+		actionStatus, err = scanner.RunHandlerForSchema(ctx, *statusSchema)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+
+			return errors.Wrapf(err, "unable to produce status type for action %s", resourceName)
+		}
+	}
+
+	if _, ok := result.ResourceDefinitions[resourceName]; ok {
+		// Already extracted via another expansion of the parent path
+		return nil
+	}
+
+	result.ResourceDefinitions[resourceName] = ResourceDefinition{
+		SourceFile:          extractor.swaggerPath,
+		SpecType:            actionSpec,
+		StatusType:          actionStatus,
+		ARMType:             armType + "/" + action,
+		ARMURI:              parentPath + "/" + action,
+		SupportedOperations: set.Make(astmodel.ResourceOperationPost),
+	}
+
+	return nil
+}
+
 // ExtractOneOfTypes ensures we haven't missed any of the required OneOf type definitions.
 // The depth-first search of the Swagger spec done by ExtractResourcetypes() won't have found any "loose" one of
 // options, so we need this extra step.
@@ -372,6 +562,43 @@ func (extractor *SwaggerTypeExtractor) findARMResourceSchema(op spec.PathItem, r
 		return nil, nil, false
 	}
 
+	foundSpec, ok := extractor.findPutBodySchema(op, rawOperationPath)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return foundSpec, foundStatus, true
+}
+
+// findDataPlaneResourceSchema returns the schemas for the spec and status of a data plane resource.
+// Data plane resources don't follow ARM conventions, so we can't look for the usual markers; instead we take the
+// response of the GET, falling back to the response of the PUT if there's no GET at the same path.
+func (extractor *SwaggerTypeExtractor) findDataPlaneResourceSchema(op spec.PathItem, rawOperationPath string) (*Schema, *Schema, bool) {
+	var foundStatus *Schema
+	for _, candidate := range []*spec.Operation{op.Get, op.Put} {
+		if candidate == nil || candidate.Responses == nil {
+			continue
+		}
+
+		if response, ok := candidate.Responses.StatusCodeResponses[200]; ok {
+			foundStatus, _ = extractor.doesResponseRepresentARMResource(response, rawOperationPath)
+			if foundStatus != nil {
+				break
+			}
+		}
+	}
+
+	foundSpec, ok := extractor.findPutBodySchema(op, rawOperationPath)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return foundSpec, foundStatus, true
+}
+
+// findPutBodySchema returns the schema of the body of the PUT, which becomes the spec of the resource.
+// A nil schema indicates the PUT has no body.
+func (extractor *SwaggerTypeExtractor) findPutBodySchema(op spec.PathItem, rawOperationPath string) (*Schema, bool) {
 	var foundSpec *Schema
 
 	params := op.Put.Parameters
@@ -415,11 +642,11 @@ func (extractor *SwaggerTypeExtractor) findARMResourceSchema(op spec.PathItem, r
 				"no schema found for PUT operation",
 				"operation", rawOperationPath,
 				"swagger", extractor.swaggerPath)
-			return nil, nil, false
+			return nil, false
 		}
 	}
 
-	return foundSpec, foundStatus, true
+	return foundSpec, true
 }
 
 // fullyResolveParameter resolves the parameter and returns the file that contained the parameter and the parameter
@@ -767,6 +994,17 @@ func (extractor *SwaggerTypeExtractor) resourceNameFromOperationPath(operationPa
 // For example: “…/Microsoft.GroupName/resourceType/{parameterId}/differentType/{otherId}/something/{moreId}”
 // would return "Microsoft.GroupName", "resourceType/{parameterId}/differentType/{otherId}/something/{moreId}"
 func (extractor *SwaggerTypeExtractor) extractResourceSubpath(operationPath string) (string, string, error) {
+	if extractor.isDataPlane() {
+		// Data plane paths are relative to the endpoint of the service, so don't contain the group;
+		// instead we take it from the path of the Swagger file.
+		group := SwaggerGroupRegex.FindString(filepath.ToSlash(extractor.swaggerPath))
+		if group == "" {
+			return "", "", errors.Errorf("no group name (‘Microsoft…’) found in %s", extractor.swaggerPath)
+		}
+
+		return group, operationPath, nil
+	}
+
 	urlParts := strings.Split(operationPath, "/")
 	for i, urlPart := range urlParts {
 		if len(urlPart) == 0 {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
 	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
 )
 
 func Test_InferNameFromURLPath_ParentResource(t *testing.T) {
@@ -393,3 +394,366 @@ func makeResourceGroupParameter() spec.Parameter {
 		},
 	}
 }
+
+func Test_ExtractActionTypes_ExtractsConfiguredActions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ctx := context.Background()
+	pkg := test.MakeLocalPackageReference("group", "2020-01-01")
+
+	cfg := config.NewConfiguration()
+	regenerateKey := astmodel.MakeInternalTypeName(pkg, "Type_RegenerateKey")
+	g.Expect(
+		cfg.ObjectModelConfiguration.ModifyType(
+			regenerateKey,
+			func(tc *config.TypeConfiguration) error {
+				tc.Export.Set(true)
+				return nil
+			})).
+		To(Succeed())
+
+	parentPath := "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroup}/providers/Microsoft.Group/type/{typeName}"
+	parentParameters := []spec.Parameter{
+		makeSubscriptionIDParameter(),
+		makeResourceGroupParameter(),
+		{
+			ParamProps: spec.ParamProps{
+				Name: "typeName",
+				In:   "path",
+				Schema: &spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"string"},
+					},
+				},
+			},
+		},
+	}
+
+	stringProperty := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"string"},
+		},
+	}
+
+	post := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Parameters: []spec.Parameter{
+				{
+					ParamProps: spec.ParamProps{
+						Name: "parameters",
+						In:   "body",
+						Schema: &spec.Schema{
+							SchemaProps: spec.SchemaProps{
+								Type:       spec.StringOrArray{"object"},
+								Properties: spec.SchemaProperties{"keyName": stringProperty},
+							},
+						},
+					},
+				},
+			},
+			Responses: &spec.Responses{
+				ResponsesProps: spec.ResponsesProps{
+					StatusCodeResponses: map[int]spec.Response{
+						200: {
+							ResponseProps: spec.ResponseProps{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:       spec.StringOrArray{"object"},
+										Properties: spec.SchemaProperties{"primaryKey": stringProperty},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	swagger := spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Paths: &spec.Paths{
+				Paths: map[string]spec.PathItem{
+					parentPath: {
+						PathItemProps: spec.PathItemProps{
+							Put:        &spec.Operation{},
+							Get:        &spec.Operation{},
+							Parameters: parentParameters,
+						},
+					},
+					parentPath + "/regenerateKey": {
+						PathItemProps: spec.PathItemProps{
+							Post: post,
+						},
+					},
+					parentPath + "/restart": {
+						PathItemProps: spec.PathItemProps{
+							// Not configured, so won't be extracted
+							Post: &spec.Operation{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	extractor := NewSwaggerTypeExtractor(
+		cfg,
+		astmodel.NewIdentifierFactory(),
+		swagger,
+		"test.json",
+		pkg,
+		NewCachingFileLoader(nil),
+		logr.Discard())
+	scanner := NewSchemaScanner(astmodel.NewIdentifierFactory(), cfg, logr.Discard())
+
+	result := SwaggerTypes{
+		ResourceDefinitions: make(ResourceDefinitionSet),
+		OtherDefinitions:    make(astmodel.TypeDefinitionSet),
+	}
+
+	g.Expect(extractor.ExtractActionTypes(ctx, scanner, result)).To(Succeed())
+	g.Expect(result.ResourceDefinitions).To(HaveLen(1))
+	g.Expect(result.ResourceDefinitions).To(HaveKey(regenerateKey))
+
+	action := result.ResourceDefinitions[regenerateKey]
+	g.Expect(action.ARMType).To(Equal("Microsoft.Group/type/regenerateKey"))
+	g.Expect(action.ARMURI).To(Equal(parentPath + "/regenerateKey"))
+	g.Expect(action.SupportedOperations.Contains(astmodel.ResourceOperationPost)).To(BeTrue())
+	g.Expect(action.SupportedOperations.Contains(astmodel.ResourceOperationPut)).To(BeFalse())
+}
+
+func Test_ExtractResourceTypes_GivenDataPlaneSwagger_ExtractsResources(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ctx := context.Background()
+	pkg := test.MakeLocalPackageReference("keyvault", "7.4")
+
+	cfg := config.NewConfiguration()
+	cfg.Pipeline = config.GenerationPipelineDataPlane
+
+	stringProperty := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"string"},
+		},
+	}
+
+	// Key Vault secrets have no GET at the same path as the PUT, so the status comes from the response to the PUT
+	put := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Parameters: []spec.Parameter{
+				{
+					ParamProps: spec.ParamProps{
+						Name: "parameters",
+						In:   "body",
+						Schema: &spec.Schema{
+							SchemaProps: spec.SchemaProps{
+								Type:       spec.StringOrArray{"object"},
+								Properties: spec.SchemaProperties{"value": stringProperty},
+							},
+						},
+					},
+				},
+			},
+			Responses: &spec.Responses{
+				ResponsesProps: spec.ResponsesProps{
+					StatusCodeResponses: map[int]spec.Response{
+						200: {
+							ResponseProps: spec.ResponseProps{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:       spec.StringOrArray{"object"},
+										Properties: spec.SchemaProperties{"id": stringProperty},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	swagger := spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Paths: &spec.Paths{
+				Paths: map[string]spec.PathItem{
+					"/secrets/{secret-name}": {
+						PathItemProps: spec.PathItemProps{
+							Put:    put,
+							Delete: &spec.Operation{},
+							Parameters: []spec.Parameter{
+								{
+									ParamProps: spec.ParamProps{
+										Name:     "secret-name",
+										In:       "path",
+										Required: true,
+									},
+									SimpleSchema: spec.SimpleSchema{
+										Type: "string",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	extractor := NewSwaggerTypeExtractor(
+		cfg,
+		astmodel.NewIdentifierFactory(),
+		swagger,
+		"specification/keyvault/data-plane/Microsoft.KeyVault/stable/7.4/secrets.json",
+		pkg,
+		NewCachingFileLoader(nil),
+		logr.Discard())
+	scanner := NewSchemaScanner(astmodel.NewIdentifierFactory(), cfg, logr.Discard())
+
+	result := SwaggerTypes{
+		ResourceDefinitions: make(ResourceDefinitionSet),
+		OtherDefinitions:    make(astmodel.TypeDefinitionSet),
+	}
+
+	g.Expect(extractor.ExtractResourceTypes(ctx, scanner, result)).To(Succeed())
+
+	secretName := astmodel.MakeInternalTypeName(pkg, "Secret")
+	g.Expect(result.ResourceDefinitions).To(HaveKey(secretName))
+
+	secret := result.ResourceDefinitions[secretName]
+	g.Expect(secret.ARMType).To(Equal("Microsoft.KeyVault/secrets"))
+	g.Expect(secret.ARMURI).To(Equal("/secrets/{secret-name}"))
+	g.Expect(secret.StatusType).ToNot(BeNil())
+	g.Expect(secret.SupportedOperations.Contains(astmodel.ResourceOperationPut)).To(BeTrue())
+	g.Expect(secret.SupportedOperations.Contains(astmodel.ResourceOperationDelete)).To(BeTrue())
+}
+
+func Test_ExtractActionTypes_ExtractsActionsConfiguredForARM(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ctx := context.Background()
+	pkg := test.MakeLocalPackageReference("cache", "2023-04-01")
+
+	cfg, err := config.LoadConfiguration("../../../../azure-arm.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The shape of regenerateKey on Microsoft.Cache/redis
+	parentPath := "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/redis/{name}"
+	parentParameters := []spec.Parameter{
+		makeSubscriptionIDParameter(),
+		makeResourceGroupParameter(),
+		{
+			ParamProps: spec.ParamProps{
+				Name: "name",
+				In:   "path",
+				Schema: &spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"string"},
+					},
+				},
+			},
+		},
+	}
+
+	stringProperty := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"string"},
+		},
+	}
+
+	post := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Parameters: []spec.Parameter{
+				{
+					ParamProps: spec.ParamProps{
+						Name: "parameters",
+						In:   "body",
+						Schema: &spec.Schema{
+							SchemaProps: spec.SchemaProps{
+								Type:       spec.StringOrArray{"object"},
+								Properties: spec.SchemaProperties{"keyType": stringProperty},
+							},
+						},
+					},
+				},
+			},
+			Responses: &spec.Responses{
+				ResponsesProps: spec.ResponsesProps{
+					StatusCodeResponses: map[int]spec.Response{
+						200: {
+							ResponseProps: spec.ResponseProps{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: spec.StringOrArray{"object"},
+										Properties: spec.SchemaProperties{
+											"primaryKey":   stringProperty,
+											"secondaryKey": stringProperty,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	swagger := spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Paths: &spec.Paths{
+				Paths: map[string]spec.PathItem{
+					parentPath: {
+						PathItemProps: spec.PathItemProps{
+							Put:        &spec.Operation{},
+							Get:        &spec.Operation{},
+							Parameters: parentParameters,
+						},
+					},
+					parentPath + "/regenerateKey": {
+						PathItemProps: spec.PathItemProps{
+							Post: post,
+						},
+					},
+					parentPath + "/forceReboot": {
+						PathItemProps: spec.PathItemProps{
+							// Not configured, so won't be extracted
+							Post: &spec.Operation{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	extractor := NewSwaggerTypeExtractor(
+		cfg,
+		astmodel.NewIdentifierFactory(),
+		swagger,
+		"redis.json",
+		pkg,
+		NewCachingFileLoader(nil),
+		logr.Discard())
+	scanner := NewSchemaScanner(astmodel.NewIdentifierFactory(), cfg, logr.Discard())
+
+	result := SwaggerTypes{
+		ResourceDefinitions: make(ResourceDefinitionSet),
+		OtherDefinitions:    make(astmodel.TypeDefinitionSet),
+	}
+
+	g.Expect(extractor.ExtractActionTypes(ctx, scanner, result)).To(Succeed())
+
+	regenerateKey := astmodel.MakeInternalTypeName(pkg, "Redis_RegenerateKey")
+	g.Expect(result.ResourceDefinitions).To(HaveLen(1))
+	g.Expect(result.ResourceDefinitions).To(HaveKey(regenerateKey))
+	g.Expect(result.ResourceDefinitions[regenerateKey].ARMType).To(Equal("Microsoft.Cache/redis/regenerateKey"))
+
+	// The keys are written to secrets rather than captured in the status
+	secrets, err := cfg.ObjectModelConfiguration.AzureGeneratedSecrets.Lookup(regenerateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secrets).To(ConsistOf("PrimaryKey", "SecondaryKey"))
+}
@@ -0,0 +1,360 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package versiondiff
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/Azure/azure-service-operator/v2/internal/set"
+	"github.com/Azure/azure-service-operator/v2/internal/util/typo"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/storage"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/conversions"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/functions"
+)
+
+// renameSimilarity is how similar (between 0 and 1) two names must be for us to suggest one was renamed to the other
+const renameSimilarity = 0.5
+
+// VersionDiff captures the differences between the types of two versions of a group
+type VersionDiff struct {
+	from         astmodel.LocalPackageReference
+	to           astmodel.LocalPackageReference
+	addedTypes   []string
+	removedTypes []string
+	renamedTypes []Rename
+	types        []*TypeDiff
+}
+
+// TypeDiff captures the differences between two versions of an object type
+type TypeDiff struct {
+	name              string // Name of the type in the earlier version
+	nextName          string // Name of the type in the later version
+	added             []*PropertyChange
+	removed           []*PropertyChange
+	retyped           []*PropertyChange
+	renamed           []*PropertyChange
+	conversionProblem string // Why a conversion between the versions can't be generated, if it can't
+}
+
+// PropertyChange captures a change to a single property
+type PropertyChange struct {
+	name        astmodel.PropertyName // Name in the earlier version (or the later one, for added properties)
+	nextName    astmodel.PropertyName // Name in the later version
+	fromType    string                // Description of the type in the earlier version
+	toType      string                // Description of the type in the later version
+	propertyBag bool                  // True if the value will be stored in the PropertyBag when converting
+	configured  bool                  // True if the rename is already configured with $nameInNextVersion
+}
+
+// Rename captures a likely rename of a type
+type Rename struct {
+	From string
+	To   string
+}
+
+// Compare finds the differences between the object types in the from and to packages.
+// definitions must contain the types of both packages, along with their storage variants.
+// graph is the conversion graph between the storage variants, used to predict which values are stored in the
+// PropertyBag when converting.
+// omc is used to find types already configured with $nameInNextVersion.
+func Compare(
+	definitions astmodel.TypeDefinitionSet,
+	graph *storage.ConversionGraph,
+	omc *config.ObjectModelConfiguration,
+	from astmodel.LocalPackageReference,
+	to astmodel.LocalPackageReference,
+) *VersionDiff {
+	result := &VersionDiff{
+		from: from,
+		to:   to,
+	}
+
+	fromTypes := findObjectTypes(definitions, from)
+	toTypes := findObjectTypes(definitions, to)
+
+	// Pair up types with the same name, respecting any configured renames
+	pairs := make(map[string]string)
+	unmatchedTo := set.Make(maps.Keys(toTypes)...)
+
+	fromNames := maps.Keys(fromTypes)
+	slices.Sort(fromNames)
+
+	var unmatchedFrom []string
+	for _, name := range fromNames {
+		nextName := name
+		if n, err := omc.TypeNameInNextVersion.Lookup(astmodel.MakeInternalTypeName(from, name)); err == nil {
+			nextName = n
+		}
+
+		if _, ok := toTypes[nextName]; ok {
+			pairs[name] = nextName
+			unmatchedTo.Remove(nextName)
+			continue
+		}
+
+		unmatchedFrom = append(unmatchedFrom, name)
+	}
+
+	// Look for likely renames amongst the types that didn't pair up
+	for _, name := range unmatchedFrom {
+		nextName, ok := suggest(name, unmatchedTo)
+		if !ok {
+			result.removedTypes = append(result.removedTypes, name)
+			continue
+		}
+
+		result.renamedTypes = append(result.renamedTypes, Rename{From: name, To: nextName})
+		pairs[name] = nextName
+		unmatchedTo.Remove(nextName)
+	}
+
+	result.addedTypes = set.AsSortedSlice(unmatchedTo)
+
+	pairedNames := maps.Keys(pairs)
+	slices.Sort(pairedNames)
+
+	for _, name := range pairedNames {
+		nextName := pairs[name]
+		diff := compareObjectTypes(
+			astmodel.MakeInternalTypeName(from, name),
+			fromTypes[name],
+			astmodel.MakeInternalTypeName(to, nextName),
+			toTypes[nextName],
+			definitions,
+			graph,
+			omc,
+			pairs)
+		if diff.hasChanges() {
+			result.types = append(result.types, diff)
+		}
+	}
+
+	return result
+}
+
+// IsEmpty returns true if there are no differences between the versions
+func (diff *VersionDiff) IsEmpty() bool {
+	return len(diff.addedTypes) == 0 &&
+		len(diff.removedTypes) == 0 &&
+		len(diff.renamedTypes) == 0 &&
+		len(diff.types) == 0
+}
+
+// compareObjectTypes finds the differences between two versions of an object type.
+// graph and omc are used to generate the conversions between the storage variants of the two versions, to find the
+// properties stored in the PropertyBag.
+// omc is also used to find properties already configured with $nameInNextVersion.
+// typePairs maps the names of types in the earlier version to their names in the later one.
+func compareObjectTypes(
+	name astmodel.InternalTypeName,
+	fromType *astmodel.ObjectType,
+	nextName astmodel.InternalTypeName,
+	toType *astmodel.ObjectType,
+	definitions astmodel.TypeDefinitionSet,
+	graph *storage.ConversionGraph,
+	omc *config.ObjectModelConfiguration,
+	typePairs map[string]string,
+) *TypeDiff {
+	result := &TypeDiff{
+		name:     name.Name(),
+		nextName: nextName.Name(),
+	}
+
+	from := name.InternalPackageReference()
+	to := nextName.InternalPackageReference()
+
+	// Types in the two versions are considered equal if they're paired up
+	overrides := astmodel.EqualityOverrides{
+		InternalTypeName: func(left astmodel.InternalTypeName, right astmodel.InternalTypeName) bool {
+			if next, ok := typePairs[left.Name()]; ok {
+				return next == right.Name()
+			}
+
+			return left.Name() == right.Name()
+		},
+	}
+
+	propertyBagWrites, err := findPropertyBagWrites(name, nextName, definitions, graph, omc)
+	if err != nil {
+		result.conversionProblem = err.Error()
+	}
+
+	addedProperties := set.Make[string]()
+	for _, prop := range toType.Properties().Copy() {
+		if _, ok := fromType.Property(prop.PropertyName()); !ok {
+			addedProperties.Add(string(prop.PropertyName()))
+		}
+	}
+
+	for _, prop := range fromType.Properties().AsSlice() {
+		change := &PropertyChange{
+			name:        prop.PropertyName(),
+			nextName:    prop.PropertyName(),
+			fromType:    astmodel.DebugDescription(prop.PropertyType(), from),
+			propertyBag: propertyBagWrites.Contains(string(prop.PropertyName())),
+		}
+
+		if configuredName, err := omc.PropertyNameInNextVersion.Lookup(name, prop.PropertyName()); err == nil {
+			if nextProp, ok := toType.Property(astmodel.PropertyName(configuredName)); ok {
+				addedProperties.Remove(string(nextProp.PropertyName()))
+				change.nextName = nextProp.PropertyName()
+				change.toType = astmodel.DebugDescription(nextProp.PropertyType(), to)
+				change.configured = true
+				result.renamed = append(result.renamed, change)
+				continue
+			}
+		}
+
+		nextProp, ok := toType.Property(prop.PropertyName())
+		if !ok {
+			// Property is missing, see if it has likely been renamed
+			nextName, renamed := suggest(string(prop.PropertyName()), addedProperties)
+			if !renamed {
+				result.removed = append(result.removed, change)
+				continue
+			}
+
+			addedProperties.Remove(nextName)
+			nextProp, _ = toType.Property(astmodel.PropertyName(nextName))
+			change.nextName = nextProp.PropertyName()
+			change.toType = astmodel.DebugDescription(nextProp.PropertyType(), to)
+			result.renamed = append(result.renamed, change)
+			continue
+		}
+
+		if astmodel.TypeEquals(prop.PropertyType(), nextProp.PropertyType(), overrides) {
+			continue
+		}
+
+		change.toType = astmodel.DebugDescription(nextProp.PropertyType(), to)
+		result.retyped = append(result.retyped, change)
+	}
+
+	for _, name := range set.AsSortedSlice(addedProperties) {
+		prop, _ := toType.Property(astmodel.PropertyName(name))
+		result.added = append(result.added, &PropertyChange{
+			name:     prop.PropertyName(),
+			nextName: prop.PropertyName(),
+			toType:   astmodel.DebugDescription(prop.PropertyType(), to),
+		})
+	}
+
+	return result
+}
+
+func (diff *TypeDiff) hasChanges() bool {
+	return diff.conversionProblem != "" ||
+		len(diff.added) > 0 ||
+		len(diff.removed) > 0 ||
+		len(diff.retyped) > 0 ||
+		len(diff.renamed) > 0
+}
+
+// findPropertyBagWrites finds the properties of name whose values are stored in the PropertyBag when converting to
+// nextName. We follow the conversion graph from the storage variant of name to the storage variant of nextName,
+// building the property assignment functions used for each step, exactly as they'll be generated.
+// Returns an empty set if the types aren't converted, and an error if the conversion can't be generated.
+func findPropertyBagWrites(
+	name astmodel.InternalTypeName,
+	nextName astmodel.InternalTypeName,
+	definitions astmodel.TypeDefinitionSet,
+	graph *storage.ConversionGraph,
+	omc *config.ObjectModelConfiguration,
+) (set.Set[string], error) {
+	conversionContext := conversions.NewPropertyConversionContext(
+		conversions.AssignPropertiesMethodPrefix,
+		definitions,
+		astmodel.NewIdentifierFactory()).
+		WithConfiguration(omc).
+		WithConversionGraph(graph)
+
+	result := set.Make[string]()
+	current := storageVariant(name)
+	last := storageVariant(nextName)
+	for current != last {
+		next, err := graph.FindNextType(current, definitions)
+		if err != nil {
+			return result, errors.Wrapf(err, "finding next type after %s", current)
+		}
+
+		if next.IsEmpty() {
+			// Not converted (e.g. a type rename that isn't configured), so nothing is stored in the PropertyBag
+			return set.Make[string](), nil
+		}
+
+		currentDef, ok := definitions[current]
+		if !ok {
+			return result, errors.Errorf("definition for %s not found", current)
+		}
+
+		nextDef, ok := definitions[next]
+		if !ok {
+			return result, errors.Errorf("definition for %s not found", next)
+		}
+
+		builder := functions.NewPropertyAssignmentFunctionBuilder(currentDef, nextDef, conversions.ConvertTo)
+		fn, err := builder.Build(conversionContext)
+		if err != nil {
+			return result, errors.Wrapf(err, "converting %s to %s", current, next)
+		}
+
+		for _, prop := range fn.PropertyBagWrites() {
+			result.Add(prop)
+		}
+
+		current = next
+	}
+
+	return result, nil
+}
+
+// storageVariant returns the name of the storage variant of the specified type
+func storageVariant(name astmodel.InternalTypeName) astmodel.InternalTypeName {
+	return name.WithPackageReference(astmodel.MakeStoragePackageReference(name.InternalPackageReference()))
+}
+
+// findObjectTypes returns all the object types in the specified package, indexed by name
+func findObjectTypes(
+	definitions astmodel.TypeDefinitionSet,
+	pkg astmodel.InternalPackageReference,
+) map[string]*astmodel.ObjectType {
+	result := make(map[string]*astmodel.ObjectType)
+	for name, def := range definitions {
+		if !name.InternalPackageReference().Equals(pkg) {
+			continue
+		}
+
+		if name.IsARMType() {
+			// ARM types mirror the others, so would only repeat the same differences
+			continue
+		}
+
+		if ot, ok := def.Type().(*astmodel.ObjectType); ok {
+			result[name.Name()] = ot
+		}
+	}
+
+	return result
+}
+
+// suggest returns the candidate most similar to name, if there is one similar enough to be a likely rename
+func suggest(name string, candidates set.Set[string]) (string, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	advisor := typo.NewAdvisor()
+	for c := range candidates {
+		advisor.AddTerm(c)
+	}
+
+	return advisor.Suggest(name, renameSimilarity)
+}
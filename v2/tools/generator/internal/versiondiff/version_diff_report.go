@@ -0,0 +1,188 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package versiondiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/reporting"
+)
+
+// SaveTo writes a human-readable report of the differences, followed by suggested configuration for azure-arm.yaml
+func (diff *VersionDiff) SaveTo(writer io.Writer) error {
+	group := diff.from.Group()
+	fromVersion := diff.from.ApiVersion()
+	toVersion := diff.to.ApiVersion()
+
+	if diff.IsEmpty() {
+		_, err := fmt.Fprintf(writer, "No differences found between %s/%s and %s/%s\n", group, fromVersion, group, toVersion)
+		return err
+	}
+
+	rpt := reporting.NewStructureReport(
+		fmt.Sprintf("Differences from %s/%s to %s/%s", group, fromVersion, group, toVersion))
+	diff.addTypeChanges(rpt)
+	for _, td := range diff.types {
+		td.addTo(rpt)
+	}
+
+	if err := rpt.SaveTo(writer); err != nil {
+		return err
+	}
+
+	var buffer strings.Builder
+	diff.writePropertyBagSummary(&buffer)
+	diff.writeSuggestedConfiguration(&buffer)
+
+	_, err := io.WriteString(writer, buffer.String())
+	return err
+}
+
+func (diff *VersionDiff) addTypeChanges(rpt *reporting.StructureReport) {
+	if len(diff.addedTypes) > 0 {
+		added := rpt.Addf("Types added")
+		for _, name := range diff.addedTypes {
+			added.Addf("%s", name)
+		}
+	}
+
+	if len(diff.removedTypes) > 0 {
+		removed := rpt.Addf("Types removed")
+		for _, name := range diff.removedTypes {
+			removed.Addf("%s", name)
+		}
+	}
+
+	if len(diff.renamedTypes) > 0 {
+		renamed := rpt.Addf("Types likely renamed")
+		for _, r := range diff.renamedTypes {
+			renamed.Addf("%s → %s", r.From, r.To)
+		}
+	}
+}
+
+func (td *TypeDiff) addTo(rpt *reporting.StructureReport) {
+	title := td.name
+	if td.nextName != td.name {
+		title = fmt.Sprintf("%s → %s", td.name, td.nextName)
+	}
+
+	nested := rpt.Addf("%s", title)
+	if td.conversionProblem != "" {
+		nested.Addf("Conversion can't be generated: %s", td.conversionProblem)
+	}
+
+	for _, c := range td.added {
+		nested.Addf("Added %s: %s", c.name, c.toType)
+	}
+
+	for _, c := range td.removed {
+		nested.Addf("Removed %s: %s%s", c.name, c.fromType, c.propertyBagNote())
+	}
+
+	for _, c := range td.retyped {
+		nested.Addf("Retyped %s: %s → %s%s", c.name, c.fromType, c.toType, c.propertyBagNote())
+	}
+
+	for _, c := range td.renamed {
+		if c.configured {
+			nested.Addf("Renamed %s → %s: %s%s", c.name, c.nextName, c.toType, c.propertyBagNote())
+			continue
+		}
+
+		nested.Addf("Likely renamed %s → %s: %s%s", c.name, c.nextName, c.toType, c.propertyBagNote())
+	}
+}
+
+func (c *PropertyChange) propertyBagNote() string {
+	if c.propertyBag {
+		return " (stored in PropertyBag)"
+	}
+
+	return ""
+}
+
+// writePropertyBagSummary lists all the properties that will be stored in the PropertyBag when converting
+func (diff *VersionDiff) writePropertyBagSummary(buffer *strings.Builder) {
+	var properties []string
+	for _, td := range diff.types {
+		for _, changes := range [][]*PropertyChange{td.removed, td.retyped, td.renamed} {
+			for _, c := range changes {
+				if c.propertyBag {
+					properties = append(properties, fmt.Sprintf("%s.%s", td.name, c.name))
+				}
+			}
+		}
+	}
+
+	if len(properties) == 0 {
+		return
+	}
+
+	buffer.WriteString("\n")
+	fmt.Fprintf(
+		buffer,
+		"Properties stored in the PropertyBag when converting from %s to %s:\n",
+		diff.from.ApiVersion(),
+		diff.to.ApiVersion())
+	for _, p := range properties {
+		fmt.Fprintf(buffer, "  %s\n", p)
+	}
+}
+
+// writeSuggestedConfiguration writes configuration for any likely renames. Type renames are configured on the earlier
+// version with $nameInNextVersion; property renames use $renameTo on the later version to restore the earlier name.
+func (diff *VersionDiff) writeSuggestedConfiguration(buffer *strings.Builder) {
+	var typesRenamed []*TypeDiff
+	for _, td := range diff.types {
+		if len(td.suggestedRenames()) > 0 {
+			typesRenamed = append(typesRenamed, td)
+		}
+	}
+
+	if len(diff.renamedTypes) == 0 && len(typesRenamed) == 0 {
+		return
+	}
+
+	buffer.WriteString("\n")
+	buffer.WriteString("Suggested configuration for azure-arm.yaml (review before use):\n")
+	buffer.WriteString("\n")
+	buffer.WriteString("objectModelConfiguration:\n")
+	fmt.Fprintf(buffer, "  %s:\n", diff.from.Group())
+
+	if len(diff.renamedTypes) > 0 {
+		fmt.Fprintf(buffer, "    %s:\n", diff.from.ApiVersion())
+		for _, r := range diff.renamedTypes {
+			fmt.Fprintf(buffer, "      %s:\n", r.From)
+			fmt.Fprintf(buffer, "        $nameInNextVersion: %s\n", r.To)
+		}
+	}
+
+	if len(typesRenamed) > 0 {
+		fmt.Fprintf(buffer, "    %s:\n", diff.to.ApiVersion())
+		for _, td := range typesRenamed {
+			fmt.Fprintf(buffer, "      %s:\n", td.nextName)
+			for _, c := range td.suggestedRenames() {
+				fmt.Fprintf(buffer, "        %s:\n", c.nextName)
+				fmt.Fprintf(buffer, "          $renameTo: %s\n", c.name)
+			}
+		}
+	}
+}
+
+// suggestedRenames returns the likely renames of properties that aren't already configured
+func (td *TypeDiff) suggestedRenames() []*PropertyChange {
+	var result []*PropertyChange
+	for _, c := range td.renamed {
+		if !c.configured {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) Microsoft Corporation.
+ * Licensed under the MIT license.
+ */
+
+package versiondiff
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sebdah/goldie/v2"
+
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/astmodel"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/codegen/pipeline"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/config"
+	"github.com/Azure/azure-service-operator/v2/tools/generator/internal/test"
+)
+
+func TestGolden_VersionDiff_SaveTo_ReportsExpectedChanges(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gold := goldie.New(t)
+
+	ageInt := astmodel.NewPropertyDefinition("Age", "age", astmodel.OptionalIntType)
+	ageRequired := astmodel.NewPropertyDefinition("Age", "age", astmodel.IntType)
+	tlsVersion := astmodel.NewPropertyDefinition("TlsVersion", "tlsVersion", astmodel.OptionalStringType)
+	minimumTlsVersion := astmodel.NewPropertyDefinition("MinimumTlsVersion", "minimumTlsVersion", astmodel.OptionalStringType)
+	zone := astmodel.NewPropertyDefinition("Zone", "zone", astmodel.OptionalStringType)
+
+	from := []astmodel.TypeDefinition{
+		test.CreateObjectDefinition(test.Pkg2020, "Person_Spec", test.FullNameProperty, ageInt, tlsVersion, test.KnownAsProperty),
+		test.CreateObjectDefinition(test.Pkg2020, "Address", test.FullAddressProperty),
+		test.CreateObjectDefinition(test.Pkg2020, "Legacy", test.CityProperty),
+	}
+
+	to := []astmodel.TypeDefinition{
+		test.CreateObjectDefinition(test.Pkg2021, "Person_Spec", test.FullNameProperty, ageRequired, minimumTlsVersion, zone),
+		test.CreateObjectDefinition(test.Pkg2021, "Addresses", test.FullAddressProperty),
+		test.CreateObjectDefinition(test.Pkg2021, "Location", test.CityProperty),
+	}
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(from...)
+	defs.AddAll(to...)
+
+	diff := compareTestVersions(g, defs, config.NewObjectModelConfiguration())
+
+	var buffer bytes.Buffer
+	g.Expect(diff.SaveTo(&buffer)).To(Succeed())
+
+	gold.Assert(t, t.Name(), buffer.Bytes())
+}
+
+func TestVersionDiff_WhenTypeRenameConfigured_PairsTypes(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	from := test.CreateObjectDefinition(test.Pkg2020, "Legacy", test.CityProperty)
+	to := test.CreateObjectDefinition(test.Pkg2021, "Location", test.CityProperty)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(from, to)
+
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyType(
+			from.Name(),
+			func(tc *config.TypeConfiguration) error {
+				tc.NameInNextVersion.Set("Location")
+				return nil
+			})).
+		To(Succeed())
+
+	diff := compareTestVersions(g, defs, omc)
+	g.Expect(diff.IsEmpty()).To(BeTrue())
+}
+
+func TestVersionDiff_WhenPropertyRenameConfigured_PairsProperties(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	tlsVersion := astmodel.NewPropertyDefinition("TlsVersion", "tlsVersion", astmodel.OptionalStringType)
+	minimumTlsVersion := astmodel.NewPropertyDefinition("MinimumTlsVersion", "minimumTlsVersion", astmodel.OptionalStringType)
+
+	from := test.CreateObjectDefinition(test.Pkg2020, "Person_Spec", test.FullNameProperty, tlsVersion)
+	to := test.CreateObjectDefinition(test.Pkg2021, "Person_Spec", test.FullNameProperty, minimumTlsVersion)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(from, to)
+
+	omc := config.NewObjectModelConfiguration()
+	g.Expect(
+		omc.ModifyProperty(
+			from.Name(),
+			tlsVersion.PropertyName(),
+			func(pc *config.PropertyConfiguration) error {
+				pc.NameInNextVersion.Set("MinimumTlsVersion")
+				return nil
+			})).
+		To(Succeed())
+
+	diff := compareTestVersions(g, defs, omc)
+	g.Expect(diff.types).To(HaveLen(1))
+
+	td := diff.types[0]
+	g.Expect(td.added).To(BeEmpty())
+	g.Expect(td.removed).To(BeEmpty())
+	g.Expect(td.renamed).To(HaveLen(1))
+	g.Expect(td.renamed[0].nextName).To(Equal(minimumTlsVersion.PropertyName()))
+	g.Expect(td.renamed[0].configured).To(BeTrue())
+
+	var buffer bytes.Buffer
+	g.Expect(diff.SaveTo(&buffer)).To(Succeed())
+	g.Expect(buffer.String()).To(ContainSubstring("Renamed TlsVersion → MinimumTlsVersion"))
+	g.Expect(buffer.String()).NotTo(ContainSubstring("Suggested configuration"))
+}
+
+func TestVersionDiff_WhenRetypedPropertyCanBeConverted_DoesNotReportPropertyBag(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// Enums are stored as their base type, so the value converts directly
+	tier := astmodel.MakeTypeDefinition(
+		astmodel.MakeInternalTypeName(test.Pkg2021, "Tier"),
+		astmodel.NewEnumType(astmodel.StringType, astmodel.MakeEnumValue("Basic", "\"Basic\"")))
+	tierString := astmodel.NewPropertyDefinition("Tier", "tier", astmodel.OptionalStringType)
+	tierEnum := astmodel.NewPropertyDefinition("Tier", "tier", astmodel.NewOptionalType(tier.Name()))
+
+	from := test.CreateObjectDefinition(test.Pkg2020, "Person_Spec", test.FullNameProperty, tierString)
+	to := test.CreateObjectDefinition(test.Pkg2021, "Person_Spec", test.FullNameProperty, tierEnum)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(from, to, tier)
+
+	diff := compareTestVersions(g, defs, config.NewObjectModelConfiguration())
+	g.Expect(diff.types).To(HaveLen(1))
+
+	td := diff.types[0]
+	g.Expect(td.conversionProblem).To(BeEmpty())
+	g.Expect(td.retyped).To(HaveLen(1))
+	g.Expect(td.retyped[0].propertyBag).To(BeFalse())
+}
+
+func TestVersionDiff_WhenRetypedPropertyCannotBeConverted_ReportsConversionProblem(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	ageInt := astmodel.NewPropertyDefinition("Age", "age", astmodel.OptionalIntType)
+	ageString := astmodel.NewPropertyDefinition("Age", "age", astmodel.OptionalStringType)
+
+	from := test.CreateObjectDefinition(test.Pkg2020, "Person_Spec", test.FullNameProperty, ageInt)
+	to := test.CreateObjectDefinition(test.Pkg2021, "Person_Spec", test.FullNameProperty, ageString)
+
+	defs := make(astmodel.TypeDefinitionSet)
+	defs.AddAll(from, to)
+
+	diff := compareTestVersions(g, defs, config.NewObjectModelConfiguration())
+	g.Expect(diff.types).To(HaveLen(1))
+
+	td := diff.types[0]
+	g.Expect(td.conversionProblem).To(ContainSubstring(`no conversion found to assign "*string" from "*int"`))
+	g.Expect(td.retyped).To(HaveLen(1))
+	g.Expect(td.retyped[0].propertyBag).To(BeFalse())
+}
+
+// compareTestVersions compares the types of test.Pkg2020 with those of test.Pkg2021, after creating their storage
+// variants and the conversion graph between them
+func compareTestVersions(
+	g *WithT,
+	defs astmodel.TypeDefinitionSet,
+	omc *config.ObjectModelConfiguration,
+) *VersionDiff {
+	cfg := config.NewConfiguration()
+	cfg.ObjectModelConfiguration = omc
+
+	state := pipeline.NewState().WithDefinitions(defs)
+	for _, stage := range []*pipeline.Stage{
+		pipeline.CreateStorageTypes(),
+		pipeline.CreateConversionGraph(cfg, "v"),
+	} {
+		var err error
+		state, err = stage.Run(context.TODO(), state)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	return Compare(state.Definitions(), state.ConversionGraph(), omc, test.Pkg2020, test.Pkg2021)
+}
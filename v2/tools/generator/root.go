@@ -55,6 +55,7 @@ func newRootCommand() (*cobra.Command, error) {
 	cmdFuncs := []func() (*cobra.Command, error){
 		NewGenTypesCommand,
 		NewGenKustomizeCommand,
+		NewDiffVersionsCommand,
 		version.NewCommand,
 	}
 